@@ -0,0 +1,89 @@
+package dataModel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LinkClickDay counts the clicks of a mapping on one UTC day. Click analytics
+// add these up over the retention period of the plan of whoever reads them,
+// URLMapping.Clicks keeps the clicks of all time.
+type LinkClickDay struct {
+	URLMappingID uint      `gorm:"primaryKey"`
+	Day          time.Time `gorm:"primaryKey;type:date;index"`
+	Clicks       int64     `gorm:"not null;default:0"`
+}
+
+// AddLinkClicks adds counts to the clicks of the mappings they are keyed by,
+// and to their clicks of today.
+func (db *DB) AddLinkClicks(counts map[uint]int64) error {
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	return db.Transaction(func(tx *gorm.DB) error {
+		for id, n := range counts {
+			err := tx.Model(&URLMapping{}).Where("id = ?", id).
+				UpdateColumn("clicks", gorm.Expr("clicks + ?", n)).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "url_mapping_id"}, {Name: "day"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"clicks": gorm.Expr("link_click_days.clicks + EXCLUDED.clicks")}),
+			}).Create(&LinkClickDay{URLMappingID: id, Day: day, Clicks: n}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetLinkClicks adds up the clicks of mappings on the days since since,
+// keyed by mapping ID. Mappings without clicks are left out.
+func (db *DB) GetLinkClicks(mappingIDs []uint, since time.Time) (map[uint]int64, error) {
+	var rows []struct {
+		URLMappingID uint
+		Clicks       int64
+	}
+	err := db.Model(&LinkClickDay{}).Select("url_mapping_id, SUM(clicks) AS clicks").
+		Where("url_mapping_id IN ? AND day >= ?", mappingIDs, since).
+		Group("url_mapping_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	clicks := make(map[uint]int64, len(rows))
+	for _, r := range rows {
+		clicks[r.URLMappingID] = r.Clicks
+	}
+	return clicks, nil
+}
+
+// PruneLinkClicks deletes the clicks counted on days before before and
+// returns how many days of links were deleted.
+func (db *DB) PruneLinkClicks(before time.Time) (int64, error) {
+	result := db.Where("day < ?", before).Delete(&LinkClickDay{})
+	return result.RowsAffected, result.Error
+}
+
+// recentClicks joins the clicks of url_mappings on the days since since as
+// recent_clicks.clicks, NULL for mappings without any.
+func recentClicks(since time.Time) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		return query.Joins("LEFT JOIN (SELECT url_mapping_id, SUM(clicks) AS clicks FROM link_click_days "+
+			"WHERE day >= ? GROUP BY url_mapping_id) AS recent_clicks ON recent_clicks.url_mapping_id = url_mappings.id", since)
+	}
+}
+
+// migrateLinkClicks dates the clicks counted before LinkClickDays existed on
+// the day of the migration, so they are not lost to analytics.
+func (db *DB) migrateLinkClicks() error {
+	if db.Migrator().HasTable(&LinkClickDay{}) || !db.Migrator().HasColumn(&URLMapping{}, "Clicks") {
+		return nil
+	}
+	if err := db.Migrator().CreateTable(&LinkClickDay{}); err != nil {
+		return err
+	}
+	return db.Exec("INSERT INTO link_click_days (url_mapping_id, day, clicks) " +
+		"SELECT id, CURRENT_DATE, clicks FROM url_mappings WHERE clicks > 0").Error
+}
@@ -1,22 +1,95 @@
 package dataModel
 
 import (
-	"log"
-	"net/url" 
+	"errors"
 	"fmt"
+	"log"
+	"net/url"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // URLMapping represents the mapping between short URL ID and long URL.
 type URLMapping struct {
 	gorm.Model
-	ShortURLID string `gorm:"uniqueIndex"`
-	LongURL    string `gorm:"uniqueIndex"`
+	// Host is the branded domain the link is served on, empty for the
+	// service's own hosts. Short URL IDs are unique per host.
+	Host       string `gorm:"uniqueIndex:idx_url_mappings_host_short_url_id,priority:1;not null;default:''"`
+	ShortURLID string `gorm:"uniqueIndex:idx_url_mappings_host_short_url_id,priority:2"`
+	LongURL    string `gorm:"index:idx_url_mappings_long_url_lookup"`
 	DomainName string `gorm:"index"` // Added for metrics
+	UserID     uint   `gorm:"index"`
+	// WorkspaceID is the workspace owning the link, UserID the member who
+	// created it.
+	WorkspaceID   uint `gorm:"index;not null;default:0"`
+	IsCustomAlias bool
+	// Disabled links are kept but no longer resolve, e.g. because their
+	// destination was blocked after they were created.
+	Disabled       bool `gorm:"not null;default:false"`
+	DisabledReason string
+	// DisabledRuleID is the policy rule that disabled the link, 0 for rules
+	// from the policy file.
+	DisabledRuleID uint `gorm:"not null;default:0"`
+	// ResolvedURL is where LongURL ends up when it points at another
+	// shortener, empty when LongURL is the final destination.
+	ResolvedURL string
+	// RedirectStatus is the HTTP status sent when following the link.
+	RedirectStatus int `gorm:"not null;default:302"`
+	// QueryPassthrough merges the incoming query string into LongURL.
+	QueryPassthrough bool `gorm:"not null;default:false"`
+	// ForwardPath appends the path after the short code to LongURL.
+	ForwardPath bool `gorm:"not null;default:false"`
+	// PasswordHash is the bcrypt hash of the link password, empty for
+	// unprotected links.
+	PasswordHash string
+	// MaxClicks limits how often the link may be followed, 0 for no limit.
+	// ClicksRemaining counts down from MaxClicks.
+	MaxClicks       int64 `gorm:"not null;default:0"`
+	ClicksRemaining int64 `gorm:"not null;default:0"`
+	// Interstitial links always show the preview page instead of redirecting.
+	Interstitial bool `gorm:"not null;default:false"`
+	// HasRoutingRules is set while the mapping has RoutingRules, sparing
+	// their lookup on every redirect of the others.
+	HasRoutingRules bool `gorm:"not null;default:false"`
+	// HasVariants is set while the mapping splits its traffic across
+	// LinkVariants instead of sending it to LongURL.
+	HasVariants bool `gorm:"not null;default:false"`
+	// VariantStickiness is how visitors keep their variant, "cookie" or "ip".
+	VariantStickiness string
+	// HasSchedule is set while the mapping switches to ScheduledDestinations
+	// over time.
+	HasSchedule bool `gorm:"not null;default:false"`
+	// CampaignID and FolderID are the LinkGroups the mapping is in, 0 for
+	// none.
+	CampaignID uint `gorm:"index;not null;default:0"`
+	FolderID   uint `gorm:"index;not null;default:0"`
+	// Clicks counts the redirects served, including those continued from a
+	// preview page.
+	Clicks int64 `gorm:"not null;default:0"`
+	// UTMSource and UTMMedium are copied from the utm_* parameters of
+	// LongURL to group analytics by.
+	UTMSource string `gorm:"column:utm_source"`
+	UTMMedium string `gorm:"column:utm_medium"`
+	// OG is served to crawlers unfurling the link instead of a redirect.
+	OG OpenGraph `gorm:"embedded;embeddedPrefix:og_"`
+	// Health is kept up to date by the link checker.
+	Health LinkHealth `gorm:"embedded;embeddedPrefix:health_"`
 }
 
+// Exhausted reports whether a click limited mapping has no clicks left.
+func (m *URLMapping) Exhausted() bool {
+	return m.MaxClicks > 0 && m.ClicksRemaining <= 0
+}
+
+// ErrLinkDisabled is returned when resolving a disabled mapping.
+var ErrLinkDisabled = errors.New("link is disabled")
+
+// ErrLinkExhausted is returned when a click limited mapping has no clicks left.
+var ErrLinkExhausted = errors.New("link has no clicks left")
+
 // User represents a user in the system.
 type User struct {
 	gorm.Model
@@ -24,6 +97,12 @@ type User struct {
 	FirstName string    `gorm:"not null"`
 	LastName  string    `gorm:"not null"`
 	APIKey    uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4()"`
+	Plan      string    `gorm:"not null;default:'free'"`
+	IsAdmin   bool      `gorm:"not null;default:false"`
+	// Workspace is the workspace of the API key the user was looked up by,
+	// see GetUserByAPIKey, and Role the user's role in it.
+	Workspace Workspace `gorm:"-"`
+	Role      string    `gorm:"-"`
 }
 
 // DomainCount holds the domain name and its count.
@@ -34,13 +113,81 @@ type DomainCount struct {
 
 // DataAccessLayer defines the interface for accessing data.
 type DataAccessLayer interface {
-	CreateURLMapping(mapping *URLMapping) error
+	CreateURLMapping(mapping *URLMapping, events ...*OutboxEvent) error
+	CreateURLMappings(mappings []*URLMapping, events ...*OutboxEvent) error
+	FindShortURLIDs(host string, shortURLIDs []string) ([]string, error)
 	GetLongURL(shortURLID string) (string, error)
+	GetURLMapping(shortURLID string) (*URLMapping, error)
+	GetURLMappingOnHost(host, shortURLID string) (*URLMapping, error)
+	GetURLMappingByLongURL(workspaceID uint, longURL string) (*URLMapping, error)
+	ConsumeClick(host, shortURLID string) (int64, error)
 	CreateUser(user *User) error
 	GetUserByEmail(email string) (*User, error)
 	GetAPIKeyByEmail(email string) (string, error)
 	CheckAPIKey(apiKey string) (bool, error)
+	GetUserByAPIKey(apiKey string) (*User, error)
+	GetUserByID(id uint) (*User, error)
 	GetTopDomains(limit int) ([]DomainCount, error) // Added for metrics
+	CreatePolicyRule(rule *PolicyRule) error
+	DeletePolicyRule(id uint) error
+	ListPolicyRules() ([]PolicyRule, error)
+	ListPolicyCandidates(scope PolicyScope, afterID uint, limit int) ([]URLMapping, error)
+	DisableURLMappings(ids []uint, ruleID uint, reason string) (int64, error)
+	EnableURLMappings(ids []uint) (int64, error)
+	GetUsage(userID uint, cycleStart time.Time) (*UsageCounter, error)
+	ConsumeUsage(userID uint, cycleStart time.Time, delta UsageDelta, limits UsageLimits) (bool, error)
+	ReleaseUsage(userID uint, cycleStart time.Time, delta UsageDelta) error
+	CreateWebhookSubscription(sub *WebhookSubscription) error
+	ListWebhookSubscriptions(workspaceID uint) ([]WebhookSubscription, error)
+	DeleteWebhookSubscription(workspaceID, id uint) error
+	CreateWebhookDeliveries(deliveries []*WebhookDelivery) error
+	ClaimWebhookDeliveries(now time.Time, limit int, lease time.Duration) ([]WebhookDelivery, error)
+	UpdateWebhookDelivery(d *WebhookDelivery) error
+	ListWebhookDeliveries(workspaceID, subscriptionID uint, status string, limit int) ([]WebhookDelivery, error)
+	ListWebhookDeadLetters(workspaceID uint, limit int) ([]WebhookDeadLetter, error)
+	InsertOutboxEvents(events []*OutboxEvent) error
+	PublishOutboxEvents(limit int, publish func(*OutboxEvent) error) (int, error)
+	CreateDomain(domain *Domain) error
+	ListDomains(workspaceID uint) ([]Domain, error)
+	GetDomain(workspaceID, id uint) (*Domain, error)
+	GetVerifiedDomain(host string) (*Domain, error)
+	VerifyDomain(domain *Domain, now time.Time) error
+	DeleteDomain(workspaceID, id uint) error
+	CreateRoutingRule(rule *RoutingRule) error
+	ListRoutingRules(mappingID uint) ([]RoutingRule, error)
+	DeleteRoutingRule(mappingID, id uint) error
+	ReplaceLinkVariants(mappingID uint, stickiness string, variants []LinkVariant) error
+	ListLinkVariants(mappingID uint) ([]LinkVariant, error)
+	RecordVariantClick(id uint) error
+	RecordVariantConversion(mappingID, id uint) error
+	ReplaceSchedule(mappingID uint, schedule []ScheduledDestination) error
+	ListSchedule(mappingID uint) ([]ScheduledDestination, error)
+	CreateLinkGroup(group *LinkGroup) error
+	ListLinkGroups(workspaceID uint, kind string) ([]LinkGroup, error)
+	GetLinkGroup(workspaceID, id uint) (*LinkGroup, error)
+	RenameLinkGroup(group *LinkGroup, name string) error
+	FindWorkspaceURLMappings(workspaceID uint, host string, shortURLIDs []string) ([]URLMapping, error)
+	AssignLinks(group *LinkGroup, mappingIDs []uint, assign bool) (int64, error)
+	ListLinkTags(mappingIDs []uint) ([]LinkTag, error)
+	ListWorkspaceURLMappings(workspaceID uint, filter LinkFilter) ([]URLMapping, error)
+	GetLinkGroupStats(group *LinkGroup, top int, since time.Time) (*LinkGroupStats, error)
+	AddLinkClicks(counts map[uint]int64) error
+	GetLinkClicks(mappingIDs []uint, since time.Time) (map[uint]int64, error)
+	PruneLinkClicks(before time.Time) (int64, error)
+	SetCampaignUTM(group *LinkGroup, utm UTMParams) error
+	ReplaceUTMRules(workspaceID uint, rules []UTMRule) error
+	ListUTMRules(workspaceID uint) ([]UTMRule, error)
+	GetUTMStats(workspaceID, campaignID uint, since time.Time) ([]UTMStats, error)
+	SetLinkOpenGraph(mapping *URLMapping, og OpenGraph) error
+	ClaimLinkChecks(now time.Time, limit int, lease time.Duration) ([]URLMapping, error)
+	RecordLinkCheck(mapping *URLMapping) error
+	CreateWorkspace(workspace *Workspace, userID uint) (*WorkspaceMember, error)
+	ListUserWorkspaces(userID uint) ([]WorkspaceMembership, error)
+	ListWorkspaceMembers(workspaceID uint) ([]MemberUser, error)
+	GetWorkspaceMember(workspaceID, userID uint) (*WorkspaceMember, error)
+	AddWorkspaceMember(member *WorkspaceMember) error
+	SetWorkspaceMemberRole(workspaceID, userID uint, role string) error
+	RemoveWorkspaceMember(workspaceID, userID uint) error
 	AutoMigrate(dst ...interface{}) error
 }
 
@@ -54,8 +201,60 @@ func NewDB(db *gorm.DB) *DB {
 	return &DB{db}
 }
 
-// CreateURLMapping creates a new URL mapping in the database.
-func (db *DB) CreateURLMapping(mapping *URLMapping) error {
+// CreateURLMapping creates a new URL mapping in the database, together with
+// the outbox events announcing it.
+func (db *DB) CreateURLMapping(mapping *URLMapping, events ...*OutboxEvent) error {
+	if err := setDomainName(mapping); err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return db.Create(mapping).Error
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(mapping).Error; err != nil {
+			return err
+		}
+		return tx.Create(events).Error
+	})
+}
+
+// CreateURLMappings creates several URL mappings with a single multi-row
+// insert, together with the outbox events announcing them. Either all of
+// them are created or none.
+func (db *DB) CreateURLMappings(mappings []*URLMapping, events ...*OutboxEvent) error {
+	if len(mappings) == 0 {
+		return nil
+	}
+	for _, mapping := range mappings {
+		if err := setDomainName(mapping); err != nil {
+			return err
+		}
+	}
+	if len(events) == 0 {
+		return db.Create(&mappings).Error
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&mappings).Error; err != nil {
+			return err
+		}
+		return tx.Create(events).Error
+	})
+}
+
+// FindShortURLIDs returns which of the given short URL IDs are taken on host.
+func (db *DB) FindShortURLIDs(host string, shortURLIDs []string) ([]string, error) {
+	var taken []string
+	if len(shortURLIDs) == 0 {
+		return taken, nil
+	}
+	err := db.Model(&URLMapping{}).Unscoped().
+		Where("host = ? AND short_url_id IN ?", host, shortURLIDs).
+		Pluck("short_url_id", &taken).Error
+	return taken, err
+}
+
+// setDomainName fills in the DomainName of mapping from its LongURL.
+func setDomainName(mapping *URLMapping) error {
 	// Parse domain from LongURL
 	parsedURL, err := url.Parse(mapping.LongURL)
 	if err != nil {
@@ -63,29 +262,88 @@ func (db *DB) CreateURLMapping(mapping *URLMapping) error {
 		return fmt.Errorf("invalid Url as parsing failed")
 	} else {
 		mapping.DomainName = parsedURL.Hostname()
-		// Ensure DomainName is "" if Hostname() returns empty (e.g. for file URLs)
 		if mapping.DomainName == "" {
 			log.Printf("Parsed URL %s resulted in an empty hostname.", mapping.LongURL)
+			return fmt.Errorf("invalid Url as it has no hostname")
 		}
 	}
-	return db.Create(mapping).Error
+	return nil
+}
+
+// GetURLMapping retrieves the mapping for a given short URL ID on the
+// service's own hosts, including disabled ones.
+func (db *DB) GetURLMapping(shortURLID string) (*URLMapping, error) {
+	return db.GetURLMappingOnHost("", shortURLID)
+}
+
+// GetURLMappingOnHost retrieves the mapping for a given short URL ID on a
+// branded host, including disabled ones. Only mappings of the workspace
+// that verified the host are found, so links left behind by a workspace
+// that deleted the domain are not served for whoever verifies it next.
+func (db *DB) GetURLMappingOnHost(host, shortURLID string) (*URLMapping, error) {
+	var mapping URLMapping
+	query := db.Where("host = ? AND short_url_id = ?", host, shortURLID)
+	if host != "" {
+		owner := db.Model(&Domain{}).Select("workspace_id").Where("host = ? AND verified_at IS NOT NULL", host)
+		query = query.Where("workspace_id IN (?)", owner)
+	}
+	err := query.First(&mapping).Error
+	if err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// GetURLMappingByLongURL retrieves the mapping of a long URL already
+// shortened in a workspace. Click limited mappings, those on branded hosts,
+// password protected and disabled ones are never returned since they
+// cannot be shared, nor are those sending visitors elsewhere through
+// routing rules, variants or a schedule.
+func (db *DB) GetURLMappingByLongURL(workspaceID uint, longURL string) (*URLMapping, error) {
+	var mapping URLMapping
+	err := db.Where(&URLMapping{LongURL: longURL}).
+		Where("workspace_id = ? AND max_clicks = 0 AND host = '' AND COALESCE(password_hash, '') = '' AND NOT disabled", workspaceID).
+		Where("NOT has_routing_rules AND NOT has_variants AND NOT has_schedule").
+		First(&mapping).Error
+	if err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// ConsumeClick atomically uses up one click of a click limited mapping and
+// returns the clicks left. The conditional update keeps concurrent redirects
+// on different replicas from overspending; ErrLinkExhausted is returned once
+// no clicks are left.
+func (db *DB) ConsumeClick(host, shortURLID string) (int64, error) {
+	var mapping URLMapping
+	result := db.Model(&mapping).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "clicks_remaining"}}}).
+		Where("host = ? AND short_url_id = ? AND max_clicks > 0 AND clicks_remaining > 0", host, shortURLID).
+		UpdateColumn("clicks_remaining", gorm.Expr("clicks_remaining - 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, ErrLinkExhausted
+	}
+	return mapping.ClicksRemaining, nil
 }
 
-// GetLongURL retrieves the long URL for a given short URL ID.
+// GetLongURL retrieves the long URL for a given short URL ID on the
+// service's own hosts. Disabled mappings yield ErrLinkDisabled.
 func (db *DB) GetLongURL(shortURLID string) (string, error) {
 	var mapping URLMapping
-	err := db.Where(&URLMapping{ShortURLID: shortURLID}).First(&mapping).Error
+	err := db.Where("host = '' AND short_url_id = ?", shortURLID).First(&mapping).Error
 	if err != nil {
 		return "", err
 	}
+	if mapping.Disabled {
+		return "", ErrLinkDisabled
+	}
 	return mapping.LongURL, nil
 }
 
-// CreateUser creates a new user in the database.
-func (db *DB) CreateUser(user *User) error {
-	return db.Create(user).Error
-}
-
 // GetUserByEmail retrieves a user from the database by email.
 func (db *DB) GetUserByEmail(email string) (*User, error) {
 	var user User
@@ -108,13 +366,13 @@ func (db *DB) GetAPIKeyByEmail(email string) (string, error) {
 
 // CheckAPIKey checks if an API key exists in the database.
 func (db *DB) CheckAPIKey(apiKey string) (bool, error) {
-	var user User
 	api, err := uuid.Parse(apiKey)
 	if err != nil {
 		log.Printf("api key invalid")
 		return false, err
 	}
-	err = db.Where(&User{APIKey: api}).First(&user).Error
+	var member WorkspaceMember
+	err = db.Where(&WorkspaceMember{APIKey: api}).First(&member).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return false, nil
@@ -124,12 +382,71 @@ func (db *DB) CheckAPIKey(apiKey string) (bool, error) {
 	return true, nil
 }
 
+// GetUserByAPIKey retrieves the user holding the given API key, with the
+// workspace the key belongs to and their role in it. A malformed key is
+// reported as gorm.ErrRecordNotFound.
+func (db *DB) GetUserByAPIKey(apiKey string) (*User, error) {
+	api, err := uuid.Parse(apiKey)
+	if err != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	var member WorkspaceMember
+	if err := db.Where(&WorkspaceMember{APIKey: api}).First(&member).Error; err != nil {
+		return nil, err
+	}
+	var user User
+	if err := db.First(&user, member.UserID).Error; err != nil {
+		return nil, err
+	}
+	if err := db.First(&user.Workspace, member.WorkspaceID).Error; err != nil {
+		return nil, err
+	}
+	user.Role = member.Role
+	return &user, nil
+}
+
+// GetUserByID retrieves a user by ID.
+func (db *DB) GetUserByID(id uint) (*User, error) {
+	var user User
+	err := db.First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (db *DB) AutoMigrate(dst ...interface{}) error {
 	if err := db.DB.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"").Error; err != nil {
 		log.Printf("failed to create uuid-ossp extension: %v", err)
 		return err
 	}
-	return db.DB.AutoMigrate(dst...)
+	// Long URLs used to be unique; click limited links may share one.
+	if db.Migrator().HasIndex(&URLMapping{}, "idx_url_mappings_long_url") {
+		if err := db.Migrator().DropIndex(&URLMapping{}, "idx_url_mappings_long_url"); err != nil {
+			log.Printf("failed to drop unique long URL index: %v", err)
+			return err
+		}
+	}
+	if err := db.migrateWorkspaces(); err != nil {
+		log.Printf("failed to migrate to workspaces: %v", err)
+		return err
+	}
+	if err := db.migrateLinkClicks(); err != nil {
+		log.Printf("failed to migrate link clicks: %v", err)
+		return err
+	}
+	if err := db.DB.AutoMigrate(dst...); err != nil {
+		return err
+	}
+	// Short URL IDs used to be unique; they are now unique per host, see
+	// idx_url_mappings_host_short_url_id.
+	if db.Migrator().HasIndex(&URLMapping{}, "idx_url_mappings_short_url_id") {
+		if err := db.Migrator().DropIndex(&URLMapping{}, "idx_url_mappings_short_url_id"); err != nil {
+			log.Printf("failed to drop unique short URL index: %v", err)
+			return err
+		}
+	}
+	return nil
 }
 
 // GetTopDomains retrieves the top N domains with the most shortened URLs.
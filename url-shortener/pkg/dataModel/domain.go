@@ -0,0 +1,104 @@
+package dataModel
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrDomainTaken is returned when verifying a host another workspace
+// already verified.
+var ErrDomainTaken = errors.New("domain is already verified by another workspace")
+
+// Domain is a branded host a workspace serves its links on, e.g.
+// go.acme.com. Several workspaces may claim a host, but only the first to
+// prove ownership through DNS gets it verified; links are only served on
+// verified domains. UserID is the member who added it.
+type Domain struct {
+	gorm.Model
+	UserID      uint   `gorm:"index;not null"`
+	WorkspaceID uint   `gorm:"uniqueIndex:idx_domains_workspace_host;not null;default:0"`
+	Host        string `gorm:"uniqueIndex:idx_domains_workspace_host;uniqueIndex:idx_domains_verified_host,where:verified_at IS NOT NULL;not null"`
+	// VerificationToken must be published in a DNS TXT record to verify
+	// the domain.
+	VerificationToken string `gorm:"not null"`
+	VerifiedAt        *time.Time
+}
+
+// Verified reports whether ownership of the domain was proven.
+func (d *Domain) Verified() bool {
+	return d.VerifiedAt != nil
+}
+
+// CreateDomain creates a new, unverified domain.
+func (db *DB) CreateDomain(domain *Domain) error {
+	return db.Create(domain).Error
+}
+
+// ListDomains retrieves the domains of a workspace.
+func (db *DB) ListDomains(workspaceID uint) ([]Domain, error) {
+	var domains []Domain
+	err := db.Where("workspace_id = ?", workspaceID).Order("id").Find(&domains).Error
+	if err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// GetDomain retrieves a domain of a workspace.
+func (db *DB) GetDomain(workspaceID, id uint) (*Domain, error) {
+	var domain Domain
+	err := db.Where("workspace_id = ?", workspaceID).First(&domain, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+// GetVerifiedDomain retrieves the verified domain of host.
+func (db *DB) GetVerifiedDomain(host string) (*Domain, error) {
+	var domain Domain
+	err := db.Where("host = ? AND verified_at IS NOT NULL", host).First(&domain).Error
+	if err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+// VerifyDomain marks domain verified at now. ErrDomainTaken is returned when
+// another workspace verified the host first.
+func (db *DB) VerifyDomain(domain *Domain, now time.Time) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var taken int64
+		err := tx.Model(&Domain{}).
+			Where("host = ? AND id <> ? AND verified_at IS NOT NULL", domain.Host, domain.ID).
+			Count(&taken).Error
+		if err != nil {
+			return err
+		}
+		if taken > 0 {
+			return ErrDomainTaken
+		}
+		if err := tx.Model(domain).Update("verified_at", now).Error; err != nil {
+			return err
+		}
+		domain.VerifiedAt = &now
+		return nil
+	})
+}
+
+// DeleteDomain deletes a domain of a workspace. Its links stay but are no longer
+// served until the workspace adds and verifies the domain again; another
+// workspace verifying the host does not get them. Domains are deleted for
+// good so the host can be claimed again.
+func (db *DB) DeleteDomain(workspaceID, id uint) error {
+	result := db.Unscoped().Where("workspace_id = ?", workspaceID).Delete(&Domain{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package dataModel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Kinds of link groups.
+const (
+	GroupKindTag      = "tag"
+	GroupKindCampaign = "campaign"
+	GroupKindFolder   = "folder"
+)
+
+// LinkGroup organizes the links of a workspace. A link has any number of
+// tags, through LinkTags, but is in at most one campaign and one folder,
+// through URLMapping.CampaignID and FolderID. UserID is the member who
+// created the group.
+type LinkGroup struct {
+	gorm.Model
+	UserID      uint   `gorm:"index;not null"`
+	WorkspaceID uint   `gorm:"uniqueIndex:idx_link_groups_workspace_kind_name,priority:1;not null;default:0"`
+	Kind        string `gorm:"uniqueIndex:idx_link_groups_workspace_kind_name,priority:2;not null"`
+	Name        string `gorm:"uniqueIndex:idx_link_groups_workspace_kind_name,priority:3;not null"`
+	// UTM are the parameters links created in a campaign inherit.
+	UTM UTMParams `gorm:"embedded"`
+}
+
+// LinkTag puts a tag on a link.
+type LinkTag struct {
+	URLMappingID uint `gorm:"primaryKey"`
+	LinkGroupID  uint `gorm:"primaryKey;index"`
+}
+
+// LinkFilter selects links of a workspace. Zero fields select any link.
+type LinkFilter struct {
+	TagID      uint
+	CampaignID uint
+	FolderID   uint
+	// Broken only selects links whose destination is broken.
+	Broken bool
+	// BeforeID only selects links created before the one with this ID.
+	BeforeID uint
+	Limit    int
+}
+
+// LinkGroupStats aggregates the links of a group.
+type LinkGroupStats struct {
+	Links       int64
+	Clicks      int64
+	Conversions int64
+	// TopLinks are the most clicked links, TopDomains their destination
+	// domains by clicks.
+	TopLinks   []URLMapping
+	TopDomains []DomainCount
+}
+
+// CreateLinkGroup creates a new link group.
+func (db *DB) CreateLinkGroup(group *LinkGroup) error {
+	return db.Create(group).Error
+}
+
+// ListLinkGroups retrieves the link groups of a workspace of kind, or of
+// every kind when kind is empty.
+func (db *DB) ListLinkGroups(workspaceID uint, kind string) ([]LinkGroup, error) {
+	var groups []LinkGroup
+	query := db.Where("workspace_id = ?", workspaceID)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if err := query.Order("kind, name").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// GetLinkGroup retrieves a link group of a workspace.
+func (db *DB) GetLinkGroup(workspaceID, id uint) (*LinkGroup, error) {
+	var group LinkGroup
+	err := db.Where("workspace_id = ?", workspaceID).First(&group, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// RenameLinkGroup renames a link group.
+func (db *DB) RenameLinkGroup(group *LinkGroup, name string) error {
+	if err := db.Model(group).Update("name", name).Error; err != nil {
+		return err
+	}
+	group.Name = name
+	return nil
+}
+
+// FindWorkspaceURLMappings retrieves the mappings of a workspace with the
+// given short URL IDs on host. IDs of other workspaces or unknown IDs are
+// left out.
+func (db *DB) FindWorkspaceURLMappings(workspaceID uint, host string, shortURLIDs []string) ([]URLMapping, error) {
+	var mappings []URLMapping
+	err := db.Where("workspace_id = ? AND host = ? AND short_url_id IN ?", workspaceID, host, shortURLIDs).Find(&mappings).Error
+	if err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+// AssignLinks adds mappings to a group, or removes them from it when assign
+// is false. Removing mappings from a campaign or folder leaves those in
+// another one alone. It returns how many mappings changed.
+func (db *DB) AssignLinks(group *LinkGroup, mappingIDs []uint, assign bool) (int64, error) {
+	if len(mappingIDs) == 0 {
+		return 0, nil
+	}
+	if group.Kind == GroupKindTag {
+		if !assign {
+			result := db.Where("link_group_id = ? AND url_mapping_id IN ?", group.ID, mappingIDs).Delete(&LinkTag{})
+			return result.RowsAffected, result.Error
+		}
+		tags := make([]LinkTag, len(mappingIDs))
+		for i, id := range mappingIDs {
+			tags[i] = LinkTag{URLMappingID: id, LinkGroupID: group.ID}
+		}
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags)
+		return result.RowsAffected, result.Error
+	}
+
+	column := groupColumn(group.Kind)
+	query := db.Model(&URLMapping{}).Where("workspace_id = ? AND id IN ?", group.WorkspaceID, mappingIDs)
+	var result *gorm.DB
+	if assign {
+		result = query.Where(column+" <> ?", group.ID).UpdateColumn(column, group.ID)
+	} else {
+		result = query.Where(column+" = ?", group.ID).UpdateColumn(column, 0)
+	}
+	return result.RowsAffected, result.Error
+}
+
+// ListLinkTags retrieves the tags of the given mappings.
+func (db *DB) ListLinkTags(mappingIDs []uint) ([]LinkTag, error) {
+	var tags []LinkTag
+	if len(mappingIDs) == 0 {
+		return tags, nil
+	}
+	err := db.Where("url_mapping_id IN ?", mappingIDs).Order("url_mapping_id, link_group_id").Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ListWorkspaceURLMappings retrieves the mappings of a workspace selected by
+// filter, newest first.
+func (db *DB) ListWorkspaceURLMappings(workspaceID uint, filter LinkFilter) ([]URLMapping, error) {
+	query := db.Where("workspace_id = ?", workspaceID)
+	if filter.TagID != 0 {
+		query = query.Scopes(inGroup(&LinkGroup{Model: gorm.Model{ID: filter.TagID}, Kind: GroupKindTag}))
+	}
+	if filter.CampaignID != 0 {
+		query = query.Where("campaign_id = ?", filter.CampaignID)
+	}
+	if filter.FolderID != 0 {
+		query = query.Where("folder_id = ?", filter.FolderID)
+	}
+	if filter.Broken {
+		query = query.Where("health_broken = ?", true)
+	}
+	if filter.BeforeID != 0 {
+		query = query.Where("id < ?", filter.BeforeID)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var mappings []URLMapping
+	if err := query.Order("id DESC").Find(&mappings).Error; err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+// GetLinkGroupStats aggregates the mappings in a group, with its top most
+// clicked links and destination domains. Only clicks on the days since since
+// are counted.
+func (db *DB) GetLinkGroupStats(group *LinkGroup, top int, since time.Time) (*LinkGroupStats, error) {
+	links := func() *gorm.DB {
+		return db.Model(&URLMapping{}).Where("url_mappings.workspace_id = ?", group.WorkspaceID).Scopes(inGroup(group))
+	}
+	clicked := func() *gorm.DB {
+		return links().Scopes(recentClicks(since))
+	}
+	stats := &LinkGroupStats{}
+	var totals struct {
+		Links  int64
+		Clicks int64
+	}
+	err := clicked().Select("COUNT(*) AS links, COALESCE(SUM(recent_clicks.clicks), 0) AS clicks").Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.Links, stats.Clicks = totals.Links, totals.Clicks
+
+	err = db.Model(&LinkVariant{}).Select("COALESCE(SUM(conversions), 0)").
+		Where("url_mapping_id IN (?)", links().Select("url_mappings.id")).Scan(&stats.Conversions).Error
+	if err != nil {
+		return nil, err
+	}
+	if stats.TopLinks, err = db.topClickedLinks(clicked(), top); err != nil {
+		return nil, err
+	}
+	err = clicked().Select("url_mappings.domain_name, COALESCE(SUM(recent_clicks.clicks), 0) AS count").
+		Group("url_mappings.domain_name").Order("count DESC, domain_name").Limit(top).Scan(&stats.TopDomains).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// topClickedLinks retrieves the top most clicked mappings of query, which
+// joins recentClicks, with their Clicks set to those recent clicks.
+func (db *DB) topClickedLinks(query *gorm.DB, top int) ([]URLMapping, error) {
+	var ranked []struct {
+		ID     uint
+		Clicks int64
+	}
+	err := query.Select("url_mappings.id, COALESCE(recent_clicks.clicks, 0) AS clicks").
+		Order("clicks DESC, url_mappings.id DESC").Limit(top).Scan(&ranked).Error
+	if err != nil || len(ranked) == 0 {
+		return nil, err
+	}
+	ids := make([]uint, len(ranked))
+	for i, r := range ranked {
+		ids[i] = r.ID
+	}
+	var mappings []URLMapping
+	if err := db.Where("id IN ?", ids).Find(&mappings).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]URLMapping, len(mappings))
+	for _, m := range mappings {
+		byID[m.ID] = m
+	}
+	links := make([]URLMapping, 0, len(ranked))
+	for _, r := range ranked {
+		if m, ok := byID[r.ID]; ok {
+			m.Clicks = r.Clicks
+			links = append(links, m)
+		}
+	}
+	return links, nil
+}
+
+// groupColumn is the URLMapping column holding the group of a kind a link
+// is in, for kinds other than tags.
+func groupColumn(kind string) string {
+	if kind == GroupKindCampaign {
+		return "campaign_id"
+	}
+	return "folder_id"
+}
+
+// inGroup selects the mappings in group.
+func inGroup(group *LinkGroup) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		if group.Kind == GroupKindTag {
+			return query.Where("url_mappings.id IN (SELECT url_mapping_id FROM link_tags WHERE link_group_id = ?)", group.ID)
+		}
+		return query.Where("url_mappings."+groupColumn(group.Kind)+" = ?", group.ID)
+	}
+}
@@ -0,0 +1,66 @@
+package dataModel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LinkHealth is the outcome of the latest health checks of the destination
+// of a mapping.
+type LinkHealth struct {
+	// CheckedAt is nil until the destination was checked.
+	CheckedAt *time.Time
+	// NextCheckAt is when the destination is due to be checked, nil for
+	// mappings never claimed.
+	NextCheckAt *time.Time `gorm:"index"`
+	// StatusCode is the HTTP status of the final response, 0 when none was
+	// received, e.g. because the host does not resolve.
+	StatusCode int
+	Error      string
+	// FinalURL is where the destination ended up after redirects.
+	FinalURL  string
+	LatencyMS int64
+	// Failures counts the consecutive failed checks.
+	Failures int `gorm:"not null;default:0"`
+	// Broken is set once enough consecutive checks failed.
+	Broken bool `gorm:"index;not null;default:false"`
+	// Skipped is set when robots.txt disallowed the latest check.
+	Skipped bool `gorm:"not null;default:false"`
+}
+
+// ClaimLinkChecks returns up to limit enabled mappings whose destination is
+// due to be checked at now. Claimed mappings are not due again for lease,
+// so concurrent checkers do not check them twice. Exhausted click limited
+// mappings are left out.
+func (db *DB) ClaimLinkChecks(now time.Time, limit int, lease time.Duration) ([]URLMapping, error) {
+	var mappings []URLMapping
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("disabled = ? AND (max_clicks = 0 OR clicks_remaining > 0)", false).
+			Where("health_next_check_at IS NULL OR health_next_check_at <= ?", now).
+			Order("health_next_check_at NULLS FIRST, id").Limit(limit).
+			Find(&mappings).Error
+		if err != nil || len(mappings) == 0 {
+			return err
+		}
+		ids := make([]uint, len(mappings))
+		for i, m := range mappings {
+			ids[i] = m.ID
+		}
+		return tx.Model(&URLMapping{}).Where("id IN ?", ids).
+			UpdateColumn("health_next_check_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+// RecordLinkCheck stores the Health of a mapping.
+func (db *DB) RecordLinkCheck(mapping *URLMapping) error {
+	return db.Model(mapping).Select("health_checked_at", "health_next_check_at", "health_status_code",
+		"health_error", "health_final_url", "health_latency_ms", "health_failures", "health_broken", "health_skipped").
+		UpdateColumns(&URLMapping{Health: mapping.Health}).Error
+}
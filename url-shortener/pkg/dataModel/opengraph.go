@@ -0,0 +1,25 @@
+package dataModel
+
+// OpenGraph is the metadata shown when a link is shared in chat apps and
+// social networks. Empty fields are left out.
+type OpenGraph struct {
+	Title       string
+	Description string
+	ImageURL    string
+}
+
+// IsZero reports whether no field is set.
+func (og OpenGraph) IsZero() bool {
+	return og == OpenGraph{}
+}
+
+// SetLinkOpenGraph stores the OpenGraph metadata of a mapping.
+func (db *DB) SetLinkOpenGraph(mapping *URLMapping, og OpenGraph) error {
+	err := db.Model(mapping).Select("og_title", "og_description", "og_image_url").
+		Updates(&URLMapping{OG: og}).Error
+	if err != nil {
+		return err
+	}
+	mapping.OG = og
+	return nil
+}
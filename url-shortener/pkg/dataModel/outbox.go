@@ -0,0 +1,91 @@
+package dataModel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is a domain event waiting to be published. Events are written
+// in the same transaction as the change they describe and deleted once
+// published, so none are lost while the event bus is unreachable.
+type OutboxEvent struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	EventID   string `gorm:"uniqueIndex;not null"`
+	Type      string `gorm:"not null"`
+	// Payload is the protobuf encoded event.
+	Payload   []byte `gorm:"not null"`
+	Attempts  int    `gorm:"not null;default:0"`
+	LastError string
+}
+
+// InsertOutboxEvents queues events for publishing.
+func (db *DB) InsertOutboxEvents(events []*OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return db.Create(events).Error
+}
+
+// outboxLockKey is the Postgres advisory lock held by the replica
+// publishing the outbox ("outbox" in ASCII).
+const outboxLockKey int64 = 0x6f7574626f78
+
+// PublishOutboxEvents hands up to limit queued events, oldest first, to
+// publish and deletes the ones it accepted. It stops at the first event
+// publish fails, recording the error on it, so events are published in
+// the order they were queued. Only one caller at a time publishes, holding
+// an advisory lock; the others return 0 without waiting. Events are
+// published outside of any transaction, an event published right before
+// a failure to delete it is published again.
+// It returns how many events were published.
+func (db *DB) PublishOutboxEvents(limit int, publish func(*OutboxEvent) error) (int, error) {
+	published := 0
+	err := db.Connection(func(conn *gorm.DB) (err error) {
+		var locked bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", outboxLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+		defer func() {
+			// Session locks outlive transactions, release it on this connection.
+			if unlockErr := conn.Exec("SELECT pg_advisory_unlock(?)", outboxLockKey).Error; unlockErr != nil && err == nil {
+				err = unlockErr
+			}
+		}()
+
+		var events []OutboxEvent
+		if err := conn.Order("id").Limit(limit).Find(&events).Error; err != nil {
+			return err
+		}
+		var ids []uint
+		for i := range events {
+			if err := publish(&events[i]); err != nil {
+				err := conn.Model(&events[i]).Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": err.Error(),
+				}).Error
+				if err != nil {
+					return err
+				}
+				break
+			}
+			ids = append(ids, events[i].ID)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := conn.Delete(&OutboxEvent{}, ids).Error; err != nil {
+			return err
+		}
+		published = len(ids)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, nil
+}
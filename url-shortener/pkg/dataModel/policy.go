@@ -0,0 +1,110 @@
+package dataModel
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// PolicyRule is a block or allow rule for destination URLs managed through
+// the admin API. Kind is one of "domain", "suffix", "regex" or "prefix" and
+// Action is "block" or "allow".
+type PolicyRule struct {
+	gorm.Model
+	Kind      string `gorm:"not null"`
+	Pattern   string `gorm:"not null"`
+	Action    string `gorm:"not null;default:'block'"`
+	Reason    string
+	CreatedBy uint
+}
+
+// CreatePolicyRule creates a new policy rule in the database.
+func (db *DB) CreatePolicyRule(rule *PolicyRule) error {
+	return db.Create(rule).Error
+}
+
+// DeletePolicyRule deletes the policy rule with the given ID.
+func (db *DB) DeletePolicyRule(id uint) error {
+	result := db.Delete(&PolicyRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListPolicyRules retrieves all policy rules.
+func (db *DB) ListPolicyRules() ([]PolicyRule, error) {
+	var rules []PolicyRule
+	err := db.Order("id").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// PolicyScope selects mappings a policy rule may apply to. Rules are only
+// matched in Go, by the policy engine, so the scope may select more
+// mappings than the rule matches: regex rules select every mapping.
+type PolicyScope struct {
+	Kind    string
+	Pattern string
+	// RuleID also selects the mappings disabled by the rule with this ID.
+	RuleID uint
+	// Disabled selects disabled mappings instead of enabled ones.
+	Disabled bool
+}
+
+// ListPolicyCandidates retrieves up to limit mappings in scope with IDs
+// above afterID, in ID order.
+func (db *DB) ListPolicyCandidates(scope PolicyScope, afterID uint, limit int) ([]URLMapping, error) {
+	var match string
+	var args []interface{}
+	switch scope.Kind {
+	case "domain":
+		match, args = "LOWER(domain_name) = ?", []interface{}{scope.Pattern}
+	case "suffix":
+		match, args = "LOWER(domain_name) = ? OR LOWER(domain_name) LIKE ?", []interface{}{scope.Pattern, "%." + escapeLike(scope.Pattern)}
+	case "prefix":
+		match, args = "long_url LIKE ?", []interface{}{escapeLike(scope.Pattern) + "%"}
+	case "regex":
+		match = "TRUE"
+	default:
+		return nil, fmt.Errorf("unknown rule kind %q", scope.Kind)
+	}
+	if scope.RuleID != 0 {
+		match, args = "("+match+") OR disabled_rule_id = ?", append(args, scope.RuleID)
+	}
+	var mappings []URLMapping
+	err := db.Where("disabled = ? AND id > ?", scope.Disabled, afterID).Where(match, args...).
+		Order("id").Limit(limit).Find(&mappings).Error
+	if err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+// DisableURLMappings disables the mappings with ids, recording the ID of
+// the policy rule blocking them, 0 for rules from files, and why. It
+// returns how many mappings were updated.
+func (db *DB) DisableURLMappings(ids []uint, ruleID uint, reason string) (int64, error) {
+	result := db.Model(&URLMapping{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{"disabled": true, "disabled_rule_id": ruleID, "disabled_reason": reason})
+	return result.RowsAffected, result.Error
+}
+
+// EnableURLMappings enables the disabled mappings with ids and returns how
+// many were enabled.
+func (db *DB) EnableURLMappings(ids []uint) (int64, error) {
+	result := db.Model(&URLMapping{}).Where("id IN ? AND disabled = ?", ids, true).
+		Updates(map[string]interface{}{"disabled": false, "disabled_rule_id": 0, "disabled_reason": ""})
+	return result.RowsAffected, result.Error
+}
+
+// escapeLike escapes the wildcard characters of a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
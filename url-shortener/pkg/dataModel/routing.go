@@ -0,0 +1,65 @@
+package dataModel
+
+import (
+	"gorm.io/gorm"
+)
+
+// RoutingRule sends visitors of a link whose user agent matches OS, Device
+// and Browser, and whose location matches Country and Region, to
+// Destination instead of the link's long URL. Empty conditions match
+// anything. Rules are tried by ascending Priority, then age; visitors
+// matching none get the long URL.
+type RoutingRule struct {
+	gorm.Model
+	URLMappingID uint   `gorm:"index;not null"`
+	Priority     int    `gorm:"not null;default:0"`
+	OS           string `gorm:"column:os"`
+	Device       string
+	Browser      string
+	// Country is an ISO 3166-1 alpha-2 code, Region an ISO 3166-2 code.
+	Country string
+	Region  string
+	// Destination is an http(s) URL or an app deep link, e.g. myapp://item/1.
+	Destination string `gorm:"not null"`
+}
+
+// CreateRoutingRule adds a routing rule to its mapping.
+func (db *DB) CreateRoutingRule(rule *RoutingRule) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rule).Error; err != nil {
+			return err
+		}
+		return tx.Model(&URLMapping{}).Where("id = ?", rule.URLMappingID).
+			Update("has_routing_rules", true).Error
+	})
+}
+
+// ListRoutingRules retrieves the routing rules of a mapping in the order
+// they are tried.
+func (db *DB) ListRoutingRules(mappingID uint) ([]RoutingRule, error) {
+	var rules []RoutingRule
+	err := db.Where("url_mapping_id = ?", mappingID).Order("priority, id").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// DeleteRoutingRule deletes a routing rule of a mapping.
+func (db *DB) DeleteRoutingRule(mappingID, id uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("url_mapping_id = ?", mappingID).Delete(&RoutingRule{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		var left int64
+		if err := tx.Model(&RoutingRule{}).Where("url_mapping_id = ?", mappingID).Count(&left).Error; err != nil {
+			return err
+		}
+		return tx.Model(&URLMapping{}).Where("id = ?", mappingID).
+			Update("has_routing_rules", left > 0).Error
+	})
+}
@@ -0,0 +1,50 @@
+package dataModel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ScheduledDestination replaces the long URL of a link from ActivatesAt
+// until the next destination of its schedule activates.
+type ScheduledDestination struct {
+	gorm.Model
+	URLMappingID uint      `gorm:"index;not null"`
+	ActivatesAt  time.Time `gorm:"not null"`
+	// TimeZone is the IANA zone ActivatesAt was given in, kept to show it
+	// the same way.
+	TimeZone    string `gorm:"not null;default:'UTC'"`
+	Destination string `gorm:"not null"`
+}
+
+// ReplaceSchedule makes schedule the scheduled destinations of a mapping.
+// An empty schedule removes it.
+func (db *DB) ReplaceSchedule(mappingID uint, schedule []ScheduledDestination) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("url_mapping_id = ?", mappingID).Delete(&ScheduledDestination{}).Error; err != nil {
+			return err
+		}
+		for i := range schedule {
+			schedule[i].URLMappingID = mappingID
+		}
+		if len(schedule) > 0 {
+			if err := tx.Create(&schedule).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&URLMapping{}).Where("id = ?", mappingID).
+			Update("has_schedule", len(schedule) > 0).Error
+	})
+}
+
+// ListSchedule retrieves the scheduled destinations of a mapping by
+// activation time.
+func (db *DB) ListSchedule(mappingID uint) ([]ScheduledDestination, error) {
+	var schedule []ScheduledDestination
+	err := db.Where("url_mapping_id = ?", mappingID).Order("activates_at, id").Find(&schedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
@@ -0,0 +1,82 @@
+package dataModel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UsageCounter tracks how much of a plan a user consumed in one billing cycle.
+// A new row is started for every cycle, which is what resets the counters.
+type UsageCounter struct {
+	gorm.Model
+	UserID        uint      `gorm:"uniqueIndex:idx_usage_user_cycle;not null"`
+	CycleStart    time.Time `gorm:"uniqueIndex:idx_usage_user_cycle;not null"`
+	LinksCreated  int64     `gorm:"not null;default:0"`
+	CustomAliases int64     `gorm:"not null;default:0"`
+}
+
+// UsageDelta is the amount of each counter a single operation consumes.
+type UsageDelta struct {
+	Links         int64
+	CustomAliases int64
+}
+
+// UsageLimits caps each counter for a cycle. Zero means unlimited.
+type UsageLimits struct {
+	MaxLinks         int64
+	MaxCustomAliases int64
+}
+
+// GetUsage retrieves the usage counter of a user for the cycle starting at cycleStart.
+// A cycle without any recorded usage yields a zeroed counter.
+func (db *DB) GetUsage(userID uint, cycleStart time.Time) (*UsageCounter, error) {
+	var counter UsageCounter
+	err := db.Where(&UsageCounter{UserID: userID, CycleStart: cycleStart}).First(&counter).Error
+	if err == gorm.ErrRecordNotFound {
+		return &UsageCounter{UserID: userID, CycleStart: cycleStart}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &counter, nil
+}
+
+// ConsumeUsage atomically adds delta to the user's counters for the cycle,
+// provided the result stays within limits. It reports false without changing
+// anything when the operation would exceed a limit.
+func (db *DB) ConsumeUsage(userID uint, cycleStart time.Time, delta UsageDelta, limits UsageLimits) (bool, error) {
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&UsageCounter{UserID: userID, CycleStart: cycleStart}).Error
+	if err != nil {
+		return false, err
+	}
+
+	query := db.Model(&UsageCounter{}).Where("user_id = ? AND cycle_start = ?", userID, cycleStart)
+	if limits.MaxLinks > 0 {
+		query = query.Where("links_created + ? <= ?", delta.Links, limits.MaxLinks)
+	}
+	if limits.MaxCustomAliases > 0 {
+		query = query.Where("custom_aliases + ? <= ?", delta.CustomAliases, limits.MaxCustomAliases)
+	}
+	result := query.Updates(map[string]interface{}{
+		"links_created":  gorm.Expr("links_created + ?", delta.Links),
+		"custom_aliases": gorm.Expr("custom_aliases + ?", delta.CustomAliases),
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseUsage gives back usage previously taken by ConsumeUsage, e.g. when
+// the operation it was reserved for failed.
+func (db *DB) ReleaseUsage(userID uint, cycleStart time.Time, delta UsageDelta) error {
+	return db.Model(&UsageCounter{}).
+		Where("user_id = ? AND cycle_start = ?", userID, cycleStart).
+		Updates(map[string]interface{}{
+			"links_created":  gorm.Expr("GREATEST(links_created - ?, 0)", delta.Links),
+			"custom_aliases": gorm.Expr("GREATEST(custom_aliases - ?, 0)", delta.CustomAliases),
+		}).Error
+}
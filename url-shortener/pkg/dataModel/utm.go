@@ -0,0 +1,99 @@
+package dataModel
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UTMParams are the utm_* query parameters of a destination.
+type UTMParams struct {
+	Source   string `gorm:"column:utm_source"`
+	Medium   string `gorm:"column:utm_medium"`
+	Campaign string `gorm:"column:utm_campaign"`
+	Term     string `gorm:"column:utm_term"`
+	Content  string `gorm:"column:utm_content"`
+}
+
+// UTMRule requires links of a workspace to Domain, or its subdomains, to
+// carry the UTM parameters in Required. UserID is the member who set it.
+type UTMRule struct {
+	gorm.Model
+	UserID      uint   `gorm:"index;not null"`
+	WorkspaceID uint   `gorm:"index;not null;default:0"`
+	Domain      string `gorm:"not null"`
+	// Required lists parameter names separated by commas, e.g.
+	// "utm_source,utm_medium".
+	Required string `gorm:"not null"`
+}
+
+// RequiredParams returns the parameter names the rule requires.
+func (r *UTMRule) RequiredParams() []string {
+	return strings.Split(r.Required, ",")
+}
+
+// UTMStats counts the links of a UTM source and medium.
+type UTMStats struct {
+	Source string
+	Medium string
+	Links  int64
+	Clicks int64
+}
+
+// SetCampaignUTM stores the UTM parameters links of a campaign inherit.
+func (db *DB) SetCampaignUTM(group *LinkGroup, utm UTMParams) error {
+	err := db.Model(group).Select("utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content").
+		Updates(&LinkGroup{UTM: utm}).Error
+	if err != nil {
+		return err
+	}
+	group.UTM = utm
+	return nil
+}
+
+// ReplaceUTMRules makes rules the UTM rules of a workspace.
+func (db *DB) ReplaceUTMRules(workspaceID uint, rules []UTMRule) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("workspace_id = ?", workspaceID).Delete(&UTMRule{}).Error; err != nil {
+			return err
+		}
+		for i := range rules {
+			rules[i].WorkspaceID = workspaceID
+		}
+		if len(rules) > 0 {
+			if err := tx.Create(&rules).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&Workspace{}).Where("id = ?", workspaceID).Update("has_utm_rules", len(rules) > 0).Error
+	})
+}
+
+// ListUTMRules retrieves the UTM rules of a workspace.
+func (db *DB) ListUTMRules(workspaceID uint) ([]UTMRule, error) {
+	var rules []UTMRule
+	err := db.Where("workspace_id = ?", workspaceID).Order("id").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetUTMStats counts the links and clicks of a workspace by UTM source and
+// medium, only of one campaign unless campaignID is 0. Only clicks on the
+// days since since are counted.
+func (db *DB) GetUTMStats(workspaceID, campaignID uint, since time.Time) ([]UTMStats, error) {
+	query := db.Model(&URLMapping{}).Scopes(recentClicks(since)).Where("url_mappings.workspace_id = ?", workspaceID)
+	if campaignID != 0 {
+		query = query.Where("url_mappings.campaign_id = ?", campaignID)
+	}
+	var stats []UTMStats
+	err := query.Select("utm_source AS source, utm_medium AS medium, COUNT(*) AS links, " +
+		"COALESCE(SUM(recent_clicks.clicks), 0) AS clicks").
+		Group("utm_source, utm_medium").Order("clicks DESC, source, medium").Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,87 @@
+package dataModel
+
+import (
+	"gorm.io/gorm"
+)
+
+// LinkVariant is one of the destinations a link splits its traffic across.
+// Each visitor is sent to one of them, by Weight relative to the others.
+type LinkVariant struct {
+	gorm.Model
+	URLMappingID uint   `gorm:"index;not null"`
+	Destination  string `gorm:"not null"`
+	Weight       int    `gorm:"not null"`
+	Clicks       int64  `gorm:"not null;default:0"`
+	Conversions  int64  `gorm:"not null;default:0"`
+}
+
+// ReplaceLinkVariants makes variants the variants of a mapping and sets how
+// visitors stick to them. Variants with the destination of an existing one
+// take its place, keeping its ID and counters; the others are added and
+// existing ones not given are removed. An empty list stops the split.
+func (db *DB) ReplaceLinkVariants(mappingID uint, stickiness string, variants []LinkVariant) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var existing []LinkVariant
+		if err := tx.Where("url_mapping_id = ?", mappingID).Find(&existing).Error; err != nil {
+			return err
+		}
+		byDestination := make(map[string]LinkVariant, len(existing))
+		for _, v := range existing {
+			byDestination[v.Destination] = v
+		}
+
+		for i := range variants {
+			v := &variants[i]
+			v.URLMappingID = mappingID
+			if old, ok := byDestination[v.Destination]; ok {
+				delete(byDestination, v.Destination)
+				v.Model, v.Clicks, v.Conversions = old.Model, old.Clicks, old.Conversions
+				if err := tx.Model(v).Update("weight", v.Weight).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Create(v).Error; err != nil {
+				return err
+			}
+		}
+		for _, old := range byDestination {
+			if err := tx.Delete(&old).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&URLMapping{}).Where("id = ?", mappingID).Updates(map[string]any{
+			"has_variants":       len(variants) > 0,
+			"variant_stickiness": stickiness,
+		}).Error
+	})
+}
+
+// ListLinkVariants retrieves the variants of a mapping, oldest first.
+func (db *DB) ListLinkVariants(mappingID uint) ([]LinkVariant, error) {
+	var variants []LinkVariant
+	err := db.Where("url_mapping_id = ?", mappingID).Order("id").Find(&variants).Error
+	if err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// RecordVariantClick counts a visitor sent to a variant.
+func (db *DB) RecordVariantClick(id uint) error {
+	return db.Model(&LinkVariant{}).Where("id = ?", id).
+		UpdateColumn("clicks", gorm.Expr("clicks + 1")).Error
+}
+
+// RecordVariantConversion counts a conversion of a variant of a mapping.
+func (db *DB) RecordVariantConversion(mappingID, id uint) error {
+	result := db.Model(&LinkVariant{}).Where("id = ? AND url_mapping_id = ?", id, mappingID).
+		UpdateColumn("conversions", gorm.Expr("conversions + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package dataModel
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Webhook delivery statuses. Failed attempts keep a delivery pending until
+// it runs out of attempts and is dead lettered.
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookDead      = "dead"
+	// WebhookCanceled deliveries belong to a deleted subscription.
+	WebhookCanceled = "canceled"
+)
+
+// WebhookSubscription asks for an HTTP POST to URL whenever one of Events
+// happens to a link of WorkspaceID. Events is a comma separated list.
+// UserID is the member who subscribed.
+type WebhookSubscription struct {
+	gorm.Model
+	UserID      uint   `gorm:"index;not null"`
+	WorkspaceID uint   `gorm:"index;not null;default:0"`
+	URL         string `gorm:"not null"`
+	Events      string `gorm:"not null"`
+	// Secret signs the deliveries.
+	Secret string `gorm:"not null"`
+}
+
+// EventList returns the events of the subscription.
+func (s *WebhookSubscription) EventList() []string {
+	return strings.Split(s.Events, ",")
+}
+
+// Subscribed reports whether the subscription wants event.
+func (s *WebhookSubscription) Subscribed(event string) bool {
+	for _, e := range s.EventList() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event to be posted to one subscription. Pending
+// rows form an outbox the dispatcher works through, and all rows together
+// are the delivery log.
+type WebhookDelivery struct {
+	gorm.Model
+	SubscriptionID uint                `gorm:"index;not null"`
+	Subscription   WebhookSubscription `gorm:"constraint:OnDelete:CASCADE"`
+	UserID         uint                `gorm:"index;not null"`
+	WorkspaceID    uint                `gorm:"index;not null;default:0"`
+	Event          string              `gorm:"not null"`
+	Payload        string              `gorm:"type:text;not null"`
+	Status         string              `gorm:"index:idx_webhook_deliveries_due;not null;default:'pending'"`
+	NextAttemptAt  time.Time           `gorm:"index:idx_webhook_deliveries_due"`
+	Attempts       int                 `gorm:"not null;default:0"`
+	LastStatusCode int
+	LastError      string
+	DeliveredAt    *time.Time
+}
+
+// WebhookDeadLetter keeps a delivery that failed all its attempts.
+type WebhookDeadLetter struct {
+	gorm.Model
+	DeliveryID     uint `gorm:"uniqueIndex;not null"`
+	SubscriptionID uint `gorm:"index;not null"`
+	UserID         uint `gorm:"index;not null"`
+	WorkspaceID    uint `gorm:"index;not null;default:0"`
+	Event          string
+	Payload        string `gorm:"type:text"`
+	Attempts       int
+	LastStatusCode int
+	LastError      string
+}
+
+// CreateWebhookSubscription creates a new webhook subscription.
+func (db *DB) CreateWebhookSubscription(sub *WebhookSubscription) error {
+	return db.Create(sub).Error
+}
+
+// ListWebhookSubscriptions retrieves the webhook subscriptions of a
+// workspace.
+func (db *DB) ListWebhookSubscriptions(workspaceID uint) ([]WebhookSubscription, error) {
+	var subs []WebhookSubscription
+	err := db.Where("workspace_id = ?", workspaceID).Order("id").Find(&subs).Error
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// DeleteWebhookSubscription deletes a webhook subscription of a workspace
+// and cancels its pending deliveries.
+func (db *DB) DeleteWebhookSubscription(workspaceID, id uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("workspace_id = ?", workspaceID).Delete(&WebhookSubscription{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&WebhookDelivery{}).
+			Where("subscription_id = ? AND status = ?", id, WebhookPending).
+			Update("status", WebhookCanceled).Error
+	})
+}
+
+// CreateWebhookDeliveries queues deliveries with a single insert.
+func (db *DB) CreateWebhookDeliveries(deliveries []*WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return db.Omit("Subscription").Create(deliveries).Error
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries due at now,
+// with their subscription. Claimed deliveries are not due again for lease,
+// so concurrent dispatchers do not send them twice and deliveries of a
+// dispatcher that crashed are retried after it.
+func (db *DB) ClaimWebhookDeliveries(now time.Time, limit int, lease time.Duration) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", WebhookPending, now).
+			Order("next_attempt_at").Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		ids := make([]uint, len(deliveries))
+		for i, d := range deliveries {
+			ids[i] = d.ID
+		}
+		return tx.Model(&WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+
+	// Deleted subscriptions are not loaded, leaving Subscription zero.
+	subIDs := make([]uint, len(deliveries))
+	for i, d := range deliveries {
+		subIDs[i] = d.SubscriptionID
+	}
+	var subs []WebhookSubscription
+	if err := db.Where("id IN ?", subIDs).Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]WebhookSubscription, len(subs))
+	for _, sub := range subs {
+		byID[sub.ID] = sub
+	}
+	for i := range deliveries {
+		deliveries[i].Subscription = byID[deliveries[i].SubscriptionID]
+	}
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery records the outcome of a delivery attempt. Dead
+// deliveries are copied to the dead letter table as well.
+func (db *DB) UpdateWebhookDelivery(d *WebhookDelivery) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(d).Select("Status", "NextAttemptAt", "Attempts", "LastStatusCode", "LastError", "DeliveredAt").
+			Updates(d).Error
+		if err != nil || d.Status != WebhookDead {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&WebhookDeadLetter{
+			DeliveryID:     d.ID,
+			SubscriptionID: d.SubscriptionID,
+			UserID:         d.UserID,
+			WorkspaceID:    d.WorkspaceID,
+			Event:          d.Event,
+			Payload:        d.Payload,
+			Attempts:       d.Attempts,
+			LastStatusCode: d.LastStatusCode,
+			LastError:      d.LastError,
+		}).Error
+	})
+}
+
+// ListWebhookDeliveries retrieves the latest deliveries of a workspace,
+// newest first, optionally only of one subscription or with one status.
+func (db *DB) ListWebhookDeliveries(workspaceID, subscriptionID uint, status string, limit int) ([]WebhookDelivery, error) {
+	query := db.Where("workspace_id = ?", workspaceID)
+	if subscriptionID != 0 {
+		query = query.Where("subscription_id = ?", subscriptionID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var deliveries []WebhookDelivery
+	if err := query.Order("id DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ListWebhookDeadLetters retrieves the latest dead letters of a workspace,
+// newest first.
+func (db *DB) ListWebhookDeadLetters(workspaceID uint, limit int) ([]WebhookDeadLetter, error) {
+	var letters []WebhookDeadLetter
+	err := db.Where("workspace_id = ?", workspaceID).Order("id DESC").Limit(limit).Find(&letters).Error
+	if err != nil {
+		return nil, err
+	}
+	return letters, nil
+}
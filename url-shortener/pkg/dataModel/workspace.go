@@ -0,0 +1,345 @@
+package dataModel
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Roles of workspace members, from most to least privileged. Owners manage
+// the members and the workspace, admins its domains, webhooks and UTM
+// rules, editors its links and viewers may only read.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// roleRanks orders the roles, higher ranks include the lower ones.
+var roleRanks = map[string]int{RoleViewer: 1, RoleEditor: 2, RoleAdmin: 3, RoleOwner: 4}
+
+// ValidRole reports whether role is one of the roles above.
+func ValidRole(role string) bool {
+	return roleRanks[role] > 0
+}
+
+// RoleAtLeast reports whether role grants everything min does.
+func RoleAtLeast(role, min string) bool {
+	return ValidRole(role) && roleRanks[role] >= roleRanks[min]
+}
+
+// ErrLastOwner is returned when removing or demoting the only owner of a
+// workspace.
+var ErrLastOwner = errors.New("workspace must keep an owner")
+
+// ErrAlreadyMember is returned when adding a user to a workspace they are
+// a member of.
+var ErrAlreadyMember = errors.New("user is already a member of the workspace")
+
+// Workspace owns links, domains, link groups, UTM rules and webhooks,
+// shared by its members. Every user has a personal workspace, created
+// with them.
+type Workspace struct {
+	gorm.Model
+	Name string `gorm:"not null"`
+	// PersonalUserID is the user whose personal workspace this is, 0 for
+	// shared workspaces.
+	PersonalUserID uint `gorm:"index;not null;default:0"`
+	// HasUTMRules is set while the workspace has UTMRules, sparing their
+	// lookup on every link the others create.
+	HasUTMRules bool `gorm:"not null;default:false"`
+}
+
+// WorkspaceMember gives a user a role in a workspace. APIKey authenticates
+// the user within that workspace only, so each membership has its own.
+type WorkspaceMember struct {
+	gorm.Model
+	WorkspaceID uint      `gorm:"uniqueIndex:idx_workspace_members_workspace_user,priority:1;not null"`
+	UserID      uint      `gorm:"uniqueIndex:idx_workspace_members_workspace_user,priority:2;index;not null"`
+	Role        string    `gorm:"not null"`
+	APIKey      uuid.UUID `gorm:"type:uuid;uniqueIndex;default:uuid_generate_v4()"`
+}
+
+// WorkspaceMembership is a workspace as seen by one of its members.
+type WorkspaceMembership struct {
+	Workspace Workspace
+	Role      string
+	APIKey    uuid.UUID
+}
+
+// MemberUser is a member of a workspace with their user.
+type MemberUser struct {
+	User User
+	Role string
+}
+
+// CreateUser creates a new user in the database with their personal
+// workspace. Their API key authenticates them within it.
+func (db *DB) CreateUser(user *User) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return createPersonalWorkspace(tx, user)
+	})
+}
+
+// createPersonalWorkspace creates the personal workspace of user, owned by
+// them through their API key.
+func createPersonalWorkspace(tx *gorm.DB, user *User) error {
+	workspace := &Workspace{Name: personalWorkspaceName(user), PersonalUserID: user.ID}
+	if err := tx.Create(workspace).Error; err != nil {
+		return err
+	}
+	member := &WorkspaceMember{WorkspaceID: workspace.ID, UserID: user.ID, Role: RoleOwner, APIKey: user.APIKey}
+	return tx.Create(member).Error
+}
+
+func personalWorkspaceName(user *User) string {
+	return fmt.Sprintf("%s %s", user.FirstName, user.LastName)
+}
+
+// CreateWorkspace creates a shared workspace owned by user and returns the
+// owner's membership.
+func (db *DB) CreateWorkspace(workspace *Workspace, userID uint) (*WorkspaceMember, error) {
+	member := &WorkspaceMember{UserID: userID, Role: RoleOwner}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(workspace).Error; err != nil {
+			return err
+		}
+		member.WorkspaceID = workspace.ID
+		return tx.Create(member).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// ListUserWorkspaces retrieves the workspaces a user is a member of, their
+// personal workspace first.
+func (db *DB) ListUserWorkspaces(userID uint) ([]WorkspaceMembership, error) {
+	var members []WorkspaceMember
+	if err := db.Where("user_id = ?", userID).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	ids := make([]uint, len(members))
+	for i, m := range members {
+		ids[i] = m.WorkspaceID
+	}
+	var workspaces []Workspace
+	// Personal workspaces have the lowest IDs of those a user belongs to,
+	// they are created with the user.
+	if err := db.Where("id IN ?", ids).Order("id").Find(&workspaces).Error; err != nil {
+		return nil, err
+	}
+	byWorkspace := make(map[uint]WorkspaceMember, len(members))
+	for _, m := range members {
+		byWorkspace[m.WorkspaceID] = m
+	}
+	memberships := make([]WorkspaceMembership, len(workspaces))
+	for i, w := range workspaces {
+		m := byWorkspace[w.ID]
+		memberships[i] = WorkspaceMembership{Workspace: w, Role: m.Role, APIKey: m.APIKey}
+	}
+	return memberships, nil
+}
+
+// ListWorkspaceMembers retrieves the members of a workspace in the order
+// they joined.
+func (db *DB) ListWorkspaceMembers(workspaceID uint) ([]MemberUser, error) {
+	var members []WorkspaceMember
+	if err := db.Where("workspace_id = ?", workspaceID).Order("id").Find(&members).Error; err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	ids := make([]uint, len(members))
+	for i, m := range members {
+		ids[i] = m.UserID
+	}
+	var users []User
+	if err := db.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	result := make([]MemberUser, 0, len(members))
+	for _, m := range members {
+		if u, ok := byID[m.UserID]; ok {
+			result = append(result, MemberUser{User: u, Role: m.Role})
+		}
+	}
+	return result, nil
+}
+
+// AddWorkspaceMember adds a user to a workspace with a new API key.
+// ErrAlreadyMember is returned for members.
+func (db *DB) AddWorkspaceMember(member *WorkspaceMember) error {
+	var existing int64
+	err := db.Model(&WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", member.WorkspaceID, member.UserID).Count(&existing).Error
+	if err != nil {
+		return err
+	}
+	if existing > 0 {
+		return ErrAlreadyMember
+	}
+	return db.Create(member).Error
+}
+
+// SetWorkspaceMemberRole changes the role of a member of a workspace.
+// ErrLastOwner is returned when demoting its only owner.
+func (db *DB) SetWorkspaceMemberRole(workspaceID, userID uint, role string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		member, err := lockWorkspaceMember(tx, workspaceID, userID)
+		if err != nil {
+			return err
+		}
+		if member.Role == RoleOwner && role != RoleOwner {
+			if err := checkOtherOwner(tx, member); err != nil {
+				return err
+			}
+		}
+		return tx.Model(member).Update("role", role).Error
+	})
+}
+
+// RemoveWorkspaceMember removes a user from a workspace, revoking their API
+// key for it. ErrLastOwner is returned when removing its only owner.
+func (db *DB) RemoveWorkspaceMember(workspaceID, userID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		member, err := lockWorkspaceMember(tx, workspaceID, userID)
+		if err != nil {
+			return err
+		}
+		if member.Role == RoleOwner {
+			if err := checkOtherOwner(tx, member); err != nil {
+				return err
+			}
+		}
+		// Deleted for good, the member may be added again.
+		return tx.Unscoped().Delete(member).Error
+	})
+}
+
+// GetWorkspaceMember retrieves the membership of a user in a workspace.
+func (db *DB) GetWorkspaceMember(workspaceID, userID uint) (*WorkspaceMember, error) {
+	var member WorkspaceMember
+	err := db.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// lockWorkspaceMember retrieves a membership and locks the owners of its
+// workspace, so concurrent changes cannot leave the workspace without one.
+func lockWorkspaceMember(tx *gorm.DB, workspaceID, userID uint) (*WorkspaceMember, error) {
+	var owners []WorkspaceMember
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("workspace_id = ? AND role = ?", workspaceID, RoleOwner).Find(&owners).Error
+	if err != nil {
+		return nil, err
+	}
+	var member WorkspaceMember
+	err = tx.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// checkOtherOwner returns ErrLastOwner unless the workspace of member has
+// another owner.
+func checkOtherOwner(tx *gorm.DB, member *WorkspaceMember) error {
+	var owners int64
+	err := tx.Model(&WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ? AND id <> ?", member.WorkspaceID, RoleOwner, member.ID).
+		Count(&owners).Error
+	if err != nil {
+		return err
+	}
+	if owners == 0 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// workspaceScoped are the models owned by a workspace through their
+// WorkspaceID.
+var workspaceScoped = []interface{}{
+	&URLMapping{}, &Domain{}, &LinkGroup{}, &UTMRule{},
+	&WebhookSubscription{}, &WebhookDelivery{}, &WebhookDeadLetter{},
+}
+
+// migrateWorkspaces moves data owned by users before workspaces existed
+// into their personal workspaces. The workspace_id columns are added and
+// filled before the other models are migrated, so their unique indexes
+// including it can be created.
+func (db *DB) migrateWorkspaces() error {
+	if err := db.DB.AutoMigrate(&User{}, &Workspace{}, &WorkspaceMember{}); err != nil {
+		return err
+	}
+	var users []User
+	err := db.Where("id NOT IN (?)", db.Model(&Workspace{}).Select("personal_user_id")).Find(&users).Error
+	if err != nil {
+		return err
+	}
+	for i := range users {
+		if err := db.Transaction(func(tx *gorm.DB) error { return createPersonalWorkspace(tx, &users[i]) }); err != nil {
+			return fmt.Errorf("creating personal workspace of user %d: %w", users[i].ID, err)
+		}
+	}
+
+	for _, model := range workspaceScoped {
+		if !db.Migrator().HasTable(model) || db.Migrator().HasColumn(model, "WorkspaceID") {
+			continue
+		}
+		if err := db.Migrator().AddColumn(model, "WorkspaceID"); err != nil {
+			return err
+		}
+		stmt := &gorm.Statement{DB: db.DB}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		err := db.Exec(fmt.Sprintf(
+			"UPDATE %s SET workspace_id = workspaces.id FROM workspaces WHERE workspaces.personal_user_id = %[1]s.user_id",
+			stmt.Schema.Table)).Error
+		if err != nil {
+			return err
+		}
+		if _, ok := model.(*UTMRule); ok {
+			err := db.Exec("UPDATE workspaces SET has_utm_rules = true WHERE id IN (SELECT workspace_id FROM utm_rules WHERE deleted_at IS NULL)").Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Replaced by the workspace unique indexes.
+	for _, index := range []struct {
+		model interface{}
+		name  string
+	}{
+		{&Domain{}, "idx_domains_user_host"},
+		{&LinkGroup{}, "idx_link_groups_user_kind_name"},
+	} {
+		if db.Migrator().HasIndex(index.model, index.name) {
+			if err := db.Migrator().DropIndex(index.model, index.name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+// Package events publishes domain events, such as links being created or
+// clicked, for other services to consume. Events are proto.Event messages.
+//
+// Events are not published directly: they are written to the outbox table in
+// the same transaction as the change they describe, and a Relay moves them
+// from there to an EventPublisher. Events therefore survive the event bus
+// being down, and are published at least once; consumers drop duplicates by
+// event ID.
+package events
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/geoip"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/google/uuid"
+	gproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Event types.
+const (
+	TypeLinkCreated = "link.created"
+	TypeLinkClicked = "link.clicked"
+)
+
+// DefaultBatchSize is how many outbox events a Relay publishes per
+// transaction by default.
+const DefaultBatchSize = 100
+
+// ErrClosed is returned when publishing to a closed publisher.
+var ErrClosed = errors.New("event publisher is closed")
+
+// EventPublisher sends events to an event bus.
+type EventPublisher interface {
+	// Publish sends event, returning once the bus accepted it.
+	Publish(ctx context.Context, event *proto.Event) error
+	// Close releases the resources of the publisher.
+	Close() error
+}
+
+// LinkCreated returns the event announcing mapping was created.
+func LinkCreated(mapping *dataModel.URLMapping) *proto.Event {
+	return newEvent(TypeLinkCreated, &proto.Event{Payload: &proto.Event_LinkCreated{LinkCreated: &proto.LinkCreated{
+		ShortUrl:    mapping.ShortURLID,
+		LongUrl:     mapping.LongURL,
+		UserId:      uint64(mapping.UserID),
+		CustomAlias: mapping.IsCustomAlias,
+		MaxClicks:   mapping.MaxClicks,
+	}}})
+}
+
+// Click describes a visitor following a link.
+type Click struct {
+	Referrer  string
+	UserAgent string
+	Location  geoip.Location
+	// VariantID is the variant the visitor was sent to, 0 for links
+	// without variants.
+	VariantID uint
+}
+
+// LinkClicked returns the event announcing mapping was followed.
+func LinkClicked(mapping *dataModel.URLMapping, click Click) *proto.Event {
+	return newEvent(TypeLinkClicked, &proto.Event{Payload: &proto.Event_LinkClicked{LinkClicked: &proto.LinkClicked{
+		ShortUrl:        mapping.ShortURLID,
+		LongUrl:         mapping.LongURL,
+		UserId:          uint64(mapping.UserID),
+		Referrer:        click.Referrer,
+		UserAgent:       click.UserAgent,
+		ClicksRemaining: mapping.ClicksRemaining,
+		Country:         click.Location.Country,
+		Region:          click.Location.Region,
+		VariantId:       uint64(click.VariantID),
+	}}})
+}
+
+func newEvent(eventType string, event *proto.Event) *proto.Event {
+	event.Id = uuid.NewString()
+	event.Type = eventType
+	event.OccurredAt = timestamppb.Now()
+	return event
+}
+
+// NewOutboxEvent encodes event for the outbox.
+func NewOutboxEvent(event *proto.Event) (*dataModel.OutboxEvent, error) {
+	payload, err := gproto.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &dataModel.OutboxEvent{EventID: event.Id, Type: event.Type, Payload: payload}, nil
+}
+
+// Store holds the outbox. *dataModel.DB implements it.
+type Store interface {
+	PublishOutboxEvents(limit int, publish func(*dataModel.OutboxEvent) error) (int, error)
+}
+
+// Relay publishes the events of the outbox.
+type Relay struct {
+	Store     Store
+	Publisher EventPublisher
+	// BatchSize is how many events are published per call of PublishPending.
+	BatchSize int
+
+	wakeOnce sync.Once
+	wake     chan struct{}
+}
+
+// NewRelay returns a relay from store to publisher.
+func NewRelay(store Store, publisher EventPublisher) *Relay {
+	return &Relay{Store: store, Publisher: publisher, BatchSize: DefaultBatchSize}
+}
+
+func (r *Relay) wakeChan() chan struct{} {
+	r.wakeOnce.Do(func() { r.wake = make(chan struct{}, 1) })
+	return r.wake
+}
+
+// Notify tells a running relay that events were added to the outbox, so they
+// are published without waiting for the next poll.
+func (r *Relay) Notify() {
+	select {
+	case r.wakeChan() <- struct{}{}:
+	default:
+	}
+}
+
+// Run publishes outbox events every interval, and when notified, until ctx
+// is done.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Keep going while full batches come back, there may be more queued.
+		for {
+			n, err := r.PublishPending(ctx)
+			if err != nil {
+				log.Printf("Error publishing events: %v", err)
+			}
+			if err != nil || n < r.BatchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wakeChan():
+		}
+	}
+}
+
+// PublishPending publishes one batch of outbox events in order, and returns
+// how many were published. While another replica publishes it returns 0.
+// An event the publisher rejects is retried on the next call, and the
+// events after it wait for it.
+func (r *Relay) PublishPending(ctx context.Context) (int, error) {
+	var publishErr error
+	n, err := r.Store.PublishOutboxEvents(r.BatchSize, func(e *dataModel.OutboxEvent) error {
+		var event proto.Event
+		if err := gproto.Unmarshal(e.Payload, &event); err != nil {
+			// Retrying will not help, do not hold up the events after it.
+			log.Printf("Dropping undecodable %s event %s: %v", e.Type, e.EventID, err)
+			return nil
+		}
+		if err := r.Publisher.Publish(ctx, &event); err != nil {
+			publishErr = err
+			return err
+		}
+		return nil
+	})
+	if err == nil {
+		err = publishErr
+	}
+	return n, err
+}
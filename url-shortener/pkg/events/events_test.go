@@ -0,0 +1,262 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/geoip"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+// memStore keeps the outbox in memory.
+type memStore struct {
+	mu       sync.Mutex
+	events   []*dataModel.OutboxEvent
+	attempts map[string]int
+}
+
+func (m *memStore) add(t *testing.T, evs ...*proto.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ev := range evs {
+		e, err := NewOutboxEvent(ev)
+		require.NoError(t, err)
+		m.events = append(m.events, e)
+	}
+}
+
+func (m *memStore) PublishOutboxEvents(limit int, publish func(*dataModel.OutboxEvent) error) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	published := 0
+	for published < len(m.events) && published < limit {
+		e := m.events[published]
+		if err := publish(e); err != nil {
+			if m.attempts == nil {
+				m.attempts = make(map[string]int)
+			}
+			m.attempts[e.EventID]++
+			break
+		}
+		published++
+	}
+	m.events = m.events[published:]
+	return published, nil
+}
+
+// failingPublisher rejects the first failures events, then records them.
+type failingPublisher struct {
+	failures  int
+	published []*proto.Event
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, event *proto.Event) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("bus unavailable")
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+func (p *failingPublisher) Close() error { return nil }
+
+// fakeStreamClient records the entries added to streams.
+type fakeStreamClient struct {
+	args []*redis.XAddArgs
+}
+
+func (c *fakeStreamClient) XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd {
+	c.args = append(c.args, a)
+	return redis.NewStringResult("1-0", nil)
+}
+
+func testMapping() *dataModel.URLMapping {
+	return &dataModel.URLMapping{ShortURLID: "abc", LongURL: "https://example.com/", UserID: 3, MaxClicks: 10, ClicksRemaining: 9}
+}
+
+func TestLinkEvents(t *testing.T) {
+	created := LinkCreated(testMapping())
+	assert.Equal(t, TypeLinkCreated, created.Type)
+	assert.NotEmpty(t, created.Id)
+	assert.NotNil(t, created.OccurredAt)
+	assert.Equal(t, "abc", created.GetLinkCreated().ShortUrl)
+	assert.Equal(t, uint64(3), created.GetLinkCreated().UserId)
+	assert.Equal(t, int64(10), created.GetLinkCreated().MaxClicks)
+
+	clicked := LinkClicked(testMapping(), Click{
+		Referrer:  "https://ref.example/",
+		UserAgent: "curl/8",
+		Location:  geoip.Location{Country: "DE", Region: "DE-BE"},
+		VariantID: 4,
+	})
+	assert.Equal(t, TypeLinkClicked, clicked.Type)
+	assert.NotEqual(t, created.Id, clicked.Id)
+	assert.Equal(t, "https://ref.example/", clicked.GetLinkClicked().Referrer)
+	assert.Equal(t, int64(9), clicked.GetLinkClicked().ClicksRemaining)
+	assert.Equal(t, "DE", clicked.GetLinkClicked().Country)
+	assert.Equal(t, "DE-BE", clicked.GetLinkClicked().Region)
+	assert.Equal(t, uint64(4), clicked.GetLinkClicked().VariantId)
+
+	e, err := NewOutboxEvent(clicked)
+	require.NoError(t, err)
+	assert.Equal(t, clicked.Id, e.EventID)
+	assert.Equal(t, TypeLinkClicked, e.Type)
+	var decoded proto.Event
+	require.NoError(t, gproto.Unmarshal(e.Payload, &decoded))
+	assert.True(t, gproto.Equal(clicked, &decoded))
+}
+
+func TestRelay(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Published in order", func(t *testing.T) {
+		store := &memStore{}
+		first, second := LinkCreated(testMapping()), LinkClicked(testMapping(), Click{})
+		store.add(t, first, second)
+		publisher := &failingPublisher{}
+		relay := NewRelay(store, publisher)
+
+		n, err := relay.PublishPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		require.Len(t, publisher.published, 2)
+		assert.Equal(t, first.Id, publisher.published[0].Id)
+		assert.Equal(t, second.Id, publisher.published[1].Id)
+		assert.Empty(t, store.events)
+	})
+
+	t.Run("Retried after failure", func(t *testing.T) {
+		store := &memStore{}
+		first, second := LinkCreated(testMapping()), LinkCreated(testMapping())
+		store.add(t, first, second)
+		publisher := &failingPublisher{failures: 1}
+		relay := NewRelay(store, publisher)
+
+		n, err := relay.PublishPending(ctx)
+		assert.EqualError(t, err, "bus unavailable")
+		assert.Equal(t, 0, n)
+		assert.Empty(t, publisher.published)
+		assert.Equal(t, 1, store.attempts[first.Id])
+		assert.Len(t, store.events, 2)
+
+		n, err = relay.PublishPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		require.Len(t, publisher.published, 2)
+		assert.Equal(t, first.Id, publisher.published[0].Id)
+	})
+
+	t.Run("Undecodable event dropped", func(t *testing.T) {
+		store := &memStore{events: []*dataModel.OutboxEvent{{EventID: "bad", Type: TypeLinkCreated, Payload: []byte{0xff}}}}
+		good := LinkCreated(testMapping())
+		store.add(t, good)
+		publisher := &failingPublisher{}
+
+		n, err := NewRelay(store, publisher).PublishPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		require.Len(t, publisher.published, 1)
+		assert.Equal(t, good.Id, publisher.published[0].Id)
+	})
+
+	t.Run("Run publishes when notified", func(t *testing.T) {
+		store := &memStore{}
+		bus := NewMemoryBus()
+		sub := bus.Subscribe(1)
+		defer sub.Close()
+		relay := NewRelay(store, bus)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go relay.Run(ctx, time.Hour)
+
+		ev := LinkCreated(testMapping())
+		store.add(t, ev)
+		relay.Notify()
+		select {
+		case got := <-sub.C:
+			assert.Equal(t, ev.Id, got.Id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("event not published")
+		}
+	})
+}
+
+func TestMemoryBus(t *testing.T) {
+	ctx := context.Background()
+	bus := NewMemoryBus()
+	a, b := bus.Subscribe(1), bus.Subscribe(1)
+	ev := LinkCreated(testMapping())
+
+	require.NoError(t, bus.Publish(ctx, ev))
+	assert.Equal(t, ev, <-a.C)
+	assert.Equal(t, ev, <-b.C)
+
+	// A full subscriber holds up Publish until the context is done, a closed
+	// one does not.
+	require.NoError(t, bus.Publish(ctx, ev))
+	b.Close()
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	<-a.C
+	require.NoError(t, bus.Publish(timeout, ev))
+	assert.ErrorIs(t, bus.Publish(timeout, ev), context.DeadlineExceeded)
+
+	require.NoError(t, bus.Close())
+	assert.ErrorIs(t, bus.Publish(ctx, ev), ErrClosed)
+}
+
+func TestRedisStream(t *testing.T) {
+	client := &fakeStreamClient{}
+	stream := &RedisStream{Client: client, Stream: "events", MaxLen: 100}
+	ev := LinkCreated(testMapping())
+
+	require.NoError(t, stream.Publish(context.Background(), ev))
+	require.Len(t, client.args, 1)
+	args := client.args[0]
+	assert.Equal(t, "events", args.Stream)
+	assert.Equal(t, int64(100), args.MaxLen)
+	assert.True(t, args.Approx)
+	values := args.Values.([]interface{})
+	assert.Equal(t, []interface{}{"id", ev.Id, "type", TypeLinkCreated, "event"}, values[:5])
+	var decoded proto.Event
+	require.NoError(t, gproto.Unmarshal(values[5].([]byte), &decoded))
+	assert.True(t, gproto.Equal(ev, &decoded))
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	evs := []*proto.Event{LinkCreated(testMapping()), LinkClicked(testMapping(), Click{UserAgent: "curl/8"})}
+	for _, ev := range evs {
+		require.NoError(t, sink.Publish(context.Background(), ev))
+	}
+	require.NoError(t, sink.Close())
+	assert.ErrorIs(t, sink.Publish(context.Background(), evs[0]), ErrClosed)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	var lines int
+	for ; scanner.Scan(); lines++ {
+		var decoded proto.Event
+		require.NoError(t, protojson.Unmarshal(scanner.Bytes(), &decoded))
+		assert.True(t, gproto.Equal(evs[lines], &decoded))
+		assert.Contains(t, scanner.Text(), `"short_url"`)
+	}
+	assert.Equal(t, 2, lines)
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// FileSink appends events to a file as newline delimited JSON, one event
+// per line in the protobuf JSON mapping with the field names of the proto
+// file.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink opens path for appending, creating it if needed.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Publish appends event to the file.
+func (s *FileSink) Publish(ctx context.Context, event *proto.Event) error {
+	line, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return ErrClosed
+	}
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,84 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+)
+
+// MemoryBus is an in-process EventPublisher. Each subscriber gets every
+// event published while it is subscribed; Publish waits for subscribers
+// whose buffer is full.
+type MemoryBus struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription receives the events of a MemoryBus on C.
+type Subscription struct {
+	C <-chan *proto.Event
+
+	events chan *proto.Event
+	done   chan struct{}
+	bus    *MemoryBus
+	once   sync.Once
+}
+
+// NewMemoryBus returns an empty bus.
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe starts receiving events, buffering up to buffer of them.
+func (b *MemoryBus) Subscribe(buffer int) *Subscription {
+	events := make(chan *proto.Event, buffer)
+	sub := &Subscription{C: events, events: events, done: make(chan struct{}), bus: b}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// Close stops the subscription. C is not closed.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.bus.mu.Lock()
+		defer s.bus.mu.Unlock()
+		delete(s.bus.subs, s)
+	})
+}
+
+// Publish hands event to every subscriber.
+func (b *MemoryBus) Publish(ctx context.Context, event *proto.Event) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrClosed
+	}
+	subs := make([]*Subscription, 0, len(b.subs))
+	for sub := range b.subs {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		select {
+		case sub.events <- event:
+		case <-sub.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close rejects further events.
+func (b *MemoryBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return nil
+}
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"context"
+
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/redis/go-redis/v9"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+// StreamClient is the part of a Redis client RedisStream uses.
+// *redis.Client implements it.
+type StreamClient interface {
+	XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd
+}
+
+// RedisStream publishes events to a Redis stream. Every entry has the fields
+// "id" and "type" of the event, and "event" with the protobuf encoded event.
+type RedisStream struct {
+	Client StreamClient
+	Stream string
+	// MaxLen approximately caps the length of the stream, 0 keeps all entries.
+	MaxLen int64
+}
+
+// Publish adds event to the stream.
+func (r *RedisStream) Publish(ctx context.Context, event *proto.Event) error {
+	data, err := gproto.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: r.Stream,
+		MaxLen: r.MaxLen,
+		Approx: r.MaxLen > 0,
+		Values: []interface{}{"id", event.Id, "type", event.Type, "event", data},
+	}).Err()
+}
+
+// Close does nothing, the client is owned by the caller.
+func (r *RedisStream) Close() error {
+	return nil
+}
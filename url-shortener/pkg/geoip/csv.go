@@ -0,0 +1,157 @@
+package geoip
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Ranges is a database of address ranges read from CSV. Each record is
+// either
+//
+//	first_ip,last_ip,country[,region]
+//	network,country[,region]
+//
+// with network in CIDR notation. Lines starting with '#' and a header line
+// are skipped. Ranges must not overlap.
+type Ranges struct {
+	// ranges is sorted by first. Addresses are kept in their 16 byte form
+	// so IPv4 and IPv6 ranges compare with each other.
+	ranges []ipRange
+}
+
+type ipRange struct {
+	first, last netip.Addr
+	location    Location
+}
+
+// OpenCSV reads the CSV range file at path.
+func OpenCSV(path string) (*Ranges, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCSV(f)
+}
+
+// ReadCSV reads CSV range records from r.
+func ReadCSV(r io.Reader) (*Ranges, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	db := &Ranges{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDatabase, err)
+		}
+		rng, err := parseRange(record)
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			row, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidDatabase, row, err)
+		}
+		db.ranges = append(db.ranges, rng)
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool {
+		return db.ranges[i].first.Less(db.ranges[j].first)
+	})
+	for i := 1; i < len(db.ranges); i++ {
+		if !db.ranges[i-1].last.Less(db.ranges[i].first) {
+			return nil, fmt.Errorf("%w: range starting at %s overlaps the one before",
+				ErrInvalidDatabase, db.ranges[i].first.Unmap())
+		}
+	}
+	return db, nil
+}
+
+func parseRange(record []string) (ipRange, error) {
+	var rng ipRange
+	var rest []string
+	if len(record) > 0 && strings.Contains(record[0], "/") {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(record[0]))
+		if err != nil {
+			return rng, err
+		}
+		rng.first, rng.last = prefixBounds(prefix)
+		rest = record[1:]
+	} else {
+		if len(record) < 2 {
+			return rng, errors.New("missing addresses")
+		}
+		first, err := netip.ParseAddr(strings.TrimSpace(record[0]))
+		if err != nil {
+			return rng, err
+		}
+		last, err := netip.ParseAddr(strings.TrimSpace(record[1]))
+		if err != nil {
+			return rng, err
+		}
+		if first.Is4() != last.Is4() {
+			return rng, errors.New("range mixes IPv4 and IPv6 addresses")
+		}
+		rng.first, rng.last = as16(first), as16(last)
+		if rng.last.Less(rng.first) {
+			return rng, errors.New("last address is before the first")
+		}
+		rest = record[2:]
+	}
+	if len(rest) == 0 || len(rest) > 2 {
+		return rng, errors.New("expected a country and an optional region")
+	}
+	country := strings.ToUpper(strings.TrimSpace(rest[0]))
+	if len(country) != 2 {
+		return rng, fmt.Errorf("invalid country code %q", rest[0])
+	}
+	rng.location.Country = country
+	if len(rest) == 2 {
+		rng.location.Region = regionCode(country, rest[1])
+	}
+	return rng, nil
+}
+
+// Lookup returns the location of the range containing ip.
+func (db *Ranges) Lookup(ip netip.Addr) (Location, error) {
+	ip = as16(ip.Unmap())
+	i := sort.Search(len(db.ranges), func(i int) bool {
+		return ip.Less(db.ranges[i].first)
+	})
+	if i == 0 || db.ranges[i-1].last.Less(ip) {
+		return Location{}, nil
+	}
+	return db.ranges[i-1].location, nil
+}
+
+// as16 returns ip in its 16 byte form, IPv4 addresses mapped into IPv6.
+func as16(ip netip.Addr) netip.Addr {
+	return netip.AddrFrom16(ip.As16())
+}
+
+// prefixBounds returns the first and last address of prefix in their 16
+// byte form.
+func prefixBounds(prefix netip.Prefix) (netip.Addr, netip.Addr) {
+	prefix = prefix.Masked()
+	bits := prefix.Bits()
+	if prefix.Addr().Is4() {
+		bits += 96
+	}
+	last := prefix.Addr().As16()
+	for i := bits; i < 128; i++ {
+		last[i/8] |= 0x80 >> (i % 8)
+	}
+	return as16(prefix.Addr()), netip.AddrFrom16(last)
+}
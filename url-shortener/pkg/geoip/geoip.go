@@ -0,0 +1,54 @@
+// Package geoip tells the country and region of IP addresses from a local
+// MaxMind DB (mmdb) or CSV range file. Lookups never go to the network.
+package geoip
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidDatabase is returned for files that cannot be read as a
+// location database.
+var ErrInvalidDatabase = errors.New("invalid geoip database")
+
+// Location is where an address is located. Unknown fields are empty.
+type Location struct {
+	// Country is the ISO 3166-1 alpha-2 code, e.g. "US".
+	Country string
+	// Region is the ISO 3166-2 code of the first level subdivision, e.g.
+	// "US-CA".
+	Region string
+}
+
+// Database locates IP addresses.
+type Database interface {
+	// Lookup returns the location of ip, the zero Location when it is not
+	// in the database.
+	Lookup(ip netip.Addr) (Location, error)
+}
+
+// Open loads the database at path, a MaxMind DB when it ends in .mmdb and a
+// CSV range file when it ends in .csv.
+func Open(path string) (Database, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mmdb":
+		return OpenMMDB(path)
+	case ".csv":
+		return OpenCSV(path)
+	default:
+		return nil, fmt.Errorf("%w: %s is neither a .mmdb nor a .csv file", ErrInvalidDatabase, path)
+	}
+}
+
+// regionCode qualifies a subdivision code with its country, leaving codes
+// that already are alone.
+func regionCode(country, region string) string {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region == "" || country == "" || strings.Contains(region, "-") {
+		return region
+	}
+	return country + "-" + region
+}
@@ -0,0 +1,247 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"math"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pointer encodes as a pointer to a data section offset below 2048.
+type pointer int
+
+// encode writes v in the MaxMind DB data format. Sizes stay below 29.
+func encode(v any) []byte {
+	ctrl := func(typ, size int) []byte {
+		if typ < 8 {
+			return []byte{byte(typ<<5 | size)}
+		}
+		return []byte{byte(size), byte(typ - 7)}
+	}
+	switch v := v.(type) {
+	case pointer:
+		return []byte{byte(typePointer<<5 | int(v)>>8), byte(v)}
+	case string:
+		return append(ctrl(typeString, len(v)), v...)
+	case uint16:
+		return append(ctrl(typeUint16, 2), byte(v>>8), byte(v))
+	case uint32:
+		return binary.BigEndian.AppendUint32(ctrl(typeUint32, 4), v)
+	case float64:
+		return binary.BigEndian.AppendUint64(ctrl(typeDouble, 8), math.Float64bits(v))
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		return ctrl(typeBool, size)
+	case []any:
+		b := ctrl(typeArray, len(v))
+		for _, item := range v {
+			b = append(b, encode(item)...)
+		}
+		return b
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b := ctrl(typeMap, len(v))
+		for _, k := range keys {
+			b = append(b, encode(k)...)
+			b = append(b, encode(v[k])...)
+		}
+		return b
+	}
+	panic("cannot encode value")
+}
+
+// writeMMDB builds an IPv6 MaxMind DB mapping networks to records. shared
+// is written first in the data section, for records to point at.
+func writeMMDB(t *testing.T, recordSize int, shared []byte, networks map[string]any) []byte {
+	t.Helper()
+	type node struct {
+		kids [2]*node
+		data int // offset into the data section of leaves, else -1
+	}
+	data := append([]byte(nil), shared...)
+	root := &node{data: -1}
+	for network, record := range networks {
+		prefix := netip.MustParsePrefix(network)
+		bits := prefix.Bits()
+		addr := prefix.Addr().As16()
+		if prefix.Addr().Is4() {
+			// IPv4 networks live in ::/96.
+			bits += 96
+			copy(addr[:12], make([]byte, 12))
+		}
+		n := root
+		for i := 0; i < bits; i++ {
+			bit := addr[i/8] >> (7 - i%8) & 1
+			if n.kids[bit] == nil {
+				n.kids[bit] = &node{data: -1}
+			}
+			n = n.kids[bit]
+		}
+		n.data = len(data)
+		data = append(data, encode(record)...)
+	}
+
+	var nodes []*node
+	index := map[*node]int{}
+	for queue := []*node{root}; len(queue) > 0; queue = queue[1:] {
+		n := queue[0]
+		index[n] = len(nodes)
+		nodes = append(nodes, n)
+		for _, kid := range n.kids {
+			if kid != nil && kid.data < 0 {
+				queue = append(queue, kid)
+			}
+		}
+	}
+	count := len(nodes)
+	value := func(kid *node) uint32 {
+		switch {
+		case kid == nil:
+			return uint32(count)
+		case kid.data >= 0:
+			return uint32(count + dataSectionSeparator + kid.data)
+		default:
+			return uint32(index[kid])
+		}
+	}
+
+	var buf []byte
+	for _, n := range nodes {
+		left, right := value(n.kids[0]), value(n.kids[1])
+		switch recordSize {
+		case 24:
+			buf = append(buf, byte(left>>16), byte(left>>8), byte(left), byte(right>>16), byte(right>>8), byte(right))
+		case 28:
+			buf = append(buf, byte(left>>16), byte(left>>8), byte(left), byte(left>>20&0xf0|right>>24&0x0f),
+				byte(right>>16), byte(right>>8), byte(right))
+		case 32:
+			buf = binary.BigEndian.AppendUint32(buf, left)
+			buf = binary.BigEndian.AppendUint32(buf, right)
+		}
+	}
+	buf = append(buf, make([]byte, dataSectionSeparator)...)
+	buf = append(buf, data...)
+	buf = append(buf, metadataMarker...)
+	return append(buf, encode(map[string]any{
+		"node_count":    uint32(count),
+		"record_size":   uint16(recordSize),
+		"ip_version":    uint16(6),
+		"database_type": "Test-City",
+		"languages":     []any{"en"},
+	})...)
+}
+
+func TestMMDB(t *testing.T) {
+	shared := encode(map[string]any{"iso_code": "US", "names": map[string]any{"en": "United States"}})
+	networks := map[string]any{
+		"81.2.69.0/24": map[string]any{
+			"city":         map[string]any{"names": map[string]any{"en": "London"}},
+			"country":      map[string]any{"iso_code": "GB"},
+			"location":     map[string]any{"latitude": 51.5, "longitude": -0.1, "accuracy_radius": uint16(10)},
+			"subdivisions": []any{map[string]any{"iso_code": "ENG"}},
+			"is_anycast":   false,
+		},
+		"2001:db8::/32": map[string]any{
+			"country":      pointer(0),
+			"subdivisions": []any{map[string]any{"iso_code": "CA"}, map[string]any{"iso_code": "SF"}},
+		},
+		"10.0.0.0/8": map[string]any{
+			"registered_country": map[string]any{"iso_code": "DE"},
+		},
+	}
+	tests := []struct {
+		ip   string
+		want Location
+	}{
+		{"81.2.69.142", Location{"GB", "GB-ENG"}},
+		{"::ffff:81.2.69.1", Location{"GB", "GB-ENG"}},
+		{"2001:db8:1::1", Location{"US", "US-CA"}},
+		{"10.1.2.3", Location{Country: "DE"}},
+		{"8.8.8.8", Location{}},
+		{"2001:db9::1", Location{}},
+	}
+	for _, recordSize := range []int{24, 28, 32} {
+		db, err := NewMMDB(writeMMDB(t, recordSize, shared, networks))
+		require.NoError(t, err)
+		for _, tt := range tests {
+			loc, err := db.Lookup(netip.MustParseAddr(tt.ip))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, loc, "%s with %d bit records", tt.ip, recordSize)
+		}
+	}
+}
+
+func TestMMDBInvalid(t *testing.T) {
+	_, err := NewMMDB([]byte("not a database"))
+	assert.ErrorIs(t, err, ErrInvalidDatabase)
+
+	buf := append([]byte{}, metadataMarker...)
+	buf = append(buf, encode(map[string]any{"node_count": uint32(1000), "record_size": uint16(24), "ip_version": uint16(6)})...)
+	_, err = NewMMDB(buf)
+	assert.ErrorIs(t, err, ErrInvalidDatabase)
+}
+
+func TestReadCSV(t *testing.T) {
+	db, err := ReadCSV(strings.NewReader(`network_or_first,last,country,region
+# Comments are skipped.
+81.2.69.0/24,gb,ENG
+1.0.0.0,1.0.0.255,AU
+2001:db8::/32,US,US-CA
+`))
+	require.NoError(t, err)
+	tests := []struct {
+		ip   string
+		want Location
+	}{
+		{"81.2.69.255", Location{"GB", "GB-ENG"}},
+		{"1.0.0.0", Location{Country: "AU"}},
+		{"1.0.0.255", Location{Country: "AU"}},
+		{"1.0.1.0", Location{}},
+		{"2001:db8:ffff::1", Location{"US", "US-CA"}},
+		{"0.0.0.1", Location{}},
+	}
+	for _, tt := range tests {
+		loc, err := db.Lookup(netip.MustParseAddr(tt.ip))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, loc, tt.ip)
+	}
+
+	for name, data := range map[string]string{
+		"Overlap":       "1.0.0.0/24,AU\n1.0.0.128,1.0.1.0,NZ\n",
+		"Bad country":   "1.0.0.0/24,AU\n2.0.0.0/24,Narnia\n",
+		"Mixed":         "1.0.0.0/24,AU\n2.0.0.0,2001:db8::,NZ\n",
+		"Reversed":      "1.0.0.0/24,AU\n2.0.0.9,2.0.0.1,NZ\n",
+		"Missing field": "1.0.0.0/24,AU\n2.0.0.0/24\n",
+	} {
+		_, err := ReadCSV(strings.NewReader(data))
+		assert.ErrorIs(t, err, ErrInvalidDatabase, name)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ranges.csv")
+	require.NoError(t, os.WriteFile(path, []byte("1.0.0.0/24,AU\n"), 0o600))
+	db, err := Open(path)
+	require.NoError(t, err)
+	loc, err := db.Lookup(netip.MustParseAddr("1.0.0.1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "AU", loc.Country)
+
+	_, err = Open(filepath.Join(dir, "ranges.txt"))
+	assert.ErrorIs(t, err, ErrInvalidDatabase)
+}
@@ -0,0 +1,402 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/netip"
+	"os"
+)
+
+// metadataMarker precedes the metadata map at the end of a MaxMind DB.
+var metadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// dataSectionSeparator is the gap between the search tree and the data
+// section.
+const dataSectionSeparator = 16
+
+// maxDecodeDepth bounds the nesting of decoded values, so that pointer
+// cycles in a corrupt file cannot recurse forever.
+const maxDecodeDepth = 32
+
+// Data section types, see
+// https://maxmind.github.io/MaxMind-DB/#output-data-section.
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+// MMDB is a database in the MaxMind DB format, such as GeoLite2 Country or
+// City. The whole file is held in memory.
+type MMDB struct {
+	tree       []byte
+	data       decoder
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	// ipv4Start is the node IPv4 lookups start at in an IPv6 tree.
+	ipv4Start uint
+}
+
+// OpenMMDB reads the MaxMind DB at path.
+func OpenMMDB(path string) (*MMDB, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMMDB(buf)
+}
+
+// NewMMDB reads a MaxMind DB from buf.
+func NewMMDB(buf []byte) (*MMDB, error) {
+	at := bytes.LastIndex(buf, metadataMarker)
+	if at < 0 {
+		return nil, fmt.Errorf("%w: metadata not found", ErrInvalidDatabase)
+	}
+	meta := decoder(buf[at+len(metadataMarker):])
+	value, _, err := meta.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: metadata: %v", ErrInvalidDatabase, err)
+	}
+	fields, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata is not a map", ErrInvalidDatabase)
+	}
+	db := &MMDB{
+		nodeCount:  uintField(fields, "node_count"),
+		recordSize: uintField(fields, "record_size"),
+		ipVersion:  uintField(fields, "ip_version"),
+	}
+	if db.recordSize != 24 && db.recordSize != 28 && db.recordSize != 32 {
+		return nil, fmt.Errorf("%w: unsupported record size %d", ErrInvalidDatabase, db.recordSize)
+	}
+	if db.ipVersion != 4 && db.ipVersion != 6 {
+		return nil, fmt.Errorf("%w: unsupported IP version %d", ErrInvalidDatabase, db.ipVersion)
+	}
+	treeSize := db.nodeCount * db.recordSize / 4
+	if treeSize+dataSectionSeparator > uint(at) {
+		return nil, fmt.Errorf("%w: search tree larger than the file", ErrInvalidDatabase)
+	}
+	db.tree = buf[:treeSize]
+	db.data = decoder(buf[treeSize+dataSectionSeparator : at])
+
+	if db.ipVersion == 6 {
+		for i := 0; i < 96 && db.ipv4Start < db.nodeCount; i++ {
+			db.ipv4Start = db.record(db.ipv4Start, 0)
+		}
+	}
+	return db, nil
+}
+
+func uintField(fields map[string]any, key string) uint {
+	switch v := fields[key].(type) {
+	case uint64:
+		return uint(v)
+	case int32:
+		return uint(v)
+	}
+	return 0
+}
+
+// Lookup returns the country and region of ip.
+func (db *MMDB) Lookup(ip netip.Addr) (Location, error) {
+	ip = ip.Unmap()
+	node, bits := uint(0), 128
+	if ip.Is4() {
+		bits = 32
+		node = db.ipv4Start
+	} else if db.ipVersion == 4 {
+		return Location{}, nil
+	}
+	addr := ip.As16()
+	if ip.Is4() {
+		copy(addr[:4], addr[12:])
+	}
+	for i := 0; i < bits && node < db.nodeCount; i++ {
+		bit := uint(addr[i/8]>>(7-i%8)) & 1
+		node = db.record(node, bit)
+	}
+	if node <= db.nodeCount {
+		return Location{}, nil
+	}
+	offset := int(node - db.nodeCount - dataSectionSeparator)
+	return db.location(offset)
+}
+
+// record returns the left (bit 0) or right (bit 1) record of node.
+func (db *MMDB) record(node, bit uint) uint {
+	b := db.tree[node*db.recordSize/4:]
+	switch db.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xf0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0f)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:]))
+	}
+}
+
+// location decodes the country and region of the record at offset. Only
+// the fields needed are decoded; City records carry a lot more.
+func (db *MMDB) location(offset int) (Location, error) {
+	typ, size, offset, err := db.data.follow(offset)
+	if err != nil {
+		return Location{}, err
+	}
+	if typ != typeMap {
+		return Location{}, fmt.Errorf("%w: record is not a map", ErrInvalidDatabase)
+	}
+	var loc Location
+	var registered, subdivision string
+	for i := 0; i < size; i++ {
+		key, next, err := db.data.decode(offset, 0)
+		if err != nil {
+			return Location{}, err
+		}
+		var value any
+		switch key {
+		case "country", "registered_country", "subdivisions":
+			value, offset, err = db.data.decode(next, 0)
+		default:
+			offset, err = db.data.skip(next, 0)
+		}
+		if err != nil {
+			return Location{}, err
+		}
+		switch key {
+		case "country":
+			loc.Country = isoCode(value)
+		case "registered_country":
+			registered = isoCode(value)
+		case "subdivisions":
+			if list, ok := value.([]any); ok && len(list) > 0 {
+				subdivision = isoCode(list[0])
+			}
+		}
+	}
+	if loc.Country == "" {
+		loc.Country = registered
+	}
+	loc.Region = regionCode(loc.Country, subdivision)
+	return loc, nil
+}
+
+// isoCode returns the iso_code of a GeoIP2 country or subdivision, or the
+// value itself in databases storing plain codes.
+func isoCode(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		code, _ := v["iso_code"].(string)
+		return code
+	}
+	return ""
+}
+
+// decoder decodes values of a MaxMind DB data section. Pointers are offsets
+// into it.
+type decoder []byte
+
+// header reads the control byte(s) at offset and returns the type and size
+// of the value and where its payload starts. The size of a pointer is the
+// offset it points to.
+func (d decoder) header(offset int) (typ, size, next int, err error) {
+	if offset < 0 || offset >= len(d) {
+		return 0, 0, 0, fmt.Errorf("%w: offset %d out of range", ErrInvalidDatabase, offset)
+	}
+	ctrl := d[offset]
+	offset++
+	typ = int(ctrl >> 5)
+	if typ == typePointer {
+		n := int(ctrl>>3&0x3) + 1
+		if offset+n > len(d) {
+			return 0, 0, 0, fmt.Errorf("%w: truncated pointer", ErrInvalidDatabase)
+		}
+		b := d[offset : offset+n]
+		v := int(ctrl & 0x7)
+		switch n {
+		case 1:
+			size = v<<8 | int(b[0])
+		case 2:
+			size = (v<<16 | int(b[0])<<8 | int(b[1])) + 2048
+		case 3:
+			size = (v<<24 | int(b[0])<<16 | int(b[1])<<8 | int(b[2])) + 526336
+		default:
+			size = int(binary.BigEndian.Uint32(b))
+		}
+		return typ, size, offset + n, nil
+	}
+	if typ == typeExtended {
+		if offset >= len(d) {
+			return 0, 0, 0, fmt.Errorf("%w: truncated type", ErrInvalidDatabase)
+		}
+		typ = 7 + int(d[offset])
+		offset++
+	}
+	size = int(ctrl & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		if offset+n > len(d) {
+			return 0, 0, 0, fmt.Errorf("%w: truncated size", ErrInvalidDatabase)
+		}
+		b := d[offset : offset+n]
+		switch n {
+		case 1:
+			size = 29 + int(b[0])
+		case 2:
+			size = 285 + (int(b[0])<<8 | int(b[1]))
+		default:
+			size = 65821 + (int(b[0])<<16 | int(b[1])<<8 | int(b[2]))
+		}
+		offset += n
+	}
+	return typ, size, offset, nil
+}
+
+// follow is header resolving a pointer at offset to the value it points
+// to. Pointers to pointers are not allowed by the format.
+func (d decoder) follow(offset int) (typ, size, next int, err error) {
+	typ, size, next, err = d.header(offset)
+	if err != nil || typ != typePointer {
+		return typ, size, next, err
+	}
+	return d.header(size)
+}
+
+// decode returns the value at offset and the offset following it. Maps
+// decode to map[string]any, arrays to []any, unsigned integers to uint64
+// and uint128 to its big endian bytes.
+func (d decoder) decode(offset, depth int) (any, int, error) {
+	if depth >= maxDecodeDepth {
+		return nil, 0, fmt.Errorf("%w: values nested too deep", ErrInvalidDatabase)
+	}
+	typ, size, next, err := d.header(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if typ == typePointer {
+		value, _, err := d.decode(size, depth+1)
+		return value, next, err
+	}
+
+	switch typ {
+	case typeMap:
+		m := make(map[string]any, size)
+		for i := 0; i < size; i++ {
+			key, after, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("%w: map key is not a string", ErrInvalidDatabase)
+			}
+			if m[k], next, err = d.decode(after, depth+1); err != nil {
+				return nil, 0, err
+			}
+		}
+		return m, next, nil
+	case typeArray:
+		list := make([]any, 0, size)
+		for i := 0; i < size; i++ {
+			var value any
+			if value, next, err = d.decode(next, depth+1); err != nil {
+				return nil, 0, err
+			}
+			list = append(list, value)
+		}
+		return list, next, nil
+	case typeBool:
+		return size != 0, next, nil
+	}
+
+	if next+size > len(d) {
+		return nil, 0, fmt.Errorf("%w: truncated value", ErrInvalidDatabase)
+	}
+	b := []byte(d[next : next+size])
+	switch typ {
+	case typeString:
+		return string(b), next + size, nil
+	case typeBytes, typeUint128:
+		return b, next + size, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("%w: double of %d bytes", ErrInvalidDatabase, size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next + size, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("%w: float of %d bytes", ErrInvalidDatabase, size)
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), next + size, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("%w: integer of %d bytes", ErrInvalidDatabase, size)
+		}
+		var v uint64
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+		return v, next + size, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("%w: integer of %d bytes", ErrInvalidDatabase, size)
+		}
+		var v uint32
+		for _, c := range b {
+			v = v<<8 | uint32(c)
+		}
+		return int32(v), next + size, nil
+	}
+	return nil, 0, fmt.Errorf("%w: unknown type %d", ErrInvalidDatabase, typ)
+}
+
+// skip returns the offset following the value at offset without decoding
+// it.
+func (d decoder) skip(offset, depth int) (int, error) {
+	if depth >= maxDecodeDepth {
+		return 0, fmt.Errorf("%w: values nested too deep", ErrInvalidDatabase)
+	}
+	typ, size, next, err := d.header(offset)
+	if err != nil {
+		return 0, err
+	}
+	switch typ {
+	case typePointer, typeBool:
+		return next, nil
+	case typeMap:
+		size *= 2
+		fallthrough
+	case typeArray:
+		for i := 0; i < size; i++ {
+			if next, err = d.skip(next, depth+1); err != nil {
+				return 0, err
+			}
+		}
+		return next, nil
+	}
+	if next+size > len(d) {
+		return 0, fmt.Errorf("%w: truncated value", ErrInvalidDatabase)
+	}
+	return next + size, nil
+}
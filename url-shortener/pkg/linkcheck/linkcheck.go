@@ -0,0 +1,411 @@
+// Package linkcheck periodically checks that link destinations still
+// answer. Destinations are requested with HEAD, falling back to GET, and
+// redirects are followed. Requests to a host are spaced out and robots.txt
+// is honored. A link is broken once enough consecutive checks failed.
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+)
+
+// UserAgent identifies the checker to destinations and in robots.txt.
+const UserAgent = "url-shortener-linkcheck"
+
+// Defaults of the Checker settings.
+const (
+	DefaultInterval         = 24 * time.Hour
+	DefaultTimeout          = 10 * time.Second
+	DefaultBatchSize        = 100
+	DefaultConcurrency      = 8
+	DefaultDomainDelay      = time.Second
+	DefaultFailureThreshold = 2
+	DefaultRobotsTTL        = 24 * time.Hour
+	DefaultMaxRedirects     = 10
+)
+
+// MaxCrawlDelay caps the Crawl-delay of robots.txt files.
+const MaxCrawlDelay = 30 * time.Second
+
+// maxBodyRead caps how much of a GET response is read before closing it.
+const maxBodyRead = 64 << 10
+
+// Store holds the mappings to check. *dataModel.DB implements it.
+type Store interface {
+	ClaimLinkChecks(now time.Time, limit int, lease time.Duration) ([]dataModel.URLMapping, error)
+	RecordLinkCheck(mapping *dataModel.URLMapping) error
+}
+
+// Checker checks the due destinations of a Store.
+type Checker struct {
+	Store Store
+	// Client sends the requests. NewChecker sets it up to follow at most
+	// DefaultMaxRedirects redirects, vetting each with CheckURL.
+	Client *http.Client
+	// CheckURL, when set, vets the destination, and every URL it redirects
+	// to, before it is requested. An error fails the check.
+	CheckURL func(ctx context.Context, url string) error
+	// OnBroken, when set, is called after a mapping turned broken.
+	OnBroken func(mapping *dataModel.URLMapping)
+
+	// Interval is how often each destination is checked.
+	Interval time.Duration
+	// BatchSize is how many mappings are claimed at once, Concurrency how
+	// many hosts are checked in parallel.
+	BatchSize   int
+	Concurrency int
+	// DomainDelay is the least time between two requests to a host, raised
+	// by its Crawl-delay.
+	DomainDelay time.Duration
+	// FailureThreshold is how many consecutive checks have to fail for a
+	// link to be broken.
+	FailureThreshold int
+	// RobotsTTL is how long robots.txt files are cached.
+	RobotsTTL time.Duration
+
+	// Now returns the current time, time.Now when nil.
+	Now func() time.Time
+
+	hosts  hostLimiter
+	mu     sync.Mutex
+	robots map[string]robotsEntry
+}
+
+type robotsEntry struct {
+	rules   *robotsRules
+	expires time.Time
+}
+
+// NewChecker returns a checker of store with the default settings.
+func NewChecker(store Store) *Checker {
+	c := &Checker{
+		Store:            store,
+		Interval:         DefaultInterval,
+		BatchSize:        DefaultBatchSize,
+		Concurrency:      DefaultConcurrency,
+		DomainDelay:      DefaultDomainDelay,
+		FailureThreshold: DefaultFailureThreshold,
+		RobotsTTL:        DefaultRobotsTTL,
+	}
+	c.Client = &http.Client{
+		Timeout: DefaultTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= DefaultMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", len(via))
+			}
+			if c.CheckURL != nil {
+				return c.CheckURL(req.Context(), req.URL.String())
+			}
+			return nil
+		},
+	}
+	return c
+}
+
+func (c *Checker) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// Run checks due destinations every interval until ctx is done.
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Keep going while full batches come back, there may be more due.
+		for {
+			n, err := c.CheckDue(ctx)
+			if err != nil {
+				log.Printf("Error checking link destinations: %v", err)
+			}
+			if err != nil || n < c.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckDue claims one batch of due mappings, checks their destinations and
+// records the outcomes. Destinations on the same host are checked one after
+// the other, so waiting for a slow host does not hold up the others. It
+// returns how many mappings were checked.
+func (c *Checker) CheckDue(ctx context.Context) (int, error) {
+	now := c.now()
+	c.hosts.prune(now)
+	mappings, err := c.Store.ClaimLinkChecks(now, c.BatchSize, c.lease())
+	if err != nil {
+		return 0, err
+	}
+
+	byHost := make(map[string][]*dataModel.URLMapping)
+	var hosts []string
+	for i := range mappings {
+		host := ""
+		if u, err := url.Parse(mappings[i].LongURL); err == nil {
+			host = u.Host
+		}
+		if _, ok := byHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		byHost[host] = append(byHost[host], &mappings[i])
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(c.Concurrency, 1))
+	for _, host := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			for _, mapping := range byHost[host] {
+				c.Check(ctx, mapping)
+				if err := c.Store.RecordLinkCheck(mapping); err != nil {
+					log.Printf("Error recording health of %s: %v", mapping.ShortURLID, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return len(mappings), nil
+}
+
+// Check checks the destination of mapping once and updates its Health,
+// calling OnBroken when it turned broken.
+func (c *Checker) Check(ctx context.Context, mapping *dataModel.URLMapping) {
+	health := &mapping.Health
+	wasBroken := health.Broken
+
+	status, finalURL, latency, err := c.probe(ctx, mapping.LongURL)
+	if ctx.Err() != nil {
+		// Shutting down, the mapping is checked again after its lease.
+		return
+	}
+	now := c.now()
+	next := now.Add(c.Interval)
+	health.CheckedAt = &now
+	health.NextCheckAt = &next
+	health.Skipped = errors.Is(err, errDisallowed)
+	if health.Skipped {
+		// Nothing new is known, the last outcome stands.
+		return
+	}
+	health.StatusCode = status
+	health.FinalURL = finalURL
+	health.LatencyMS = latency.Milliseconds()
+	health.Error = ""
+	switch {
+	case err != nil:
+		health.Error = err.Error()
+		health.Failures++
+	case Broken(status):
+		health.Error = http.StatusText(status)
+		health.Failures++
+	default:
+		health.Failures = 0
+	}
+	health.Broken = health.Failures >= max(c.FailureThreshold, 1)
+	if health.Broken && !wasBroken && c.OnBroken != nil {
+		c.OnBroken(mapping)
+	}
+}
+
+// Broken reports whether a response status means the destination is gone or
+// failing. Statuses asking for credentials or a slower pace do not.
+func Broken(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusProxyAuthRequired, http.StatusTooManyRequests:
+		return false
+	}
+	return status >= 400
+}
+
+// errDisallowed is returned by probe for destinations robots.txt disallows.
+var errDisallowed = errors.New("disallowed by robots.txt")
+
+// probe requests rawURL with HEAD and, when that fails with an error status,
+// with GET, since some servers do not support HEAD. It returns the status
+// and URL of the final response and how long getting it took.
+func (c *Checker) probe(ctx context.Context, rawURL string) (int, string, time.Duration, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return 0, "", 0, fmt.Errorf("not an http or https URL")
+	}
+	if c.CheckURL != nil {
+		if err := c.CheckURL(ctx, rawURL); err != nil {
+			return 0, "", 0, err
+		}
+	}
+	rules, err := c.robotsRules(ctx, u)
+	if err != nil {
+		return 0, "", 0, err
+	}
+	if !rules.allowed(u.RequestURI()) {
+		return 0, "", 0, errDisallowed
+	}
+	delay := max(c.DomainDelay, rules.crawlDelay)
+
+	var status int
+	var finalURL string
+	var latency time.Duration
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		if err := c.hosts.wait(ctx, u.Host, delay, c.now); err != nil {
+			return 0, "", 0, err
+		}
+		start := time.Now()
+		status, finalURL, err = c.request(ctx, method, rawURL)
+		latency = time.Since(start)
+		if err != nil || status < 400 {
+			break
+		}
+	}
+	return status, finalURL, latency, err
+}
+
+// request sends one request and returns the status and URL of the final
+// response.
+func (c *Checker) request(ctx context.Context, method, rawURL string) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyRead))
+	return resp.StatusCode, resp.Request.URL.String(), nil
+}
+
+// robotsRules returns the cached robots.txt rules of the host of u, fetching
+// them when missing or expired. Like RFC 9309 asks, missing files allow
+// everything and failing servers nothing. Unreachable hosts allow
+// everything too, so their links are found broken.
+func (c *Checker) robotsRules(ctx context.Context, u *url.URL) (*robotsRules, error) {
+	key := u.Scheme + "://" + u.Host
+	now := c.now()
+	c.mu.Lock()
+	entry, ok := c.robots[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.rules, nil
+	}
+
+	rules := &robotsRules{}
+	status, body, err := 0, []byte(nil), c.hosts.wait(ctx, u.Host, c.DomainDelay, c.now)
+	if err == nil {
+		status, body, err = c.fetchRobots(ctx, key+"/robots.txt")
+	}
+	switch {
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case err != nil:
+	case status >= 500:
+		rules = disallowAll
+	case status < 300:
+		rules = parseRobots(body, UserAgent)
+	}
+
+	c.mu.Lock()
+	if c.robots == nil {
+		c.robots = make(map[string]robotsEntry)
+	}
+	c.robots[key] = robotsEntry{rules: rules, expires: now.Add(c.RobotsTTL)}
+	c.mu.Unlock()
+	return rules, nil
+}
+
+func (c *Checker) fetchRobots(ctx context.Context, robotsURL string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsSize))
+	return resp.StatusCode, body, err
+}
+
+// lease is how long claimed mappings are not due again: long enough for
+// a batch to be checked before another checker claims it again.
+func (c *Checker) lease() time.Duration {
+	timeout := c.Client.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	// Every mapping may take a robots.txt, a HEAD and a GET request, and
+	// in the worst case all of them are on one host.
+	perMapping := 3 * (timeout + max(c.DomainDelay, MaxCrawlDelay))
+	return time.Duration(max(c.BatchSize, 1)) * perMapping
+}
+
+// hostLimiter spaces out the requests to each host.
+type hostLimiter struct {
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// wait blocks until a request to host may be sent, delay after the one
+// before it, or ctx is done.
+func (l *hostLimiter) wait(ctx context.Context, host string, delay time.Duration, now func() time.Time) error {
+	l.mu.Lock()
+	t := now()
+	at := t
+	if next, ok := l.next[host]; ok && next.After(t) {
+		at = next
+	}
+	if l.next == nil {
+		l.next = make(map[string]time.Time)
+	}
+	l.next[host] = at.Add(delay)
+	l.mu.Unlock()
+
+	if at.Equal(t) {
+		return nil
+	}
+	timer := time.NewTimer(at.Sub(t))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// prune forgets hosts that may be requested again at now.
+func (l *hostLimiter) prune(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for host, next := range l.next {
+		if !next.After(now) {
+			delete(l.next, host)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// memStore keeps mappings in memory.
+type memStore struct {
+	mu       sync.Mutex
+	mappings []dataModel.URLMapping
+}
+
+func (m *memStore) ClaimLinkChecks(now time.Time, limit int, lease time.Duration) ([]dataModel.URLMapping, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []dataModel.URLMapping
+	for i := range m.mappings {
+		mapping := &m.mappings[i]
+		next := mapping.Health.NextCheckAt
+		if len(due) < limit && (next == nil || !next.After(now)) {
+			claimed := now.Add(lease)
+			mapping.Health.NextCheckAt = &claimed
+			due = append(due, *mapping)
+		}
+	}
+	return due, nil
+}
+
+func (m *memStore) RecordLinkCheck(mapping *dataModel.URLMapping) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.mappings {
+		if m.mappings[i].ID == mapping.ID {
+			m.mappings[i] = *mapping
+		}
+	}
+	return nil
+}
+
+func (m *memStore) get(id uint) dataModel.URLMapping {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, mapping := range m.mappings {
+		if mapping.ID == id {
+			return mapping
+		}
+	}
+	return dataModel.URLMapping{}
+}
+
+func newMapping(id uint, url string) dataModel.URLMapping {
+	return dataModel.URLMapping{Model: gorm.Model{ID: id}, ShortURLID: "abc", LongURL: url}
+}
+
+// newTestChecker returns a checker of store without delays between
+// requests.
+func newTestChecker(store Store) *Checker {
+	c := NewChecker(store)
+	c.DomainDelay = 0
+	return c
+}
+
+func TestCheckDue(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) { http.Error(w, "gone", http.StatusGone) })
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/ok", http.StatusFound) })
+	mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) { http.Error(w, "", http.StatusForbidden) })
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("User-agent: *\nDisallow: /secret\n"))
+	})
+	var secretHits int
+	mux.HandleFunc("/secret", func(w http.ResponseWriter, r *http.Request) { secretHits++ })
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadURL := dead.URL + "/page"
+	dead.Close()
+
+	store := &memStore{mappings: []dataModel.URLMapping{
+		newMapping(1, srv.URL+"/ok"),
+		newMapping(2, srv.URL+"/gone"),
+		newMapping(3, srv.URL+"/moved"),
+		newMapping(4, srv.URL+"/private"),
+		newMapping(5, srv.URL+"/no-head"),
+		newMapping(6, srv.URL+"/secret"),
+		newMapping(7, deadURL),
+	}}
+	c := newTestChecker(store)
+	c.FailureThreshold = 1
+	var broken []uint
+	var mu sync.Mutex
+	c.OnBroken = func(m *dataModel.URLMapping) {
+		mu.Lock()
+		defer mu.Unlock()
+		broken = append(broken, m.ID)
+	}
+
+	n, err := c.CheckDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 7, n)
+
+	ok := store.get(1).Health
+	require.NotNil(t, ok.CheckedAt)
+	assert.Equal(t, http.StatusOK, ok.StatusCode)
+	assert.False(t, ok.Broken)
+	assert.Empty(t, ok.Error)
+	assert.True(t, ok.NextCheckAt.After(time.Now().Add(23*time.Hour)))
+
+	gone := store.get(2).Health
+	assert.Equal(t, http.StatusGone, gone.StatusCode)
+	assert.True(t, gone.Broken)
+	assert.Equal(t, "Gone", gone.Error)
+
+	moved := store.get(3).Health
+	assert.Equal(t, http.StatusOK, moved.StatusCode)
+	assert.Equal(t, srv.URL+"/ok", moved.FinalURL)
+
+	assert.False(t, store.get(4).Health.Broken, "403 means the destination exists")
+	assert.Equal(t, http.StatusOK, store.get(5).Health.StatusCode, "falls back to GET")
+
+	secret := store.get(6).Health
+	assert.True(t, secret.Skipped)
+	assert.Zero(t, secretHits)
+	assert.False(t, secret.Broken)
+
+	unreachable := store.get(7).Health
+	assert.Zero(t, unreachable.StatusCode)
+	assert.NotEmpty(t, unreachable.Error)
+	assert.True(t, unreachable.Broken)
+
+	assert.ElementsMatch(t, []uint{2, 7}, broken)
+
+	n, err = c.CheckDue(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n, "nothing is due until the interval passed")
+}
+
+func TestFailureThreshold(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	c := newTestChecker(nil)
+	notified := 0
+	c.OnBroken = func(*dataModel.URLMapping) { notified++ }
+	mapping := newMapping(1, srv.URL+"/page")
+	ctx := context.Background()
+
+	c.Check(ctx, &mapping)
+	assert.Equal(t, 1, mapping.Health.Failures)
+	assert.False(t, mapping.Health.Broken)
+	c.Check(ctx, &mapping)
+	assert.True(t, mapping.Health.Broken)
+	c.Check(ctx, &mapping)
+	assert.Equal(t, 1, notified, "owners are told once")
+
+	status = http.StatusOK
+	c.Check(ctx, &mapping)
+	assert.Zero(t, mapping.Health.Failures)
+	assert.False(t, mapping.Health.Broken)
+}
+
+func TestCheckURL(t *testing.T) {
+	var hits int
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer target.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+"/internal", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	c := newTestChecker(nil)
+	c.FailureThreshold = 1
+	errInternal := errors.New("not publicly reachable")
+	c.CheckURL = func(ctx context.Context, url string) error {
+		if url == target.URL+"/internal" {
+			return errInternal
+		}
+		return nil
+	}
+	mapping := newMapping(1, srv.URL+"/page")
+	c.Check(context.Background(), &mapping)
+	assert.True(t, mapping.Health.Broken)
+	assert.Contains(t, mapping.Health.Error, errInternal.Error())
+	assert.Zero(t, hits)
+}
+
+func TestDomainDelay(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	store := &memStore{mappings: []dataModel.URLMapping{
+		newMapping(1, srv.URL+"/a"), newMapping(2, srv.URL+"/b"), newMapping(3, srv.URL+"/c"),
+	}}
+	c := NewChecker(store)
+	c.DomainDelay = 50 * time.Millisecond
+	_, err := c.CheckDue(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, times, 3)
+	for i := 1; i < len(times); i++ {
+		assert.GreaterOrEqual(t, times[i].Sub(times[i-1]), 45*time.Millisecond)
+	}
+}
+
+func TestRobotsServerError(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		hits++
+	}))
+	defer srv.Close()
+
+	c := newTestChecker(nil)
+	mapping := newMapping(1, srv.URL+"/page")
+	c.Check(context.Background(), &mapping)
+	assert.True(t, mapping.Health.Skipped)
+	assert.Zero(t, hits)
+}
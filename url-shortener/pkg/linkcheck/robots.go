@@ -0,0 +1,137 @@
+package linkcheck
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRobotsSize caps how much of a robots.txt file is read, like RFC 9309
+// allows.
+const maxRobotsSize = 500 << 10
+
+// robotsRules are the rules of a robots.txt file for one user agent. The
+// zero value allows everything.
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// disallowAll is used for hosts whose robots.txt could not be fetched
+// because of a server error.
+var disallowAll = &robotsRules{rules: []robotsRule{{pattern: "/"}}}
+
+// parseRobots parses the groups of a robots.txt file applying to agent:
+// those naming it, ignoring case, or else those for "*".
+func parseRobots(body []byte, agent string) *robotsRules {
+	type group struct {
+		agents     []string
+		rules      []robotsRule
+		crawlDelay time.Duration
+	}
+	var groups []*group
+	var current *group
+	// A user-agent line after rules starts a new group, consecutive ones
+	// share it.
+	inAgents := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				current = &group{}
+				groups = append(groups, current)
+				inAgents = true
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inAgents = false
+			// An empty disallow allows everything, like no rule.
+			if current == nil || value == "" {
+				continue
+			}
+			current.rules = append(current.rules, robotsRule{allow: key == "allow", pattern: value})
+		case "crawl-delay":
+			inAgents = false
+			if current == nil {
+				continue
+			}
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.crawlDelay = min(time.Duration(seconds*float64(time.Second)), MaxCrawlDelay)
+			}
+		}
+	}
+
+	agent = strings.ToLower(agent)
+	var named, wildcard robotsRules
+	for _, g := range groups {
+		for _, a := range g.agents {
+			target := &wildcard
+			if a == agent {
+				target = &named
+			} else if a != "*" {
+				continue
+			}
+			target.rules = append(target.rules, g.rules...)
+			target.crawlDelay = max(target.crawlDelay, g.crawlDelay)
+			break
+		}
+	}
+	if len(named.rules) > 0 || named.crawlDelay > 0 {
+		return &named
+	}
+	return &wildcard
+}
+
+// allowed reports whether path, with its query, may be requested. The most
+// specific, that is longest, matching rule wins, allow rules on ties.
+func (r *robotsRules) allowed(path string) bool {
+	allow, longest := true, -1
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > longest || (n == longest && rule.allow) {
+			allow, longest = rule.allow, n
+		}
+	}
+	return allow
+}
+
+// robotsMatch reports whether path matches a robots.txt pattern, where "*"
+// matches any characters and a final "$" the end of the path.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	for i, part := range parts[1:] {
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(path[pos:], part)
+		}
+		idx := strings.Index(path[pos:], part)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(part)
+	}
+	return !anchored || pos == len(path)
+}
@@ -0,0 +1,74 @@
+package linkcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRobots(t *testing.T) {
+	body := []byte(`# comment
+User-agent: Googlebot
+Disallow: /
+
+User-agent: *
+Disallow: /private # trailing comment
+Allow: /private/public
+Disallow: /*.pdf$
+Crawl-delay: 2
+
+User-agent: url-shortener-linkcheck
+User-agent: Other
+Disallow: /only-us
+Crawl-delay: 120
+`)
+
+	rules := parseRobots(body, UserAgent)
+	assert.Equal(t, MaxCrawlDelay, rules.crawlDelay)
+	assert.False(t, rules.allowed("/only-us/page"))
+	assert.True(t, rules.allowed("/private"), "named groups replace the * group")
+
+	rules = parseRobots(body, "someone-else")
+	assert.Equal(t, 2*time.Second, rules.crawlDelay)
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/private", false},
+		{"/private/page", false},
+		{"/private/public/page", true},
+		{"/docs/a.pdf", false},
+		{"/docs/a.pdf?x=1", true},
+		{"/docs/a.html", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, rules.allowed(tt.path), tt.path)
+	}
+}
+
+func TestParseRobotsEmpty(t *testing.T) {
+	rules := parseRobots([]byte("User-agent: *\nDisallow:\n"), UserAgent)
+	assert.True(t, rules.allowed("/anything"))
+	assert.False(t, disallowAll.allowed("/anything"))
+}
+
+func TestRobotsMatch(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/a", "/a/b", true},
+		{"/a", "/b", false},
+		{"/*/b", "/x/y/b", true},
+		{"/a$", "/a", true},
+		{"/a$", "/ab", false},
+		{"/*.php$", "/x/index.php", true},
+		{"/*.php$", "/x/index.php5", false},
+		{"*", "/anything", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, robotsMatch(tt.pattern, tt.path), "%s %s", tt.pattern, tt.path)
+	}
+}
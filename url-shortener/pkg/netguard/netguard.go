@@ -0,0 +1,268 @@
+// Package netguard classifies destination hosts so that links cannot point
+// at loopback, private, link-local or cloud metadata addresses, or back at
+// the service itself.
+package netguard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+// Category is a class of addresses a destination may resolve to.
+type Category string
+
+const (
+	CategoryLoopback  Category = "loopback"
+	CategoryPrivate   Category = "private"
+	CategoryLinkLocal Category = "link-local"
+	CategoryMetadata  Category = "metadata"
+	// CategoryOwnHost is a host served by this service.
+	CategoryOwnHost Category = "own-host"
+	// CategoryUnresolvable is a host without any address.
+	CategoryUnresolvable Category = "unresolvable"
+)
+
+// AllCategories lists every category, the default set of blocked ones.
+var AllCategories = []Category{
+	CategoryLoopback, CategoryPrivate, CategoryLinkLocal, CategoryMetadata, CategoryOwnHost, CategoryUnresolvable,
+}
+
+// Resolver looks up the addresses of a host. *net.Resolver implements it.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// Config selects what the guard rejects.
+type Config struct {
+	// Blocked lists the rejected categories.
+	Blocked []Category
+	// OwnHosts are the hosts of this service; subdomains match as well.
+	OwnHosts []string
+	// Exceptions are hosts or CIDR ranges that are always allowed.
+	Exceptions []string
+}
+
+// BlockedError reports why a host was rejected.
+type BlockedError struct {
+	Host     string
+	IP       netip.Addr
+	Category Category
+}
+
+func (e *BlockedError) Error() string {
+	switch {
+	case e.Category == CategoryUnresolvable:
+		return fmt.Sprintf("host %s does not resolve", e.Host)
+	case e.IP.IsValid():
+		return fmt.Sprintf("host %s resolves to %s address %s", e.Host, e.Category, e.IP)
+	default:
+		return fmt.Sprintf("host %s is a %s host", e.Host, e.Category)
+	}
+}
+
+// Guard checks hosts against a Config.
+type Guard struct {
+	resolver       Resolver
+	blocked        map[Category]bool
+	ownHosts       []string
+	exceptionHosts map[string]bool
+	exceptionNets  []netip.Prefix
+}
+
+// New creates a guard. A nil resolver uses net.DefaultResolver.
+func New(cfg Config, resolver Resolver) (*Guard, error) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	g := &Guard{
+		resolver:       resolver,
+		blocked:        map[Category]bool{},
+		exceptionHosts: map[string]bool{},
+	}
+	for _, c := range cfg.Blocked {
+		g.blocked[c] = true
+	}
+	for _, h := range cfg.OwnHosts {
+		g.ownHosts = append(g.ownHosts, normalizeHost(h))
+	}
+	for _, e := range cfg.Exceptions {
+		if strings.Contains(e, "/") {
+			prefix, err := netip.ParsePrefix(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exception %q: %w", e, err)
+			}
+			g.exceptionNets = append(g.exceptionNets, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(e); err == nil {
+			g.exceptionNets = append(g.exceptionNets, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		g.exceptionHosts[normalizeHost(e)] = true
+	}
+	return g, nil
+}
+
+// Check returns a *BlockedError when host, or any address it resolves to,
+// falls into a blocked category.
+func (g *Guard) Check(ctx context.Context, host string) error {
+	host = normalizeHost(host)
+	if g.exceptionHosts[host] {
+		return nil
+	}
+	if g.blocked[CategoryOwnHost] && g.isOwnHost(host) {
+		return &BlockedError{Host: host, Category: CategoryOwnHost}
+	}
+	if category := classifyName(host); category != "" && g.blocked[category] {
+		return &BlockedError{Host: host, Category: category}
+	}
+
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		ipAddrs, err := g.resolver.LookupIPAddr(ctx, host)
+		if err != nil || len(ipAddrs) == 0 {
+			if g.blocked[CategoryUnresolvable] {
+				return &BlockedError{Host: host, Category: CategoryUnresolvable}
+			}
+			return nil
+		}
+		for _, ip := range ipAddrs {
+			if addr, ok := netip.AddrFromSlice(ip.IP); ok {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		if g.isException(addr) {
+			continue
+		}
+		if category := Classify(addr); category != "" && g.blocked[category] {
+			return &BlockedError{Host: host, IP: addr, Category: category}
+		}
+	}
+	return nil
+}
+
+// Blocks reports whether the guard rejects hosts of category.
+func (g *Guard) Blocks(category Category) bool {
+	return g.blocked[category]
+}
+
+// DialContext returns a dial function for an http.Transport that dials like
+// dialer but refuses to connect to addresses of a blocked category. The
+// address is checked when the connection is made, so a host that passed
+// Check cannot resolve to an internal address by the time it is dialed.
+func (g *Guard) DialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if g.exceptionHosts[normalizeHost(host)] {
+			return dialer.DialContext(ctx, network, address)
+		}
+		d := *dialer
+		control := dialer.Control
+		d.Control = func(network, address string, c syscall.RawConn) error {
+			if err := g.checkDial(host, address); err != nil {
+				return err
+			}
+			if control != nil {
+				return control(network, address, c)
+			}
+			return nil
+		}
+		return d.DialContext(ctx, network, address)
+	}
+}
+
+// checkDial checks the resolved address, host and port, dialed for host.
+func (g *Guard) checkDial(host, address string) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	addr := addrPort.Addr().Unmap()
+	if g.isException(addr) {
+		return nil
+	}
+	if category := Classify(addr); category != "" && g.blocked[category] {
+		return &BlockedError{Host: normalizeHost(host), IP: addr, Category: category}
+	}
+	return nil
+}
+
+func (g *Guard) isOwnHost(host string) bool {
+	for _, own := range g.ownHosts {
+		if host == own || strings.HasSuffix(host, "."+own) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Guard) isException(addr netip.Addr) bool {
+	for _, prefix := range g.exceptionNets {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	metadataAddrs = []netip.Addr{
+		netip.MustParseAddr("169.254.169.254"), // AWS, GCP, Azure, OpenStack
+		netip.MustParseAddr("169.254.170.2"),   // AWS ECS task metadata
+		netip.MustParseAddr("100.100.100.200"), // Alibaba Cloud
+		netip.MustParseAddr("fd00:ec2::254"),   // AWS IPv6
+	}
+	metadataNames = []string{"metadata.google.internal", "metadata.goog", "metadata"}
+	// sharedAddressSpace is the carrier-grade NAT range of RFC 6598.
+	sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+)
+
+// Classify returns the category of an address, or "" for public addresses.
+func Classify(addr netip.Addr) Category {
+	addr = addr.Unmap()
+	for _, m := range metadataAddrs {
+		if addr == m {
+			return CategoryMetadata
+		}
+	}
+	switch {
+	case addr.IsLoopback(), addr.IsUnspecified():
+		return CategoryLoopback
+	case addr.IsLinkLocalUnicast(), addr.IsLinkLocalMulticast():
+		return CategoryLinkLocal
+	case addr.IsPrivate(), sharedAddressSpace.Contains(addr):
+		return CategoryPrivate
+	}
+	return ""
+}
+
+// classifyName catches well-known internal names before they reach DNS.
+func classifyName(host string) Category {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return CategoryLoopback
+	}
+	for _, name := range metadataNames {
+		if host == name {
+			return CategoryMetadata
+		}
+	}
+	return ""
+}
+
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	return strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+}
@@ -0,0 +1,153 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeResolver answers lookups from a fixed table.
+type fakeResolver map[string][]string
+
+func (f fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := f[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	var addrs []net.IPAddr
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func TestClassify(t *testing.T) {
+	tests := map[string]Category{
+		"127.0.0.1":        CategoryLoopback,
+		"::1":              CategoryLoopback,
+		"0.0.0.0":          CategoryLoopback,
+		"10.1.2.3":         CategoryPrivate,
+		"172.16.0.1":       CategoryPrivate,
+		"192.168.1.1":      CategoryPrivate,
+		"100.64.0.1":       CategoryPrivate,
+		"fd12::1":          CategoryPrivate,
+		"169.254.1.1":      CategoryLinkLocal,
+		"fe80::1":          CategoryLinkLocal,
+		"169.254.169.254":  CategoryMetadata,
+		"::ffff:127.0.0.1": CategoryLoopback,
+		"93.184.216.34":    "",
+		"2606:4700::1111":  "",
+	}
+	for ip, want := range tests {
+		t.Run(ip, func(t *testing.T) {
+			assert.Equal(t, want, Classify(netip.MustParseAddr(ip)))
+		})
+	}
+}
+
+func TestGuardCheck(t *testing.T) {
+	resolver := fakeResolver{
+		"example.com":         {"93.184.216.34"},
+		"internal.corp":       {"10.0.0.5"},
+		"rebind.example":      {"93.184.216.34", "127.0.0.1"},
+		"allowed.internal":    {"10.0.0.6"},
+		"lab.example":         {"192.168.50.10"},
+		"sho.rt":              {"93.184.216.35"},
+		"metadata.attacker.x": {"169.254.169.254"},
+	}
+	guard, err := New(Config{
+		Blocked:    AllCategories,
+		OwnHosts:   []string{"sho.rt"},
+		Exceptions: []string{"allowed.internal", "192.168.50.0/24"},
+	}, resolver)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		host     string
+		category Category
+	}{
+		{"example.com", ""},
+		{"EXAMPLE.com.", ""},
+		{"internal.corp", CategoryPrivate},
+		{"rebind.example", CategoryLoopback},
+		{"localhost", CategoryLoopback},
+		{"api.localhost", CategoryLoopback},
+		{"127.0.0.1", CategoryLoopback},
+		{"[::1]", CategoryLoopback},
+		{"169.254.169.254", CategoryMetadata},
+		{"metadata.google.internal", CategoryMetadata},
+		{"metadata.attacker.x", CategoryMetadata},
+		{"sho.rt", CategoryOwnHost},
+		{"www.sho.rt", CategoryOwnHost},
+		{"allowed.internal", ""},
+		{"lab.example", ""},
+		{"does-not-exist.example", CategoryUnresolvable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := guard.Check(context.Background(), tt.host)
+			if tt.category == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var blocked *BlockedError
+			if assert.True(t, errors.As(err, &blocked), "expected BlockedError, got %v", err) {
+				assert.Equal(t, tt.category, blocked.Category)
+			}
+		})
+	}
+}
+
+func TestGuardOnlyBlocksConfiguredCategories(t *testing.T) {
+	guard, err := New(Config{Blocked: []Category{CategoryMetadata}}, fakeResolver{"internal.corp": {"10.0.0.5"}})
+	assert.NoError(t, err)
+	assert.NoError(t, guard.Check(context.Background(), "internal.corp"))
+	assert.NoError(t, guard.Check(context.Background(), "unknown.example"))
+	assert.Error(t, guard.Check(context.Background(), "169.254.169.254"))
+}
+
+func TestInvalidException(t *testing.T) {
+	_, err := New(Config{Exceptions: []string{"10.0.0.0/99"}}, nil)
+	assert.Error(t, err)
+}
+
+func TestGuardDialContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on loopback: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	address := listener.Addr().String()
+
+	t.Run("Blocked address is not dialed", func(t *testing.T) {
+		guard, err := New(Config{Blocked: AllCategories}, nil)
+		assert.NoError(t, err)
+		_, err = guard.DialContext(&net.Dialer{})(context.Background(), "tcp", address)
+		var blocked *BlockedError
+		if assert.True(t, errors.As(err, &blocked), "expected BlockedError, got %v", err) {
+			assert.Equal(t, CategoryLoopback, blocked.Category)
+		}
+	})
+
+	t.Run("Exception is dialed", func(t *testing.T) {
+		guard, err := New(Config{Blocked: AllCategories, Exceptions: []string{"127.0.0.1"}}, nil)
+		assert.NoError(t, err)
+		conn, err := guard.DialContext(&net.Dialer{})(context.Background(), "tcp", address)
+		if assert.NoError(t, err) {
+			conn.Close()
+		}
+	})
+}
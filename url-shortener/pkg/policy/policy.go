@@ -0,0 +1,346 @@
+// Package policy decides whether a destination URL may be shortened or
+// followed, based on block and allow rules loaded from one or more sources.
+package policy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind selects how a rule's pattern is matched against a URL.
+type Kind string
+
+const (
+	// KindDomain matches the host exactly.
+	KindDomain Kind = "domain"
+	// KindSuffix matches the host and all of its subdomains.
+	KindSuffix Kind = "suffix"
+	// KindRegex matches the full URL against a regular expression.
+	KindRegex Kind = "regex"
+	// KindPrefix matches URLs starting with the pattern.
+	KindPrefix Kind = "prefix"
+)
+
+// Action is what happens to a URL matching a rule.
+type Action string
+
+const (
+	// ActionBlock rejects matching URLs.
+	ActionBlock Action = "block"
+	// ActionAllow exempts matching URLs from block rules.
+	ActionAllow Action = "allow"
+)
+
+// Rule is a single block or allow entry.
+type Rule struct {
+	ID      uint
+	Kind    Kind
+	Pattern string
+	Action  Action
+	Reason  string
+	Source  string
+
+	re *regexp.Regexp
+}
+
+// key identifies a rule across reloads.
+func (r Rule) key() string {
+	return fmt.Sprintf("%s#%d %s %s %s", r.Source, r.ID, r.Action, r.Kind, r.Pattern)
+}
+
+// Compile validates the rule and prepares it for matching.
+func (r *Rule) Compile() error {
+	r.Pattern = strings.TrimSpace(r.Pattern)
+	if r.Pattern == "" {
+		return fmt.Errorf("empty pattern")
+	}
+	if r.Action == "" {
+		r.Action = ActionBlock
+	}
+	if r.Action != ActionBlock && r.Action != ActionAllow {
+		return fmt.Errorf("unknown action %q", r.Action)
+	}
+	switch r.Kind {
+	case KindDomain, KindSuffix:
+		r.Pattern = strings.Trim(strings.ToLower(r.Pattern), ".")
+	case KindPrefix:
+	case KindRegex:
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex %q: %w", r.Pattern, err)
+		}
+		r.re = re
+	default:
+		return fmt.Errorf("unknown kind %q", r.Kind)
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to u. rawURL is the string form
+// of u and is used by prefix and regex rules.
+func (r *Rule) Matches(u *url.URL, rawURL string) bool {
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	switch r.Kind {
+	case KindDomain:
+		return host == r.Pattern
+	case KindSuffix:
+		return host == r.Pattern || strings.HasSuffix(host, "."+r.Pattern)
+	case KindPrefix:
+		return strings.HasPrefix(rawURL, r.Pattern)
+	case KindRegex:
+		return r.re != nil && r.re.MatchString(rawURL)
+	}
+	return false
+}
+
+// Decision is the outcome of checking a URL.
+type Decision struct {
+	Blocked bool
+	// Rule is the rule that decided the outcome, nil when nothing matched.
+	Rule *Rule
+}
+
+// Source provides a set of rules.
+type Source interface {
+	Name() string
+	Load() ([]Rule, error)
+}
+
+// Engine evaluates URLs against the rules of all its sources.
+// Allow rules take precedence over block rules.
+type Engine struct {
+	sources []Source
+	// OnNewBlockRule is called for every block rule that appears on a reload
+	// after the first one, e.g. to disable links created before the rule.
+	OnNewBlockRule func(Rule)
+	// OnLiftedRule is called for every block rule that disappears and every
+	// allow rule that appears on a reload after the first one, e.g. to
+	// enable links the policy no longer blocks.
+	OnLiftedRule func(Rule)
+
+	// reloadMu serializes reloads, which run from Watch and on demand, so
+	// sources are loaded and rule changes reported one reload at a time.
+	reloadMu sync.Mutex
+	mu       sync.RWMutex
+	rules    []*Rule
+	loaded   bool
+}
+
+// NewEngine creates an engine reading from the given sources. Rules are
+// loaded by Reload.
+func NewEngine(sources ...Source) *Engine {
+	return &Engine{sources: sources}
+}
+
+// Reload loads the rules of every source and swaps them in atomically.
+// A failing source keeps the engine on its previous rules.
+func (e *Engine) Reload() error {
+	e.reloadMu.Lock()
+	defer e.reloadMu.Unlock()
+
+	var rules []*Rule
+	for _, src := range e.sources {
+		loaded, err := src.Load()
+		if err != nil {
+			return fmt.Errorf("loading policy source %s: %w", src.Name(), err)
+		}
+		for i := range loaded {
+			rule := loaded[i]
+			if rule.Source == "" {
+				rule.Source = src.Name()
+			}
+			if err := rule.Compile(); err != nil {
+				log.Printf("Skipping policy rule %q from %s: %v", rule.Pattern, src.Name(), err)
+				continue
+			}
+			rules = append(rules, &rule)
+		}
+	}
+
+	e.mu.Lock()
+	previous := e.rules
+	notify := e.loaded
+	e.rules = rules
+	e.loaded = true
+	e.mu.Unlock()
+
+	if notify {
+		e.notify(previous, rules)
+	}
+	return nil
+}
+
+// notify reports the rules that appeared or disappeared between two loads
+// to the hooks.
+func (e *Engine) notify(previous, current []*Rule) {
+	keys := func(rules []*Rule) map[string]bool {
+		set := make(map[string]bool, len(rules))
+		for _, r := range rules {
+			set[r.key()] = true
+		}
+		return set
+	}
+	before, after := keys(previous), keys(current)
+	for _, r := range current {
+		if before[r.key()] {
+			continue
+		}
+		if r.Action == ActionBlock && e.OnNewBlockRule != nil {
+			e.OnNewBlockRule(*r)
+		}
+		if r.Action == ActionAllow && e.OnLiftedRule != nil {
+			e.OnLiftedRule(*r)
+		}
+	}
+	for _, r := range previous {
+		if r.Action == ActionBlock && !after[r.key()] && e.OnLiftedRule != nil {
+			e.OnLiftedRule(*r)
+		}
+	}
+}
+
+// Watch reloads the rules every interval until ctx is done.
+func (e *Engine) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.Reload(); err != nil {
+				log.Printf("Error reloading policy rules: %v", err)
+			}
+		}
+	}
+}
+
+// Check evaluates rawURL against the loaded rules.
+func (e *Engine) Check(rawURL string) Decision {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Decision{}
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	var blockedBy *Rule
+	for _, r := range e.rules {
+		if !r.Matches(u, rawURL) {
+			continue
+		}
+		if r.Action == ActionAllow {
+			return Decision{Rule: r}
+		}
+		if blockedBy == nil {
+			blockedBy = r
+		}
+	}
+	return Decision{Blocked: blockedBy != nil, Rule: blockedBy}
+}
+
+// Rules returns a copy of the currently loaded rules.
+func (e *Engine) Rules() []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	rules := make([]Rule, 0, len(e.rules))
+	for _, r := range e.rules {
+		rules = append(rules, *r)
+	}
+	return rules
+}
+
+// FileSource reads rules from a text file with one rule per line:
+//
+//	<block|allow> <domain|suffix|regex|prefix> <pattern> [# reason]
+//
+// Blank lines and lines starting with '#' are ignored. The file is only
+// parsed again when its modification time changes. Load is not safe for
+// concurrent use, Engine.Reload never runs it concurrently.
+type FileSource struct {
+	Path string
+
+	modTime time.Time
+	rules   []Rule
+}
+
+// Name implements Source.
+func (f *FileSource) Name() string {
+	return "file:" + f.Path
+}
+
+// Load implements Source.
+func (f *FileSource) Load() ([]Rule, error) {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	if f.rules != nil && info.ModTime().Equal(f.modTime) {
+		return f.rules, nil
+	}
+
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	rules, err := ParseRules(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Path, err)
+	}
+	f.rules, f.modTime = rules, info.ModTime()
+	return rules, nil
+}
+
+// ParseRules reads rules in the FileSource format.
+func ParseRules(r io.Reader) ([]Rule, error) {
+	rules := []Rule{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var reason string
+		if i := strings.Index(text, " #"); i >= 0 {
+			reason = strings.TrimSpace(text[i+2:])
+			text = strings.TrimSpace(text[:i])
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected \"<action> <kind> <pattern>\"", line)
+		}
+		rules = append(rules, Rule{
+			Action:  Action(fields[0]),
+			Kind:    Kind(fields[1]),
+			Pattern: fields[2],
+			Reason:  reason,
+		})
+	}
+	return rules, scanner.Err()
+}
+
+// SourceFunc adapts a function to the Source interface.
+type SourceFunc struct {
+	SourceName string
+	LoadFunc   func() ([]Rule, error)
+}
+
+// Name implements Source.
+func (s SourceFunc) Name() string {
+	return s.SourceName
+}
+
+// Load implements Source.
+func (s SourceFunc) Load() ([]Rule, error) {
+	return s.LoadFunc()
+}
@@ -0,0 +1,136 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func staticSource(rules ...Rule) Source {
+	return SourceFunc{SourceName: "static", LoadFunc: func() ([]Rule, error) { return rules, nil }}
+}
+
+func TestEngineCheck(t *testing.T) {
+	engine := NewEngine(staticSource(
+		Rule{Kind: KindDomain, Pattern: "evil.com", Action: ActionBlock},
+		Rule{Kind: KindSuffix, Pattern: ".phish.example", Action: ActionBlock},
+		Rule{Kind: KindRegex, Pattern: `^https?://[^/]*paypa1\.`, Action: ActionBlock},
+		Rule{Kind: KindPrefix, Pattern: "https://docs.example.com/forms/", Action: ActionBlock, Reason: "form abuse"},
+		Rule{Kind: KindDomain, Pattern: "safe.phish.example", Action: ActionAllow},
+	))
+	assert.NoError(t, engine.Reload())
+
+	tests := []struct {
+		url     string
+		blocked bool
+	}{
+		{"http://evil.com/", true},
+		{"http://EVIL.com./x", true},
+		{"http://sub.evil.com/", false},
+		{"https://phish.example/", true},
+		{"https://a.b.phish.example/", true},
+		{"https://notphish.example/", false},
+		{"https://safe.phish.example/", false},
+		{"http://login.paypa1.com/", true},
+		{"https://docs.example.com/forms/123", true},
+		{"https://docs.example.com/docs/123", false},
+		{"https://example.org/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.blocked, engine.Check(tt.url).Blocked)
+		})
+	}
+	assert.Equal(t, "form abuse", engine.Check("https://docs.example.com/forms/1").Rule.Reason)
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(strings.NewReader(`
+# phishing
+block domain evil.com # reported 2025-01-02
+allow suffix good.example
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Action: ActionBlock, Kind: KindDomain, Pattern: "evil.com", Reason: "reported 2025-01-02"},
+		{Action: ActionAllow, Kind: KindSuffix, Pattern: "good.example"},
+	}, rules)
+
+	_, err = ParseRules(strings.NewReader("block evil.com"))
+	assert.Error(t, err)
+}
+
+func TestInvalidRulesAreSkipped(t *testing.T) {
+	engine := NewEngine(staticSource(
+		Rule{Kind: KindRegex, Pattern: "(", Action: ActionBlock},
+		Rule{Kind: "glob", Pattern: "*.com", Action: ActionBlock},
+		Rule{Kind: KindDomain, Pattern: "evil.com", Action: ActionBlock},
+	))
+	assert.NoError(t, engine.Reload())
+	assert.Len(t, engine.Rules(), 1)
+}
+
+func TestFileSourceHotReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("block domain evil.com\n"), 0o644))
+
+	var added []Rule
+	engine := NewEngine(&FileSource{Path: path})
+	engine.OnNewBlockRule = func(r Rule) { added = append(added, r) }
+	assert.NoError(t, engine.Reload())
+	assert.True(t, engine.Check("http://evil.com/").Blocked)
+	assert.Empty(t, added, "initial load must not report new rules")
+
+	assert.NoError(t, os.WriteFile(path, []byte("block domain evil.com\nblock domain worse.com\n"), 0o644))
+	future := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(path, future, future))
+	assert.NoError(t, engine.Reload())
+	assert.True(t, engine.Check("http://worse.com/").Blocked)
+	if assert.Len(t, added, 1) {
+		assert.Equal(t, "worse.com", added[0].Pattern)
+	}
+
+	assert.NoError(t, os.Remove(path))
+	assert.Error(t, engine.Reload())
+	assert.True(t, engine.Check("http://worse.com/").Blocked, "failed reload keeps previous rules")
+}
+
+func TestLiftedRules(t *testing.T) {
+	rules := []Rule{
+		{Kind: KindDomain, Pattern: "evil.com", Action: ActionBlock},
+		{Kind: KindDomain, Pattern: "worse.com", Action: ActionBlock},
+	}
+	engine := NewEngine(SourceFunc{SourceName: "static", LoadFunc: func() ([]Rule, error) { return rules, nil }})
+	var lifted []string
+	engine.OnLiftedRule = func(r Rule) { lifted = append(lifted, string(r.Action)+" "+r.Pattern) }
+	assert.NoError(t, engine.Reload())
+
+	rules = []Rule{
+		{Kind: KindDomain, Pattern: "evil.com", Action: ActionBlock},
+		{Kind: KindDomain, Pattern: "ok.evil.com", Action: ActionAllow},
+	}
+	assert.NoError(t, engine.Reload())
+	assert.Equal(t, []string{"allow ok.evil.com", "block worse.com"}, lifted)
+}
+
+func TestConcurrentReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("block domain evil.com\n"), 0o644))
+	engine := NewEngine(&FileSource{Path: path})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, engine.Reload())
+		}()
+	}
+	wg.Wait()
+	assert.True(t, engine.Check("http://evil.com/").Blocked)
+}
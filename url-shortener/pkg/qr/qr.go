@@ -0,0 +1,220 @@
+// Package qr renders QR codes as PNG or SVG images.
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Format is an output image format.
+type Format string
+
+const (
+	PNG Format = "png"
+	SVG Format = "svg"
+)
+
+// ContentType returns the MIME type of images in format f.
+func (f Format) ContentType() string {
+	if strings.EqualFold(string(f), string(SVG)) {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+const (
+	DefaultSize   = 256
+	MinSize       = 21
+	MaxSize       = 2048
+	DefaultMargin = 4
+	MaxMargin     = 32
+)
+
+// levels maps the error correction level letters to the encoder's levels,
+// recovering about 7%, 15%, 25% and 30% of the symbol respectively.
+var levels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+var ErrInvalidOptions = errors.New("invalid QR code options")
+
+// Options control how a code is rendered. The zero value renders a 256
+// pixel black on white PNG with medium error correction and the standard
+// four module margin.
+type Options struct {
+	Format Format
+	// Size is the width and height of the image in pixels.
+	Size int
+	// Level is the error correction level, one of L, M, Q or H.
+	Level string
+	// Margin is the quiet zone around the symbol in modules. Nil means
+	// DefaultMargin.
+	Margin *int
+	// Foreground and Background are "#rgb" or "#rrggbb" colours.
+	Foreground string
+	Background string
+}
+
+// normalized returns o with defaults filled in, or an error when an option
+// is out of range.
+func (o Options) normalized() (Options, error) {
+	if o.Format == "" {
+		o.Format = PNG
+	}
+	o.Format = Format(strings.ToLower(string(o.Format)))
+	if o.Format != PNG && o.Format != SVG {
+		return o, fmt.Errorf("%w: unknown format %q", ErrInvalidOptions, o.Format)
+	}
+	if o.Size == 0 {
+		o.Size = DefaultSize
+	}
+	if o.Size < MinSize || o.Size > MaxSize {
+		return o, fmt.Errorf("%w: size must be between %d and %d", ErrInvalidOptions, MinSize, MaxSize)
+	}
+	o.Level = strings.ToUpper(o.Level)
+	if o.Level == "" {
+		o.Level = "M"
+	}
+	if _, ok := levels[o.Level]; !ok {
+		return o, fmt.Errorf("%w: error correction level must be L, M, Q or H", ErrInvalidOptions)
+	}
+	margin := DefaultMargin
+	if o.Margin != nil {
+		margin = *o.Margin
+	}
+	if margin < 0 || margin > MaxMargin {
+		return o, fmt.Errorf("%w: margin must be between 0 and %d", ErrInvalidOptions, MaxMargin)
+	}
+	o.Margin = &margin
+	if o.Foreground == "" {
+		o.Foreground = "#000000"
+	}
+	if o.Background == "" {
+		o.Background = "#ffffff"
+	}
+	for _, c := range []*string{&o.Foreground, &o.Background} {
+		rgb, err := parseColor(*c)
+		if err != nil {
+			return o, err
+		}
+		*c = fmt.Sprintf("#%02x%02x%02x", rgb.R, rgb.G, rgb.B)
+	}
+	return o, nil
+}
+
+// Key returns a string identifying the image Render produces for o.
+func (o Options) Key() (string, error) {
+	n, err := o.normalized()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%d:%s:%d:%s:%s", n.Format, n.Size, n.Level, *n.Margin, n.Foreground, n.Background), nil
+}
+
+// Render encodes content as a QR code image.
+func Render(content string, opts Options) ([]byte, error) {
+	opts, err := opts.normalized()
+	if err != nil {
+		return nil, err
+	}
+	code, err := qrcode.New(content, levels[opts.Level])
+	if err != nil {
+		return nil, err
+	}
+	code.DisableBorder = true
+	modules := code.Bitmap()
+	fg, _ := parseColor(opts.Foreground)
+	bg, _ := parseColor(opts.Background)
+
+	if opts.Format == SVG {
+		return renderSVG(modules, opts), nil
+	}
+	return renderPNG(modules, *opts.Margin, opts.Size, fg, bg)
+}
+
+// renderPNG draws modules with margin scaled to the largest whole number
+// of pixels per module fitting size, centred in the image.
+func renderPNG(modules [][]bool, margin, size int, fg, bg color.RGBA) ([]byte, error) {
+	total := len(modules) + 2*margin
+	scale := size / total
+	if scale == 0 {
+		return nil, fmt.Errorf("%w: size %d is too small for %d modules", ErrInvalidOptions, size, total)
+	}
+	offset := (size-total*scale)/2 + margin*scale
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{bg, fg})
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for py := 0; py < scale; py++ {
+				for px := 0; px < scale; px++ {
+					img.SetColorIndex(offset+x*scale+px, offset+y*scale+py, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderSVG draws modules as one path, merging horizontal runs.
+func renderSVG(modules [][]bool, opts Options) []byte {
+	margin := *opts.Margin
+	total := len(modules) + 2*margin
+
+	var path strings.Builder
+	for y, row := range modules {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", start+margin, y+margin, x-start, x-start)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		opts.Size, opts.Size, total, total)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, total, total, opts.Background)
+	fmt.Fprintf(&buf, `<path d="%s" fill="%s"/>`, path.String(), opts.Foreground)
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+// parseColor parses "#rgb" and "#rrggbb" colours. The "#" is optional.
+func parseColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("%w: invalid colour %q", ErrInvalidOptions, s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("%w: invalid colour %q", ErrInvalidOptions, s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
@@ -0,0 +1,67 @@
+package qr
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPNG(t *testing.T) {
+	margin := 2
+	data, err := Render("https://sho.rt/d/abc", Options{Size: 300, Margin: &margin, Foreground: "#f00", Background: "00ff00"})
+	assert.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 300, img.Bounds().Dx())
+	assert.Equal(t, 300, img.Bounds().Dy())
+
+	red := color.RGBA{R: 0xff, A: 0xff}
+	green := color.RGBA{G: 0xff, A: 0xff}
+	// The corner pixel is in the margin, the finder pattern starts right after it.
+	assert.Equal(t, green, color.RGBAModel.Convert(img.At(0, 0)))
+	// Version 2 codes have 25 modules, 29 with margin, so 10 pixels per
+	// module and a 5 pixel border to centre them.
+	assert.Equal(t, red, color.RGBAModel.Convert(img.At(5+2*10, 5+2*10)))
+}
+
+func TestRenderSVG(t *testing.T) {
+	data, err := Render("https://sho.rt/d/abc", Options{Format: "SVG", Level: "h"})
+	assert.NoError(t, err)
+	svg := string(data)
+	assert.True(t, strings.HasPrefix(svg, "<svg "))
+	assert.Contains(t, svg, `width="256"`)
+	assert.Contains(t, svg, `fill="#ffffff"`)
+	assert.Contains(t, svg, `fill="#000000"`)
+	assert.Contains(t, svg, "M4 4h7v1h-7z")
+}
+
+func TestOptions(t *testing.T) {
+	negative := -1
+	for _, opts := range []Options{
+		{Format: "gif"},
+		{Size: 10},
+		{Size: 5000},
+		{Level: "X"},
+		{Margin: &negative},
+		{Foreground: "red"},
+		{Background: "#12345"},
+	} {
+		_, err := Render("x", opts)
+		assert.ErrorIs(t, err, ErrInvalidOptions, "%+v", opts)
+	}
+
+	k1, err := Options{}.Key()
+	assert.NoError(t, err)
+	four := 4
+	k2, err := Options{Format: "PNG", Size: 256, Level: "m", Margin: &four, Foreground: "#000", Background: "#FFFFFF"}.Key()
+	assert.NoError(t, err)
+	assert.Equal(t, k1, k2)
+
+	assert.Equal(t, "image/svg+xml", Format("svg").ContentType())
+	assert.Equal(t, "image/png", Format("").ContentType())
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/events"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"google.golang.org/grpc/status"
+)
+
+// BatchShortenURLs shortens up to MaxBatchSize URLs for one user. Each item
+// is handled like a ShortenURL request and gets its own result.
+func (s *UrlShortenerService) BatchShortenURLs(ctx context.Context, req *proto.BatchShortenURLsRequest) (*proto.BatchShortenURLsResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Items) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: %d items, at most %d allowed", ErrBatchTooLarge, len(req.Items), MaxBatchSize)
+	}
+	return &proto.BatchShortenURLsResponse{Results: s.shortenBatch(ctx, user, req.Items)}, nil
+}
+
+// shortenBatch shortens items on behalf of user. Unlike calling ShortenURL
+// per item, quota is consumed once, generated codes come from a single
+// counter range and all new mappings are created with one insert. Failing
+// to do any of those fails every item not shortened before. An item
+// repeating the long URL of an earlier one gets that item's result, like
+// ShortenURL reusing an existing link.
+func (s *UrlShortenerService) shortenBatch(ctx context.Context, user *dataModel.User, items []*proto.ShortenURLRequest) []*proto.BatchShortenResult {
+	results := make([]*proto.BatchShortenResult, len(items))
+	mappings := make([]*dataModel.URLMapping, len(items))
+
+	// Validation resolves hosts and may follow redirects, so items are
+	// prepared concurrently.
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, BatchConcurrency)
+	for i, item := range items {
+		results[i] = &proto.BatchShortenResult{Index: int32(i), LongUrl: item.LongUrl}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			mapping, existing, err := s.prepareURLMapping(ctx, user, item)
+			switch {
+			case err != nil:
+				results[i].Error = errorMessage(err)
+			case existing:
+				results[i].ShortUrl = displayShortURL(mapping)
+			default:
+				mappings[i] = mapping
+			}
+		}()
+	}
+	wg.Wait()
+
+	repeats := dedupeBatch(mappings, results)
+	// Repeated items take the result of their first item however the batch
+	// ends.
+	defer func() {
+		for i, first := range repeats {
+			results[i].ShortUrl, results[i].Error = results[first].ShortUrl, results[first].Error
+		}
+	}()
+
+	pending := s.claimCustomAliases(mappings, results)
+	if len(pending) == 0 {
+		return results
+	}
+	fail := func(err error) []*proto.BatchShortenResult {
+		for _, i := range pending {
+			results[i].Error = errorMessage(err)
+		}
+		return results
+	}
+
+	var delta dataModel.UsageDelta
+	var generated int64
+	for _, i := range pending {
+		d := usageDelta(mappings[i])
+		delta.Links += d.Links
+		delta.CustomAliases += d.CustomAliases
+		if mappings[i].ShortURLID == "" {
+			generated++
+		}
+	}
+	release, err := s.consumeQuota(user, delta)
+	if err != nil {
+		return fail(err)
+	}
+
+	if generated > 0 {
+		counter, err := requestCounterRangeFunc(s, generated)
+		if err != nil {
+			release()
+			return fail(err)
+		}
+		for _, i := range pending {
+			if mappings[i].ShortURLID == "" {
+				mappings[i].ShortURLID = base62Encode(counter)
+				counter++
+			}
+		}
+	}
+
+	toCreate := make([]*dataModel.URLMapping, len(pending))
+	for j, i := range pending {
+		toCreate[j] = mappings[i]
+	}
+	var created []*proto.Event
+	if s.eventRelay != nil {
+		created = make([]*proto.Event, len(toCreate))
+		for j, mapping := range toCreate {
+			created[j] = events.LinkCreated(mapping)
+		}
+	}
+	if err := s.db.CreateURLMappings(toCreate, s.outboxEvents(created...)...); err != nil {
+		log.Printf("Error creating %d mappings for user %d: %v", len(toCreate), user.ID, err)
+		release()
+		return fail(err)
+	}
+	s.notifyEvents()
+	linkEvents := make([]linkEvent, len(pending))
+	for j, i := range pending {
+		results[i].ShortUrl = displayShortURL(mappings[i])
+		linkEvents[j] = newLinkEvent(mappings[i])
+	}
+	s.emitWebhook(user.Workspace.ID, EventLinkCreated, linkEvents...)
+	return results
+}
+
+// dedupeBatch drops the mappings repeating the long URL of an earlier
+// mapping that would be shared, and returns the index of the earlier item
+// by index of the dropped one. As in prepareURLMapping, a repeat asking for
+// a different alias or different redirect settings fails instead.
+func dedupeBatch(mappings []*dataModel.URLMapping, results []*proto.BatchShortenResult) map[int]int {
+	repeats := make(map[int]int)
+	firsts := make(map[string]int)
+	for i, mapping := range mappings {
+		if mapping == nil || !shareable(mapping) {
+			continue
+		}
+		first, ok := firsts[mapping.LongURL]
+		if !ok {
+			firsts[mapping.LongURL] = i
+			continue
+		}
+		existing := mappings[first]
+		switch {
+		case mapping.IsCustomAlias && (!existing.IsCustomAlias || mapping.ShortURLID != existing.ShortURLID):
+			results[i].Error = ErrLongURLExists.Error()
+		case !sameRedirectSettings(existing, mapping):
+			results[i].Error = fmt.Errorf("%w with different settings", ErrLongURLExists).Error()
+		default:
+			repeats[i] = first
+		}
+		mappings[i] = nil
+	}
+	return repeats
+}
+
+// shareable reports whether mapping may be reused for a later request of
+// the same long URL, see prepareURLMapping.
+func shareable(mapping *dataModel.URLMapping) bool {
+	return mapping.MaxClicks == 0 && mapping.Host == "" && mapping.CampaignID == 0 &&
+		mapping.OG.IsZero() && mapping.PasswordHash == ""
+}
+
+// claimCustomAliases fails the items whose custom alias is already taken on
+// their host or requested by an earlier item, and returns the indexes of the
+// mappings left to create.
+func (s *UrlShortenerService) claimCustomAliases(mappings []*dataModel.URLMapping, results []*proto.BatchShortenResult) []int {
+	var pending []int
+	var hosts []string
+	aliases := make(map[string][]string)
+	for i, mapping := range mappings {
+		if mapping == nil {
+			continue
+		}
+		pending = append(pending, i)
+		if mapping.IsCustomAlias {
+			if _, ok := aliases[mapping.Host]; !ok {
+				hosts = append(hosts, mapping.Host)
+			}
+			aliases[mapping.Host] = append(aliases[mapping.Host], mapping.ShortURLID)
+		}
+	}
+	if len(aliases) == 0 {
+		return pending
+	}
+
+	// Aliases are claimed per host, keyed by host and alias.
+	claimed := make(map[[2]string]bool)
+	for _, host := range hosts {
+		taken, err := s.db.FindShortURLIDs(host, aliases[host])
+		if err != nil {
+			log.Printf("Error looking up custom aliases: %v", err)
+			for _, i := range pending {
+				results[i].Error = errorMessage(err)
+			}
+			return nil
+		}
+		for _, alias := range taken {
+			claimed[[2]string{host, alias}] = true
+		}
+	}
+	free := pending[:0]
+	for _, i := range pending {
+		mapping := mappings[i]
+		if mapping.IsCustomAlias {
+			key := [2]string{mapping.Host, mapping.ShortURLID}
+			if claimed[key] {
+				results[i].Error = ErrCustomAliasTaken.Error()
+				continue
+			}
+			claimed[key] = true
+		}
+		free = append(free, i)
+	}
+	return free
+}
+
+// errorMessage returns the message of err without the gRPC status prefix.
+func errorMessage(err error) string {
+	if st, ok := status.FromError(err); ok {
+		return st.Message()
+	}
+	return err.Error()
+}
+
+// csvColumns are the columns a CSV upload may have, named like the
+// ShortenURLRequest fields they set.
+var csvColumns = map[string]func(item *proto.ShortenURLRequest, value string) error{
+	"long_url":     func(item *proto.ShortenURLRequest, v string) error { item.LongUrl = v; return nil },
+	"custom_alias": func(item *proto.ShortenURLRequest, v string) error { item.CustomAlias = v; return nil },
+	"password":     func(item *proto.ShortenURLRequest, v string) error { item.Password = v; return nil },
+	"redirect_type": func(item *proto.ShortenURLRequest, v string) error {
+		if v == "" {
+			return nil
+		}
+		t, ok := proto.RedirectType_value[v]
+		if !ok {
+			return fmt.Errorf("%w: unknown redirect_type %q", ErrInvalidCSV, v)
+		}
+		item.RedirectType = proto.RedirectType(t)
+		return nil
+	},
+	"max_clicks": func(item *proto.ShortenURLRequest, v string) error {
+		if v == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: invalid max_clicks %q", ErrInvalidCSV, v)
+		}
+		item.MaxClicks = n
+		return nil
+	},
+	"query_passthrough": csvBool(func(item *proto.ShortenURLRequest, b bool) { item.QueryPassthrough = b }),
+	"forward_path":      csvBool(func(item *proto.ShortenURLRequest, b bool) { item.ForwardPath = b }),
+	"interstitial":      csvBool(func(item *proto.ShortenURLRequest, b bool) { item.Interstitial = b }),
+}
+
+func csvBool(set func(item *proto.ShortenURLRequest, b bool)) func(*proto.ShortenURLRequest, string) error {
+	return func(item *proto.ShortenURLRequest, v string) error {
+		if v == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%w: invalid boolean %q", ErrInvalidCSV, v)
+		}
+		set(item, b)
+		return nil
+	}
+}
+
+// batchCSVHandler shortens the URLs of an uploaded CSV file. The first row
+// names the columns: long_url is required, the other csvColumns are
+// optional. The file is sent as the request body or as the "file" field of
+// a multipart form, with the API key in the X-API-Key header or the api_key
+// query parameter.
+//
+// Rows are shortened in batches of MaxBatchSize and the results are streamed
+// back as CSV with the columns row, long_url, short_url and error, where row
+// counts the data rows from 1.
+func (s *UrlShortenerService) batchCSVHandler(w http.ResponseWriter, r *http.Request) {
+	apiKey := r.Header.Get("X-API-Key")
+	if apiKey == "" {
+		apiKey = r.URL.Query().Get("api_key")
+	}
+	user, err := s.authorize(apiKey, dataModel.RoleEditor)
+	if err != nil {
+		code := http.StatusUnauthorized
+		if errors.Is(err, ErrPermissionDenied) {
+			code = http.StatusForbidden
+		} else if !errors.Is(err, ErrMissingApiKey) && !errors.Is(err, ErrInvalidApiKey) {
+			code = http.StatusInternalServerError
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxCSVUploadSize)
+	body := io.Reader(r.Body)
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "missing file: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%v: reading header: %v", ErrInvalidCSV, err), http.StatusBadRequest)
+		return
+	}
+	columns := make([]string, len(header))
+	hasLongURL := false
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := csvColumns[name]; !ok {
+			http.Error(w, fmt.Sprintf("%v: unknown column %q", ErrInvalidCSV, name), http.StatusBadRequest)
+			return
+		}
+		columns[i] = name
+		hasLongURL = hasLongURL || name == "long_url"
+	}
+	if !hasLongURL {
+		http.Error(w, fmt.Sprintf("%v: missing long_url column", ErrInvalidCSV), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	out := csv.NewWriter(w)
+	out.Write([]string{"row", "long_url", "short_url", "error"})
+	flush := func() {
+		out.Flush()
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+
+	row := 0
+	var readErr error
+	for done := false; !done; {
+		var items []*proto.ShortenURLRequest
+		// Rows that could not be parsed, by position in items.
+		parseErrors := map[int]error{}
+		for len(items) < MaxBatchSize {
+			record, err := reader.Read()
+			if err == io.EOF {
+				done = true
+				break
+			}
+			if err != nil {
+				// The rest of the upload cannot be trusted, stop after this batch.
+				readErr = err
+				done = true
+				break
+			}
+			item := &proto.ShortenURLRequest{}
+			for i, value := range record {
+				if i >= len(columns) {
+					break
+				}
+				if err := csvColumns[columns[i]](item, strings.TrimSpace(value)); err != nil {
+					parseErrors[len(items)] = err
+				}
+			}
+			items = append(items, item)
+		}
+
+		var toShorten []*proto.ShortenURLRequest
+		for i, item := range items {
+			if parseErrors[i] == nil {
+				toShorten = append(toShorten, item)
+			}
+		}
+		results := s.shortenBatch(r.Context(), user, toShorten)
+		for i, item := range items {
+			row++
+			if err := parseErrors[i]; err != nil {
+				out.Write([]string{strconv.Itoa(row), item.LongUrl, "", err.Error()})
+				continue
+			}
+			result := results[0]
+			results = results[1:]
+			out.Write([]string{strconv.Itoa(row), result.LongUrl, result.ShortUrl, result.Error})
+		}
+		if readErr != nil {
+			out.Write([]string{strconv.Itoa(row + 1), "", "", fmt.Sprintf("%v: %v", ErrInvalidCSV, readErr)})
+		}
+		flush()
+	}
+	if err := out.Error(); err != nil {
+		log.Printf("Error writing CSV results for user %d: %v", user.ID, err)
+	}
+}
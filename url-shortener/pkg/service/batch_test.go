@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/go-zookeeper/zk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestBatchShortenURLs(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) {
+		t.Fatal("batches must not take single counter values")
+		return 0, nil
+	}
+	var rangeSizes []int64
+	requestCounterRangeFunc = func(s *UrlShortenerService, n int64) (int64, error) {
+		rangeSizes = append(rangeSizes, n)
+		return 100, nil
+	}
+	t.Cleanup(func() {
+		requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return s.requestCounter() }
+		requestCounterRangeFunc = func(s *UrlShortenerService, n int64) (int64, error) { return s.requestCounterRange(n) }
+	})
+
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+	mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), "http://example.com/existing").
+		Return(&dataModel.URLMapping{ShortURLID: "old", LongURL: "http://example.com/existing"}, nil).Once()
+	mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	mockDb.On("FindShortURLIDs", "", []string{"taken-alias", "my-alias", "my-alias"}).Return([]string{"taken-alias"}, nil).Once()
+	mockDb.On("CreateURLMappings", mock.MatchedBy(func(m []*dataModel.URLMapping) bool {
+		return len(m) == 3 &&
+			m[0].ShortURLID == base62Encode(100) && m[0].LongURL == "http://example.com/a" &&
+			m[1].ShortURLID == "my-alias" &&
+			m[2].ShortURLID == base62Encode(101) && m[2].LongURL == "http://example.com/c"
+	})).Return(nil).Once()
+
+	resp, err := s.BatchShortenURLs(ctx, &proto.BatchShortenURLsRequest{ApiKey: "key", Items: []*proto.ShortenURLRequest{
+		{LongUrl: "http://example.com/a"},
+		{LongUrl: "ftp://example.com/b"},
+		{LongUrl: "http://example.com/existing"},
+		{LongUrl: "http://example.com/taken", CustomAlias: "taken-alias"},
+		{LongUrl: "http://example.com/mine", CustomAlias: "my-alias"},
+		{LongUrl: "http://example.com/again", CustomAlias: "my-alias"},
+		{LongUrl: "http://example.com/c"},
+	}})
+	assert.NoError(t, err)
+	mockDb.AssertExpectations(t)
+	assert.Equal(t, []int64{2}, rangeSizes)
+
+	got := make([]string, len(resp.Results))
+	for i, r := range resp.Results {
+		assert.Equal(t, int32(i), r.Index)
+		got[i] = r.ShortUrl + "|" + r.Error
+	}
+	assert.Equal(t, base62Encode(100)+"|", got[0])
+	assert.Contains(t, got[1], ErrInvalidURL.Error())
+	assert.Equal(t, "old|", got[2])
+	assert.Equal(t, "|"+ErrCustomAliasTaken.Error(), got[3])
+	assert.Equal(t, "my-alias|", got[4])
+	assert.Equal(t, "|"+ErrCustomAliasTaken.Error(), got[5])
+	assert.Equal(t, base62Encode(101)+"|", got[6])
+}
+
+func TestBatchShortenURLsRepeatedLongURL(t *testing.T) {
+	requestCounterRangeFunc = func(s *UrlShortenerService, n int64) (int64, error) { return 100, nil }
+	t.Cleanup(func() {
+		requestCounterRangeFunc = func(s *UrlShortenerService, n int64) (int64, error) { return s.requestCounterRange(n) }
+	})
+
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	mockDb.On("CreateURLMappings", mock.MatchedBy(func(m []*dataModel.URLMapping) bool {
+		return len(m) == 2 && m[0].LongURL == "http://example.com/a" && m[1].MaxClicks == 1
+	})).Return(nil).Once()
+
+	results := s.shortenBatch(context.Background(), testUser(1), []*proto.ShortenURLRequest{
+		{LongUrl: "http://example.com/a"},
+		{LongUrl: "http://example.com/a"},
+		{LongUrl: "http://example.com/a", CustomAlias: "my-alias"},
+		{LongUrl: "http://example.com/a", Interstitial: true},
+		{LongUrl: "http://example.com/a", MaxClicks: 1},
+	})
+	mockDb.AssertExpectations(t)
+	assert.Equal(t, base62Encode(100), results[0].ShortUrl)
+	assert.Equal(t, base62Encode(100), results[1].ShortUrl)
+	assert.Empty(t, results[1].Error)
+	assert.Equal(t, ErrLongURLExists.Error(), results[2].Error)
+	assert.Contains(t, results[3].Error, "different settings")
+	assert.Equal(t, base62Encode(101), results[4].ShortUrl)
+}
+
+func TestBatchShortenURLsTooLarge(t *testing.T) {
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil).Once()
+
+	_, err := s.BatchShortenURLs(context.Background(), &proto.BatchShortenURLsRequest{
+		ApiKey: "key",
+		Items:  make([]*proto.ShortenURLRequest, MaxBatchSize+1),
+	})
+	assert.ErrorIs(t, err, ErrBatchTooLarge)
+}
+
+func TestBatchCSVHandler(t *testing.T) {
+	requestCounterRangeFunc = func(s *UrlShortenerService, n int64) (int64, error) { return 1, nil }
+	t.Cleanup(func() {
+		requestCounterRangeFunc = func(s *UrlShortenerService, n int64) (int64, error) { return s.requestCounterRange(n) }
+	})
+
+	t.Run("Results are streamed back", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+		mockDb.On("CreateURLMappings", mock.MatchedBy(func(m []*dataModel.URLMapping) bool {
+			return len(m) == 2 && m[1].MaxClicks == 1
+		})).Return(nil).Once()
+
+		body := "long_url,max_clicks\nhttp://example.com/a,\nhttp://example.com/b,x\nhttp://example.com/c,1\n"
+		req := httptest.NewRequest("POST", "/shorten/batch.csv", strings.NewReader(body))
+		req.Header.Set("X-API-Key", "key")
+		rr := httptest.NewRecorder()
+		s.batchCSVHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Equal(t, strings.Join([]string{
+			"row,long_url,short_url,error",
+			"1,http://example.com/a," + base62Encode(1) + ",",
+			`2,http://example.com/b,,"invalid CSV: invalid max_clicks ""x"""`,
+			"3,http://example.com/c," + base62Encode(2) + ",",
+		}, "\n")+"\n", rr.Body.String())
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Missing long_url column", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil).Once()
+
+		req := httptest.NewRequest("POST", "/shorten/batch.csv?api_key=key", strings.NewReader("custom_alias\nabcd\n"))
+		rr := httptest.NewRecorder()
+		s.batchCSVHandler(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Missing API key", func(t *testing.T) {
+		s := &UrlShortenerService{db: new(MockDB)}
+		rr := httptest.NewRecorder()
+		s.batchCSVHandler(rr, httptest.NewRequest("POST", "/shorten/batch.csv", strings.NewReader("long_url\n")))
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestRequestCounterRange(t *testing.T) {
+	t.Run("Range within current batch", func(t *testing.T) {
+		s := &UrlShortenerService{ZookeeperClient: new(MockZookeeperClient), isCounterExists: true}
+		s.currentCounterVal = 5
+		s.uppLimitVal = 10
+		first, err := s.requestCounterRange(5)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(6), first)
+		assert.Equal(t, int64(10), s.currentCounterVal)
+	})
+
+	t.Run("Range larger than a batch", func(t *testing.T) {
+		mockZk := new(MockZookeeperClient)
+		s := &UrlShortenerService{ZookeeperClient: mockZk, isCounterExists: true}
+		s.currentCounterVal = 5
+		s.uppLimitVal = 10
+		mockZk.On("Get", ZkCounterPath).Return([]byte("1000"), &zk.Stat{Version: 1}, nil).Once()
+		mockZk.On("Set", ZkCounterPath, []byte(strconv.Itoa(1000+25000)), int32(1)).Return(&zk.Stat{}, nil).Once()
+
+		first, err := s.requestCounterRange(25000)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1001), first)
+		assert.Equal(t, int64(26000), s.currentCounterVal)
+		mockZk.AssertExpectations(t)
+	})
+}
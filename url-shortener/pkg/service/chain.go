@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"gorm.io/gorm"
+)
+
+// resolveChain follows a destination through short links until it reaches a
+// URL that is neither one of our own short links nor hosted by a known
+// shortener. It returns the URL to store for the new link and the final
+// destination, which are equal unless the chain leaves through another
+// shortener.
+//
+// Our own links are flattened: shortening https://<public host>/d/abc, or
+// https://<branded domain>/abc, stores abc's destination rather than
+// creating a link to a link. With SelfLinkPolicy "reject" they are refused
+// instead. Known shorteners are
+// followed with HEAD requests for at most MaxRedirectChain hops.
+func (s *UrlShortenerService) resolveChain(ctx context.Context, longURL string) (string, string, error) {
+	maxHops := s.Config.MaxRedirectChain
+	if maxHops <= 0 {
+		maxHops = DefaultMaxRedirectChain
+	}
+
+	storeURL, current := longURL, longURL
+	// Links made only of our own hops are stored flattened.
+	flatten := true
+	visited := map[string]bool{}
+	for hops := 0; ; hops++ {
+		if visited[current] {
+			return "", "", fmt.Errorf("%w: %s", ErrRedirectLoop, current)
+		}
+		visited[current] = true
+		if hops > maxHops {
+			return "", "", fmt.Errorf("%w: more than %d hops", ErrRedirectChainTooLong, maxHops)
+		}
+
+		u, err := url.Parse(current)
+		if err != nil {
+			return "", "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+		}
+
+		var next string
+		switch {
+		case s.Config.isPublicHost(u.Hostname()) || s.isBrandedHost(u.Hostname()):
+			if strings.EqualFold(s.Config.SelfLinkPolicy, SelfLinkReject) {
+				return "", "", ErrSelfReferencingURL
+			}
+			next, err = s.resolveOwnLink(u)
+			if err != nil {
+				return "", "", err
+			}
+		case s.Config.isKnownShortener(u.Hostname()):
+			flatten = false
+			if err := s.checkDestinationHost(ctx, current); err != nil {
+				return "", "", err
+			}
+			next, err = s.followRedirect(ctx, current)
+			if err != nil {
+				return "", "", err
+			}
+			if next == "" {
+				return storeURL, current, nil
+			}
+		default:
+			return storeURL, current, nil
+		}
+
+		current, err = s.Config.normalizeURL(next)
+		if err != nil {
+			return "", "", err
+		}
+		if flatten {
+			storeURL = current
+		}
+	}
+}
+
+// resolveOwnLink returns the destination of a short link served by us, on
+// one of our public hosts or a verified branded domain. Password protected,
+// click limited and disabled links are refused, their destination must
+// only be reached through them.
+func (s *UrlShortenerService) resolveOwnLink(u *url.URL) (string, error) {
+	host := ""
+	if !s.Config.isPublicHost(u.Hostname()) {
+		host, _ = normalizeHost(u.Hostname())
+	}
+	shortURL := ownShortCode(u, host != "")
+	if shortURL == "" {
+		return "", fmt.Errorf("%w: %s is not a short link", ErrSelfReferencingURL, u)
+	}
+	var mapping *dataModel.URLMapping
+	var err error
+	if host == "" {
+		mapping, err = s.db.GetURLMapping(shortURL)
+	} else {
+		mapping, err = s.db.GetURLMappingOnHost(host, shortURL)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("%w: unknown short link %s", ErrSelfReferencingURL, shortURL)
+		}
+		return "", err
+	}
+	if mapping.PasswordHash != "" || mapping.MaxClicks > 0 || mapping.Disabled {
+		return "", fmt.Errorf("%w: short link %s is protected", ErrSelfReferencingURL, shortURL)
+	}
+	if mapping.ResolvedURL != "" {
+		return mapping.ResolvedURL, nil
+	}
+	return mapping.LongURL, nil
+}
+
+// ownShortCode extracts the short URL ID from a /d/{shortChar} path, or on
+// a branded domain also from a /{shortChar} path.
+func ownShortCode(u *url.URL, branded bool) string {
+	rest, ok := strings.CutPrefix(u.Path, "/d/")
+	if !ok {
+		if !branded {
+			return ""
+		}
+		rest = strings.TrimPrefix(u.Path, "/")
+	}
+	shortURL, _, _ := strings.Cut(rest, "/")
+	return shortURL
+}
+
+// followRedirect asks a shortener where rawURL points. It returns "" when
+// the response is not a redirect.
+func (s *UrlShortenerService) followRedirect(ctx context.Context, rawURL string) (string, error) {
+	client := *s.client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	resp, err := s.doRequest(ctx, &client, http.MethodHead, rawURL)
+	if err == nil && resp.StatusCode == http.StatusMethodNotAllowed {
+		resp, err = s.doRequest(ctx, &client, http.MethodGet, rawURL)
+	}
+	if err != nil {
+		log.Printf("Error following %s: %v", rawURL, err)
+		return "", fmt.Errorf("%w: %v", ErrUnresolvableRedirect, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return "", nil
+	}
+	location, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s answered %d without a valid Location", ErrUnresolvableRedirect, rawURL, resp.StatusCode)
+	}
+	return location.String(), nil
+}
+
+func (s *UrlShortenerService) doRequest(ctx context.Context, client *http.Client, method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// client returns the HTTP client used to reach destinations.
+func (s *UrlShortenerService) client() *http.Client {
+	if s.httpClient != nil {
+		return s.httpClient
+	}
+	return &http.Client{Timeout: 5 * time.Second}
+}
+
+// isPublicHost reports whether host is one of the hosts this service is served on.
+func (c Config) isPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, h := range c.PublicHosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// isKnownShortener reports whether host belongs to a URL shortener whose
+// links should be followed.
+func (c Config) isKnownShortener(host string) bool {
+	shorteners := c.KnownShorteners
+	if len(shorteners) == 0 {
+		shorteners = DefaultKnownShorteners
+	}
+	return inDomains(host, shorteners)
+}
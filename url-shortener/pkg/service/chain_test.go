@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// newShortener starts a fake URL shortener redirecting each path to the
+// mapped location.
+func newShortener(t *testing.T, redirects map[string]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if location, ok := redirects[r.URL.Path]; ok {
+			w.Header().Set("Location", location)
+			w.WriteHeader(http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestShortenURLRedirectChains(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return 99, nil }
+	cfg := Config{PublicHosts: []string{"sho.rt"}, KnownShorteners: []string{"127.0.0.1"}}
+
+	// newDB returns a mock on which only the branded hosts are verified
+	// domains.
+	newDB := func(branded ...string) *MockDB {
+		mockDb := new(MockDB)
+		for _, host := range branded {
+			mockDb.On("GetVerifiedDomain", host).Return(&dataModel.Domain{Host: host}, nil)
+		}
+		mockDb.On("GetVerifiedDomain", mock.Anything).Return(nil, gorm.ErrRecordNotFound).Maybe()
+		return mockDb
+	}
+	expectCreate := func(mockDb *MockDB, longURL, resolvedURL string) {
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), longURL).Return(nil, gorm.ErrRecordNotFound).Once()
+		mockDb.On("CreateURLMapping", mock.MatchedBy(func(m *dataModel.URLMapping) bool {
+			return m.LongURL == longURL && m.ResolvedURL == resolvedURL
+		})).Return(nil).Once()
+	}
+
+	t.Run("Own short link is flattened", func(t *testing.T) {
+		mockDb := newDB()
+		s := &UrlShortenerService{Config: cfg, db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(&dataModel.URLMapping{LongURL: "https://example.com/x"}, nil).Once()
+		expectCreate(mockDb, "https://example.com/x", "")
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "https://SHO.RT/d/abc"})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Branded short link is flattened", func(t *testing.T) {
+		mockDb := newDB("go.acme.com")
+		s := &UrlShortenerService{Config: cfg, db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMappingOnHost", "go.acme.com", "launch").Return(&dataModel.URLMapping{LongURL: "https://example.com/x"}, nil).Once()
+		expectCreate(mockDb, "https://example.com/x", "")
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "https://go.acme.com/launch"})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Branded short link rejected by policy", func(t *testing.T) {
+		mockDb := newDB("go.acme.com")
+		rejecting := cfg
+		rejecting.SelfLinkPolicy = SelfLinkReject
+		s := &UrlShortenerService{Config: rejecting, db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "https://go.acme.com/d/launch"})
+		assert.ErrorIs(t, err, ErrSelfReferencingURL)
+	})
+
+	for name, mapping := range map[string]*dataModel.URLMapping{
+		"Password protected": {LongURL: "https://example.com/x", PasswordHash: "hash"},
+		"Click limited":      {LongURL: "https://example.com/x", MaxClicks: 1, ClicksRemaining: 1},
+		"Disabled":           {LongURL: "https://example.com/x", Disabled: true},
+	} {
+		t.Run("Own short link not flattened: "+name, func(t *testing.T) {
+			mockDb := newDB()
+			s := &UrlShortenerService{Config: cfg, db: mockDb}
+			mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+			mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+
+			resp, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "https://sho.rt/d/abc"})
+			assert.ErrorIs(t, err, ErrSelfReferencingURL)
+			assert.Nil(t, resp)
+			assert.NotContains(t, err.Error(), "example.com")
+			mockDb.AssertNotCalled(t, "CreateURLMapping", mock.Anything)
+		})
+	}
+
+	t.Run("Own short link rejected by policy", func(t *testing.T) {
+		mockDb := newDB()
+		rejecting := cfg
+		rejecting.SelfLinkPolicy = SelfLinkReject
+		s := &UrlShortenerService{Config: rejecting, db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "https://sho.rt/d/abc"})
+		assert.ErrorIs(t, err, ErrSelfReferencingURL)
+	})
+
+	t.Run("Own host outside of short links", func(t *testing.T) {
+		mockDb := newDB()
+		s := &UrlShortenerService{Config: cfg, db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "https://sho.rt/shorten"})
+		assert.ErrorIs(t, err, ErrSelfReferencingURL)
+	})
+
+	t.Run("Known shortener is followed", func(t *testing.T) {
+		srv := newShortener(t, map[string]string{"/s1": "https://example.org/final"})
+		mockDb := newDB()
+		s := &UrlShortenerService{Config: cfg, db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		expectCreate(mockDb, srv.URL+"/s1", "https://example.org/final")
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: srv.URL + "/s1"})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Shortener pointing at our own link", func(t *testing.T) {
+		srv := newShortener(t, map[string]string{"/s1": "https://sho.rt/d/abc"})
+		mockDb := newDB()
+		s := &UrlShortenerService{Config: cfg, db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(&dataModel.URLMapping{LongURL: "https://example.com/x"}, nil).Once()
+		expectCreate(mockDb, srv.URL+"/s1", "https://example.com/x")
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: srv.URL + "/s1"})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Chain longer than the limit", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", r.URL.Path+"x")
+			w.WriteHeader(http.StatusFound)
+		}))
+		defer srv.Close()
+		mockDb := newDB()
+		limited := cfg
+		limited.MaxRedirectChain = 2
+		s := &UrlShortenerService{Config: limited, db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: srv.URL + "/a"})
+		assert.ErrorIs(t, err, ErrRedirectChainTooLong)
+	})
+
+	t.Run("Redirect loop", func(t *testing.T) {
+		srv := newShortener(t, map[string]string{"/a": "/b", "/b": "/a"})
+		mockDb := newDB()
+		s := &UrlShortenerService{Config: cfg, db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: srv.URL + "/a"})
+		assert.ErrorIs(t, err, ErrRedirectLoop)
+	})
+}
+
+func TestGetURLResolvedURL(t *testing.T) {
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetURLMapping", "abc").Return(&dataModel.URLMapping{
+		LongURL:     "https://bit.ly/xyz",
+		ResolvedURL: "https://example.org/final",
+	}, nil).Once()
+
+	resp, err := s.GetURL(context.Background(), &proto.GetURLRequest{ShortUrl: "abc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://bit.ly/xyz", resp.LongUrl)
+	assert.Equal(t, "https://example.org/final", resp.ResolvedUrl)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+)
+
+// clickCounter adds up the clicks of links between writes to their click
+// counts, sparing a database write per redirect. The zero value is ready to
+// use.
+type clickCounter struct {
+	mu      sync.Mutex
+	pending map[uint]int64
+}
+
+// add counts a click on the mapping with id.
+func (c *clickCounter) add(id uint) {
+	c.addAll(map[uint]int64{id: 1})
+}
+
+// addAll adds counts, keyed by mapping ID, to the pending clicks.
+func (c *clickCounter) addAll(counts map[uint]int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending == nil {
+		c.pending = make(map[uint]int64, len(counts))
+	}
+	for id, n := range counts {
+		c.pending[id] += n
+	}
+}
+
+// take returns the pending clicks and starts counting from zero.
+func (c *clickCounter) take() map[uint]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := c.pending
+	c.pending = nil
+	return counts
+}
+
+// flushClickCounts adds the counted clicks to the click counts of their
+// links every interval until ctx is done. Clicks that could not be written
+// are kept for the next attempt.
+func (s *UrlShortenerService) flushClickCounts(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.writeClickCounts()
+			return
+		case <-ticker.C:
+			s.writeClickCounts()
+		}
+	}
+}
+
+// retainClicks sets the clicks of mappings to those within the analytics
+// retention of the plan of user.
+func (s *UrlShortenerService) retainClicks(user *dataModel.User, mappings []dataModel.URLMapping) error {
+	if len(mappings) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(mappings))
+	for i, m := range mappings {
+		ids[i] = m.ID
+	}
+	clicks, err := s.db.GetLinkClicks(ids, analyticsSince(user, time.Now()))
+	if err != nil {
+		log.Printf("Error adding up clicks of %d links: %v", len(ids), err)
+		return err
+	}
+	for i := range mappings {
+		mappings[i].Clicks = clicks[mappings[i].ID]
+	}
+	return nil
+}
+
+// pruneLinkClicks deletes the clicks of days no plan retains every interval
+// until ctx is done.
+func (s *UrlShortenerService) pruneLinkClicks(ctx context.Context, interval time.Duration) {
+	days := longestRetention()
+	if days == 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		before := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -int(days)+1)
+		if n, err := s.db.PruneLinkClicks(before); err != nil {
+			log.Printf("Error pruning clicks before %s: %v", before.Format(time.DateOnly), err)
+		} else if n > 0 {
+			log.Printf("Pruned %d days of link clicks before %s", n, before.Format(time.DateOnly))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeClickCounts adds the counted clicks to the click counts of their
+// links.
+func (s *UrlShortenerService) writeClickCounts() {
+	counts := s.clickCounts.take()
+	if len(counts) == 0 {
+		return
+	}
+	if err := s.db.AddLinkClicks(counts); err != nil {
+		log.Printf("Error adding up clicks of %d links: %v", len(counts), err)
+		s.clickCounts.addAll(counts)
+	}
+}
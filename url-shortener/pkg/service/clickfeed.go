@@ -0,0 +1,115 @@
+package service
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/events"
+)
+
+// click is a followed short link as seen by click watchers.
+type click struct {
+	events.Click
+	ShortURL        string
+	WorkspaceID     uint
+	At              time.Time
+	ClicksRemaining int64
+}
+
+// newClick describes the visitor sending r.
+func (s *UrlShortenerService) newClick(r *http.Request) events.Click {
+	return events.Click{Referrer: r.Referer(), UserAgent: r.UserAgent(), Location: s.locate(r)}
+}
+
+// recordClick counts a click on mapping and publishes it to the owner's
+// click watchers and webhooks, and to the event bus.
+func (s *UrlShortenerService) recordClick(mapping *dataModel.URLMapping, c events.Click) {
+	s.clickCounts.add(mapping.ID)
+	s.clicks.publish(click{
+		Click:           c,
+		ShortURL:        mapping.ShortURLID,
+		WorkspaceID:     mapping.WorkspaceID,
+		At:              time.Now(),
+		ClicksRemaining: mapping.ClicksRemaining,
+	})
+	event := newLinkEvent(mapping)
+	event.Referrer = c.Referrer
+	event.UserAgent = c.UserAgent
+	event.Country = c.Location.Country
+	event.Region = c.Location.Region
+	event.VariantID = c.VariantID
+	s.emitWebhook(mapping.WorkspaceID, EventLinkClicked, event)
+	if s.eventRelay != nil {
+		s.publishEvents(events.LinkClicked(mapping, c))
+	}
+}
+
+// clickFeed fans clicks out to the WatchClicks streams of the link owners.
+// Watchers only see the clicks served by this replica. The zero value is
+// ready to use.
+type clickFeed struct {
+	mu       sync.Mutex
+	watchers map[uint]map[*clickWatcher]struct{}
+}
+
+// clickWatcher receives the clicks of one user's links.
+type clickWatcher struct {
+	// shortURLs limits the watched links, nil watches all of them.
+	shortURLs map[string]bool
+	events    chan click
+	// dropped counts the clicks skipped because events was full.
+	dropped atomic.Uint64
+}
+
+// watch registers a watcher for the clicks of the links of workspaceID, or
+// only of shortURLs when given. It must be stopped when no longer read.
+func (f *clickFeed) watch(workspaceID uint, shortURLs []string) *clickWatcher {
+	w := &clickWatcher{events: make(chan click, ClickWatchBuffer)}
+	if len(shortURLs) > 0 {
+		w.shortURLs = make(map[string]bool, len(shortURLs))
+		for _, shortURL := range shortURLs {
+			w.shortURLs[shortURL] = true
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.watchers == nil {
+		f.watchers = make(map[uint]map[*clickWatcher]struct{})
+	}
+	if f.watchers[workspaceID] == nil {
+		f.watchers[workspaceID] = make(map[*clickWatcher]struct{})
+	}
+	f.watchers[workspaceID][w] = struct{}{}
+	return w
+}
+
+// stop unregisters a watcher of workspaceID.
+func (f *clickFeed) stop(workspaceID uint, w *clickWatcher) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.watchers[workspaceID], w)
+	if len(f.watchers[workspaceID]) == 0 {
+		delete(f.watchers, workspaceID)
+	}
+}
+
+// publish hands c to the watchers of its owner. It never blocks: watchers
+// that fell behind miss the click and count it as dropped instead.
+func (f *clickFeed) publish(c click) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for w := range f.watchers[c.WorkspaceID] {
+		if w.shortURLs != nil && !w.shortURLs[c.ShortURL] {
+			continue
+		}
+		select {
+		case w.events <- c:
+		default:
+			w.dropped.Add(1)
+		}
+	}
+}
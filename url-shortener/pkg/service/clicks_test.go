@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestShortenURLMaxClicks(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return 99, nil }
+
+	t.Run("Limited link is never deduplicated", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("CreateURLMapping", mock.MatchedBy(func(m *dataModel.URLMapping) bool {
+			return m.MaxClicks == 1 && m.ClicksRemaining == 1
+		})).Return(nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/secret", MaxClicks: 1})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Negative limit", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/", MaxClicks: -1})
+		assert.Equal(t, ErrInvalidMaxClicks, err)
+	})
+}
+
+func TestClickLimitedRedirect(t *testing.T) {
+	limited := func(remaining int64) *dataModel.URLMapping {
+		return &dataModel.URLMapping{ShortURLID: "abc", LongURL: "https://example.com/secret", MaxClicks: 1, ClicksRemaining: remaining}
+	}
+	serve := func(s *UrlShortenerService) *httptest.ResponseRecorder {
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/d/abc", nil), map[string]string{"shortChar": "abc"})
+		rr := httptest.NewRecorder()
+		s.redirectHandler(rr, req)
+		return rr
+	}
+
+	t.Run("Last click redirects", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetURLMapping", "abc").Return(limited(1), nil).Once()
+		mockDb.On("ConsumeClick", "", "abc").Return(int64(0), nil).Once()
+
+		rr := serve(s)
+		assert.Equal(t, http.StatusFound, rr.Code)
+		assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Click lost to a concurrent redirect", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetURLMapping", "abc").Return(limited(1), nil).Once()
+		mockDb.On("ConsumeClick", "", "abc").Return(int64(0), dataModel.ErrLinkExhausted).Once()
+
+		assert.Equal(t, http.StatusGone, serve(s).Code)
+	})
+
+	t.Run("Exhausted link is gone", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetURLMapping", "abc").Return(limited(0), nil).Once()
+
+		assert.Equal(t, http.StatusGone, serve(s).Code)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("GetURL reports remaining clicks without using one up", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mapping := limited(3)
+		mapping.MaxClicks = 5
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+
+		resp, err := s.GetURL(context.Background(), &proto.GetURLRequest{ShortUrl: "abc"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), resp.MaxClicks)
+		assert.Equal(t, int64(3), resp.ClicksRemaining)
+		mockDb.AssertNotCalled(t, "ConsumeClick", mock.Anything, mock.Anything)
+	})
+}
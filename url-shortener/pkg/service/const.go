@@ -1,6 +1,9 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 const (
 	PostgresHost     = "POSTGRES_HOST"
@@ -18,9 +21,344 @@ const (
 
 	GrpcPort = "GRPC_PORT"
 	HttpPort = "HTTP_PORT"
+
+	// EnforceQuotas disables plan quotas when set to "false".
+	EnforceQuotas = "ENFORCE_QUOTAS"
+
+	// AllowedSchemes is a comma separated list of URL schemes that may be shortened.
+	AllowedSchemes = "ALLOWED_SCHEMES"
+	// MaxURLLength caps the length of destination URLs.
+	MaxURLLength = "MAX_URL_LENGTH"
+	// KeepURLFragments keeps "#fragment" parts of destinations when set to "true".
+	KeepURLFragments = "KEEP_URL_FRAGMENTS"
+
+	// PolicyFile is the path of a block/allow rules file, see policy.FileSource.
+	PolicyFile = "POLICY_FILE"
+	// PolicyReloadInterval is how often policy rules are reloaded, e.g. "30s".
+	PolicyReloadInterval = "POLICY_RELOAD_INTERVAL"
+
+	// PublicHosts is a comma separated list of the hosts this service is reachable on.
+	PublicHosts = "PUBLIC_HOSTS"
+	// PublicBaseURL is the URL short links are served under, e.g.
+	// "https://sho.rt". Defaults to https on the first of PublicHosts.
+	PublicBaseURL = "PUBLIC_BASE_URL"
+	// BlockedHostCategories is a comma separated list of netguard categories
+	// destinations may not resolve to; "none" disables the check.
+	BlockedHostCategories = "BLOCKED_HOST_CATEGORIES"
+	// DestinationExceptions is a comma separated list of hosts and CIDR ranges
+	// exempt from the destination host check.
+	DestinationExceptions = "DESTINATION_EXCEPTIONS"
+
+	// SelfLinkPolicy is "resolve" (default) to store the destination of our own
+	// short links when they are shortened again, or "reject" to refuse them.
+	SelfLinkPolicy = "SELF_LINK_POLICY"
+	// KnownShorteners is a comma separated list of shortener domains whose
+	// links are followed to their final destination.
+	KnownShorteners = "KNOWN_SHORTENERS"
+	// MaxRedirectChain limits how many short links a destination may go through.
+	MaxRedirectChain = "MAX_REDIRECT_CHAIN"
+
+	// LinkCookieSecret signs the cookies unlocking password protected links.
+	// A random secret is generated when unset, so cookies do not survive
+	// restarts and are not shared between replicas.
+	LinkCookieSecret = "LINK_COOKIE_SECRET"
+	// LinkCookieTTL is how long an unlocked password protected link stays
+	// unlocked, e.g. "15m".
+	LinkCookieTTL = "LINK_COOKIE_TTL"
+	// MaxPasswordAttempts is how many wrong passwords a client may try per
+	// link within PasswordAttemptWindow. Each replica counts on its own.
+	MaxPasswordAttempts = "MAX_PASSWORD_ATTEMPTS"
+
+	// InterstitialDomains is a comma separated list of flagged domains whose
+	// links always show the preview page instead of redirecting.
+	InterstitialDomains = "INTERSTITIAL_DOMAINS"
+	// CrawlerUserAgents is a comma separated list of user agent substrings,
+	// matched ignoring case, of crawlers that get the OpenGraph page of links
+	// having one instead of a redirect.
+	CrawlerUserAgents = "CRAWLER_USER_AGENTS"
+
+	// WebhookMaxAttempts is how often a webhook delivery is tried before it
+	// is dead lettered.
+	WebhookMaxAttempts = "WEBHOOK_MAX_ATTEMPTS"
+	// WebhookTimeout bounds a single webhook request, e.g. "10s".
+	WebhookTimeout = "WEBHOOK_TIMEOUT"
+
+	// EventPublisher selects where domain events are published: "redis" for
+	// a Redis stream, "file" for an NDJSON file, "memory" for in-process
+	// subscribers only, or "none", the default.
+	EventPublisher = "EVENT_PUBLISHER"
+	// EventStream is the Redis stream events are added to.
+	EventStream = "EVENT_STREAM"
+	// EventFile is the file events are appended to.
+	EventFile = "EVENT_FILE"
+
+	// GeoIPDatabase is the path of a MaxMind DB (.mmdb) or CSV range file
+	// (.csv) locating visitors by IP address, see geoip.Open.
+	GeoIPDatabase = "GEOIP_DB"
+	// TrustedProxies is a comma separated list of proxy addresses and CIDR
+	// ranges whose X-Forwarded-For headers are believed.
+	TrustedProxies = "TRUSTED_PROXIES"
+
+	// LinkCheckInterval is how often each link destination is checked for
+	// being broken, e.g. "12h". "0" turns the checks off.
+	LinkCheckInterval = "LINK_CHECK_INTERVAL"
+	// LinkCheckConcurrency is how many hosts are checked in parallel.
+	LinkCheckConcurrency = "LINK_CHECK_CONCURRENCY"
+	// LinkCheckDomainDelay is the least time between two checks of
+	// destinations on one host, e.g. "2s".
+	LinkCheckDomainDelay = "LINK_CHECK_DOMAIN_DELAY"
 )
 
+const (
+	// DefaultPlan is assigned to users without a known plan.
+	DefaultPlan = "free"
+	// QuotaErrorDomain is the ErrorInfo domain of quota violations.
+	QuotaErrorDomain = "url-shortener"
+
+	// DefaultMaxURLLength is used when MAX_URL_LENGTH is not set.
+	DefaultMaxURLLength = 2048
+
+	// DefaultPolicyReloadInterval is used when POLICY_RELOAD_INTERVAL is not set.
+	DefaultPolicyReloadInterval = 30 * time.Second
+
+	// SelfLinkReject is the SELF_LINK_POLICY refusing links to our own links.
+	SelfLinkReject = "reject"
+	// DefaultMaxRedirectChain is used when MAX_REDIRECT_CHAIN is not set.
+	DefaultMaxRedirectChain = 3
+
+	// DefaultLinkCookieTTL is used when LINK_COOKIE_TTL is not set.
+	DefaultLinkCookieTTL = 15 * time.Minute
+	// DefaultMaxPasswordAttempts is used when MAX_PASSWORD_ATTEMPTS is not set.
+	DefaultMaxPasswordAttempts = 5
+	// PasswordAttemptWindow is the period failed password attempts are counted over.
+	PasswordAttemptWindow = 15 * time.Minute
+	// MaxPasswordAttemptKeys caps the links and clients failed password
+	// attempts are counted for. Past it, those failing longest ago are
+	// forgotten first.
+	MaxPasswordAttemptKeys = 100000
+	// MaxLinkPasswordLength is the longest password bcrypt can hash.
+	MaxLinkPasswordLength = 72
+
+	// QRCodeCacheTTL is how long rendered QR codes are cached in Redis.
+	QRCodeCacheTTL = 24 * time.Hour
+
+	// MaxBatchSize is the most items BatchShortenURLs takes. CSV uploads are
+	// processed in batches of this size.
+	MaxBatchSize = 1000
+	// BatchConcurrency is how many batch items are validated at once.
+	BatchConcurrency = 16
+	// MaxCSVUploadSize caps the size of uploaded CSV files in bytes.
+	MaxCSVUploadSize = 64 << 20
+
+	// StreamConcurrency is how many StreamShorten items are in flight per
+	// stream. No further requests are read until one of them is answered.
+	StreamConcurrency = 16
+	// StreamItemTimeout bounds the destination checks of a StreamShorten item.
+	StreamItemTimeout = 30 * time.Second
+	// StreamIdleTimeout ends a StreamShorten stream that sent no request
+	// for that long.
+	StreamIdleTimeout = 5 * time.Minute
+	// ClickWatchBuffer is how many click events are queued per WatchClicks
+	// stream before further events are dropped.
+	ClickWatchBuffer = 256
+
+	// WebhookPollInterval is how often queued webhook deliveries are checked
+	// for being due.
+	WebhookPollInterval = 5 * time.Second
+	// MaxWebhooksPerWorkspace caps the webhook subscriptions of a workspace.
+	MaxWebhooksPerWorkspace = 10
+	// WebhookCacheTTL is how long the webhook subscriptions of a workspace
+	// are remembered. Changes made on another replica show after it.
+	WebhookCacheTTL = time.Minute
+	// DefaultWebhookListLimit and MaxWebhookListLimit bound how many
+	// deliveries or dead letters are listed at once.
+	DefaultWebhookListLimit = 50
+	MaxWebhookListLimit     = 500
+
+	// DefaultEventStream is used when EVENT_STREAM is not set.
+	DefaultEventStream = "url-shortener:events"
+	// EventStreamMaxLen approximately caps the length of the event stream.
+	EventStreamMaxLen = 1000000
+	// EventPollInterval is how often the outbox is checked for events that
+	// failed to publish.
+	EventPollInterval = 5 * time.Second
+
+	// MaxDomainsPerWorkspace caps the branded domains of a workspace.
+	MaxDomainsPerWorkspace = 5
+	// DomainVerificationLabel is prepended to a branded domain to name its
+	// verification TXT record.
+	DomainVerificationLabel = "_url-shortener-challenge"
+	// DomainVerificationPrefix starts the value of the verification TXT record.
+	DomainVerificationPrefix = "url-shortener-verification="
+	// DomainCacheTTL is how long request hosts are remembered as being a
+	// verified branded domain or not.
+	DomainCacheTTL = time.Minute
+	// DomainLookupTimeout bounds the DNS lookup verifying a domain.
+	DomainLookupTimeout = 10 * time.Second
+
+	// MaxRoutingRulesPerLink caps the routing rules of a link.
+	MaxRoutingRulesPerLink = 20
+
+	// MaxLinkVariants caps the variants a link splits its traffic across.
+	MaxLinkVariants = 10
+	// MaxVariantWeight caps the weight of a variant.
+	MaxVariantWeight = 10000
+	// VariantCookieTTL is how long a visitor keeps their variant of a link
+	// with cookie stickiness.
+	VariantCookieTTL = 30 * 24 * time.Hour
+
+	// MaxScheduledDestinations caps the schedule of a link.
+	MaxScheduledDestinations = 20
+
+	// MaxLinkGroupsPerWorkspace caps the tags, campaigns and folders of a
+	// workspace.
+	MaxLinkGroupsPerWorkspace = 1000
+	// MaxLinkGroupName is the longest name of a link group, in characters.
+	MaxLinkGroupName = 64
+	// DefaultLinksPageSize and MaxLinksPageSize bound the pages of ListLinks.
+	DefaultLinksPageSize = 50
+	MaxLinksPageSize     = 1000
+	// LinkGroupTopLinks is how many links and domains link group stats show.
+	LinkGroupTopLinks = 10
+	// ClickCountFlushInterval is how often counted clicks are added to the
+	// clicks of their links.
+	ClickCountFlushInterval = 10 * time.Second
+	// PolicyBatchSize is how many links are checked against the policy at
+	// once when its rules change.
+	PolicyBatchSize = 500
+	// ClickPruneInterval is how often clicks older than the analytics
+	// retention of every plan are deleted.
+	ClickPruneInterval = time.Hour
+
+	// MaxUTMRules caps the UTM rules of a user.
+	MaxUTMRules = 100
+	// MaxUTMValueLength is the longest UTM parameter value, in characters.
+	MaxUTMValueLength = 200
+
+	// MaxOGTitleLength and MaxOGDescriptionLength are the longest OpenGraph
+	// title and description, in characters.
+	MaxOGTitleLength       = 200
+	MaxOGDescriptionLength = 1000
+
+	// LinkCheckPollInterval is how often link destinations due to be
+	// checked are looked for.
+	LinkCheckPollInterval = time.Minute
+
+	// MaxWorkspacesPerUser caps the workspaces a user is a member of.
+	MaxWorkspacesPerUser = 100
+	// MaxWorkspaceName is the longest name of a workspace, in characters.
+	MaxWorkspaceName = 64
+)
+
+// Webhook events about links.
+const (
+	EventLinkCreated = "link.created"
+	EventLinkUpdated = "link.updated"
+	EventLinkDeleted = "link.deleted"
+	EventLinkExpired = "link.expired"
+	EventLinkClicked = "link.clicked"
+	EventLinkBroken  = "link.broken"
+)
+
+// WebhookEvents lists the events webhooks may subscribe to.
+var WebhookEvents = []string{EventLinkCreated, EventLinkUpdated, EventLinkDeleted, EventLinkExpired, EventLinkClicked, EventLinkBroken}
+
+// How visitors keep their variant of a link.
+const (
+	VariantStickinessCookie = "cookie"
+	VariantStickinessIP     = "ip"
+)
+
+// DefaultAllowedSchemes is used when ALLOWED_SCHEMES is not set.
+var DefaultAllowedSchemes = []string{"http", "https"}
+
+// DefaultKnownShorteners is used when KNOWN_SHORTENERS is not set.
+var DefaultKnownShorteners = []string{
+	"bit.ly", "bitly.com", "t.co", "tinyurl.com", "goo.gl", "ow.ly", "is.gd",
+	"buff.ly", "rebrand.ly", "cutt.ly", "shorturl.at", "t.ly", "rb.gy", "tiny.cc",
+}
+
+// DefaultCrawlerUserAgents is used when CRAWLER_USER_AGENTS is not set. It
+// lists the link unfurlers of common chat apps and social networks.
+var DefaultCrawlerUserAgents = []string{
+	"facebookexternalhit", "facebot", "twitterbot", "linkedinbot", "slackbot",
+	"slack-imgproxy", "discordbot", "telegrambot", "whatsapp", "skypeuripreview",
+	"microsoftpreview", "teams", "pinterest", "pinterestbot", "redditbot", "mastodon", "embedly",
+	"iframely", "vkshare", "google-pagerenderer", "applebot",
+}
+
 var (
 	ErrMissingApiKey = errors.New("missing API key")
 	ErrInvalidApiKey = errors.New("invalid API key")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	ErrInvalidURL    = errors.New("invalid URL")
+	ErrLongURLExists = errors.New("long URL is already shortened")
+
+	ErrInvalidRedirectType = errors.New("invalid redirect type")
+
+	ErrInvalidLinkPassword     = errors.New("link password must be at most 72 bytes")
+	ErrPasswordRequired        = errors.New("link is password protected")
+	ErrWrongPassword           = errors.New("wrong link password")
+	ErrTooManyPasswordAttempts = errors.New("too many wrong link passwords, try again later")
+
+	ErrInvalidMaxClicks = errors.New("max clicks must not be negative")
+
+	ErrNoPublicHost = errors.New("no public host configured")
+
+	ErrBatchTooLarge    = errors.New("too many items in batch")
+	ErrCustomAliasTaken = errors.New("custom alias is already taken")
+	ErrInvalidCSV       = errors.New("invalid CSV")
+	ErrStreamIdle       = errors.New("no request received for too long")
+
+	ErrInvalidWebhook  = errors.New("invalid webhook")
+	ErrTooManyWebhooks = errors.New("too many webhooks")
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	ErrInvalidDomain     = errors.New("invalid domain")
+	ErrTooManyDomains    = errors.New("too many domains")
+	ErrDomainNotFound    = errors.New("domain not found")
+	ErrDomainNotVerified = errors.New("domain is not verified")
+
+	ErrInvalidRoutingRule  = errors.New("invalid routing rule")
+	ErrTooManyRoutingRules = errors.New("too many routing rules")
+	ErrRoutingRuleNotFound = errors.New("routing rule not found")
+	ErrLinkNotFound        = errors.New("link not found")
+
+	ErrInvalidVariants = errors.New("invalid variants")
+	ErrVariantNotFound = errors.New("variant not found")
+
+	ErrInvalidSchedule = errors.New("invalid schedule")
+
+	ErrInvalidLinkGroup  = errors.New("invalid link group")
+	ErrTooManyLinkGroups = errors.New("too many link groups")
+	ErrLinkGroupNotFound = errors.New("link group not found")
+	ErrInvalidPageToken  = errors.New("invalid page token")
+
+	ErrInvalidUTM     = errors.New("invalid UTM parameters")
+	ErrMissingUTM     = errors.New("missing required UTM parameters")
+	ErrInvalidUTMRule = errors.New("invalid UTM rule")
+
+	ErrInvalidOpenGraph = errors.New("invalid OpenGraph metadata")
+
+	ErrInvalidWorkspace  = errors.New("invalid workspace")
+	ErrTooManyWorkspaces = errors.New("too many workspaces")
+	ErrInvalidRole       = errors.New("invalid role")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrMemberNotFound    = errors.New("member not found")
+	ErrAlreadyMember     = errors.New("user is already a member")
+	ErrLastOwner         = errors.New("workspace must keep an owner")
+
+	ErrBlockedDestination = errors.New("destination is blocked")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrInvalidPolicyRule  = errors.New("invalid policy rule")
+	ErrPolicyRuleNotFound = errors.New("policy rule not found")
+
+	ErrInternalDestination = errors.New("destination is not publicly reachable")
+
+	ErrSelfReferencingURL   = errors.New("destination points back at this service")
+	ErrRedirectLoop         = errors.New("destination redirects in a loop")
+	ErrRedirectChainTooLong = errors.New("destination goes through too many short links")
+	ErrUnresolvableRedirect = errors.New("could not resolve short link destination")
+
+	ErrInvalidCustomAlias = errors.New("custom alias must be 4-32 characters of letters, digits, '-' or '_', and not look like a generated code")
 )
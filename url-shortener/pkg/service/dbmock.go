@@ -3,8 +3,10 @@ package service
 
 
 import (
+	"time"
+
 	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
-	
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -23,7 +25,10 @@ func (m *MockDB) AutoMigrate(dst ...interface{}) error {
 	return args.Error(0)
 }
 
-func (m *MockDB) CreateURLMapping(urlMapping *dataModel.URLMapping) error {
+func (m *MockDB) CreateURLMapping(urlMapping *dataModel.URLMapping, events ...*dataModel.OutboxEvent) error {
+	if len(events) > 0 {
+		return m.Called(urlMapping, events).Error(0)
+	}
 	args := m.Called(urlMapping)
 	return args.Error(0)
 }
@@ -33,6 +38,51 @@ func (m *MockDB) GetLongURL(shortURLID string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockDB) CreateURLMappings(mappings []*dataModel.URLMapping, events ...*dataModel.OutboxEvent) error {
+	if len(events) > 0 {
+		return m.Called(mappings, events).Error(0)
+	}
+	args := m.Called(mappings)
+	return args.Error(0)
+}
+
+func (m *MockDB) FindShortURLIDs(host string, shortURLIDs []string) ([]string, error) {
+	args := m.Called(host, shortURLIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockDB) GetURLMapping(shortURLID string) (*dataModel.URLMapping, error) {
+	args := m.Called(shortURLID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dataModel.URLMapping), args.Error(1)
+}
+
+func (m *MockDB) GetURLMappingOnHost(host, shortURLID string) (*dataModel.URLMapping, error) {
+	args := m.Called(host, shortURLID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dataModel.URLMapping), args.Error(1)
+}
+
+func (m *MockDB) ConsumeClick(host, shortURLID string) (int64, error) {
+	args := m.Called(host, shortURLID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDB) GetURLMappingByLongURL(workspaceID uint, longURL string) (*dataModel.URLMapping, error) {
+	args := m.Called(workspaceID, longURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dataModel.URLMapping), args.Error(1)
+}
+
 func (m *MockDB) CreateUser(user *dataModel.User) error {
 	args := m.Called(user)
 	if args.Error(0) == nil {
@@ -63,4 +113,426 @@ func (m *MockDB) CheckAPIKey(apiKey string) (bool, error) {
 func (m* MockDB) GetTopDomains(limit int) ([]dataModel.DomainCount, error){
 	args := m.Called(limit)
 	return args.Get(0).([]dataModel.DomainCount), args.Error(1)
-}
\ No newline at end of file
+}
+
+func (m *MockDB) GetUserByAPIKey(apiKey string) (*dataModel.User, error) {
+	args := m.Called(apiKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dataModel.User), args.Error(1)
+}
+
+func (m *MockDB) GetUserByID(id uint) (*dataModel.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dataModel.User), args.Error(1)
+}
+
+func (m *MockDB) GetUsage(userID uint, cycleStart time.Time) (*dataModel.UsageCounter, error) {
+	args := m.Called(userID, cycleStart)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dataModel.UsageCounter), args.Error(1)
+}
+
+func (m *MockDB) ConsumeUsage(userID uint, cycleStart time.Time, delta dataModel.UsageDelta, limits dataModel.UsageLimits) (bool, error) {
+	args := m.Called(userID, cycleStart, delta, limits)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDB) ReleaseUsage(userID uint, cycleStart time.Time, delta dataModel.UsageDelta) error {
+	args := m.Called(userID, cycleStart, delta)
+	return args.Error(0)
+}
+
+func (m *MockDB) CreateWebhookSubscription(sub *dataModel.WebhookSubscription) error {
+	args := m.Called(sub)
+	if args.Error(0) == nil {
+		sub.ID = 1 // Simulate GORM behavior
+	}
+	return args.Error(0)
+}
+
+func (m *MockDB) ListWebhookSubscriptions(workspaceID uint) ([]dataModel.WebhookSubscription, error) {
+	args := m.Called(workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockDB) DeleteWebhookSubscription(workspaceID, id uint) error {
+	args := m.Called(workspaceID, id)
+	return args.Error(0)
+}
+
+func (m *MockDB) CreateWebhookDeliveries(deliveries []*dataModel.WebhookDelivery) error {
+	args := m.Called(deliveries)
+	return args.Error(0)
+}
+
+func (m *MockDB) ClaimWebhookDeliveries(now time.Time, limit int, lease time.Duration) ([]dataModel.WebhookDelivery, error) {
+	args := m.Called(now, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockDB) UpdateWebhookDelivery(d *dataModel.WebhookDelivery) error {
+	args := m.Called(d)
+	return args.Error(0)
+}
+
+func (m *MockDB) ListWebhookDeliveries(workspaceID, subscriptionID uint, status string, limit int) ([]dataModel.WebhookDelivery, error) {
+	args := m.Called(workspaceID, subscriptionID, status, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockDB) ListWebhookDeadLetters(workspaceID uint, limit int) ([]dataModel.WebhookDeadLetter, error) {
+	args := m.Called(workspaceID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.WebhookDeadLetter), args.Error(1)
+}
+
+func (m *MockDB) InsertOutboxEvents(events []*dataModel.OutboxEvent) error {
+	args := m.Called(events)
+	return args.Error(0)
+}
+
+func (m *MockDB) PublishOutboxEvents(limit int, publish func(*dataModel.OutboxEvent) error) (int, error) {
+	args := m.Called(limit, publish)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDB) CreatePolicyRule(rule *dataModel.PolicyRule) error {
+	args := m.Called(rule)
+	if args.Error(0) == nil {
+		rule.ID = 1 // Simulate GORM behavior
+	}
+	return args.Error(0)
+}
+
+func (m *MockDB) DeletePolicyRule(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDB) ListPolicyRules() ([]dataModel.PolicyRule, error) {
+	args := m.Called()
+	return args.Get(0).([]dataModel.PolicyRule), args.Error(1)
+}
+
+func (m *MockDB) ListPolicyCandidates(scope dataModel.PolicyScope, afterID uint, limit int) ([]dataModel.URLMapping, error) {
+	args := m.Called(scope, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.URLMapping), args.Error(1)
+}
+
+func (m *MockDB) DisableURLMappings(ids []uint, ruleID uint, reason string) (int64, error) {
+	args := m.Called(ids, ruleID, reason)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDB) EnableURLMappings(ids []uint) (int64, error) {
+	args := m.Called(ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDB) CreateDomain(domain *dataModel.Domain) error {
+	args := m.Called(domain)
+	if args.Error(0) == nil {
+		domain.ID = 1 // Simulate GORM behavior
+	}
+	return args.Error(0)
+}
+
+func (m *MockDB) ListDomains(workspaceID uint) ([]dataModel.Domain, error) {
+	args := m.Called(workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.Domain), args.Error(1)
+}
+
+func (m *MockDB) GetDomain(workspaceID, id uint) (*dataModel.Domain, error) {
+	args := m.Called(workspaceID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dataModel.Domain), args.Error(1)
+}
+
+func (m *MockDB) GetVerifiedDomain(host string) (*dataModel.Domain, error) {
+	args := m.Called(host)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dataModel.Domain), args.Error(1)
+}
+
+func (m *MockDB) VerifyDomain(domain *dataModel.Domain, now time.Time) error {
+	args := m.Called(domain, now)
+	if args.Error(0) == nil {
+		domain.VerifiedAt = &now
+	}
+	return args.Error(0)
+}
+
+func (m *MockDB) DeleteDomain(workspaceID, id uint) error {
+	args := m.Called(workspaceID, id)
+	return args.Error(0)
+}
+
+func (m *MockDB) CreateRoutingRule(rule *dataModel.RoutingRule) error {
+	args := m.Called(rule)
+	if args.Error(0) == nil {
+		rule.ID = 1 // Simulate GORM behavior
+	}
+	return args.Error(0)
+}
+
+func (m *MockDB) ListRoutingRules(mappingID uint) ([]dataModel.RoutingRule, error) {
+	args := m.Called(mappingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.RoutingRule), args.Error(1)
+}
+
+func (m *MockDB) DeleteRoutingRule(mappingID, id uint) error {
+	args := m.Called(mappingID, id)
+	return args.Error(0)
+}
+
+func (m *MockDB) ReplaceLinkVariants(mappingID uint, stickiness string, variants []dataModel.LinkVariant) error {
+	args := m.Called(mappingID, stickiness, variants)
+	return args.Error(0)
+}
+
+func (m *MockDB) ListLinkVariants(mappingID uint) ([]dataModel.LinkVariant, error) {
+	args := m.Called(mappingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.LinkVariant), args.Error(1)
+}
+
+func (m *MockDB) RecordVariantClick(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDB) RecordVariantConversion(mappingID, id uint) error {
+	args := m.Called(mappingID, id)
+	return args.Error(0)
+}
+
+func (m *MockDB) ReplaceSchedule(mappingID uint, schedule []dataModel.ScheduledDestination) error {
+	args := m.Called(mappingID, schedule)
+	return args.Error(0)
+}
+
+func (m *MockDB) ListSchedule(mappingID uint) ([]dataModel.ScheduledDestination, error) {
+	args := m.Called(mappingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.ScheduledDestination), args.Error(1)
+}
+
+func (m *MockDB) CreateLinkGroup(group *dataModel.LinkGroup) error {
+	args := m.Called(group)
+	if args.Error(0) == nil {
+		group.ID = 1 // Simulate GORM behavior
+	}
+	return args.Error(0)
+}
+
+func (m *MockDB) ListLinkGroups(workspaceID uint, kind string) ([]dataModel.LinkGroup, error) {
+	args := m.Called(workspaceID, kind)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.LinkGroup), args.Error(1)
+}
+
+func (m *MockDB) GetLinkGroup(workspaceID, id uint) (*dataModel.LinkGroup, error) {
+	args := m.Called(workspaceID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dataModel.LinkGroup), args.Error(1)
+}
+
+func (m *MockDB) RenameLinkGroup(group *dataModel.LinkGroup, name string) error {
+	args := m.Called(group, name)
+	if args.Error(0) == nil {
+		group.Name = name
+	}
+	return args.Error(0)
+}
+
+func (m *MockDB) FindWorkspaceURLMappings(workspaceID uint, host string, shortURLIDs []string) ([]dataModel.URLMapping, error) {
+	args := m.Called(workspaceID, host, shortURLIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.URLMapping), args.Error(1)
+}
+
+func (m *MockDB) AssignLinks(group *dataModel.LinkGroup, mappingIDs []uint, assign bool) (int64, error) {
+	args := m.Called(group, mappingIDs, assign)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDB) ListLinkTags(mappingIDs []uint) ([]dataModel.LinkTag, error) {
+	args := m.Called(mappingIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.LinkTag), args.Error(1)
+}
+
+func (m *MockDB) ListWorkspaceURLMappings(workspaceID uint, filter dataModel.LinkFilter) ([]dataModel.URLMapping, error) {
+	args := m.Called(workspaceID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.URLMapping), args.Error(1)
+}
+
+func (m *MockDB) GetLinkGroupStats(group *dataModel.LinkGroup, top int, since time.Time) (*dataModel.LinkGroupStats, error) {
+	args := m.Called(group, top, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dataModel.LinkGroupStats), args.Error(1)
+}
+
+func (m *MockDB) AddLinkClicks(counts map[uint]int64) error {
+	args := m.Called(counts)
+	return args.Error(0)
+}
+
+func (m *MockDB) GetLinkClicks(mappingIDs []uint, since time.Time) (map[uint]int64, error) {
+	args := m.Called(mappingIDs, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uint]int64), args.Error(1)
+}
+
+func (m *MockDB) PruneLinkClicks(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDB) SetCampaignUTM(group *dataModel.LinkGroup, utm dataModel.UTMParams) error {
+	args := m.Called(group, utm)
+	if args.Error(0) == nil {
+		group.UTM = utm
+	}
+	return args.Error(0)
+}
+
+func (m *MockDB) ReplaceUTMRules(workspaceID uint, rules []dataModel.UTMRule) error {
+	args := m.Called(workspaceID, rules)
+	return args.Error(0)
+}
+
+func (m *MockDB) ListUTMRules(workspaceID uint) ([]dataModel.UTMRule, error) {
+	args := m.Called(workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.UTMRule), args.Error(1)
+}
+
+func (m *MockDB) GetUTMStats(workspaceID, campaignID uint, since time.Time) ([]dataModel.UTMStats, error) {
+	args := m.Called(workspaceID, campaignID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.UTMStats), args.Error(1)
+}
+
+func (m *MockDB) SetLinkOpenGraph(mapping *dataModel.URLMapping, og dataModel.OpenGraph) error {
+	args := m.Called(mapping, og)
+	if args.Error(0) == nil {
+		mapping.OG = og
+	}
+	return args.Error(0)
+}
+
+func (m *MockDB) ClaimLinkChecks(now time.Time, limit int, lease time.Duration) ([]dataModel.URLMapping, error) {
+	args := m.Called(now, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.URLMapping), args.Error(1)
+}
+
+func (m *MockDB) RecordLinkCheck(mapping *dataModel.URLMapping) error {
+	args := m.Called(mapping)
+	return args.Error(0)
+}
+
+func (m *MockDB) CreateWorkspace(workspace *dataModel.Workspace, userID uint) (*dataModel.WorkspaceMember, error) {
+	args := m.Called(workspace, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	workspace.ID = 2 // Simulate GORM behavior
+	return args.Get(0).(*dataModel.WorkspaceMember), args.Error(1)
+}
+
+func (m *MockDB) ListUserWorkspaces(userID uint) ([]dataModel.WorkspaceMembership, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.WorkspaceMembership), args.Error(1)
+}
+
+func (m *MockDB) ListWorkspaceMembers(workspaceID uint) ([]dataModel.MemberUser, error) {
+	args := m.Called(workspaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dataModel.MemberUser), args.Error(1)
+}
+
+func (m *MockDB) GetWorkspaceMember(workspaceID, userID uint) (*dataModel.WorkspaceMember, error) {
+	args := m.Called(workspaceID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dataModel.WorkspaceMember), args.Error(1)
+}
+
+func (m *MockDB) AddWorkspaceMember(member *dataModel.WorkspaceMember) error {
+	args := m.Called(member)
+	return args.Error(0)
+}
+
+func (m *MockDB) SetWorkspaceMemberRole(workspaceID, userID uint, role string) error {
+	args := m.Called(workspaceID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockDB) RemoveWorkspaceMember(workspaceID, userID uint) error {
+	args := m.Called(workspaceID, userID)
+	return args.Error(0)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/netguard"
+)
+
+// newNetGuard builds the destination host check from the config. All
+// categories are blocked unless BlockedHostCategories says otherwise, and
+// the service's own PublicHosts count as own hosts. Verified branded domains
+// change at runtime and are left to checkDestinationHost.
+func newNetGuard(cfg Config, resolver netguard.Resolver) (*netguard.Guard, error) {
+	blocked := netguard.AllCategories
+	if len(cfg.BlockedHostCategories) > 0 {
+		blocked = nil
+		for _, c := range cfg.BlockedHostCategories {
+			if strings.EqualFold(c, "none") {
+				return nil, nil
+			}
+			category := netguard.Category(strings.ToLower(c))
+			if !isKnownCategory(category) {
+				return nil, fmt.Errorf("unknown host category %q", c)
+			}
+			blocked = append(blocked, category)
+		}
+	}
+	return netguard.New(netguard.Config{
+		Blocked:    blocked,
+		OwnHosts:   cfg.PublicHosts,
+		Exceptions: cfg.DestinationExceptions,
+	}, resolver)
+}
+
+func isKnownCategory(category netguard.Category) bool {
+	for _, c := range netguard.AllCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDestination normalizes a web destination other than a link's
+// long URL, such as a variant, and holds it to the same checks.
+func (s *UrlShortenerService) normalizeDestination(ctx context.Context, raw string) (string, error) {
+	normalized, err := s.Config.normalizeURL(raw)
+	if err != nil {
+		return "", err
+	}
+	if err := s.checkDestination(normalized); err != nil {
+		return "", err
+	}
+	if err := s.checkDestinationHost(ctx, normalized); err != nil {
+		return "", err
+	}
+	return normalized, nil
+}
+
+// checkVisitorDestination holds where a visitor is sent instead of the long
+// URL, by a routing rule or variant, to the checks of long URLs again before
+// redirecting: policy rules and host addresses may have changed since it was
+// saved. App deep links have no host to check.
+func (s *UrlShortenerService) checkVisitorDestination(ctx context.Context, destination string) error {
+	if err := s.checkDestination(destination); err != nil {
+		return err
+	}
+	u, err := url.Parse(destination)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if scheme := strings.ToLower(u.Scheme); scheme != "http" && scheme != "https" {
+		return nil
+	}
+	return s.checkDestinationHost(ctx, destination)
+}
+
+// checkDestinationHost rejects long URLs whose host is, or resolves to, an
+// internal address or one of the service's own hosts. Verified branded
+// domains are own hosts as well, the guard only knows PublicHosts.
+func (s *UrlShortenerService) checkDestinationHost(ctx context.Context, longURL string) error {
+	if s.netGuard == nil {
+		return nil
+	}
+	u, err := url.Parse(longURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if s.netGuard.Blocks(netguard.CategoryOwnHost) && s.isBrandedHost(u.Hostname()) {
+		blocked := &netguard.BlockedError{Host: u.Hostname(), Category: netguard.CategoryOwnHost}
+		return fmt.Errorf("%w: %v", ErrInternalDestination, blocked)
+	}
+	if err := s.netGuard.Check(ctx, u.Hostname()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInternalDestination, err)
+	}
+	return nil
+}
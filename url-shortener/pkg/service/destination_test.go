@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// staticResolver resolves every host to the same address.
+type staticResolver string
+
+func (r staticResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return []net.IPAddr{{IP: net.ParseIP(string(r))}}, nil
+}
+
+func TestShortenURLInternalDestination(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		longURL string
+	}{
+		{name: "Metadata address", longURL: "http://169.254.169.254/latest/meta-data/"},
+		{name: "Localhost", longURL: "http://localhost:8080/admin"},
+		{name: "Private IP", longURL: "http://192.168.0.1/"},
+		{name: "Host resolving to private IP", longURL: "http://intranet.example/"},
+		{name: "Subdomain of own public host", cfg: Config{PublicHosts: []string{"sho.rt"}}, longURL: "https://api.sho.rt/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard, err := newNetGuard(tt.cfg, staticResolver("10.0.0.1"))
+			assert.NoError(t, err)
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{Config: tt.cfg, db: mockDb, netGuard: guard}
+			mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+			mockDb.On("GetVerifiedDomain", mock.Anything).Return(nil, gorm.ErrRecordNotFound).Maybe()
+
+			resp, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: tt.longURL})
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, ErrInternalDestination)
+			mockDb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckDestinationHostBrandedDomain(t *testing.T) {
+	cfg := Config{PublicHosts: []string{"sho.rt"}}
+	mockDb := new(MockDB)
+	mockDb.On("GetVerifiedDomain", "go.acme.com").Return(&dataModel.Domain{Host: "go.acme.com"}, nil).Once()
+
+	guard, err := newNetGuard(cfg, staticResolver("93.184.216.34"))
+	assert.NoError(t, err)
+	s := &UrlShortenerService{Config: cfg, db: mockDb, netGuard: guard}
+	assert.ErrorIs(t, s.checkDestinationHost(context.Background(), "https://go.acme.com/launch"), ErrInternalDestination)
+
+	// Branded domains are only checked while own hosts are blocked.
+	cfg.BlockedHostCategories = []string{"private"}
+	s.netGuard, err = newNetGuard(cfg, staticResolver("93.184.216.34"))
+	assert.NoError(t, err)
+	assert.NoError(t, s.checkDestinationHost(context.Background(), "https://go.acme.com/launch"))
+	mockDb.AssertExpectations(t)
+}
+
+func TestNewNetGuardConfig(t *testing.T) {
+	guard, err := newNetGuard(Config{BlockedHostCategories: []string{"none"}}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, guard)
+
+	_, err = newNetGuard(Config{BlockedHostCategories: []string{"intranet"}}, nil)
+	assert.Error(t, err)
+
+	guard, err = newNetGuard(Config{
+		BlockedHostCategories: []string{"metadata"},
+		DestinationExceptions: []string{"10.0.0.0/8"},
+	}, staticResolver("10.0.0.1"))
+	assert.NoError(t, err)
+	assert.NoError(t, guard.Check(context.Background(), "intranet.example"))
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// hostLabel matches one label of a DNS host name.
+var hostLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// validateBrandedHost checks host can be used as a branded domain.
+func (c Config) validateBrandedHost(host string) error {
+	if len(host) > 253 || net.ParseIP(host) != nil {
+		return fmt.Errorf("%w: %q is not a host name", ErrInvalidDomain, host)
+	}
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("%w: %q is not a fully qualified host name", ErrInvalidDomain, host)
+	}
+	for _, label := range labels {
+		if !hostLabel.MatchString(label) {
+			return fmt.Errorf("%w: %q is not a host name", ErrInvalidDomain, host)
+		}
+	}
+	if c.isPublicHost(host) {
+		return fmt.Errorf("%w: %q is a host of this service", ErrInvalidDomain, host)
+	}
+	return nil
+}
+
+// verificationRecord returns the name and value of the TXT record verifying
+// domain.
+func verificationRecord(domain *dataModel.Domain) (string, string) {
+	return DomainVerificationLabel + "." + domain.Host, DomainVerificationPrefix + domain.VerificationToken
+}
+
+// CreateDomain adds a branded domain of the caller. It has to be verified
+// before links are served on it.
+func (s *UrlShortenerService) CreateDomain(ctx context.Context, req *proto.CreateDomainRequest) (*proto.CreateDomainResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	host, err := normalizeHost(strings.TrimSpace(req.Host))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q is not a host name", ErrInvalidDomain, req.Host)
+	}
+	if err := s.Config.validateBrandedHost(host); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.db.ListDomains(user.Workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range existing {
+		if d.Host == host {
+			return nil, fmt.Errorf("%w: %s was already added", ErrInvalidDomain, host)
+		}
+	}
+	if len(existing) >= MaxDomainsPerWorkspace {
+		return nil, fmt.Errorf("%w: at most %d allowed", ErrTooManyDomains, MaxDomainsPerWorkspace)
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	domain := &dataModel.Domain{UserID: user.ID, WorkspaceID: user.Workspace.ID, Host: host, VerificationToken: hex.EncodeToString(token)}
+	if err := s.db.CreateDomain(domain); err != nil {
+		log.Printf("Error creating domain %s for user %d: %v", host, user.ID, err)
+		return nil, err
+	}
+	return &proto.CreateDomainResponse{Domain: domainToProto(domain)}, nil
+}
+
+// ListDomains returns the branded domains of the caller.
+func (s *UrlShortenerService) ListDomains(ctx context.Context, req *proto.ListDomainsRequest) (*proto.ListDomainsResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	domains, err := s.db.ListDomains(user.Workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListDomainsResponse{}
+	for i := range domains {
+		resp.Domains = append(resp.Domains, domainToProto(&domains[i]))
+	}
+	return resp, nil
+}
+
+// VerifyDomain checks the verification TXT record of a domain of the caller
+// and marks the domain verified when it is found.
+func (s *UrlShortenerService) VerifyDomain(ctx context.Context, req *proto.VerifyDomainRequest) (*proto.VerifyDomainResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := s.db.GetDomain(user.Workspace.ID, uint(req.Id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDomainNotFound
+		}
+		return nil, err
+	}
+	if domain.Verified() {
+		return &proto.VerifyDomainResponse{Domain: domainToProto(domain)}, nil
+	}
+
+	name, value := verificationRecord(domain)
+	ctx, cancel := context.WithTimeout(ctx, DomainLookupTimeout)
+	defer cancel()
+	records, err := s.txtResolver().LookupTXT(ctx, name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: looking up TXT record %s: %v", ErrDomainNotVerified, name, dnsErr.Err)
+	}
+	found := false
+	for _, record := range records {
+		if strings.TrimSpace(record) == value {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: TXT record %s does not contain %q", ErrDomainNotVerified, name, value)
+	}
+
+	if err := s.db.VerifyDomain(domain, time.Now()); err != nil {
+		if errors.Is(err, dataModel.ErrDomainTaken) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDomain, err)
+		}
+		return nil, err
+	}
+	s.brandedHosts.forget(domain.Host)
+	return &proto.VerifyDomainResponse{Domain: domainToProto(domain)}, nil
+}
+
+// DeleteDomain removes a branded domain of the caller. Its links are no
+// longer served.
+func (s *UrlShortenerService) DeleteDomain(ctx context.Context, req *proto.DeleteDomainRequest) (*proto.DeleteDomainResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := s.db.GetDomain(user.Workspace.ID, uint(req.Id))
+	if err == nil {
+		err = s.db.DeleteDomain(user.Workspace.ID, domain.ID)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDomainNotFound
+		}
+		return nil, err
+	}
+	s.brandedHosts.forget(domain.Host)
+	return &proto.DeleteDomainResponse{}, nil
+}
+
+func domainToProto(domain *dataModel.Domain) *proto.Domain {
+	name, value := verificationRecord(domain)
+	d := &proto.Domain{
+		Id:                 uint64(domain.ID),
+		Host:               domain.Host,
+		Verified:           domain.Verified(),
+		VerificationRecord: name,
+		VerificationValue:  value,
+		CreatedAt:          timestamppb.New(domain.CreatedAt),
+	}
+	if domain.VerifiedAt != nil {
+		d.VerifiedAt = timestamppb.New(*domain.VerifiedAt)
+	}
+	return d
+}
+
+// txtResolver returns the resolver used to verify domains.
+func (s *UrlShortenerService) txtResolver() TXTResolver {
+	if s.resolver != nil {
+		return s.resolver
+	}
+	return net.DefaultResolver
+}
+
+// userHost returns the host of a verified branded domain of user, for
+// shortening links on it.
+func (s *UrlShortenerService) userHost(user *dataModel.User, host string) (string, error) {
+	normalized, err := normalizeHost(strings.TrimSpace(host))
+	if err != nil {
+		return "", fmt.Errorf("%w: %q is not a host name", ErrInvalidDomain, host)
+	}
+	domain, err := s.db.GetVerifiedDomain(normalized)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	host = normalized
+	if domain == nil || domain.WorkspaceID != user.Workspace.ID {
+		return "", fmt.Errorf("%w: %s is not a verified domain of yours", ErrDomainNotVerified, host)
+	}
+	return domain.Host, nil
+}
+
+// brandedHost returns host normalized when it is a verified branded domain,
+// of any workspace, for public lookups of the links on it.
+func (s *UrlShortenerService) brandedHost(host string) (string, error) {
+	normalized, err := normalizeHost(strings.TrimSpace(host))
+	if err != nil {
+		return "", fmt.Errorf("%w: %q is not a host name", ErrInvalidDomain, host)
+	}
+	if !s.brandedHosts.lookup(normalized, func() bool { return s.isVerifiedHost(normalized) }) {
+		return "", fmt.Errorf("%w: %s is not a verified domain", ErrDomainNotVerified, normalized)
+	}
+	return normalized, nil
+}
+
+// isBrandedHost reports whether host is a verified branded domain. Like
+// linkHost it needs PublicHosts, without them no host is branded.
+func (s *UrlShortenerService) isBrandedHost(host string) bool {
+	if len(s.Config.PublicHosts) == 0 || s.Config.isPublicHost(host) {
+		return false
+	}
+	_, err := s.brandedHost(host)
+	return err == nil
+}
+
+// displayShortURL is the short URL returned for mapping: its code, or the
+// full URL on its branded domain.
+func displayShortURL(mapping *dataModel.URLMapping) string {
+	if mapping.Host == "" {
+		return mapping.ShortURLID
+	}
+	return "https://" + mapping.Host + "/" + mapping.ShortURLID
+}
+
+// linkHost returns the branded domain r was sent to, or "" for requests to
+// the service's own hosts. Without PublicHosts every host is taken to be
+// our own.
+func (s *UrlShortenerService) linkHost(r *http.Request) string {
+	if len(s.Config.PublicHosts) == 0 {
+		return ""
+	}
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host, err := normalizeHost(host)
+	if err != nil || s.Config.isPublicHost(host) {
+		return ""
+	}
+	if s.brandedHosts.lookup(host, func() bool { return s.isVerifiedHost(host) }) {
+		return host
+	}
+	return ""
+}
+
+// isVerifiedHost reports whether host is a verified branded domain.
+func (s *UrlShortenerService) isVerifiedHost(host string) bool {
+	_, err := s.db.GetVerifiedDomain(host)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Error looking up domain %s: %v", host, err)
+	}
+	return err == nil
+}
+
+// isBrandedRequest matches requests sent to a verified branded domain.
+func (s *UrlShortenerService) isBrandedRequest(r *http.Request, _ *mux.RouteMatch) bool {
+	return s.linkHost(r) != ""
+}
+
+// brandedHostCache remembers for DomainCacheTTL which request hosts are
+// verified branded domains, sparing a query per redirect. The zero value is
+// ready to use.
+type brandedHostCache struct {
+	mu    sync.Mutex
+	hosts map[string]brandedHost
+}
+
+// maxCachedHosts is how many hosts are cached before expired ones are dropped.
+const maxCachedHosts = 1024
+
+type brandedHost struct {
+	verified bool
+	expires  time.Time
+}
+
+// lookup returns whether host is verified, asking verified when it is not
+// cached.
+func (c *brandedHostCache) lookup(host string, verified func() bool) bool {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.hosts[host]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.verified
+	}
+
+	entry = brandedHost{verified: verified(), expires: now.Add(DomainCacheTTL)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.hosts == nil {
+		c.hosts = make(map[string]brandedHost)
+	}
+	// Drop expired hosts so requests with made up hosts do not pile up.
+	if len(c.hosts) >= maxCachedHosts {
+		for h, e := range c.hosts {
+			if !now.Before(e.expires) {
+				delete(c.hosts, h)
+			}
+		}
+	}
+	c.hosts[host] = entry
+	return entry.verified
+}
+
+// forget drops host from the cache after its domain changed.
+func (c *brandedHostCache) forget(host string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.hosts, host)
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// fakeResolver serves TXT records from a map.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, ok := r[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func TestCreateDomain(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	cfg := Config{PublicHosts: []string{"sho.rt"}}
+
+	t.Run("Created", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, Config: cfg}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("ListDomains", uint(1)).Return([]dataModel.Domain{}, nil).Once()
+		mockDb.On("CreateDomain", mock.MatchedBy(func(d *dataModel.Domain) bool {
+			return d.UserID == 1 && d.Host == "go.acme.com" && len(d.VerificationToken) == 32
+		})).Return(nil).Once()
+
+		resp, err := s.CreateDomain(ctx, &proto.CreateDomainRequest{ApiKey: "key", Host: "Go.Acme.com."})
+		assert.NoError(t, err)
+		assert.Equal(t, "go.acme.com", resp.Domain.Host)
+		assert.False(t, resp.Domain.Verified)
+		assert.Equal(t, "_url-shortener-challenge.go.acme.com", resp.Domain.VerificationRecord)
+		assert.Contains(t, resp.Domain.VerificationValue, DomainVerificationPrefix)
+		mockDb.AssertExpectations(t)
+	})
+
+	for name, host := range map[string]string{
+		"Empty":        "",
+		"IP address":   "10.0.0.1",
+		"Single label": "localhost",
+		"Bad label":    "go_links.acme.com",
+		"Own host":     "sho.rt",
+		"With path":    "go.acme.com/x",
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb, Config: cfg}
+			mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+			_, err := s.CreateDomain(ctx, &proto.CreateDomainRequest{ApiKey: "key", Host: host})
+			assert.ErrorIs(t, err, ErrInvalidDomain)
+		})
+	}
+
+	t.Run("Too many", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, Config: cfg}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("ListDomains", uint(1)).Return(make([]dataModel.Domain, MaxDomainsPerWorkspace), nil).Once()
+		_, err := s.CreateDomain(ctx, &proto.CreateDomainRequest{ApiKey: "key", Host: "go.acme.com"})
+		assert.ErrorIs(t, err, ErrTooManyDomains)
+	})
+}
+
+func TestVerifyDomain(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	domain := func() *dataModel.Domain {
+		return &dataModel.Domain{Model: gorm.Model{ID: 3}, UserID: 1, WorkspaceID: 1, Host: "go.acme.com", VerificationToken: "t0ken"}
+	}
+
+	t.Run("Verified", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, resolver: fakeResolver{
+			"_url-shortener-challenge.go.acme.com": {"v=spf1 -all", "url-shortener-verification=t0ken"},
+		}}
+		s.brandedHosts.lookup("go.acme.com", func() bool { return false })
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetDomain", uint(1), uint(3)).Return(domain(), nil).Once()
+		mockDb.On("VerifyDomain", mock.Anything, mock.Anything).Return(nil).Once()
+
+		resp, err := s.VerifyDomain(ctx, &proto.VerifyDomainRequest{ApiKey: "key", Id: 3})
+		assert.NoError(t, err)
+		assert.True(t, resp.Domain.Verified)
+		assert.NotNil(t, resp.Domain.VerifiedAt)
+		assert.NotContains(t, s.brandedHosts.hosts, "go.acme.com")
+		mockDb.AssertExpectations(t)
+	})
+
+	for name, resolver := range map[string]fakeResolver{
+		"No record":    {},
+		"Wrong record": {"_url-shortener-challenge.go.acme.com": {"url-shortener-verification=other"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb, resolver: resolver}
+			mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+			mockDb.On("GetDomain", uint(1), uint(3)).Return(domain(), nil).Once()
+
+			_, err := s.VerifyDomain(ctx, &proto.VerifyDomainRequest{ApiKey: "key", Id: 3})
+			assert.ErrorIs(t, err, ErrDomainNotVerified)
+			mockDb.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Verified by another user", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, resolver: fakeResolver{
+			"_url-shortener-challenge.go.acme.com": {"url-shortener-verification=t0ken"},
+		}}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetDomain", uint(1), uint(3)).Return(domain(), nil).Once()
+		mockDb.On("VerifyDomain", mock.Anything, mock.Anything).Return(dataModel.ErrDomainTaken).Once()
+
+		_, err := s.VerifyDomain(ctx, &proto.VerifyDomainRequest{ApiKey: "key", Id: 3})
+		assert.ErrorIs(t, err, ErrInvalidDomain)
+	})
+
+	t.Run("Unknown domain", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetDomain", uint(1), uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := s.VerifyDomain(ctx, &proto.VerifyDomainRequest{ApiKey: "key", Id: 9})
+		assert.ErrorIs(t, err, ErrDomainNotFound)
+	})
+}
+
+func TestShortenURLOnDomain(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	now := time.Now()
+	verified := &dataModel.Domain{UserID: 1, WorkspaceID: 1, Host: "go.acme.com", VerifiedAt: &now}
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return 99, nil }
+	t.Cleanup(func() {
+		requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return s.requestCounter() }
+	})
+
+	t.Run("Branded URL returned", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetVerifiedDomain", "go.acme.com").Return(verified, nil).Once()
+		mockDb.On("CreateURLMapping", mock.MatchedBy(func(m *dataModel.URLMapping) bool {
+			return m.Host == "go.acme.com" && m.ShortURLID == "launch"
+		})).Return(nil).Once()
+
+		// Not deduplicated against links on other hosts.
+		resp, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{
+			ApiKey: "key", LongUrl: "http://example.com/", Domain: "GO.acme.com", CustomAlias: "launch",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "https://go.acme.com/launch", resp.ShortUrl)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Domain of another user", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		other := &dataModel.Domain{UserID: 2, WorkspaceID: 2, Host: "go.acme.com", VerifiedAt: &now}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetVerifiedDomain", "go.acme.com").Return(other, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/", Domain: "go.acme.com"})
+		assert.ErrorIs(t, err, ErrDomainNotVerified)
+	})
+
+	t.Run("Unverified domain", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetVerifiedDomain", "go.acme.com").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/", Domain: "go.acme.com"})
+		assert.ErrorIs(t, err, ErrDomainNotVerified)
+	})
+}
+
+func TestBrandedRedirect(t *testing.T) {
+	now := time.Now()
+	verified := &dataModel.Domain{UserID: 1, WorkspaceID: 1, Host: "go.acme.com", VerifiedAt: &now}
+	serve := func(s *UrlShortenerService, host, path string) *httptest.ResponseRecorder {
+		r := mux.NewRouter()
+		r.HandleFunc("/d/{shortChar}", s.redirectHandler)
+		branded := r.MatcherFunc(s.isBrandedRequest).Methods(http.MethodGet, http.MethodHead).Subrouter()
+		branded.HandleFunc("/{shortChar}", s.redirectHandler)
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = host
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("Code resolved on the request host", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, Config: Config{PublicHosts: []string{"sho.rt"}}}
+		mockDb.On("GetVerifiedDomain", "go.acme.com").Return(verified, nil).Once()
+		mockDb.On("GetURLMappingOnHost", "go.acme.com", "launch").
+			Return(&dataModel.URLMapping{Host: "go.acme.com", ShortURLID: "launch", LongURL: "https://acme.com/launch"}, nil).Twice()
+
+		rr := serve(s, "go.acme.com:443", "/launch")
+		assert.Equal(t, http.StatusFound, rr.Code)
+		assert.Equal(t, "https://acme.com/launch", rr.Header().Get("Location"))
+
+		// The host is cached, and /d/ paths work on it too.
+		rr = serve(s, "go.acme.com", "/d/launch")
+		assert.Equal(t, http.StatusFound, rr.Code)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Own host uses the default links", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, Config: Config{PublicHosts: []string{"sho.rt"}}}
+		mockDb.On("GetURLMapping", "launch").
+			Return(&dataModel.URLMapping{ShortURLID: "launch", LongURL: "https://example.com/"}, nil).Once()
+
+		rr := serve(s, "sho.rt", "/d/launch")
+		assert.Equal(t, http.StatusFound, rr.Code)
+		assert.Equal(t, "https://example.com/", rr.Header().Get("Location"))
+		assert.Equal(t, http.StatusNotFound, serve(s, "sho.rt", "/launch").Code)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Unknown host", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, Config: Config{PublicHosts: []string{"sho.rt"}}}
+		mockDb.On("GetVerifiedDomain", "evil.example").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		assert.Equal(t, http.StatusNotFound, serve(s, "evil.example", "/launch").Code)
+		mockDb.AssertExpectations(t)
+	})
+}
+
+func TestClaimCustomAliasesPerHost(t *testing.T) {
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mappings := []*dataModel.URLMapping{
+		{ShortURLID: "launch", IsCustomAlias: true},
+		{Host: "go.acme.com", ShortURLID: "launch", IsCustomAlias: true},
+		{Host: "go.acme.com", ShortURLID: "docs", IsCustomAlias: true},
+	}
+	results := []*proto.BatchShortenResult{{}, {}, {}}
+	mockDb.On("FindShortURLIDs", "", []string{"launch"}).Return([]string{}, nil).Once()
+	mockDb.On("FindShortURLIDs", "go.acme.com", []string{"launch", "docs"}).Return([]string{"docs"}, nil).Once()
+
+	free := s.claimCustomAliases(mappings, results)
+	assert.Equal(t, []int{0, 1}, free)
+	assert.Equal(t, ErrCustomAliasTaken.Error(), results[2].Error)
+	mockDb.AssertExpectations(t)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/events"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+)
+
+// newEventRelay returns the relay publishing the outbox to the event
+// publisher selected by cfg, or nil when events are not published.
+func newEventRelay(cfg Config, store events.Store, redisClient RedisClientInterface) (*events.Relay, error) {
+	var publisher events.EventPublisher
+	switch cfg.EventPublisher {
+	case "", "none":
+		return nil, nil
+	case "redis":
+		stream := cfg.EventStream
+		if stream == "" {
+			stream = DefaultEventStream
+		}
+		publisher = &events.RedisStream{Client: redisClient, Stream: stream, MaxLen: EventStreamMaxLen}
+	case "file":
+		if cfg.EventFile == "" {
+			return nil, fmt.Errorf("%s must be set to publish events to a file", EventFile)
+		}
+		sink, err := events.NewFileSink(cfg.EventFile)
+		if err != nil {
+			return nil, err
+		}
+		publisher = sink
+	case "memory":
+		publisher = events.NewMemoryBus()
+	default:
+		return nil, fmt.Errorf("unknown %s %q", EventPublisher, cfg.EventPublisher)
+	}
+	return events.NewRelay(store, publisher), nil
+}
+
+// outboxEvents encodes evs for the outbox, to be written along with the
+// change they describe. It returns nil when events are not published.
+func (s *UrlShortenerService) outboxEvents(evs ...*proto.Event) []*dataModel.OutboxEvent {
+	if s.eventRelay == nil {
+		return nil
+	}
+	outbox := make([]*dataModel.OutboxEvent, 0, len(evs))
+	for _, ev := range evs {
+		e, err := events.NewOutboxEvent(ev)
+		if err != nil {
+			log.Printf("Error encoding %s event: %v", ev.Type, err)
+			continue
+		}
+		outbox = append(outbox, e)
+	}
+	return outbox
+}
+
+// notifyEvents wakes the relay after events were written to the outbox.
+func (s *UrlShortenerService) notifyEvents() {
+	if s.eventRelay != nil {
+		s.eventRelay.Notify()
+	}
+}
+
+// publishEvents queues evs for events that are not part of a transaction,
+// like clicks. Failing to queue them is logged, it does not fail the
+// operation the events are about.
+func (s *UrlShortenerService) publishEvents(evs ...*proto.Event) {
+	outbox := s.outboxEvents(evs...)
+	if len(outbox) == 0 {
+		return
+	}
+	if err := s.db.InsertOutboxEvents(outbox); err != nil {
+		log.Printf("Error queueing %d events: %v", len(outbox), err)
+		return
+	}
+	s.notifyEvents()
+}
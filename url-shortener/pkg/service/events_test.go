@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/events"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+func decodeOutboxEvent(t *testing.T, e *dataModel.OutboxEvent) *proto.Event {
+	var event proto.Event
+	assert.NoError(t, gproto.Unmarshal(e.Payload, &event))
+	assert.Equal(t, e.EventID, event.Id)
+	assert.Equal(t, e.Type, event.Type)
+	return &event
+}
+
+func TestDomainEvents(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return 99, nil }
+	requestCounterRangeFunc = func(s *UrlShortenerService, n int64) (int64, error) { return 100, nil }
+	t.Cleanup(func() {
+		requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return s.requestCounter() }
+		requestCounterRangeFunc = func(s *UrlShortenerService, n int64) (int64, error) { return s.requestCounterRange(n) }
+	})
+
+	t.Run("Created link is written with its event", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, eventRelay: events.NewRelay(mockDb, events.NewMemoryBus())}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		var outbox []*dataModel.OutboxEvent
+		mockDb.On("CreateURLMapping", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			outbox = args.Get(1).([]*dataModel.OutboxEvent)
+		}).Return(nil).Once()
+
+		resp, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/", MaxClicks: 5})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+
+		assert.Len(t, outbox, 1)
+		event := decodeOutboxEvent(t, outbox[0])
+		assert.Equal(t, events.TypeLinkCreated, event.Type)
+		assert.Equal(t, resp.ShortUrl, event.GetLinkCreated().ShortUrl)
+		assert.Equal(t, "http://example.com/", event.GetLinkCreated().LongUrl)
+		assert.Equal(t, uint64(1), event.GetLinkCreated().UserId)
+		assert.Equal(t, int64(5), event.GetLinkCreated().MaxClicks)
+	})
+
+	t.Run("Batch is written with its events", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, eventRelay: events.NewRelay(mockDb, events.NewMemoryBus())}
+		var outbox []*dataModel.OutboxEvent
+		mockDb.On("CreateURLMappings", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			outbox = args.Get(1).([]*dataModel.OutboxEvent)
+		}).Return(nil).Once()
+
+		results := s.shortenBatch(ctx, user, []*proto.ShortenURLRequest{
+			{LongUrl: "http://example.com/a", MaxClicks: 1},
+			{LongUrl: "http://example.com/b", MaxClicks: 1},
+		})
+		mockDb.AssertExpectations(t)
+
+		assert.Len(t, outbox, 2)
+		for i, e := range outbox {
+			event := decodeOutboxEvent(t, e)
+			assert.Equal(t, events.TypeLinkCreated, event.Type)
+			assert.Equal(t, results[i].ShortUrl, event.GetLinkCreated().ShortUrl)
+		}
+	})
+
+	t.Run("Clicks are queued", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, eventRelay: events.NewRelay(mockDb, events.NewMemoryBus())}
+		mapping := &dataModel.URLMapping{ShortURLID: "abc", LongURL: "http://example.com", UserID: 5, WorkspaceID: 5}
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		var outbox []*dataModel.OutboxEvent
+		mockDb.On("InsertOutboxEvents", mock.Anything).Run(func(args mock.Arguments) {
+			outbox = args.Get(0).([]*dataModel.OutboxEvent)
+		}).Return(nil).Once()
+
+		req := httptest.NewRequest("GET", "/d/abc", nil)
+		req.Header.Set("Referer", "https://news.example/")
+		rr := httptest.NewRecorder()
+		s.redirectHandler(rr, mux.SetURLVars(req, map[string]string{"shortChar": "abc"}))
+		assert.Equal(t, http.StatusFound, rr.Code)
+		mockDb.AssertExpectations(t)
+
+		assert.Len(t, outbox, 1)
+		event := decodeOutboxEvent(t, outbox[0])
+		assert.Equal(t, events.TypeLinkClicked, event.Type)
+		assert.Equal(t, "abc", event.GetLinkClicked().ShortUrl)
+		assert.Equal(t, "https://news.example/", event.GetLinkClicked().Referrer)
+	})
+
+	t.Run("Nothing written without a publisher", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("CreateURLMapping", mock.Anything).Return(nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/", MaxClicks: 5})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+}
+
+func TestNewEventRelay(t *testing.T) {
+	mockDb := new(MockDB)
+	redisClient := new(MockRedisClient)
+
+	relay, err := newEventRelay(Config{}, mockDb, redisClient)
+	assert.NoError(t, err)
+	assert.Nil(t, relay)
+
+	relay, err = newEventRelay(Config{EventPublisher: "redis"}, mockDb, redisClient)
+	assert.NoError(t, err)
+	if assert.IsType(t, &events.RedisStream{}, relay.Publisher) {
+		assert.Equal(t, DefaultEventStream, relay.Publisher.(*events.RedisStream).Stream)
+	}
+
+	relay, err = newEventRelay(Config{EventPublisher: "file", EventFile: filepath.Join(t.TempDir(), "events.ndjson")}, mockDb, redisClient)
+	assert.NoError(t, err)
+	assert.IsType(t, &events.FileSink{}, relay.Publisher)
+	assert.NoError(t, relay.Publisher.Close())
+
+	_, err = newEventRelay(Config{EventPublisher: "file"}, mockDb, redisClient)
+	assert.Error(t, err)
+	_, err = newEventRelay(Config{EventPublisher: "kafka"}, mockDb, redisClient)
+	assert.Error(t, err)
+}
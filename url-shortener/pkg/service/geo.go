@@ -0,0 +1,87 @@
+package service
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/geoip"
+)
+
+// parsePrefixes parses CIDR ranges; plain addresses become single address
+// ranges.
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		if strings.Contains(v, "/") {
+			prefix, err := netip.ParsePrefix(v)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// isTrustedProxy reports whether ip is one of TrustedProxies.
+func (c Config) isTrustedProxy(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, prefix := range c.TrustedProxies {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// httpClientAddr returns the IP address of the client of r. Requests from
+// trusted proxies are attributed to the address they appended to
+// X-Forwarded-For, skipping further trusted proxies from the right; entries
+// left of that are set by the client and not believed.
+func (c Config) httpClientAddr(r *http.Request) string {
+	addr, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		addr = r.RemoteAddr
+	}
+	if len(c.TrustedProxies) == 0 {
+		return addr
+	}
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	ip, err := netip.ParseAddr(addr)
+	for i := len(hops) - 1; i >= 0 && err == nil && c.isTrustedProxy(ip); i-- {
+		hop := strings.TrimSpace(hops[i])
+		if ip, err = netip.ParseAddr(hop); err == nil {
+			addr = ip.String()
+		}
+	}
+	return addr
+}
+
+// locate returns the location of the client of r, the zero Location when
+// it is unknown or no GeoIP database is configured.
+func (s *UrlShortenerService) locate(r *http.Request) geoip.Location {
+	if s.geo == nil {
+		return geoip.Location{}
+	}
+	ip, err := netip.ParseAddr(s.Config.httpClientAddr(r))
+	if err != nil {
+		return geoip.Location{}
+	}
+	loc, err := s.geo.Lookup(ip)
+	if err != nil {
+		log.Printf("Error locating %s: %v", ip, err)
+	}
+	return loc
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/geoip"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func testGeoDB(t *testing.T) geoip.Database {
+	t.Helper()
+	db, err := geoip.ReadCSV(strings.NewReader("81.2.69.0/24,GB,ENG\n203.0.113.0/24,US,CA\n198.51.100.0/24,US,NY\n"))
+	require.NoError(t, err)
+	return db
+}
+
+func TestHTTPClientAddr(t *testing.T) {
+	proxies, err := parsePrefixes([]string{"10.0.0.0/8", "192.0.2.1"})
+	require.NoError(t, err)
+	tests := []struct {
+		name       string
+		proxies    bool
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"Direct", true, "81.2.69.1:4000", nil, "81.2.69.1"},
+		{"No trusted proxies", false, "10.0.0.1:4000", []string{"81.2.69.1"}, "10.0.0.1"},
+		{"Untrusted peer", true, "81.2.69.1:4000", []string{"203.0.113.9"}, "81.2.69.1"},
+		{"Trusted proxy", true, "10.0.0.1:4000", []string{"81.2.69.1"}, "81.2.69.1"},
+		{"Proxy chain", true, "10.0.0.1:4000", []string{"81.2.69.1, 192.0.2.1"}, "81.2.69.1"},
+		{"Spoofed entries are ignored", true, "10.0.0.1:4000", []string{"203.0.113.9, 81.2.69.1"}, "81.2.69.1"},
+		{"Several headers", true, "10.0.0.1:4000", []string{"203.0.113.9", "81.2.69.1"}, "81.2.69.1"},
+		{"Only proxies", true, "10.0.0.1:4000", []string{"10.0.0.2"}, "10.0.0.2"},
+		{"Garbage", true, "10.0.0.1:4000", []string{"unknown"}, "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{}
+			if tt.proxies {
+				cfg.TrustedProxies = proxies
+			}
+			req := httptest.NewRequest("GET", "/d/abc", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, f := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", f)
+			}
+			assert.Equal(t, tt.want, cfg.httpClientAddr(req))
+		})
+	}
+
+	_, err = parsePrefixes([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = parsePrefixes([]string{"proxy.local"})
+	assert.Error(t, err)
+}
+
+func TestAddGeoRoutingRule(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	mapping := &dataModel.URLMapping{Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1}
+	add := func(s *UrlShortenerService, rule *proto.RoutingRule) (*proto.AddRoutingRuleResponse, error) {
+		return s.AddRoutingRule(ctx, &proto.AddRoutingRuleRequest{ApiKey: "key", ShortUrl: "abc", Rule: rule})
+	}
+
+	t.Run("Region", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, geo: testGeoDB(t)}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		mockDb.On("ListRoutingRules", uint(7)).Return([]dataModel.RoutingRule{}, nil).Once()
+		mockDb.On("CreateRoutingRule", &dataModel.RoutingRule{
+			URLMappingID: 7, Country: "US", Region: "US-CA", Destination: "https://example.com/ca",
+		}).Return(nil).Once()
+
+		resp, err := add(s, &proto.RoutingRule{Country: "us", Region: "us-ca", Destination: "https://example.com/ca"})
+		assert.NoError(t, err)
+		assert.Equal(t, "US-CA", resp.Rule.Region)
+		mockDb.AssertExpectations(t)
+	})
+
+	for name, rule := range map[string]*proto.RoutingRule{
+		"Bad country":       {Country: "USA", Destination: "https://example.com"},
+		"Bad region":        {Region: "California", Destination: "https://example.com"},
+		"Region of another": {Country: "DE", Region: "US-CA", Destination: "https://example.com"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb, geo: testGeoDB(t)}
+			mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+			mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+
+			_, err := add(s, rule)
+			assert.ErrorIs(t, err, ErrInvalidRoutingRule)
+		})
+	}
+
+	t.Run("Without a database", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+
+		_, err := add(s, &proto.RoutingRule{Country: "US", Destination: "https://example.com"})
+		assert.ErrorIs(t, err, ErrInvalidRoutingRule)
+	})
+}
+
+func TestGeoRoutedRedirect(t *testing.T) {
+	proxies, err := parsePrefixes([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	rules := []dataModel.RoutingRule{
+		{URLMappingID: 7, Region: "US-CA", Destination: "https://example.com/ca"},
+		{URLMappingID: 7, Country: "US", Destination: "https://example.com/us"},
+		{URLMappingID: 7, Country: "GB", OS: "ios", Destination: "https://example.com/gb-ios"},
+	}
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwarded    string
+		userAgent    string
+		wantLocation string
+		wantCountry  string
+	}{
+		{"Region", "203.0.113.5:4000", "", desktopUA, "https://example.com/ca", "US"},
+		{"Country", "198.51.100.5:4000", "", desktopUA, "https://example.com/us", "US"},
+		{"Behind trusted proxy", "10.1.1.1:4000", "198.51.100.5", desktopUA, "https://example.com/us", "US"},
+		{"Country and device", "81.2.69.1:4000", "", iPhoneUA, "https://example.com/gb-ios", "GB"},
+		{"Country without device", "81.2.69.1:4000", "", desktopUA, "https://example.com/", "GB"},
+		{"Unknown location", "192.0.2.1:4000", "", desktopUA, "https://example.com/", ""},
+		{"Forwarded by untrusted peer", "192.0.2.1:4000", "198.51.100.5", desktopUA, "https://example.com/", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb, geo: testGeoDB(t), Config: Config{TrustedProxies: proxies}}
+			mapping := &dataModel.URLMapping{
+				Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1,
+				LongURL: "https://example.com/", HasRoutingRules: true,
+			}
+			mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+			mockDb.On("ListRoutingRules", uint(7)).Return(rules, nil).Once()
+			watcher := s.clicks.watch(1, nil)
+			defer s.clicks.stop(1, watcher)
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/d/abc", nil), map[string]string{"shortChar": "abc"})
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("User-Agent", tt.userAgent)
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			rr := httptest.NewRecorder()
+			s.redirectHandler(rr, req)
+
+			assert.Equal(t, http.StatusFound, rr.Code)
+			assert.Equal(t, tt.wantLocation, rr.Header().Get("Location"))
+			assert.Equal(t, "private", rr.Header().Get("Cache-Control"))
+			assert.Equal(t, tt.wantCountry, (<-watcher.events).Location.Country)
+		})
+	}
+}
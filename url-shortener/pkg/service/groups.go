@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// linkGroupKinds lists the kinds of link groups.
+var linkGroupKinds = []string{dataModel.GroupKindTag, dataModel.GroupKindCampaign, dataModel.GroupKindFolder}
+
+// CreateLinkGroup adds a tag, campaign or folder of the caller.
+func (s *UrlShortenerService) CreateLinkGroup(ctx context.Context, req *proto.CreateLinkGroupRequest) (*proto.CreateLinkGroupResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(linkGroupKinds, req.Kind) {
+		return nil, fmt.Errorf("%w: kind must be one of %s", ErrInvalidLinkGroup, strings.Join(linkGroupKinds, ", "))
+	}
+	name, err := linkGroupName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.db.ListLinkGroups(user.Workspace.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxLinkGroupsPerWorkspace {
+		return nil, fmt.Errorf("%w: at most %d allowed", ErrTooManyLinkGroups, MaxLinkGroupsPerWorkspace)
+	}
+	if err := checkLinkGroupNameFree(existing, req.Kind, name, 0); err != nil {
+		return nil, err
+	}
+
+	group := &dataModel.LinkGroup{UserID: user.ID, WorkspaceID: user.Workspace.ID, Kind: req.Kind, Name: name}
+	if err := s.db.CreateLinkGroup(group); err != nil {
+		log.Printf("Error creating %s %q for user %d: %v", req.Kind, name, user.ID, err)
+		return nil, err
+	}
+	return &proto.CreateLinkGroupResponse{Group: linkGroupToProto(group)}, nil
+}
+
+// RenameLinkGroup renames a tag, campaign or folder of the caller.
+func (s *UrlShortenerService) RenameLinkGroup(ctx context.Context, req *proto.RenameLinkGroupRequest) (*proto.RenameLinkGroupResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	name, err := linkGroupName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	group, err := s.userLinkGroup(user, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.db.ListLinkGroups(user.Workspace.ID, group.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLinkGroupNameFree(existing, group.Kind, name, group.ID); err != nil {
+		return nil, err
+	}
+	if err := s.db.RenameLinkGroup(group, name); err != nil {
+		log.Printf("Error renaming %s %d: %v", group.Kind, group.ID, err)
+		return nil, err
+	}
+	return &proto.RenameLinkGroupResponse{Group: linkGroupToProto(group)}, nil
+}
+
+// ListLinkGroups returns the tags, campaigns and folders of the caller, or
+// only those of one kind.
+func (s *UrlShortenerService) ListLinkGroups(ctx context.Context, req *proto.ListLinkGroupsRequest) (*proto.ListLinkGroupsResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	if req.Kind != "" && !slices.Contains(linkGroupKinds, req.Kind) {
+		return nil, fmt.Errorf("%w: kind must be one of %s", ErrInvalidLinkGroup, strings.Join(linkGroupKinds, ", "))
+	}
+	groups, err := s.db.ListLinkGroups(user.Workspace.ID, req.Kind)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListLinkGroupsResponse{}
+	for i := range groups {
+		resp.Groups = append(resp.Groups, linkGroupToProto(&groups[i]))
+	}
+	return resp, nil
+}
+
+// AssignLinks adds links of the caller to a tag, campaign or folder, or
+// removes them from it. Links move out of the campaign or folder they were
+// in before.
+func (s *UrlShortenerService) AssignLinks(ctx context.Context, req *proto.AssignLinksRequest) (*proto.AssignLinksResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	group, err := s.userLinkGroup(user, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	shortURLs := uniqueStrings(req.ShortUrls...)
+	if len(shortURLs) == 0 {
+		return nil, fmt.Errorf("%w: no links given", ErrInvalidLinkGroup)
+	}
+	if len(shortURLs) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: at most %d links", ErrBatchTooLarge, MaxBatchSize)
+	}
+	host := ""
+	if req.Domain != "" {
+		if host, err = s.userHost(user, req.Domain); err != nil {
+			return nil, err
+		}
+	}
+
+	mappings, err := s.db.FindWorkspaceURLMappings(user.Workspace.ID, host, shortURLs)
+	if err != nil {
+		return nil, err
+	}
+	if len(mappings) < len(shortURLs) {
+		found := make(map[string]bool, len(mappings))
+		for _, m := range mappings {
+			found[m.ShortURLID] = true
+		}
+		for _, shortURL := range shortURLs {
+			if !found[shortURL] {
+				return nil, fmt.Errorf("%w: %s", ErrLinkNotFound, shortURL)
+			}
+		}
+	}
+	ids := make([]uint, len(mappings))
+	linkEvents := make([]linkEvent, len(mappings))
+	for i := range mappings {
+		ids[i] = mappings[i].ID
+		linkEvents[i] = newLinkEvent(&mappings[i])
+	}
+	updated, err := s.db.AssignLinks(group, ids, !req.Unassign)
+	if err != nil {
+		log.Printf("Error assigning %d links to %s %d: %v", len(ids), group.Kind, group.ID, err)
+		return nil, err
+	}
+	if updated > 0 {
+		s.emitWebhook(user.Workspace.ID, EventLinkUpdated, linkEvents...)
+	}
+	return &proto.AssignLinksResponse{Updated: updated}, nil
+}
+
+// ListLinks returns a page of the links of the caller, newest first,
+// optionally only those with a tag, campaign or folder.
+func (s *UrlShortenerService) ListLinks(ctx context.Context, req *proto.ListLinksRequest) (*proto.ListLinksResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = DefaultLinksPageSize
+	}
+	pageSize = min(pageSize, MaxLinksPageSize)
+	filter := dataModel.LinkFilter{
+		TagID:      uint(req.TagId),
+		CampaignID: uint(req.CampaignId),
+		FolderID:   uint(req.FolderId),
+		Limit:      pageSize,
+	}
+	if req.PageToken != "" {
+		before, err := strconv.ParseUint(req.PageToken, 10, 0)
+		if err != nil || before == 0 {
+			return nil, ErrInvalidPageToken
+		}
+		filter.BeforeID = uint(before)
+	}
+
+	mappings, err := s.db.ListWorkspaceURLMappings(user.Workspace.ID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.retainClicks(user, mappings); err != nil {
+		return nil, err
+	}
+	links, err := s.linksToProto(user, mappings)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListLinksResponse{Links: links}
+	if len(mappings) == pageSize {
+		resp.NextPageToken = strconv.FormatUint(uint64(mappings[len(mappings)-1].ID), 10)
+	}
+	return resp, nil
+}
+
+// GetLinkGroupStats aggregates the clicks and conversions of the links of
+// a tag, campaign or folder of the caller. Clicks are counted over the
+// analytics retention of the caller's plan.
+func (s *UrlShortenerService) GetLinkGroupStats(ctx context.Context, req *proto.GetLinkGroupStatsRequest) (*proto.GetLinkGroupStatsResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	group, err := s.userLinkGroup(user, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.db.GetLinkGroupStats(group, LinkGroupTopLinks, analyticsSince(user, time.Now()))
+	if err != nil {
+		log.Printf("Error aggregating %s %d: %v", group.Kind, group.ID, err)
+		return nil, err
+	}
+	topLinks, err := s.linksToProto(user, stats.TopLinks)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.GetLinkGroupStatsResponse{
+		Group:       linkGroupToProto(group),
+		Links:       stats.Links,
+		Clicks:      stats.Clicks,
+		Conversions: stats.Conversions,
+		TopLinks:    topLinks,
+	}
+	for _, d := range stats.TopDomains {
+		resp.TopDomains = append(resp.TopDomains, &proto.DomainMetric{Domain: d.DomainName, Count: d.Count})
+	}
+	return resp, nil
+}
+
+// userLinkGroup returns the link group with id if user owns it.
+func (s *UrlShortenerService) userLinkGroup(user *dataModel.User, id uint64) (*dataModel.LinkGroup, error) {
+	group, err := s.db.GetLinkGroup(user.Workspace.ID, uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLinkGroupNotFound
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+// linkGroupName validates the name of a link group.
+func linkGroupName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxLinkGroupName {
+		return "", fmt.Errorf("%w: names have 1 to %d characters", ErrInvalidLinkGroup, MaxLinkGroupName)
+	}
+	if !utf8.ValidString(name) || strings.ContainsFunc(name, unicode.IsControl) {
+		return "", fmt.Errorf("%w: name %q has control characters", ErrInvalidLinkGroup, name)
+	}
+	return name, nil
+}
+
+// checkLinkGroupNameFree rejects a name another group of kind than the one
+// with id already has, regardless of case.
+func checkLinkGroupNameFree(groups []dataModel.LinkGroup, kind, name string, id uint) error {
+	for _, g := range groups {
+		if g.Kind == kind && g.ID != id && strings.EqualFold(g.Name, name) {
+			return fmt.Errorf("%w: a %s named %q already exists", ErrInvalidLinkGroup, kind, g.Name)
+		}
+	}
+	return nil
+}
+
+func linkGroupToProto(g *dataModel.LinkGroup) *proto.LinkGroup {
+	return &proto.LinkGroup{
+		Id:        uint64(g.ID),
+		Kind:      g.Kind,
+		Name:      g.Name,
+		CreatedAt: timestamppb.New(g.CreatedAt),
+		Utm:       utmToProto(g.UTM),
+	}
+}
+
+// linkGroups are the campaign, folder and tags of a link.
+type linkGroups struct {
+	campaign, folder *proto.LinkGroup
+	tags             []*proto.LinkGroup
+}
+
+// mappingGroups looks up the groups of mappings of user, in the order of
+// mappings.
+func (s *UrlShortenerService) mappingGroups(user *dataModel.User, mappings []dataModel.URLMapping) ([]linkGroups, error) {
+	ids := make([]uint, len(mappings))
+	grouped := false
+	for i, m := range mappings {
+		ids[i] = m.ID
+		grouped = grouped || m.CampaignID != 0 || m.FolderID != 0
+	}
+	tags, err := s.db.ListLinkTags(ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]linkGroups, len(mappings))
+	if !grouped && len(tags) == 0 {
+		return result, nil
+	}
+
+	groups, err := s.db.ListLinkGroups(user.Workspace.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*proto.LinkGroup, len(groups))
+	for i := range groups {
+		byID[groups[i].ID] = linkGroupToProto(&groups[i])
+	}
+	tagsOf := make(map[uint][]*proto.LinkGroup)
+	for _, t := range tags {
+		if g := byID[t.LinkGroupID]; g != nil {
+			tagsOf[t.URLMappingID] = append(tagsOf[t.URLMappingID], g)
+		}
+	}
+	for i, m := range mappings {
+		result[i] = linkGroups{campaign: byID[m.CampaignID], folder: byID[m.FolderID], tags: tagsOf[m.ID]}
+	}
+	return result, nil
+}
+
+// linksToProto describes mappings of user with their groups.
+func (s *UrlShortenerService) linksToProto(user *dataModel.User, mappings []dataModel.URLMapping) ([]*proto.Link, error) {
+	groups, err := s.mappingGroups(user, mappings)
+	if err != nil {
+		return nil, err
+	}
+	links := make([]*proto.Link, len(mappings))
+	for i := range mappings {
+		m := &mappings[i]
+		links[i] = &proto.Link{
+			ShortUrl:  displayShortURL(m),
+			LongUrl:   m.LongURL,
+			CreatedAt: timestamppb.New(m.CreatedAt),
+			Clicks:    m.Clicks,
+			Campaign:  groups[i].campaign,
+			Folder:    groups[i].folder,
+			Tags:      groups[i].tags,
+			Utm:       utmToProto(parseUTM(m.LongURL)),
+			Health:    linkHealthToProto(m.Health),
+		}
+	}
+	return links, nil
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestCreateLinkGroup(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	existing := []dataModel.LinkGroup{
+		{Model: gorm.Model{ID: 3}, UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindCampaign, Name: "Spring Sale"},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("ListLinkGroups", uint(1), "").Return(existing, nil).Once()
+		mockDb.On("CreateLinkGroup", &dataModel.LinkGroup{UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindTag, Name: "Spring Sale"}).Return(nil).Once()
+
+		resp, err := s.CreateLinkGroup(ctx, &proto.CreateLinkGroupRequest{ApiKey: "key", Kind: "tag", Name: "  Spring Sale "})
+		require.NoError(t, err)
+		assert.Equal(t, &proto.LinkGroup{Id: 1, Kind: "tag", Name: "Spring Sale", CreatedAt: resp.Group.CreatedAt}, resp.Group)
+		mockDb.AssertExpectations(t)
+	})
+
+	for name, req := range map[string]*proto.CreateLinkGroupRequest{
+		"Unknown kind":  {ApiKey: "key", Kind: "label", Name: "x"},
+		"Empty name":    {ApiKey: "key", Kind: "tag", Name: " "},
+		"Long name":     {ApiKey: "key", Kind: "tag", Name: strings.Repeat("a", MaxLinkGroupName+1)},
+		"Control chars": {ApiKey: "key", Kind: "tag", Name: "a\nb"},
+		"Taken":         {ApiKey: "key", Kind: "campaign", Name: "spring sale"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb}
+			mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+			mockDb.On("ListLinkGroups", uint(1), "").Return(existing, nil).Maybe()
+
+			_, err := s.CreateLinkGroup(ctx, req)
+			assert.ErrorIs(t, err, ErrInvalidLinkGroup)
+			mockDb.AssertNotCalled(t, "CreateLinkGroup", mock.Anything)
+		})
+	}
+
+	t.Run("Too many", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("ListLinkGroups", uint(1), "").Return(make([]dataModel.LinkGroup, MaxLinkGroupsPerWorkspace), nil).Once()
+
+		_, err := s.CreateLinkGroup(ctx, &proto.CreateLinkGroupRequest{ApiKey: "key", Kind: "folder", Name: "Archive"})
+		assert.ErrorIs(t, err, ErrTooManyLinkGroups)
+	})
+}
+
+func TestRenameLinkGroup(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	tags := []dataModel.LinkGroup{
+		{Model: gorm.Model{ID: 3}, UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindTag, Name: "news"},
+		{Model: gorm.Model{ID: 4}, UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindTag, Name: "press"},
+	}
+
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(user, nil)
+	mockDb.On("ListLinkGroups", uint(1), dataModel.GroupKindTag).Return(tags, nil)
+	mockDb.On("GetLinkGroup", uint(1), uint(3)).Return(&tags[0], nil)
+	mockDb.On("GetLinkGroup", uint(1), uint(9)).Return(nil, gorm.ErrRecordNotFound)
+	mockDb.On("RenameLinkGroup", &tags[0], "News").Return(nil).Once()
+
+	resp, err := s.RenameLinkGroup(ctx, &proto.RenameLinkGroupRequest{ApiKey: "key", Id: 3, Name: "News"})
+	require.NoError(t, err)
+	assert.Equal(t, "News", resp.Group.Name)
+
+	_, err = s.RenameLinkGroup(ctx, &proto.RenameLinkGroupRequest{ApiKey: "key", Id: 3, Name: "Press"})
+	assert.ErrorIs(t, err, ErrInvalidLinkGroup)
+	_, err = s.RenameLinkGroup(ctx, &proto.RenameLinkGroupRequest{ApiKey: "key", Id: 9, Name: "Other"})
+	assert.ErrorIs(t, err, ErrLinkGroupNotFound)
+	mockDb.AssertExpectations(t)
+}
+
+func TestAssignLinks(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	campaign := &dataModel.LinkGroup{Model: gorm.Model{ID: 5}, UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindCampaign, Name: "Launch"}
+	mappings := []dataModel.URLMapping{
+		{Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1},
+		{Model: gorm.Model{ID: 8}, ShortURLID: "def", UserID: 1, WorkspaceID: 1},
+	}
+
+	t.Run("Assign", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetLinkGroup", uint(1), uint(5)).Return(campaign, nil).Once()
+		mockDb.On("FindWorkspaceURLMappings", uint(1), "", []string{"abc", "def"}).Return(mappings, nil).Once()
+		mockDb.On("AssignLinks", campaign, []uint{7, 8}, true).Return(int64(2), nil).Once()
+
+		resp, err := s.AssignLinks(ctx, &proto.AssignLinksRequest{ApiKey: "key", Id: 5, ShortUrls: []string{"abc", "def", "abc"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Updated)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Unassign", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetLinkGroup", uint(1), uint(5)).Return(campaign, nil).Once()
+		mockDb.On("FindWorkspaceURLMappings", uint(1), "", []string{"abc"}).Return(mappings[:1], nil).Once()
+		mockDb.On("AssignLinks", campaign, []uint{7}, false).Return(int64(1), nil).Once()
+
+		_, err := s.AssignLinks(ctx, &proto.AssignLinksRequest{ApiKey: "key", Id: 5, ShortUrls: []string{"abc"}, Unassign: true})
+		require.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Unknown link", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetLinkGroup", uint(1), uint(5)).Return(campaign, nil).Once()
+		mockDb.On("FindWorkspaceURLMappings", uint(1), "", []string{"abc", "xyz"}).Return(mappings[:1], nil).Once()
+
+		_, err := s.AssignLinks(ctx, &proto.AssignLinksRequest{ApiKey: "key", Id: 5, ShortUrls: []string{"abc", "xyz"}})
+		assert.ErrorIs(t, err, ErrLinkNotFound)
+		assert.ErrorContains(t, err, "xyz")
+		mockDb.AssertNotCalled(t, "AssignLinks", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestListLinks(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	groups := []dataModel.LinkGroup{
+		{Model: gorm.Model{ID: 3}, UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindTag, Name: "news"},
+		{Model: gorm.Model{ID: 5}, UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindCampaign, Name: "Launch"},
+	}
+	mappings := []dataModel.URLMapping{
+		{Model: gorm.Model{ID: 8}, ShortURLID: "def", UserID: 1, WorkspaceID: 1, LongURL: "https://example.com/b", CampaignID: 5, Clicks: 40},
+		{Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1, LongURL: "https://example.com/a", CampaignID: 5},
+	}
+
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(user, nil)
+	mockDb.On("ListWorkspaceURLMappings", uint(1), dataModel.LinkFilter{CampaignID: 5, BeforeID: 10, Limit: 2}).Return(mappings, nil).Once()
+	mockDb.On("GetLinkClicks", []uint{8, 7}, mock.AnythingOfType("time.Time")).Return(map[uint]int64{8: 12}, nil).Once()
+	mockDb.On("ListLinkTags", []uint{8, 7}).Return([]dataModel.LinkTag{{URLMappingID: 7, LinkGroupID: 3}}, nil).Once()
+	mockDb.On("ListLinkGroups", uint(1), "").Return(groups, nil).Once()
+
+	resp, err := s.ListLinks(ctx, &proto.ListLinksRequest{ApiKey: "key", CampaignId: 5, PageSize: 2, PageToken: "10"})
+	require.NoError(t, err)
+	require.Len(t, resp.Links, 2)
+	assert.Equal(t, "7", resp.NextPageToken)
+	assert.Equal(t, "def", resp.Links[0].ShortUrl)
+	assert.Equal(t, int64(12), resp.Links[0].Clicks)
+	assert.Equal(t, "Launch", resp.Links[0].Campaign.Name)
+	assert.Empty(t, resp.Links[0].Tags)
+	require.Len(t, resp.Links[1].Tags, 1)
+	assert.Equal(t, "news", resp.Links[1].Tags[0].Name)
+	assert.Nil(t, resp.Links[1].Folder)
+
+	mockDb.On("ListWorkspaceURLMappings", uint(1), dataModel.LinkFilter{Limit: DefaultLinksPageSize}).Return(mappings[1:], nil).Once()
+	mockDb.On("GetLinkClicks", []uint{7}, mock.AnythingOfType("time.Time")).Return(map[uint]int64{}, nil).Once()
+	mockDb.On("ListLinkTags", []uint{7}).Return([]dataModel.LinkTag{}, nil).Once()
+	mockDb.On("ListLinkGroups", uint(1), "").Return(groups, nil).Once()
+	resp, err = s.ListLinks(ctx, &proto.ListLinksRequest{ApiKey: "key"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.NextPageToken)
+
+	_, err = s.ListLinks(ctx, &proto.ListLinksRequest{ApiKey: "key", PageToken: "next"})
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+	mockDb.AssertExpectations(t)
+}
+
+func TestGetLinkGroupStats(t *testing.T) {
+	user := testUser(1)
+	campaign := &dataModel.LinkGroup{Model: gorm.Model{ID: 5}, UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindCampaign, Name: "Launch"}
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+	mockDb.On("GetLinkGroup", uint(1), uint(5)).Return(campaign, nil).Once()
+	mockDb.On("GetLinkGroupStats", campaign, LinkGroupTopLinks, mock.AnythingOfType("time.Time")).Return(&dataModel.LinkGroupStats{
+		Links: 2, Clicks: 30, Conversions: 4,
+		TopLinks: []dataModel.URLMapping{
+			{Model: gorm.Model{ID: 8}, ShortURLID: "def", UserID: 1, WorkspaceID: 1, CampaignID: 5, Clicks: 20},
+		},
+		TopDomains: []dataModel.DomainCount{{DomainName: "example.com", Count: 30}},
+	}, nil).Once()
+	mockDb.On("ListLinkTags", []uint{8}).Return([]dataModel.LinkTag{}, nil).Once()
+	mockDb.On("ListLinkGroups", uint(1), "").Return([]dataModel.LinkGroup{*campaign}, nil).Once()
+
+	resp, err := s.GetLinkGroupStats(context.Background(), &proto.GetLinkGroupStatsRequest{ApiKey: "key", Id: 5})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), resp.Links)
+	assert.Equal(t, int64(30), resp.Clicks)
+	assert.Equal(t, int64(4), resp.Conversions)
+	require.Len(t, resp.TopLinks, 1)
+	assert.Equal(t, int64(20), resp.TopLinks[0].Clicks)
+	assert.Equal(t, []*proto.DomainMetric{{Domain: "example.com", Count: 30}}, resp.TopDomains)
+	mockDb.AssertExpectations(t)
+}
+
+func TestClickCounts(t *testing.T) {
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mapping := &dataModel.URLMapping{Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1, LongURL: "https://example.com/"}
+	mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Times(3)
+	for i := 0; i < 3; i++ {
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/d/abc", nil), map[string]string{"shortChar": "abc"})
+		s.redirectHandler(httptest.NewRecorder(), req)
+	}
+
+	mockDb.On("AddLinkClicks", map[uint]int64{7: 3}).Return(errors.New("connection reset")).Once()
+	s.writeClickCounts()
+	s.clickCounts.add(7)
+	mockDb.On("AddLinkClicks", map[uint]int64{7: 4}).Return(nil).Once()
+	s.writeClickCounts()
+	s.writeClickCounts()
+	mockDb.AssertExpectations(t)
+}
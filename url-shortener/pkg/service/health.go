@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/linkcheck"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newLinkChecker returns the checker of link destinations, nil when the
+// checks are turned off.
+func (s *UrlShortenerService) newLinkChecker(store linkcheck.Store) *linkcheck.Checker {
+	if s.Config.LinkCheckInterval <= 0 {
+		return nil
+	}
+	checker := linkcheck.NewChecker(store)
+	checker.Interval = s.Config.LinkCheckInterval
+	if s.Config.LinkCheckConcurrency > 0 {
+		checker.Concurrency = s.Config.LinkCheckConcurrency
+	}
+	if s.Config.LinkCheckDomainDelay > 0 {
+		checker.DomainDelay = s.Config.LinkCheckDomainDelay
+	}
+	// Destinations may have moved to internal addresses since they were
+	// shortened.
+	checker.CheckURL = s.checkDestinationHost
+	checker.OnBroken = s.notifyBrokenLink
+	return checker
+}
+
+// notifyBrokenLink tells the owner of mapping through their webhooks that
+// its destination broke.
+func (s *UrlShortenerService) notifyBrokenLink(mapping *dataModel.URLMapping) {
+	event := newLinkEvent(mapping)
+	event.StatusCode = mapping.Health.StatusCode
+	event.Error = mapping.Health.Error
+	s.emitWebhook(mapping.WorkspaceID, EventLinkBroken, event)
+}
+
+// ListBrokenLinks lists the links of the caller whose destination is broken.
+func (s *UrlShortenerService) ListBrokenLinks(ctx context.Context, req *proto.ListBrokenLinksRequest) (*proto.ListBrokenLinksResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = DefaultLinksPageSize
+	}
+	pageSize = min(pageSize, MaxLinksPageSize)
+	filter := dataModel.LinkFilter{Broken: true, Limit: pageSize}
+	if req.PageToken != "" {
+		before, err := strconv.ParseUint(req.PageToken, 10, 0)
+		if err != nil || before == 0 {
+			return nil, ErrInvalidPageToken
+		}
+		filter.BeforeID = uint(before)
+	}
+
+	mappings, err := s.db.ListWorkspaceURLMappings(user.Workspace.ID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.retainClicks(user, mappings); err != nil {
+		return nil, err
+	}
+	links, err := s.linksToProto(user, mappings)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListBrokenLinksResponse{Links: links}
+	if len(mappings) == pageSize {
+		resp.NextPageToken = strconv.FormatUint(uint64(mappings[len(mappings)-1].ID), 10)
+	}
+	return resp, nil
+}
+
+// linkHealthToProto returns nil for destinations never checked.
+func linkHealthToProto(h dataModel.LinkHealth) *proto.LinkHealth {
+	if h.CheckedAt == nil {
+		return nil
+	}
+	return &proto.LinkHealth{
+		CheckedAt:  timestamppb.New(*h.CheckedAt),
+		StatusCode: int32(h.StatusCode),
+		Error:      h.Error,
+		FinalUrl:   h.FinalURL,
+		LatencyMs:  h.LatencyMS,
+		Broken:     h.Broken,
+		Skipped:    h.Skipped,
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/webhook"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestLinkCheckerNotifiesOwner(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb, Config: Config{LinkCheckInterval: time.Hour, LinkCheckDomainDelay: time.Millisecond}}
+	s.webhooks = webhook.NewDispatcher(mockDb)
+	checker := s.newLinkChecker(mockDb)
+	require.NotNil(t, checker)
+
+	// One check failed before, this one makes the link broken.
+	mapping := dataModel.URLMapping{
+		Model: gorm.Model{ID: 7}, ShortURLID: "abc", LongURL: srv.URL + "/gone", UserID: 5, WorkspaceID: 5,
+		Health: dataModel.LinkHealth{Failures: 1},
+	}
+	mockDb.On("ClaimLinkChecks", mock.Anything, mock.Anything, mock.Anything).Return([]dataModel.URLMapping{mapping}, nil).Once()
+	var recorded dataModel.LinkHealth
+	mockDb.On("RecordLinkCheck", mock.Anything).Run(func(args mock.Arguments) {
+		recorded = args.Get(0).(*dataModel.URLMapping).Health
+	}).Return(nil).Once()
+	mockDb.On("ListWebhookSubscriptions", uint(5)).Return([]dataModel.WebhookSubscription{
+		{Model: gorm.Model{ID: 1}, Events: EventLinkBroken},
+	}, nil).Once()
+	var queued []*dataModel.WebhookDelivery
+	mockDb.On("CreateWebhookDeliveries", mock.Anything).Run(func(args mock.Arguments) {
+		queued = args.Get(0).([]*dataModel.WebhookDelivery)
+	}).Return(nil).Once()
+
+	n, err := checker.CheckDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.True(t, recorded.Broken)
+	assert.Equal(t, http.StatusNotFound, recorded.StatusCode)
+
+	require.Len(t, queued, 1)
+	assert.Equal(t, EventLinkBroken, queued[0].Event)
+	var payload struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(queued[0].Payload), &payload))
+	assert.Equal(t, float64(http.StatusNotFound), payload.Data["status_code"])
+	assert.Equal(t, "Not Found", payload.Data["error"])
+	mockDb.AssertExpectations(t)
+}
+
+func TestLinkCheckerOff(t *testing.T) {
+	s := &UrlShortenerService{Config: Config{LinkCheckInterval: 0}}
+	assert.Nil(t, s.newLinkChecker(new(MockDB)))
+}
+
+func TestListBrokenLinks(t *testing.T) {
+	checked := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	user := testUser(1)
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(user, nil)
+	mockDb.On("ListWorkspaceURLMappings", uint(1), dataModel.LinkFilter{Broken: true, Limit: 1}).Return([]dataModel.URLMapping{{
+		Model: gorm.Model{ID: 9}, ShortURLID: "abc", LongURL: "https://example.com/gone", UserID: 1, WorkspaceID: 1,
+		Health: dataModel.LinkHealth{
+			CheckedAt: &checked, StatusCode: http.StatusGone, Error: "Gone", FinalURL: "https://example.com/gone",
+			LatencyMS: 42, Failures: 2, Broken: true,
+		},
+	}}, nil).Once()
+	mockDb.On("GetLinkClicks", []uint{9}, mock.AnythingOfType("time.Time")).Return(map[uint]int64{}, nil).Once()
+	mockDb.On("ListLinkTags", []uint{9}).Return([]dataModel.LinkTag{}, nil).Once()
+
+	resp, err := s.ListBrokenLinks(context.Background(), &proto.ListBrokenLinksRequest{ApiKey: "key", PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.Links, 1)
+	health := resp.Links[0].Health
+	require.NotNil(t, health)
+	assert.True(t, health.Broken)
+	assert.Equal(t, int32(http.StatusGone), health.StatusCode)
+	assert.Equal(t, int64(42), health.LatencyMs)
+	assert.Equal(t, checked, health.CheckedAt.AsTime())
+	assert.Equal(t, "9", resp.NextPageToken)
+
+	_, err = s.ListBrokenLinks(context.Background(), &proto.ListBrokenLinksRequest{ApiKey: "key", PageToken: "x"})
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+	mockDb.AssertExpectations(t)
+}
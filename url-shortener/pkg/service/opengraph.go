@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+)
+
+var openGraphPage = template.Must(template.New("opengraph").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<meta property="og:type" content="website">
+{{- with .URL}}
+<meta property="og:url" content="{{.}}">
+{{- end}}
+{{- with .Title}}
+<meta property="og:title" content="{{.}}">
+<meta name="twitter:title" content="{{.}}">
+{{- end}}
+{{- with .Description}}
+<meta property="og:description" content="{{.}}">
+<meta name="twitter:description" content="{{.}}">
+{{- end}}
+{{- with .ImageURL}}
+<meta property="og:image" content="{{.}}">
+<meta name="twitter:image" content="{{.}}">
+<meta name="twitter:card" content="summary_large_image">
+{{- else}}
+<meta name="twitter:card" content="summary">
+{{- end}}
+</head>
+<body>
+{{- with .Title}}
+<h1>{{.}}</h1>
+{{- end}}
+{{- with .Description}}
+<p>{{.}}</p>
+{{- end}}
+</body>
+</html>
+`))
+
+type openGraphData struct {
+	dataModel.OpenGraph
+	URL string
+}
+
+// isCrawler reports whether userAgent belongs to a crawler unfurling links.
+// Crawlers are matched by whole words, so "teams" matches "Teams/1.0" but
+// not "Steamsbot/1.0".
+func (c Config) isCrawler(userAgent string) bool {
+	crawlers := c.CrawlerUserAgents
+	if len(crawlers) == 0 {
+		crawlers = DefaultCrawlerUserAgents
+	}
+	words := userAgentWords(userAgent)
+	for _, crawler := range crawlers {
+		if want := userAgentWords(crawler); len(want) > 0 && containsWords(words, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// userAgentWords splits s into lower case runs of letters and digits.
+func userAgentWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords reports whether want occurs in words as a contiguous run.
+func containsWords(words, want []string) bool {
+	for i := 0; i+len(want) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(want)], want) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderOpenGraph writes a page with the OpenGraph metadata of mapping for
+// crawlers. It does not redirect, since some crawlers would follow a
+// redirect and show the destination's metadata, nor name the destination:
+// anyone may claim to be a crawler, and click limited links or links behind
+// an interstitial must only lead there through a click.
+func (s *UrlShortenerService) renderOpenGraph(w http.ResponseWriter, mapping *dataModel.URLMapping) {
+	data := openGraphData{OpenGraph: mapping.OG}
+	if mapping.Host != "" {
+		data.URL = displayShortURL(mapping)
+	} else {
+		data.URL, _ = s.Config.shortLinkURL(mapping.ShortURLID)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := openGraphPage.Execute(w, data); err != nil {
+		log.Printf("Error rendering OpenGraph page of %s: %v", mapping.ShortURLID, err)
+	}
+}
+
+// openGraphFromProto validates OpenGraph metadata sent by a client.
+func openGraphFromProto(p *proto.OpenGraph) (dataModel.OpenGraph, error) {
+	var og dataModel.OpenGraph
+	if p == nil {
+		return og, nil
+	}
+	og = dataModel.OpenGraph{
+		Title:       strings.TrimSpace(p.Title),
+		Description: strings.TrimSpace(p.Description),
+		ImageURL:    strings.TrimSpace(p.ImageUrl),
+	}
+	if err := checkOpenGraphText("title", og.Title, MaxOGTitleLength); err != nil {
+		return og, err
+	}
+	if err := checkOpenGraphText("description", og.Description, MaxOGDescriptionLength); err != nil {
+		return og, err
+	}
+	if og.ImageURL != "" {
+		u, err := url.Parse(og.ImageURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return og, fmt.Errorf("%w: image URL must be an absolute http or https URL", ErrInvalidOpenGraph)
+		}
+		if len(og.ImageURL) > DefaultMaxURLLength {
+			return og, fmt.Errorf("%w: image URL is longer than %d characters", ErrInvalidOpenGraph, DefaultMaxURLLength)
+		}
+	}
+	return og, nil
+}
+
+func checkOpenGraphText(field, value string, maxLength int) error {
+	if utf8.RuneCountInString(value) > maxLength {
+		return fmt.Errorf("%w: %s is longer than %d characters", ErrInvalidOpenGraph, field, maxLength)
+	}
+	// Line breaks are fine in descriptions.
+	if !utf8.ValidString(value) || strings.ContainsFunc(value, func(r rune) bool { return unicode.IsControl(r) && r != '\n' }) {
+		return fmt.Errorf("%w: %s has control characters", ErrInvalidOpenGraph, field)
+	}
+	return nil
+}
+
+// openGraphToProto returns nil when og is empty.
+func openGraphToProto(og dataModel.OpenGraph) *proto.OpenGraph {
+	if og.IsZero() {
+		return nil
+	}
+	return &proto.OpenGraph{Title: og.Title, Description: og.Description, ImageUrl: og.ImageURL}
+}
+
+// SetLinkOpenGraph sets the OpenGraph metadata of a link of the caller.
+func (s *UrlShortenerService) SetLinkOpenGraph(ctx context.Context, req *proto.SetLinkOpenGraphRequest) (*proto.SetLinkOpenGraphResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.userLink(user, req.Domain, req.ShortUrl)
+	if err != nil {
+		return nil, err
+	}
+	og, err := openGraphFromProto(req.Og)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.SetLinkOpenGraph(mapping, og); err != nil {
+		log.Printf("Error setting OpenGraph metadata of %s: %v", mapping.ShortURLID, err)
+		return nil, err
+	}
+	s.emitWebhook(user.Workspace.ID, EventLinkUpdated, newLinkEvent(mapping))
+	return &proto.SetLinkOpenGraphResponse{Og: openGraphToProto(og)}, nil
+}
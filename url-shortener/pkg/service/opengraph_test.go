@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const slackbot = "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"
+
+func TestIsCrawler(t *testing.T) {
+	var c Config
+	assert.True(t, c.isCrawler(slackbot))
+	assert.True(t, c.isCrawler("facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)"))
+	assert.True(t, c.isCrawler("Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)"))
+	assert.False(t, c.isCrawler("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36"))
+	assert.False(t, c.isCrawler("curl/8.5.0"))
+	assert.False(t, c.isCrawler(""))
+	assert.True(t, c.isCrawler("Mozilla/5.0 (compatible; Teams/1.0)"))
+	assert.False(t, c.isCrawler("Mozilla/5.0 (compatible; Steamsbot/1.0)"))
+	assert.True(t, c.isCrawler("Slack-ImgProxy (+https://api.slack.com/robots)"))
+
+	c.CrawlerUserAgents = []string{"AcmeUnfurler"}
+	assert.True(t, c.isCrawler("acmeunfurler/2.1"))
+	assert.False(t, c.isCrawler(slackbot))
+}
+
+func TestOpenGraphRedirect(t *testing.T) {
+	og := dataModel.OpenGraph{
+		Title: `Spring "Sale"`, Description: "Up to 50% off", ImageURL: "https://cdn.example.com/sale.png",
+	}
+	tests := []struct {
+		name      string
+		og        dataModel.OpenGraph
+		userAgent string
+		maxClicks int64
+		wantPage  bool
+	}{
+		{"Crawler", og, slackbot, 0, true},
+		{"Crawler of click limited link", og, slackbot, 1, true},
+		{"Visitor", og, "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148", 0, false},
+		{"No metadata", dataModel.OpenGraph{}, slackbot, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb, Config: Config{PublicBaseURL: "https://sho.rt"}}
+			mapping := &dataModel.URLMapping{
+				Model: gorm.Model{ID: 7}, ShortURLID: "abc", LongURL: "https://example.com/sale",
+				RedirectStatus: http.StatusFound, MaxClicks: tt.maxClicks, ClicksRemaining: tt.maxClicks, OG: tt.og,
+			}
+			mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+			mockDb.On("ConsumeClick", "", "abc").Return(int64(0), nil).Maybe()
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/d/abc", nil), map[string]string{"shortChar": "abc"})
+			req.Header.Set("User-Agent", tt.userAgent)
+			rr := httptest.NewRecorder()
+			s.redirectHandler(rr, req)
+
+			if !tt.wantPage {
+				assert.Equal(t, http.StatusFound, rr.Code)
+				assert.Equal(t, "https://example.com/sale", rr.Header().Get("Location"))
+				assert.Equal(t, !tt.og.IsZero(), rr.Header().Get("Vary") == "User-Agent")
+				return
+			}
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, "User-Agent", rr.Header().Get("Vary"))
+			body := rr.Body.String()
+			assert.Contains(t, body, `<meta property="og:title" content="Spring &#34;Sale&#34;">`)
+			assert.Contains(t, body, `<meta property="og:description" content="Up to 50% off">`)
+			assert.Contains(t, body, `<meta property="og:image" content="https://cdn.example.com/sale.png">`)
+			assert.Contains(t, body, `<meta property="og:url" content="https://sho.rt/d/abc">`)
+			assert.Contains(t, body, `<meta name="twitter:card" content="summary_large_image">`)
+			assert.NotContains(t, body, "//example.com/sale")
+			assert.Contains(t, body, "<h1>Spring &#34;Sale&#34;</h1>")
+			mockDb.AssertNotCalled(t, "ConsumeClick", mock.Anything, mock.Anything)
+			assert.Zero(t, s.clickCounts.take()[7])
+		})
+	}
+}
+
+func TestOpenGraphFromProto(t *testing.T) {
+	og, err := openGraphFromProto(&proto.OpenGraph{Title: " Launch ", Description: "Line one\nline two", ImageUrl: "https://cdn.example.com/a.png"})
+	require.NoError(t, err)
+	assert.Equal(t, dataModel.OpenGraph{Title: "Launch", Description: "Line one\nline two", ImageURL: "https://cdn.example.com/a.png"}, og)
+
+	for name, p := range map[string]*proto.OpenGraph{
+		"Long title":         {Title: strings.Repeat("a", MaxOGTitleLength+1)},
+		"Long description":   {Description: strings.Repeat("a", MaxOGDescriptionLength+1)},
+		"Control characters": {Title: "a\x00b"},
+		"Relative image":     {ImageUrl: "/a.png"},
+		"Image scheme":       {ImageUrl: "javascript:alert(1)"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := openGraphFromProto(p)
+			assert.ErrorIs(t, err, ErrInvalidOpenGraph)
+		})
+	}
+}
+
+func TestSetLinkOpenGraph(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(user, nil)
+	mockDb.On("GetURLMapping", "abc").Return(&dataModel.URLMapping{Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1}, nil)
+	mockDb.On("SetLinkOpenGraph", mock.Anything, dataModel.OpenGraph{Title: "Launch"}).Return(nil).Once()
+
+	resp, err := s.SetLinkOpenGraph(ctx, &proto.SetLinkOpenGraphRequest{ApiKey: "key", ShortUrl: "abc", Og: &proto.OpenGraph{Title: "Launch "}})
+	require.NoError(t, err)
+	assert.Equal(t, "Launch", resp.Og.Title)
+
+	_, err = s.SetLinkOpenGraph(ctx, &proto.SetLinkOpenGraphRequest{ApiKey: "key", ShortUrl: "abc", Og: &proto.OpenGraph{ImageUrl: "ftp://x/a.png"}})
+	assert.ErrorIs(t, err, ErrInvalidOpenGraph)
+	mockDb.AssertExpectations(t)
+}
+
+func TestShortenURLWithOpenGraph(t *testing.T) {
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return 12345, nil }
+	t.Cleanup(func() {
+		requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return s.requestCounter() }
+	})
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil).Once()
+	var created *dataModel.URLMapping
+	mockDb.On("CreateURLMapping", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*dataModel.URLMapping)
+	}).Return(nil).Once()
+
+	_, err := s.ShortenURL(context.Background(), &proto.ShortenURLRequest{
+		ApiKey: "key", LongUrl: "https://example.com/", Og: &proto.OpenGraph{Title: "Launch"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	assert.Equal(t, "Launch", created.OG.Title)
+	mockDb.AssertNotCalled(t, "GetURLMappingByLongURL", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// passwordFormField is the form field the unlock page posts the password in.
+const passwordFormField = "link_password"
+
+var passwordForm = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Password required</title></head>
+<body>
+<form method="post">
+<p>This link is password protected.</p>
+{{if .}}<p>{{.}}</p>{{end}}
+<input type="password" name="` + passwordFormField + `" autofocus required>
+<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+// hashLinkPassword returns the bcrypt hash stored for a link password.
+func hashLinkPassword(password string) (string, error) {
+	if len(password) > MaxLinkPasswordLength {
+		return "", ErrInvalidLinkPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// checkLinkPassword verifies password against a protected mapping. Failures
+// are counted per link and client, and once MaxPasswordAttempts is reached
+// within PasswordAttemptWindow every attempt is refused until the window
+// has passed. Failures are counted in memory, per replica.
+func (s *UrlShortenerService) checkLinkPassword(mapping *dataModel.URLMapping, client, password string) error {
+	key := mapping.ShortURLID + "|" + client
+	if !s.passwordAttempts.allow(key, s.maxPasswordAttempts(), time.Now()) {
+		return ErrTooManyPasswordAttempts
+	}
+	if password == "" {
+		return ErrPasswordRequired
+	}
+	// bcrypt compares the derived hashes in constant time.
+	if err := bcrypt.CompareHashAndPassword([]byte(mapping.PasswordHash), []byte(password)); err != nil {
+		s.passwordAttempts.fail(key, time.Now())
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrWrongPassword
+		}
+		log.Printf("Error checking password of %s: %v", mapping.ShortURLID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *UrlShortenerService) maxPasswordAttempts() int {
+	if s.Config.MaxPasswordAttempts > 0 {
+		return s.Config.MaxPasswordAttempts
+	}
+	return DefaultMaxPasswordAttempts
+}
+
+// serveLinkPassword handles requests for a password protected link. It
+// reports whether the request may be redirected; otherwise the response has
+// been written. A valid unlock cookie lets the request through, a posted
+// password sets that cookie and redirects back to the link, and anything
+// else gets the password form.
+func (s *UrlShortenerService) serveLinkPassword(w http.ResponseWriter, r *http.Request, mapping *dataModel.URLMapping) bool {
+	if cookie, err := r.Cookie(linkCookieName(mapping.ShortURLID)); err == nil && s.validLinkCookie(mapping, cookie.Value, time.Now()) {
+		return true
+	}
+	if r.Method != http.MethodPost || r.PostFormValue(passwordFormField) == "" {
+		renderPasswordForm(w, http.StatusUnauthorized, "")
+		return false
+	}
+
+	err := s.checkLinkPassword(mapping, s.Config.httpClientAddr(r), r.PostFormValue(passwordFormField))
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrTooManyPasswordAttempts):
+		renderPasswordForm(w, http.StatusTooManyRequests, "Too many wrong passwords, try again later.")
+		return false
+	case errors.Is(err, ErrWrongPassword):
+		renderPasswordForm(w, http.StatusUnauthorized, "Wrong password.")
+		return false
+	default:
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return false
+	}
+
+	ttl := s.Config.LinkCookieTTL
+	if ttl <= 0 {
+		ttl = DefaultLinkCookieTTL
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     linkCookieName(mapping.ShortURLID),
+		Value:    s.signLinkCookie(mapping, time.Now().Add(ttl)),
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, r.URL.RequestURI(), http.StatusSeeOther)
+	return false
+}
+
+func renderPasswordForm(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := passwordForm.Execute(w, message); err != nil {
+		log.Printf("Error rendering password form: %v", err)
+	}
+}
+
+func linkCookieName(shortURL string) string {
+	return "link_" + shortURL
+}
+
+// signLinkCookie returns a cookie value unlocking mapping until expires. The
+// signature covers the password hash, so changing the password invalidates
+// issued cookies.
+func (s *UrlShortenerService) signLinkCookie(mapping *dataModel.URLMapping, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return exp + "." + hex.EncodeToString(s.linkCookieMAC(mapping, exp))
+}
+
+func (s *UrlShortenerService) validLinkCookie(mapping *dataModel.URLMapping, value string, now time.Time) bool {
+	exp, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return false
+	}
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || now.Unix() >= expires {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, s.linkCookieMAC(mapping, exp))
+}
+
+func (s *UrlShortenerService) linkCookieMAC(mapping *dataModel.URLMapping, exp string) []byte {
+	mac := hmac.New(sha256.New, s.linkCookieSecret())
+	fmt.Fprintf(mac, "%s|%s|%s", mapping.ShortURLID, exp, mapping.PasswordHash)
+	return mac.Sum(nil)
+}
+
+// linkCookieSecret returns the configured cookie secret, generating a random
+// one on first use when none is set.
+func (s *UrlShortenerService) linkCookieSecret() []byte {
+	s.cookieSecretOnce.Do(func() {
+		if len(s.Config.LinkCookieSecret) > 0 {
+			return
+		}
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic(fmt.Sprintf("generating link cookie secret: %v", err))
+		}
+		log.Printf("%s is not set, unlocked password protected links do not survive restarts", LinkCookieSecret)
+		s.Config.LinkCookieSecret = secret
+	})
+	return s.Config.LinkCookieSecret
+}
+
+// grpcClientAddr returns the IP address of the client of a gRPC call. Calls
+// relayed by the local gateway are attributed to the address the gateway
+// appended to X-Forwarded-For.
+func grpcClientAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return host
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if fwd := md.Get("x-forwarded-for"); len(fwd) > 0 {
+		hops := strings.Split(fwd[len(fwd)-1], ",")
+		return strings.TrimSpace(hops[len(hops)-1])
+	}
+	return host
+}
+
+// attemptLimiter counts failures per key over a sliding window. Keys
+// without failures in the window are swept as new keys come in, and at
+// most maxKeys are kept. The zero value is ready to use.
+type attemptLimiter struct {
+	mu       sync.Mutex
+	failures map[string][]time.Time
+	// maxKeys defaults to MaxPasswordAttemptKeys.
+	maxKeys int
+	// sweepAt is how many keys trigger the next sweep.
+	sweepAt int
+}
+
+// allow reports whether key has fewer than max failures within
+// PasswordAttemptWindow before now.
+func (l *attemptLimiter) allow(key string, max int, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.recent(key, now)) < max
+}
+
+// fail records a failure for key at now.
+func (l *attemptLimiter) fail(key string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.failures == nil {
+		l.failures = make(map[string][]time.Time)
+	}
+	if _, ok := l.failures[key]; !ok && len(l.failures) >= l.sweepAt {
+		l.sweep(now)
+	}
+	l.failures[key] = append(l.recent(key, now), now)
+}
+
+// sweep drops the keys without failures within the window and, past
+// maxKeys, those failing longest ago, leaving room for a tenth more. The
+// next sweep is due once the keys left doubled. The caller must hold l.mu.
+func (l *attemptLimiter) sweep(now time.Time) {
+	maxKeys := l.maxKeys
+	if maxKeys <= 0 {
+		maxKeys = MaxPasswordAttemptKeys
+	}
+	for key, failures := range l.failures {
+		if now.Sub(failures[len(failures)-1]) >= PasswordAttemptWindow {
+			delete(l.failures, key)
+		}
+	}
+	if len(l.failures) >= maxKeys {
+		keys := make([]string, 0, len(l.failures))
+		for key := range l.failures {
+			keys = append(keys, key)
+		}
+		last := func(key string) time.Time { return l.failures[key][len(l.failures[key])-1] }
+		slices.SortFunc(keys, func(a, b string) int { return last(a).Compare(last(b)) })
+		for _, key := range keys[:len(keys)-(maxKeys-maxKeys/10-1)] {
+			delete(l.failures, key)
+		}
+	}
+	l.sweepAt = min(max(2*len(l.failures), 1024), maxKeys)
+}
+
+// recent drops failures of key older than the window and returns the rest.
+// The caller must hold l.mu.
+func (l *attemptLimiter) recent(key string, now time.Time) []time.Time {
+	failures := l.failures[key]
+	i := 0
+	for i < len(failures) && now.Sub(failures[i]) >= PasswordAttemptWindow {
+		i++
+	}
+	failures = failures[i:]
+	if len(failures) == 0 {
+		delete(l.failures, key)
+		return nil
+	}
+	l.failures[key] = failures
+	return failures
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func protectedMapping(t *testing.T, password string) *dataModel.URLMapping {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	assert.NoError(t, err)
+	return &dataModel.URLMapping{ShortURLID: "abc", LongURL: "https://example.com/doc", PasswordHash: string(hash)}
+}
+
+func TestShortenURLPassword(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return 99, nil }
+
+	t.Run("Password is stored hashed", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("CreateURLMapping", mock.MatchedBy(func(m *dataModel.URLMapping) bool {
+			return m.PasswordHash != "secret" &&
+				bcrypt.CompareHashAndPassword([]byte(m.PasswordHash), []byte("secret")) == nil
+		})).Return(nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/", Password: "secret"})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Password too long", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/", Password: strings.Repeat("x", 73)})
+		assert.Equal(t, ErrInvalidLinkPassword, err)
+	})
+
+	t.Run("Protected links get their own mapping", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("CreateURLMapping", mock.MatchedBy(func(m *dataModel.URLMapping) bool {
+			return m.LongURL == "http://example.com/" && m.PasswordHash != ""
+		})).Return(nil).Once()
+
+		resp, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/", Password: "secret"})
+		assert.NoError(t, err)
+		assert.False(t, resp.Reused)
+		mockDb.AssertNotCalled(t, "GetURLMappingByLongURL", mock.Anything, mock.Anything)
+		mockDb.AssertExpectations(t)
+	})
+}
+
+func TestGetURLPassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Password required", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetURLMapping", "abc").Return(protectedMapping(t, "secret"), nil).Once()
+
+		_, err := s.GetURL(ctx, &proto.GetURLRequest{ShortUrl: "abc"})
+		assert.Equal(t, ErrPasswordRequired, err)
+	})
+
+	t.Run("Correct password", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetURLMapping", "abc").Return(protectedMapping(t, "secret"), nil).Once()
+
+		resp, err := s.GetURL(ctx, &proto.GetURLRequest{ShortUrl: "abc", Password: "secret"})
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com/doc", resp.LongUrl)
+	})
+
+	t.Run("Failed attempts are rate limited", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, Config: Config{MaxPasswordAttempts: 2}}
+		mockDb.On("GetURLMapping", "abc").Return(protectedMapping(t, "secret"), nil)
+
+		for i := 0; i < 2; i++ {
+			_, err := s.GetURL(ctx, &proto.GetURLRequest{ShortUrl: "abc", Password: "guess"})
+			assert.Equal(t, ErrWrongPassword, err)
+		}
+		_, err := s.GetURL(ctx, &proto.GetURLRequest{ShortUrl: "abc", Password: "secret"})
+		assert.Equal(t, ErrTooManyPasswordAttempts, err)
+	})
+}
+
+func TestRedirectHandlerPassword(t *testing.T) {
+	newRequest := func(method string, form url.Values, cookies ...*http.Cookie) *http.Request {
+		var req *http.Request
+		if form != nil {
+			req = httptest.NewRequest(method, "/d/abc", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		} else {
+			req = httptest.NewRequest(method, "/d/abc", nil)
+		}
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		return mux.SetURLVars(req, map[string]string{"shortChar": "abc"})
+	}
+
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb, Config: Config{LinkCookieSecret: []byte("test-secret")}}
+	mapping := protectedMapping(t, "secret")
+	mockDb.On("GetURLMapping", "abc").Return(mapping, nil)
+
+	t.Run("Form is served", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		s.redirectHandler(rr, newRequest("GET", nil))
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), `name="link_password"`)
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		s.redirectHandler(rr, newRequest("POST", url.Values{"link_password": {"guess"}}))
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Wrong password.")
+	})
+
+	t.Run("Correct password unlocks the link", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		s.redirectHandler(rr, newRequest("POST", url.Values{"link_password": {"secret"}}))
+		assert.Equal(t, http.StatusSeeOther, rr.Code)
+		cookies := rr.Result().Cookies()
+		if assert.Len(t, cookies, 1) {
+			assert.True(t, cookies[0].HttpOnly)
+
+			rr = httptest.NewRecorder()
+			s.redirectHandler(rr, newRequest("GET", nil, cookies[0]))
+			assert.Equal(t, http.StatusFound, rr.Code)
+			assert.Equal(t, "https://example.com/doc", rr.Header().Get("Location"))
+		}
+	})
+
+	t.Run("Forged and expired cookies are rejected", func(t *testing.T) {
+		expired := s.signLinkCookie(mapping, time.Now().Add(-time.Minute))
+		for _, value := range []string{expired, "9999999999.00", "garbage"} {
+			rr := httptest.NewRecorder()
+			s.redirectHandler(rr, newRequest("GET", nil, &http.Cookie{Name: "link_abc", Value: value}))
+			assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		}
+	})
+}
+
+func TestAttemptLimiterForgetsKeys(t *testing.T) {
+	now := time.Now()
+	l := &attemptLimiter{maxKeys: 10}
+	l.fail("stale", now.Add(-PasswordAttemptWindow))
+	for i := 0; i < 20; i++ {
+		l.fail(fmt.Sprintf("key%d", i), now.Add(time.Duration(i)*time.Second))
+	}
+	assert.LessOrEqual(t, len(l.failures), 10)
+	assert.NotContains(t, l.failures, "stale")
+	assert.NotContains(t, l.failures, "key0", "keys failing longest ago are forgotten first")
+	assert.Contains(t, l.failures, "key19")
+	assert.False(t, l.allow("key19", 1, now.Add(20*time.Second)))
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/policy"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+
+	"gorm.io/gorm"
+)
+
+// dbPolicySource is the policy source named in rules loaded from the database.
+const dbPolicySource = "database"
+
+// linksChanged is told about the links a policy change disabled, with
+// EventLinkDeleted, or enabled again, with EventLinkUpdated.
+type linksChanged func(event string, mappings []dataModel.URLMapping)
+
+// newPolicyEngine builds the policy engine from the rules file (if any) and
+// the policy_rules table. Block rules added to the file later disable the
+// existing links they match; removed block rules and new allow rules enable
+// the links the policy no longer blocks. Both are reported to changed.
+func newPolicyEngine(cfg Config, db dataModel.DataAccessLayer, changed linksChanged) *policy.Engine {
+	var sources []policy.Source
+	if cfg.PolicyFile != "" {
+		sources = append(sources, &policy.FileSource{Path: cfg.PolicyFile})
+	}
+	sources = append(sources, policy.SourceFunc{
+		SourceName: dbPolicySource,
+		LoadFunc: func() ([]policy.Rule, error) {
+			rows, err := db.ListPolicyRules()
+			if err != nil {
+				return nil, err
+			}
+			rules := make([]policy.Rule, 0, len(rows))
+			for _, row := range rows {
+				rules = append(rules, policyRuleFromModel(row))
+			}
+			return rules, nil
+		},
+	})
+
+	engine := policy.NewEngine(sources...)
+	engine.OnNewBlockRule = func(rule policy.Rule) {
+		if rule.Source == dbPolicySource {
+			return // AddPolicyRule already disabled the matching links
+		}
+		disabled, _, err := applyPolicy(db, engine, policyScope(rule, false), changed)
+		if err != nil {
+			log.Printf("Error disabling links matching %s %s: %v", rule.Kind, rule.Pattern, err)
+			return
+		}
+		log.Printf("Disabled %d links matching new rule %s %s from %s", disabled, rule.Kind, rule.Pattern, rule.Source)
+	}
+	engine.OnLiftedRule = func(rule policy.Rule) {
+		_, enabled, err := applyPolicy(db, engine, policyScope(rule, true), changed)
+		if err != nil {
+			log.Printf("Error enabling links matching %s %s: %v", rule.Kind, rule.Pattern, err)
+			return
+		}
+		log.Printf("Enabled %d links no longer blocked after %s rule %s %s from %s changed",
+			enabled, rule.Action, rule.Kind, rule.Pattern, rule.Source)
+	}
+	return engine
+}
+
+// policyScope selects the enabled or disabled links rule may apply to.
+func policyScope(rule policy.Rule, disabled bool) dataModel.PolicyScope {
+	scope := dataModel.PolicyScope{Kind: string(rule.Kind), Pattern: rule.Pattern, Disabled: disabled}
+	if disabled && rule.Action == policy.ActionBlock {
+		scope.RuleID = rule.ID
+	}
+	return scope
+}
+
+// applyPolicy checks the links in scope against the rules engine enforces,
+// disabling those it blocks and enabling the others. Blocked links record
+// the rule blocking them. The links disabled or enabled are reported to
+// changed, if not nil. It returns how many links were disabled and enabled.
+func applyPolicy(db dataModel.DataAccessLayer, engine *policy.Engine, scope dataModel.PolicyScope, changed linksChanged) (disabled, enabled int64, err error) {
+	type verdict struct {
+		ruleID uint
+		reason string
+	}
+	for afterID := uint(0); ; {
+		mappings, err := db.ListPolicyCandidates(scope, afterID, PolicyBatchSize)
+		if err != nil {
+			return disabled, enabled, err
+		}
+		blocked := make(map[verdict][]uint)
+		var allowed []uint
+		var newlyBlocked, lifted []dataModel.URLMapping
+		for _, m := range mappings {
+			decision := engine.Check(m.LongURL)
+			if !decision.Blocked {
+				if m.Disabled {
+					allowed = append(allowed, m.ID)
+					lifted = append(lifted, m)
+				}
+				continue
+			}
+			v := verdict{ruleID: decision.Rule.ID, reason: blockedReason(*decision.Rule)}
+			if m.Disabled && m.DisabledRuleID == v.ruleID && m.DisabledReason == v.reason {
+				continue
+			}
+			blocked[v] = append(blocked[v], m.ID)
+			if !m.Disabled {
+				newlyBlocked = append(newlyBlocked, m)
+			}
+		}
+		for v, ids := range blocked {
+			if _, err := db.DisableURLMappings(ids, v.ruleID, v.reason); err != nil {
+				return disabled, enabled, err
+			}
+		}
+		disabled += int64(len(newlyBlocked))
+		if len(allowed) > 0 {
+			n, err := db.EnableURLMappings(allowed)
+			if err != nil {
+				return disabled, enabled, err
+			}
+			enabled += n
+		}
+		if changed != nil {
+			if len(newlyBlocked) > 0 {
+				changed(EventLinkDeleted, newlyBlocked)
+			}
+			if len(lifted) > 0 {
+				changed(EventLinkUpdated, lifted)
+			}
+		}
+		if len(mappings) < PolicyBatchSize {
+			return disabled, enabled, nil
+		}
+		afterID = mappings[len(mappings)-1].ID
+	}
+}
+
+// checkDestination rejects long URLs blocked by the policy engine.
+func (s *UrlShortenerService) checkDestination(longURL string) error {
+	if s.policy == nil {
+		return nil
+	}
+	if decision := s.policy.Check(longURL); decision.Blocked {
+		return fmt.Errorf("%w: %s", ErrBlockedDestination, blockedReason(*decision.Rule))
+	}
+	return nil
+}
+
+// blockedReason describes why a rule blocks a URL.
+func blockedReason(rule policy.Rule) string {
+	if rule.Reason != "" {
+		return rule.Reason
+	}
+	return fmt.Sprintf("matches %s rule %q", rule.Kind, rule.Pattern)
+}
+
+// authenticateAdmin resolves an API key to its user and requires the user to be an admin.
+func (s *UrlShortenerService) authenticateAdmin(apiKey string) (*dataModel.User, error) {
+	user, err := s.authenticate(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin {
+		return nil, ErrPermissionDenied
+	}
+	return user, nil
+}
+
+// AddPolicyRule stores a new policy rule and reloads the policy engine.
+// Existing links matching a new block rule are disabled, and those a new
+// allow rule exempts are enabled.
+func (s *UrlShortenerService) AddPolicyRule(ctx context.Context, req *proto.AddPolicyRuleRequest) (*proto.AddPolicyRuleResponse, error) {
+	user, err := s.authenticateAdmin(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := policy.Rule{
+		Kind:    policy.Kind(req.Kind),
+		Pattern: req.Pattern,
+		Action:  policy.Action(req.Action),
+		Reason:  req.Reason,
+		Source:  dbPolicySource,
+	}
+	if err := rule.Compile(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPolicyRule, err)
+	}
+
+	row := &dataModel.PolicyRule{
+		Kind:      string(rule.Kind),
+		Pattern:   rule.Pattern,
+		Action:    string(rule.Action),
+		Reason:    rule.Reason,
+		CreatedBy: user.ID,
+	}
+	if err := s.db.CreatePolicyRule(row); err != nil {
+		log.Printf("Error creating policy rule: %v", err)
+		return nil, err
+	}
+	rule.ID = row.ID
+
+	// Links are checked against the reloaded rules, so allow rules exempt
+	// them and they record the rule blocking them.
+	var disabled int64
+	if s.policy != nil {
+		if err := s.policy.Reload(); err != nil {
+			log.Printf("Error reloading policy rules: %v", err)
+			return nil, err
+		}
+		if rule.Action == policy.ActionBlock {
+			disabled, _, err = applyPolicy(s.db, s.policy, policyScope(rule, false), s.emitLinksChanged)
+			if err != nil {
+				log.Printf("Error disabling links matching rule %d: %v", row.ID, err)
+				return nil, err
+			}
+		}
+	}
+
+	return &proto.AddPolicyRuleResponse{Rule: policyRuleToProto(rule), DisabledLinks: disabled}, nil
+}
+
+// RemovePolicyRule deletes a policy rule and reloads the policy engine.
+// Links disabled by the rule are enabled unless another rule blocks them.
+func (s *UrlShortenerService) RemovePolicyRule(ctx context.Context, req *proto.RemovePolicyRuleRequest) (*proto.RemovePolicyRuleResponse, error) {
+	if _, err := s.authenticateAdmin(req.ApiKey); err != nil {
+		return nil, err
+	}
+	if err := s.db.DeletePolicyRule(uint(req.Id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPolicyRuleNotFound
+		}
+		return nil, err
+	}
+	s.reloadPolicy()
+	return &proto.RemovePolicyRuleResponse{}, nil
+}
+
+// ListPolicyRules returns the rules currently enforced, from all sources.
+func (s *UrlShortenerService) ListPolicyRules(ctx context.Context, req *proto.ListPolicyRulesRequest) (*proto.ListPolicyRulesResponse, error) {
+	if _, err := s.authenticateAdmin(req.ApiKey); err != nil {
+		return nil, err
+	}
+	resp := &proto.ListPolicyRulesResponse{}
+	if s.policy == nil {
+		return resp, nil
+	}
+	for _, rule := range s.policy.Rules() {
+		resp.Rules = append(resp.Rules, policyRuleToProto(rule))
+	}
+	return resp, nil
+}
+
+// reloadPolicy applies rule changes right away instead of on the next tick.
+func (s *UrlShortenerService) reloadPolicy() {
+	if s.policy == nil {
+		return
+	}
+	if err := s.policy.Reload(); err != nil {
+		log.Printf("Error reloading policy rules: %v", err)
+	}
+}
+
+func policyRuleFromModel(row dataModel.PolicyRule) policy.Rule {
+	return policy.Rule{
+		ID:      row.ID,
+		Kind:    policy.Kind(row.Kind),
+		Pattern: row.Pattern,
+		Action:  policy.Action(row.Action),
+		Reason:  row.Reason,
+		Source:  dbPolicySource,
+	}
+}
+
+func policyRuleToProto(rule policy.Rule) *proto.PolicyRule {
+	return &proto.PolicyRule{
+		Id:      uint64(rule.ID),
+		Kind:    string(rule.Kind),
+		Pattern: rule.Pattern,
+		Action:  string(rule.Action),
+		Reason:  rule.Reason,
+		Source:  rule.Source,
+	}
+}
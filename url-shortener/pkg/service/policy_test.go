@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/webhook"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestDestinationPolicy(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	newService := func(rules ...dataModel.PolicyRule) (*UrlShortenerService, *MockDB) {
+		mockDb := new(MockDB)
+		mockDb.On("ListPolicyRules").Return(rules, nil)
+		s := &UrlShortenerService{db: mockDb, policy: newPolicyEngine(Config{}, mockDb, nil)}
+		assert.NoError(t, s.policy.Reload())
+		return s, mockDb
+	}
+
+	t.Run("ShortenURL rejects blocked destination", func(t *testing.T) {
+		s, mockDb := newService(dataModel.PolicyRule{Kind: "suffix", Pattern: "phish.example", Action: "block", Reason: "phishing"})
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+
+		resp, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "https://login.phish.example/"})
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBlockedDestination)
+		assert.Contains(t, err.Error(), "phishing")
+	})
+
+	t.Run("Redirect to newly blocked destination is gone", func(t *testing.T) {
+		s, mockDb := newService(dataModel.PolicyRule{Kind: "domain", Pattern: "evil.com", Action: "block"})
+		mockDb.On("GetURLMapping", "abc").Return(&dataModel.URLMapping{LongURL: "http://evil.com/"}, nil).Once()
+
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/d/abc", nil), map[string]string{"shortChar": "abc"})
+		rr := httptest.NewRecorder()
+		s.redirectHandler(rr, req)
+		assert.Equal(t, http.StatusGone, rr.Code)
+	})
+
+	t.Run("Redirect of disabled link is gone", func(t *testing.T) {
+		s, mockDb := newService()
+		mockDb.On("GetURLMapping", "abc").Return(&dataModel.URLMapping{LongURL: "http://example.com/", Disabled: true}, nil).Once()
+
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/d/abc", nil), map[string]string{"shortChar": "abc"})
+		rr := httptest.NewRecorder()
+		s.redirectHandler(rr, req)
+		assert.Equal(t, http.StatusGone, rr.Code)
+	})
+}
+
+func TestPolicyAdminRPCs(t *testing.T) {
+	ctx := context.Background()
+	admin := &dataModel.User{Model: gorm.Model{ID: 9}, IsAdmin: true}
+
+	t.Run("Non admin is rejected", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(&dataModel.User{}, nil).Once()
+		_, err := s.AddPolicyRule(ctx, &proto.AddPolicyRuleRequest{ApiKey: "key", Kind: "domain", Pattern: "evil.com"})
+		assert.Equal(t, ErrPermissionDenied, err)
+	})
+
+	t.Run("Invalid rule is rejected", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(admin, nil).Once()
+		_, err := s.AddPolicyRule(ctx, &proto.AddPolicyRuleRequest{ApiKey: "key", Kind: "regex", Pattern: "("})
+		assert.ErrorIs(t, err, ErrInvalidPolicyRule)
+	})
+
+	t.Run("Block rule disables existing links and applies immediately", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		s.webhooks = webhook.NewDispatcher(mockDb)
+		s.policy = newPolicyEngine(Config{}, mockDb, nil)
+		mockDb.On("ListPolicyRules").Return([]dataModel.PolicyRule{}, nil).Once()
+		assert.NoError(t, s.policy.Reload())
+
+		mockDb.On("GetUserByAPIKey", "key").Return(admin, nil).Once()
+		mockDb.On("CreatePolicyRule", mock.MatchedBy(func(r *dataModel.PolicyRule) bool {
+			return r.Kind == "domain" && r.Pattern == "evil.com" && r.Action == "block" && r.CreatedBy == 9
+		})).Return(nil).Once()
+		mockDb.On("ListPolicyRules").Return([]dataModel.PolicyRule{
+			{Model: gorm.Model{ID: 1}, Kind: "domain", Pattern: "evil.com", Action: "block", Reason: "phishing"},
+			{Model: gorm.Model{ID: 2}, Kind: "prefix", Pattern: "http://evil.com/ok/", Action: "allow"},
+		}, nil).Once()
+		mockDb.On("ListPolicyCandidates", dataModel.PolicyScope{Kind: "domain", Pattern: "evil.com"}, uint(0), PolicyBatchSize).
+			Return([]dataModel.URLMapping{
+				{Model: gorm.Model{ID: 4}, LongURL: "http://evil.com/a", WorkspaceID: 3},
+				{Model: gorm.Model{ID: 5}, LongURL: "http://evil.com/ok/x", WorkspaceID: 3},
+				{Model: gorm.Model{ID: 6}, LongURL: "http://EVIL.com/b", WorkspaceID: 3},
+			}, nil).Once()
+		mockDb.On("DisableURLMappings", []uint{4, 6}, uint(1), "phishing").Return(int64(2), nil).Once()
+		mockDb.On("ListWebhookSubscriptions", uint(3)).
+			Return([]dataModel.WebhookSubscription{{Model: gorm.Model{ID: 2}, Events: "link.deleted"}}, nil).Once()
+		mockDb.On("CreateWebhookDeliveries", mock.MatchedBy(func(d []*dataModel.WebhookDelivery) bool {
+			return len(d) == 2 && d[0].Event == EventLinkDeleted && d[1].Event == EventLinkDeleted
+		})).Return(nil).Once()
+		mockDb.On("ListPolicyCandidates", dataModel.PolicyScope{Kind: "prefix", Pattern: "http://evil.com/ok/", Disabled: true}, uint(0), PolicyBatchSize).
+			Return([]dataModel.URLMapping{}, nil).Once()
+
+		resp, err := s.AddPolicyRule(ctx, &proto.AddPolicyRuleRequest{ApiKey: "key", Kind: "domain", Pattern: "Evil.com", Reason: "phishing"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), resp.DisabledLinks)
+		assert.Equal(t, uint64(1), resp.Rule.Id)
+		assert.True(t, s.policy.Check("http://evil.com/").Blocked)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Removed block rule enables the links no other rule blocks", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		changed := make(map[string][]dataModel.URLMapping)
+		s.policy = newPolicyEngine(Config{}, mockDb, func(event string, mappings []dataModel.URLMapping) {
+			changed[event] = append(changed[event], mappings...)
+		})
+		mockDb.On("ListPolicyRules").Return([]dataModel.PolicyRule{
+			{Model: gorm.Model{ID: 1}, Kind: "domain", Pattern: "evil.com", Action: "block", Reason: "phishing"},
+			{Model: gorm.Model{ID: 3}, Kind: "prefix", Pattern: "http://evil.com/bad/", Action: "block"},
+		}, nil).Once()
+		assert.NoError(t, s.policy.Reload())
+
+		mockDb.On("GetUserByAPIKey", "key").Return(admin, nil).Once()
+		mockDb.On("DeletePolicyRule", uint(1)).Return(nil).Once()
+		mockDb.On("ListPolicyRules").Return([]dataModel.PolicyRule{
+			{Model: gorm.Model{ID: 3}, Kind: "prefix", Pattern: "http://evil.com/bad/", Action: "block"},
+		}, nil).Once()
+		mockDb.On("ListPolicyCandidates", dataModel.PolicyScope{Kind: "domain", Pattern: "evil.com", RuleID: 1, Disabled: true}, uint(0), PolicyBatchSize).
+			Return([]dataModel.URLMapping{
+				{Model: gorm.Model{ID: 7}, LongURL: "http://evil.com/a", Disabled: true, DisabledRuleID: 1, DisabledReason: "phishing"},
+				{Model: gorm.Model{ID: 8}, LongURL: "http://evil.com/bad/x", Disabled: true, DisabledRuleID: 1, DisabledReason: "phishing"},
+			}, nil).Once()
+		mockDb.On("DisableURLMappings", []uint{8}, uint(3), `matches prefix rule "http://evil.com/bad/"`).Return(int64(1), nil).Once()
+		mockDb.On("EnableURLMappings", []uint{7}).Return(int64(1), nil).Once()
+
+		_, err := s.RemovePolicyRule(ctx, &proto.RemovePolicyRuleRequest{ApiKey: "key", Id: 1})
+		assert.NoError(t, err)
+		assert.False(t, s.policy.Check("http://evil.com/a").Blocked)
+		// Link 8 stays disabled, by another rule.
+		assert.Len(t, changed, 1)
+		require.Len(t, changed[EventLinkUpdated], 1)
+		assert.Equal(t, uint(7), changed[EventLinkUpdated][0].ID)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Remove unknown rule", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(admin, nil).Once()
+		mockDb.On("DeletePolicyRule", uint(5)).Return(gorm.ErrRecordNotFound).Once()
+		_, err := s.RemovePolicyRule(ctx, &proto.RemovePolicyRuleRequest{ApiKey: "key", Id: 5})
+		assert.Equal(t, ErrPolicyRuleNotFound, err)
+	})
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+)
+
+// continueFormField is the form field the continue button of the
+// interstitial page posts.
+const continueFormField = "continue"
+
+var interstitialPage = template.Must(template.New("interstitial").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Link preview</title></head>
+<body>
+{{if .Destination}}<p>This link goes to <strong>{{.Domain}}</strong>:</p>
+<p><code>{{.Destination}}</code></p>
+{{else}}<p>This link may only be followed a limited number of times, its destination is shown by continuing.</p>
+{{end}}
+<p>Created {{.Created}}{{if .Owner}} by {{.Owner}}{{end}}.</p>
+<form method="post"><input type="hidden" name="` + continueFormField + `" value="1"><button type="submit">Continue</button></form>
+</body>
+</html>
+`))
+
+type interstitialData struct {
+	Destination string
+	Domain      string
+	Created     string
+	Owner       string
+}
+
+// previewHandler shows where a short link goes instead of redirecting,
+// until the visitor continues. Otherwise it treats the link like
+// redirectHandler does.
+func (s *UrlShortenerService) previewHandler(w http.ResponseWriter, r *http.Request) {
+	s.followLink(w, r, true)
+}
+
+// continued reports whether the visitor continued from the interstitial page
+// to the destination.
+func continued(r *http.Request) bool {
+	return r.Method == http.MethodPost && r.PostFormValue(continueFormField) != ""
+}
+
+// renderInterstitial writes a page describing the destination of mapping
+// with a button continuing to target through the short link, so the visit
+// is counted. The destination of click limited links is left out, showing
+// it would give it away without using up a click.
+func (s *UrlShortenerService) renderInterstitial(w http.ResponseWriter, mapping *dataModel.URLMapping, target string) {
+	data := interstitialData{
+		Created: mapping.CreatedAt.UTC().Format(time.DateOnly),
+	}
+	if mapping.MaxClicks == 0 {
+		data.Destination = target
+		if u, err := url.Parse(target); err == nil {
+			data.Domain = u.Hostname()
+		}
+	}
+	if mapping.UserID != 0 {
+		owner, err := s.db.GetUserByID(mapping.UserID)
+		if err != nil {
+			log.Printf("Error fetching owner of %s: %v", mapping.ShortURLID, err)
+		} else {
+			data.Owner = strings.TrimSpace(owner.FirstName + " " + owner.LastName)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	if err := interstitialPage.Execute(w, data); err != nil {
+		log.Printf("Error rendering interstitial of %s: %v", mapping.ShortURLID, err)
+	}
+}
+
+// isInterstitialDomain reports whether longURL is on a flagged domain whose
+// links always show the interstitial.
+func (c Config) isInterstitialDomain(longURL string) bool {
+	if len(c.InterstitialDomains) == 0 {
+		return false
+	}
+	u, err := url.Parse(longURL)
+	if err != nil {
+		return false
+	}
+	return inDomains(u.Hostname(), c.InterstitialDomains)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestLinkPreview(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mapping := func() *dataModel.URLMapping {
+		return &dataModel.URLMapping{
+			Model:      gorm.Model{CreatedAt: created},
+			ShortURLID: "abc",
+			LongURL:    "https://example.com/a?x=<1>",
+			UserID:     7,
+		}
+	}
+	owner := &dataModel.User{FirstName: "Ada", LastName: "Lovelace"}
+
+	newRouter := func(s *UrlShortenerService) *mux.Router {
+		r := mux.NewRouter()
+		r.HandleFunc("/p/{shortChar}", s.previewHandler)
+		r.HandleFunc("/d/{shortChar:[^/+]+}+", s.previewHandler)
+		r.HandleFunc("/d/{shortChar}", s.redirectHandler)
+		return r
+	}
+
+	for _, path := range []string{"/p/abc", "/d/abc+"} {
+		t.Run("Preview via "+path, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb}
+			mockDb.On("GetURLMapping", "abc").Return(mapping(), nil).Once()
+			mockDb.On("GetUserByID", uint(7)).Return(owner, nil).Once()
+
+			rr := httptest.NewRecorder()
+			newRouter(s).ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+			assert.Equal(t, http.StatusOK, rr.Code)
+			body := rr.Body.String()
+			assert.Contains(t, body, "<strong>example.com</strong>")
+			assert.Contains(t, body, "https://example.com/a?x=&lt;1&gt;")
+			assert.Contains(t, body, "Created 2024-03-01 by Ada Lovelace.")
+			assert.Contains(t, body, `<form method="post"><input type="hidden" name="continue" value="1">`)
+			mockDb.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Click limited destination is hidden until continued", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		m := mapping()
+		m.ID, m.MaxClicks, m.ClicksRemaining = 4, 1, 1
+		mockDb.On("GetURLMapping", "abc").Return(m, nil).Twice()
+		mockDb.On("GetUserByID", uint(7)).Return(owner, nil).Once()
+
+		rr := httptest.NewRecorder()
+		newRouter(s).ServeHTTP(rr, httptest.NewRequest("GET", "/p/abc", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NotContains(t, rr.Body.String(), "example.com")
+		assert.Contains(t, rr.Body.String(), "limited number of times")
+		mockDb.AssertNotCalled(t, "ConsumeClick", mock.Anything, mock.Anything)
+		assert.Empty(t, s.clickCounts.take())
+
+		mockDb.On("ConsumeClick", "", "abc").Return(int64(0), nil).Once()
+		req := httptest.NewRequest("POST", "/p/abc", strings.NewReader("continue=1"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr = httptest.NewRecorder()
+		newRouter(s).ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusSeeOther, rr.Code)
+		assert.Equal(t, "https://example.com/a?x=<1>", rr.Header().Get("Location"))
+		assert.Equal(t, map[uint]int64{4: 1}, s.clickCounts.take())
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Interstitial link", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		m := mapping()
+		m.Interstitial = true
+		mockDb.On("GetURLMapping", "abc").Return(m, nil).Once()
+		mockDb.On("GetUserByID", uint(7)).Return(owner, nil).Once()
+
+		rr := httptest.NewRecorder()
+		newRouter(s).ServeHTTP(rr, httptest.NewRequest("GET", "/d/abc", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Continue")
+	})
+
+	t.Run("Flagged domain", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, Config: Config{InterstitialDomains: []string{"example.com"}}}
+		mockDb.On("GetURLMapping", "abc").Return(mapping(), nil).Once()
+		mockDb.On("GetUserByID", uint(7)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		rr := httptest.NewRecorder()
+		newRouter(s).ServeHTTP(rr, httptest.NewRequest("GET", "/d/abc", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Created 2024-03-01.")
+	})
+
+	t.Run("Unflagged domain redirects", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, Config: Config{InterstitialDomains: []string{"evil.example"}}}
+		mockDb.On("GetURLMapping", "abc").Return(mapping(), nil).Once()
+
+		rr := httptest.NewRecorder()
+		newRouter(s).ServeHTTP(rr, httptest.NewRequest("GET", "/d/abc", nil))
+		assert.Equal(t, http.StatusFound, rr.Code)
+	})
+}
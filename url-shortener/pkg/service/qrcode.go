@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/qr"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+)
+
+// GetQRCode renders a QR code of the public URL of a short link, on a
+// branded domain when one is given. Rendered images are cached in Redis for
+// QRCodeCacheTTL.
+func (s *UrlShortenerService) GetQRCode(ctx context.Context, req *proto.GetQRCodeRequest) (*httpbody.HttpBody, error) {
+	opts := qr.Options{
+		Format:     qr.Format(req.Format),
+		Size:       int(req.Size),
+		Level:      req.ErrorCorrection,
+		Foreground: req.Foreground,
+		Background: req.Background,
+	}
+	if req.Margin != nil {
+		margin := int(*req.Margin)
+		opts.Margin = &margin
+	}
+	optsKey, err := opts.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	var link string
+	if req.Domain == "" {
+		if _, err := s.db.GetURLMapping(req.ShortUrl); err != nil {
+			return nil, err
+		}
+		if link, err = s.Config.shortLinkURL(req.ShortUrl); err != nil {
+			return nil, err
+		}
+	} else {
+		host, err := s.brandedHost(req.Domain)
+		if err != nil {
+			return nil, err
+		}
+		mapping, err := s.db.GetURLMappingOnHost(host, req.ShortUrl)
+		if err != nil {
+			return nil, err
+		}
+		link = displayShortURL(mapping)
+	}
+
+	cacheKey := "qr:" + link + ":" + optsKey
+	if s.RedisClient != nil {
+		data, err := s.RedisClient.Get(ctx, cacheKey).Bytes()
+		if err == nil {
+			return &httpbody.HttpBody{ContentType: opts.Format.ContentType(), Data: data}, nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("Error reading cached QR code %s: %v", cacheKey, err)
+		}
+	}
+
+	data, err := qr.Render(link, opts)
+	if err != nil {
+		log.Printf("Error rendering QR code of %s: %v", link, err)
+		return nil, err
+	}
+	if s.RedisClient != nil {
+		if err := s.RedisClient.Set(ctx, cacheKey, data, QRCodeCacheTTL).Err(); err != nil {
+			log.Printf("Error caching QR code %s: %v", cacheKey, err)
+		}
+	}
+	return &httpbody.HttpBody{ContentType: opts.Format.ContentType(), Data: data}, nil
+}
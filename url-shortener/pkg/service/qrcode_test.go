@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/qr"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestGetQRCode(t *testing.T) {
+	ctx := context.Background()
+	cfg := Config{PublicHosts: []string{"sho.rt"}}
+	cacheKey := "qr:https://sho.rt/d/abc:svg:256:M:4:#000000:#ffffff"
+
+	t.Run("Rendered and cached", func(t *testing.T) {
+		mockDb := new(MockDB)
+		mockRedis := new(MockRedisClient)
+		s := &UrlShortenerService{Config: cfg, db: mockDb, RedisClient: mockRedis}
+		mockDb.On("GetURLMapping", "abc").Return(&dataModel.URLMapping{ShortURLID: "abc"}, nil).Once()
+		mockRedis.On("Get", ctx, cacheKey).Return(redis.NewStringResult("", redis.Nil)).Once()
+		mockRedis.On("Set", ctx, cacheKey, mock.Anything, QRCodeCacheTTL).Return(redis.NewStatusResult("OK", nil)).Once()
+
+		resp, err := s.GetQRCode(ctx, &proto.GetQRCodeRequest{ShortUrl: "abc", Format: "svg"})
+		assert.NoError(t, err)
+		assert.Equal(t, "image/svg+xml", resp.ContentType)
+		want, _ := qr.Render("https://sho.rt/d/abc", qr.Options{Format: qr.SVG})
+		assert.Equal(t, want, resp.Data)
+		mockRedis.AssertExpectations(t)
+	})
+
+	t.Run("Served from cache", func(t *testing.T) {
+		mockDb := new(MockDB)
+		mockRedis := new(MockRedisClient)
+		s := &UrlShortenerService{Config: cfg, db: mockDb, RedisClient: mockRedis}
+		mockDb.On("GetURLMapping", "abc").Return(&dataModel.URLMapping{ShortURLID: "abc"}, nil).Once()
+		mockRedis.On("Get", ctx, cacheKey).Return(redis.NewStringResult("<svg/>", nil)).Once()
+
+		resp, err := s.GetQRCode(ctx, &proto.GetQRCodeRequest{ShortUrl: "abc", Format: "svg"})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("<svg/>"), resp.Data)
+		mockRedis.AssertExpectations(t)
+	})
+
+	t.Run("Unknown link", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{Config: cfg, db: mockDb}
+		mockDb.On("GetURLMapping", "nope").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := s.GetQRCode(ctx, &proto.GetQRCodeRequest{ShortUrl: "nope"})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		s := &UrlShortenerService{Config: cfg, db: new(MockDB)}
+		_, err := s.GetQRCode(ctx, &proto.GetQRCodeRequest{ShortUrl: "abc", ErrorCorrection: "Z"})
+		assert.ErrorIs(t, err, qr.ErrInvalidOptions)
+	})
+
+	t.Run("Branded domain", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{Config: cfg, db: mockDb}
+		mockDb.On("GetVerifiedDomain", "go.acme.com").Return(&dataModel.Domain{Host: "go.acme.com", WorkspaceID: 3}, nil).Once()
+		mockDb.On("GetURLMappingOnHost", "go.acme.com", "launch").
+			Return(&dataModel.URLMapping{Host: "go.acme.com", ShortURLID: "launch"}, nil).Once()
+
+		resp, err := s.GetQRCode(ctx, &proto.GetQRCodeRequest{ShortUrl: "launch", Domain: "Go.Acme.com", Format: "svg"})
+		assert.NoError(t, err)
+		want, _ := qr.Render("https://go.acme.com/launch", qr.Options{Format: qr.SVG})
+		assert.Equal(t, want, resp.Data)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Unverified domain", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{Config: cfg, db: mockDb}
+		mockDb.On("GetVerifiedDomain", "go.acme.com").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := s.GetQRCode(ctx, &proto.GetQRCodeRequest{ShortUrl: "launch", Domain: "go.acme.com"})
+		assert.ErrorIs(t, err, ErrDomainNotVerified)
+		mockDb.AssertNotCalled(t, "GetURLMappingOnHost", mock.Anything, mock.Anything)
+	})
+
+	t.Run("No public host", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetURLMapping", "abc").Return(&dataModel.URLMapping{ShortURLID: "abc"}, nil).Once()
+		_, err := s.GetQRCode(ctx, &proto.GetQRCodeRequest{ShortUrl: "abc"})
+		assert.Equal(t, ErrNoPublicHost, err)
+	})
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Plan describes the allowances a user gets for every billing cycle.
+// A zero limit means unlimited.
+type Plan struct {
+	Name                   string
+	MaxLinks               int64
+	MaxCustomAliases       int64
+	AnalyticsRetentionDays int32
+}
+
+// plans is the catalog of plans a User.Plan can refer to.
+var plans = map[string]Plan{
+	"free":       {Name: "free", MaxLinks: 100, MaxCustomAliases: 5, AnalyticsRetentionDays: 30},
+	"pro":        {Name: "pro", MaxLinks: 10000, MaxCustomAliases: 500, AnalyticsRetentionDays: 365},
+	"enterprise": {Name: "enterprise", AnalyticsRetentionDays: 730},
+}
+
+// planFor returns the plan assigned to the user, falling back to DefaultPlan
+// for unknown names so a typo in the database never lifts every limit.
+func planFor(user *dataModel.User) Plan {
+	if plan, ok := plans[user.Plan]; ok {
+		return plan
+	}
+	if user.Plan != "" {
+		log.Printf("Unknown plan %q for user %d, using %q", user.Plan, user.ID, DefaultPlan)
+	}
+	return plans[DefaultPlan]
+}
+
+// analyticsSince returns the first day whose analytics the plan of user
+// retains, the zero time for plans retaining them forever.
+func analyticsSince(user *dataModel.User, now time.Time) time.Time {
+	days := planFor(user).AnalyticsRetentionDays
+	if days == 0 {
+		return time.Time{}
+	}
+	return now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -int(days)+1)
+}
+
+// longestRetention returns the longest analytics retention of any plan in
+// days, 0 when a plan retains them forever.
+func longestRetention() int32 {
+	var longest int32
+	for _, plan := range plans {
+		if plan.AnalyticsRetentionDays == 0 {
+			return 0
+		}
+		longest = max(longest, plan.AnalyticsRetentionDays)
+	}
+	return longest
+}
+
+// billingCycle returns the monthly cycle containing now. Cycles are anchored
+// on the day of month the user signed up; anchors past the end of a shorter
+// month are clamped to its last day.
+func billingCycle(anchor, now time.Time) (time.Time, time.Time) {
+	anchor = anchor.UTC()
+	now = now.UTC()
+	cycleStart := func(year int, month time.Month) time.Time {
+		day := anchor.Day()
+		if last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day(); day > last {
+			day = last
+		}
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	start := cycleStart(now.Year(), now.Month())
+	if now.Before(start) {
+		start = cycleStart(now.Year(), now.Month()-1)
+	}
+	next := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	return start, cycleStart(next.Year(), next.Month())
+}
+
+// consumeQuota reserves delta from the user's plan for the current cycle.
+// The returned release func hands the reservation back and must be called if
+// the operation it was taken for does not complete.
+func (s *UrlShortenerService) consumeQuota(user *dataModel.User, delta dataModel.UsageDelta) (func(), error) {
+	if !s.Config.EnforceQuotas {
+		return func() {}, nil
+	}
+	plan := planFor(user)
+	start, end := billingCycle(user.CreatedAt, time.Now())
+	ok, err := s.db.ConsumeUsage(user.ID, start, delta, dataModel.UsageLimits{
+		MaxLinks:         plan.MaxLinks,
+		MaxCustomAliases: plan.MaxCustomAliases,
+	})
+	if err != nil {
+		log.Printf("Error consuming quota for user %d: %v", user.ID, err)
+		return nil, err
+	}
+	if !ok {
+		usage, err := s.db.GetUsage(user.ID, start)
+		if err != nil {
+			return nil, err
+		}
+		return nil, quotaExceededError(user, plan, usage, delta, end)
+	}
+	return func() {
+		if err := s.db.ReleaseUsage(user.ID, start, delta); err != nil {
+			log.Printf("Error releasing quota for user %d: %v", user.ID, err)
+		}
+	}, nil
+}
+
+// quotaExceededError builds a ResourceExhausted status carrying the quota that
+// was hit, the current consumption and when the counters reset.
+func quotaExceededError(user *dataModel.User, plan Plan, usage *dataModel.UsageCounter, delta dataModel.UsageDelta, cycleEnd time.Time) error {
+	reason, subject := "LINK_QUOTA_EXCEEDED", "links"
+	limit, used := plan.MaxLinks, usage.LinksCreated
+	if delta.CustomAliases > 0 && plan.MaxCustomAliases > 0 && usage.CustomAliases+delta.CustomAliases > plan.MaxCustomAliases {
+		reason, subject = "CUSTOM_ALIAS_QUOTA_EXCEEDED", "custom_aliases"
+		limit, used = plan.MaxCustomAliases, usage.CustomAliases
+	}
+	description := fmt.Sprintf("%s plan allows %d %s per billing cycle, %d used", plan.Name, limit, subject, used)
+
+	st, err := status.New(codes.ResourceExhausted, ErrQuotaExceeded.Error()+": "+description).WithDetails(
+		&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{
+				Subject:     "user:" + strconv.FormatUint(uint64(user.ID), 10),
+				Description: description,
+			}},
+		},
+		&errdetails.ErrorInfo{
+			Reason: reason,
+			Domain: QuotaErrorDomain,
+			Metadata: map[string]string{
+				"plan":     plan.Name,
+				"quota":    subject,
+				"limit":    strconv.FormatInt(limit, 10),
+				"used":     strconv.FormatInt(used, 10),
+				"reset_at": cycleEnd.Format(time.RFC3339),
+			},
+		},
+	)
+	if err != nil {
+		log.Printf("Error attaching quota details: %v", err)
+		return status.Error(codes.ResourceExhausted, ErrQuotaExceeded.Error())
+	}
+	return st.Err()
+}
+
+// GetUsage reports the caller's consumption for the current billing cycle
+// together with the limits of their plan.
+func (s *UrlShortenerService) GetUsage(ctx context.Context, req *proto.GetUsageRequest) (*proto.GetUsageResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := planFor(user)
+	start, end := billingCycle(user.CreatedAt, time.Now())
+	usage, err := s.db.GetUsage(user.ID, start)
+	if err != nil {
+		log.Printf("Error fetching usage for user %d: %v", user.ID, err)
+		return nil, err
+	}
+
+	return &proto.GetUsageResponse{
+		Plan:                   plan.Name,
+		CycleStart:             timestamppb.New(start),
+		CycleEnd:               timestamppb.New(end),
+		LinksCreated:           usage.LinksCreated,
+		MaxLinks:               plan.MaxLinks,
+		CustomAliasesCreated:   usage.CustomAliases,
+		MaxCustomAliases:       plan.MaxCustomAliases,
+		AnalyticsRetentionDays: plan.AnalyticsRetentionDays,
+	}, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+func TestBillingCycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		anchor    time.Time
+		now       time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "After anchor day",
+			anchor:    time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC),
+			now:       time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Before anchor day rolls back a month",
+			anchor:    time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC),
+			now:       time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Anchor clamped to short month",
+			anchor:    time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			now:       time.Date(2025, 2, 28, 12, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := billingCycle(tt.anchor, tt.now)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestAnalyticsSince(t *testing.T) {
+	now := time.Date(2025, 6, 20, 15, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 5, 22, 0, 0, 0, 0, time.UTC), analyticsSince(&dataModel.User{Plan: "free"}, now))
+	assert.Equal(t, time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC), analyticsSince(&dataModel.User{Plan: "pro"}, now))
+	assert.Equal(t, time.Date(2025, 5, 22, 0, 0, 0, 0, time.UTC), analyticsSince(&dataModel.User{Plan: "unknown"}, now))
+	assert.Equal(t, int32(730), longestRetention())
+}
+
+func TestShortenURLQuota(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(7)
+	user.CreatedAt, user.Plan = time.Now().AddDate(0, -2, 0), "free"
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return 42, nil }
+
+	t.Run("Over quota is rejected with details", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{Config: Config{EnforceQuotas: true}, db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), "http://example.com/").Return(nil, gorm.ErrRecordNotFound).Once()
+		mockDb.On("ConsumeUsage", uint(7), mock.Anything, dataModel.UsageDelta{Links: 1},
+			dataModel.UsageLimits{MaxLinks: 100, MaxCustomAliases: 5}).Return(false, nil).Once()
+		mockDb.On("GetUsage", uint(7), mock.Anything).Return(&dataModel.UsageCounter{LinksCreated: 100}, nil).Once()
+
+		resp, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com"})
+		assert.Nil(t, resp)
+		st, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.ResourceExhausted, st.Code())
+		var info *errdetails.ErrorInfo
+		for _, d := range st.Details() {
+			if i, ok := d.(*errdetails.ErrorInfo); ok {
+				info = i
+			}
+		}
+		if assert.NotNil(t, info) {
+			assert.Equal(t, "LINK_QUOTA_EXCEEDED", info.Reason)
+			assert.Equal(t, "100", info.Metadata["limit"])
+			assert.Equal(t, "100", info.Metadata["used"])
+		}
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Custom alias counts against alias quota", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{Config: Config{EnforceQuotas: true}, db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), "http://example.com/").Return(nil, gorm.ErrRecordNotFound).Once()
+		mockDb.On("ConsumeUsage", uint(7), mock.Anything, dataModel.UsageDelta{Links: 1, CustomAliases: 1},
+			mock.Anything).Return(true, nil).Once()
+		mockDb.On("CreateURLMapping", mock.MatchedBy(func(m *dataModel.URLMapping) bool {
+			return m.ShortURLID == "launch" && m.IsCustomAlias && m.UserID == 7
+		})).Return(nil).Once()
+
+		resp, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com", CustomAlias: "launch"})
+		assert.NoError(t, err)
+		assert.Equal(t, "launch", resp.ShortUrl)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Quota is released when the mapping is not stored", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{Config: Config{EnforceQuotas: true}, db: mockDb}
+		dbErr := errors.New("insert failed")
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), "http://example.com/").Return(nil, gorm.ErrRecordNotFound).Once()
+		mockDb.On("ConsumeUsage", uint(7), mock.Anything, dataModel.UsageDelta{Links: 1}, mock.Anything).Return(true, nil).Once()
+		mockDb.On("CreateURLMapping", mock.Anything).Return(dbErr).Once()
+		mockDb.On("ReleaseUsage", uint(7), mock.Anything, dataModel.UsageDelta{Links: 1}).Return(nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com"})
+		assert.Equal(t, dbErr, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Invalid custom alias", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com", CustomAlias: "abc1234"})
+		assert.Equal(t, ErrInvalidCustomAlias, err)
+	})
+}
+
+func TestGetUsage(t *testing.T) {
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	user := testUser(3)
+	user.CreatedAt, user.Plan = time.Now(), "pro"
+	mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+	mockDb.On("GetUsage", uint(3), mock.Anything).Return(&dataModel.UsageCounter{LinksCreated: 12, CustomAliases: 2}, nil).Once()
+
+	resp, err := s.GetUsage(context.Background(), &proto.GetUsageRequest{ApiKey: "key"})
+	assert.NoError(t, err)
+	assert.Equal(t, "pro", resp.Plan)
+	assert.Equal(t, int64(12), resp.LinksCreated)
+	assert.Equal(t, int64(10000), resp.MaxLinks)
+	assert.Equal(t, int64(2), resp.CustomAliasesCreated)
+	assert.Equal(t, int32(365), resp.AnalyticsRetentionDays)
+	assert.True(t, resp.CycleEnd.AsTime().After(resp.CycleStart.AsTime()))
+	mockDb.AssertExpectations(t)
+}
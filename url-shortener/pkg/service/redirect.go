@@ -0,0 +1,74 @@
+package service
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+)
+
+// redirectStatuses maps each redirect type to the HTTP status it sends.
+var redirectStatuses = map[proto.RedirectType]int{
+	proto.RedirectType_REDIRECT_TYPE_UNSPECIFIED:                 http.StatusFound,
+	proto.RedirectType_REDIRECT_TYPE_PERMANENT:                   http.StatusMovedPermanently,
+	proto.RedirectType_REDIRECT_TYPE_TEMPORARY:                   http.StatusFound,
+	proto.RedirectType_REDIRECT_TYPE_METHOD_PRESERVING_TEMPORARY: http.StatusTemporaryRedirect,
+	proto.RedirectType_REDIRECT_TYPE_METHOD_PRESERVING_PERMANENT: http.StatusPermanentRedirect,
+}
+
+// redirectStatus returns the HTTP status for a requested redirect type.
+func redirectStatus(t proto.RedirectType) (int, bool) {
+	status, ok := redirectStatuses[t]
+	return status, ok
+}
+
+// redirectType is the inverse of redirectStatus. Mappings created before
+// redirect types existed have no status and redirect with 302.
+func redirectType(status int) proto.RedirectType {
+	switch status {
+	case http.StatusMovedPermanently:
+		return proto.RedirectType_REDIRECT_TYPE_PERMANENT
+	case http.StatusTemporaryRedirect:
+		return proto.RedirectType_REDIRECT_TYPE_METHOD_PRESERVING_TEMPORARY
+	case http.StatusPermanentRedirect:
+		return proto.RedirectType_REDIRECT_TYPE_METHOD_PRESERVING_PERMANENT
+	default:
+		return proto.RedirectType_REDIRECT_TYPE_TEMPORARY
+	}
+}
+
+// sameRedirectSettings reports whether two mappings redirect the same way.
+func sameRedirectSettings(a, b *dataModel.URLMapping) bool {
+	return redirectType(a.RedirectStatus) == redirectType(b.RedirectStatus) &&
+		a.QueryPassthrough == b.QueryPassthrough &&
+		a.ForwardPath == b.ForwardPath &&
+		a.Interstitial == b.Interstitial
+}
+
+// redirectTarget builds the URL a request is sent to. extraPath is whatever
+// followed the short code in the request path and query is the incoming
+// query string; both are only used when the link enables them. Incoming
+// query parameters replace destination parameters of the same name.
+func redirectTarget(resp *proto.GetURLResponse, extraPath string, query url.Values) (string, error) {
+	if (extraPath == "" || !resp.ForwardPath) && (len(query) == 0 || !resp.QueryPassthrough) {
+		return resp.LongUrl, nil
+	}
+	target, err := url.Parse(resp.LongUrl)
+	if err != nil {
+		return "", err
+	}
+	if extraPath != "" && resp.ForwardPath {
+		target.Path = strings.TrimSuffix(target.Path, "/") + "/" + strings.TrimPrefix(extraPath, "/")
+		target.RawPath = ""
+	}
+	if len(query) > 0 && resp.QueryPassthrough {
+		merged := target.Query()
+		for key, values := range query {
+			merged[key] = values
+		}
+		target.RawQuery = merged.Encode()
+	}
+	return target.String(), nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestRedirectHandlerSemantics(t *testing.T) {
+	tests := []struct {
+		name         string
+		mapping      dataModel.URLMapping
+		path         string
+		vars         map[string]string
+		wantStatus   int
+		wantLocation string
+	}{
+		{
+			name:         "Legacy mapping without status",
+			mapping:      dataModel.URLMapping{LongURL: "https://example.com/a"},
+			path:         "/d/abc",
+			wantStatus:   http.StatusFound,
+			wantLocation: "https://example.com/a",
+		},
+		{
+			name:         "Permanent redirect",
+			mapping:      dataModel.URLMapping{LongURL: "https://example.com/a", RedirectStatus: http.StatusMovedPermanently},
+			path:         "/d/abc",
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "https://example.com/a",
+		},
+		{
+			name:         "Method preserving redirect",
+			mapping:      dataModel.URLMapping{LongURL: "https://example.com/a", RedirectStatus: http.StatusPermanentRedirect},
+			path:         "/d/abc",
+			wantStatus:   http.StatusPermanentRedirect,
+			wantLocation: "https://example.com/a",
+		},
+		{
+			name:         "Query ignored without passthrough",
+			mapping:      dataModel.URLMapping{LongURL: "https://example.com/a?x=1"},
+			path:         "/d/abc?utm_source=mail",
+			wantStatus:   http.StatusFound,
+			wantLocation: "https://example.com/a?x=1",
+		},
+		{
+			name:         "Query passthrough merges parameters",
+			mapping:      dataModel.URLMapping{LongURL: "https://example.com/a?x=1&utm_source=site", QueryPassthrough: true},
+			path:         "/d/abc?utm_source=mail&utm_medium=email",
+			wantStatus:   http.StatusFound,
+			wantLocation: "https://example.com/a?utm_medium=email&utm_source=mail&x=1",
+		},
+		{
+			name:         "Path forwarding",
+			mapping:      dataModel.URLMapping{LongURL: "https://example.com/docs/", ForwardPath: true},
+			path:         "/d/abc/guide/intro",
+			vars:         map[string]string{"extraPath": "guide/intro"},
+			wantStatus:   http.StatusFound,
+			wantLocation: "https://example.com/docs/guide/intro",
+		},
+		{
+			name:       "Extra path without forwarding",
+			mapping:    dataModel.URLMapping{LongURL: "https://example.com/docs/"},
+			path:       "/d/abc/guide",
+			vars:       map[string]string{"extraPath": "guide"},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb}
+			mapping := tt.mapping
+			mockDb.On("GetURLMapping", "abc").Return(&mapping, nil).Once()
+
+			vars := map[string]string{"shortChar": "abc"}
+			for k, v := range tt.vars {
+				vars[k] = v
+			}
+			req := mux.SetURLVars(httptest.NewRequest("GET", tt.path, nil), vars)
+			rr := httptest.NewRecorder()
+			s.redirectHandler(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantLocation != "" {
+				assert.Equal(t, tt.wantLocation, rr.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestShortenURLRedirectSettings(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return 99, nil }
+
+	t.Run("Settings are stored", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), "http://example.com/").Return(nil, gorm.ErrRecordNotFound).Once()
+		mockDb.On("CreateURLMapping", mock.MatchedBy(func(m *dataModel.URLMapping) bool {
+			return m.RedirectStatus == http.StatusTemporaryRedirect && m.QueryPassthrough && m.ForwardPath
+		})).Return(nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{
+			ApiKey:           "key",
+			LongUrl:          "http://example.com/",
+			RedirectType:     proto.RedirectType_REDIRECT_TYPE_METHOD_PRESERVING_TEMPORARY,
+			QueryPassthrough: true,
+			ForwardPath:      true,
+		})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Unknown redirect type", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/", RedirectType: 42})
+		assert.ErrorIs(t, err, ErrInvalidRedirectType)
+	})
+
+	t.Run("Existing link with different settings", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), "http://example.com/").
+			Return(&dataModel.URLMapping{ShortURLID: "existing", LongURL: "http://example.com/", RedirectStatus: http.StatusFound}, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{
+			ApiKey:       "key",
+			LongUrl:      "http://example.com/",
+			RedirectType: proto.RedirectType_REDIRECT_TYPE_PERMANENT,
+		})
+		assert.ErrorIs(t, err, ErrLongURLExists)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Existing link without interstitial", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), "http://example.com/").
+			Return(&dataModel.URLMapping{ShortURLID: "existing", LongURL: "http://example.com/", RedirectStatus: http.StatusFound}, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/", Interstitial: true})
+		assert.ErrorIs(t, err, ErrLongURLExists)
+		mockDb.AssertExpectations(t)
+	})
+}
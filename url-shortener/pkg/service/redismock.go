@@ -32,4 +32,9 @@ func (m *MockRedisClient) Close() error {
 func (m *MockRedisClient) Ping(ctx context.Context) *redis.StatusCmd {
 	args := m.Called(ctx)
 	return args.Get(0).(*redis.StatusCmd)
-}
\ No newline at end of file
+}
+
+func (m *MockRedisClient) XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd {
+	args := m.Called(ctx, a)
+	return args.Get(0).(*redis.StringCmd)
+}
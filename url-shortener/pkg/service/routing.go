@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/events"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/geoip"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/useragent"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// unsafeDeepLinkSchemes may not be used by routing rule destinations, as they
+// run code or read data in the visitor's browser rather than open an app.
+var unsafeDeepLinkSchemes = []string{"javascript", "data", "file", "vbscript", "about", "blob"}
+
+var (
+	// countryCode matches ISO 3166-1 alpha-2 codes.
+	countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+	// regionCode matches ISO 3166-2 codes.
+	regionCode = regexp.MustCompile(`^[A-Z]{2}-[A-Z0-9]{1,3}$`)
+)
+
+// AddRoutingRule adds a routing rule to a link of the caller.
+func (s *UrlShortenerService) AddRoutingRule(ctx context.Context, req *proto.AddRoutingRuleRequest) (*proto.AddRoutingRuleResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.userLink(user, req.Domain, req.ShortUrl)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := s.routingRuleFromProto(ctx, req.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.db.ListRoutingRules(mapping.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxRoutingRulesPerLink {
+		return nil, fmt.Errorf("%w: at most %d allowed per link", ErrTooManyRoutingRules, MaxRoutingRulesPerLink)
+	}
+	rule.URLMappingID = mapping.ID
+	if err := s.db.CreateRoutingRule(rule); err != nil {
+		log.Printf("Error creating routing rule for %s: %v", mapping.ShortURLID, err)
+		return nil, err
+	}
+	s.emitWebhook(user.Workspace.ID, EventLinkUpdated, newLinkEvent(mapping))
+	return &proto.AddRoutingRuleResponse{Rule: routingRuleToProto(rule)}, nil
+}
+
+// ListRoutingRules returns the routing rules of a link of the caller.
+func (s *UrlShortenerService) ListRoutingRules(ctx context.Context, req *proto.ListRoutingRulesRequest) (*proto.ListRoutingRulesResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.userLink(user, req.Domain, req.ShortUrl)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.db.ListRoutingRules(mapping.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListRoutingRulesResponse{}
+	for i := range rules {
+		resp.Rules = append(resp.Rules, routingRuleToProto(&rules[i]))
+	}
+	return resp, nil
+}
+
+// DeleteRoutingRule removes a routing rule from a link of the caller.
+func (s *UrlShortenerService) DeleteRoutingRule(ctx context.Context, req *proto.DeleteRoutingRuleRequest) (*proto.DeleteRoutingRuleResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.userLink(user, req.Domain, req.ShortUrl)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.DeleteRoutingRule(mapping.ID, uint(req.Id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoutingRuleNotFound
+		}
+		return nil, err
+	}
+	s.emitWebhook(user.Workspace.ID, EventLinkUpdated, newLinkEvent(mapping))
+	return &proto.DeleteRoutingRuleResponse{}, nil
+}
+
+// userLink returns the mapping of a short URL owned by user, on a branded
+// domain or, when domain is empty, the service's own hosts. Links of others
+// are reported as not found.
+func (s *UrlShortenerService) userLink(user *dataModel.User, domain, shortURL string) (*dataModel.URLMapping, error) {
+	var mapping *dataModel.URLMapping
+	var err error
+	if domain == "" {
+		mapping, err = s.db.GetURLMapping(shortURL)
+	} else {
+		var host string
+		if host, err = s.userHost(user, domain); err != nil {
+			return nil, err
+		}
+		mapping, err = s.db.GetURLMappingOnHost(host, shortURL)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && mapping.WorkspaceID != user.Workspace.ID) {
+		return nil, fmt.Errorf("%w: %s", ErrLinkNotFound, shortURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// routingRuleFromProto validates a routing rule sent by a client.
+func (s *UrlShortenerService) routingRuleFromProto(ctx context.Context, r *proto.RoutingRule) (*dataModel.RoutingRule, error) {
+	if r == nil {
+		return nil, fmt.Errorf("%w: missing rule", ErrInvalidRoutingRule)
+	}
+	rule := &dataModel.RoutingRule{
+		Priority: int(r.Priority),
+		OS:       strings.ToLower(strings.TrimSpace(r.Os)),
+		Device:   strings.ToLower(strings.TrimSpace(r.Device)),
+		Browser:  strings.ToLower(strings.TrimSpace(r.Browser)),
+		Country:  strings.ToUpper(strings.TrimSpace(r.Country)),
+		Region:   strings.ToUpper(strings.TrimSpace(r.Region)),
+	}
+	if rule.OS == "" && rule.Device == "" && rule.Browser == "" && rule.Country == "" && rule.Region == "" {
+		return nil, fmt.Errorf("%w: at least one of os, device, browser, country and region is required", ErrInvalidRoutingRule)
+	}
+	for _, c := range []struct {
+		name, value string
+		allowed     []string
+	}{
+		{"os", rule.OS, useragent.OSes},
+		{"device", rule.Device, useragent.Devices},
+		{"browser", rule.Browser, useragent.Browsers},
+	} {
+		if c.value != "" && !slices.Contains(c.allowed, c.value) {
+			return nil, fmt.Errorf("%w: unknown %s %q, expected one of %s",
+				ErrInvalidRoutingRule, c.name, c.value, strings.Join(c.allowed, ", "))
+		}
+	}
+
+	if rule.Country != "" || rule.Region != "" {
+		if s.geo == nil {
+			return nil, fmt.Errorf("%w: visitors cannot be located, %s is not set", ErrInvalidRoutingRule, GeoIPDatabase)
+		}
+		if rule.Country != "" && !countryCode.MatchString(rule.Country) {
+			return nil, fmt.Errorf("%w: country %q is not an ISO 3166-1 alpha-2 code", ErrInvalidRoutingRule, r.Country)
+		}
+		if rule.Region != "" && !regionCode.MatchString(rule.Region) {
+			return nil, fmt.Errorf("%w: region %q is not an ISO 3166-2 code such as US-CA", ErrInvalidRoutingRule, r.Region)
+		}
+		if rule.Country != "" && rule.Region != "" && !strings.HasPrefix(rule.Region, rule.Country+"-") {
+			return nil, fmt.Errorf("%w: region %s is not in country %s", ErrInvalidRoutingRule, rule.Region, rule.Country)
+		}
+	}
+
+	destination, err := s.routingDestination(ctx, r.Destination)
+	if err != nil {
+		return nil, err
+	}
+	rule.Destination = destination
+	return rule, nil
+}
+
+// routingDestination validates the destination of a routing rule. Web URLs
+// are held to the same checks as long URLs; app deep links need a scheme
+// that cannot run code in the browser and must pass the policy rules.
+func (s *UrlShortenerService) routingDestination(ctx context.Context, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return "", fmt.Errorf("%w: destination %q is not an absolute URL", ErrInvalidRoutingRule, raw)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		if slices.Contains(unsafeDeepLinkSchemes, scheme) {
+			return "", fmt.Errorf("%w: scheme %q is not allowed", ErrInvalidRoutingRule, scheme)
+		}
+		maxLength := s.Config.MaxURLLength
+		if maxLength <= 0 {
+			maxLength = DefaultMaxURLLength
+		}
+		if len(raw) > maxLength {
+			return "", fmt.Errorf("%w: longer than %d characters", ErrInvalidURL, maxLength)
+		}
+		if err := s.checkDestination(raw); err != nil {
+			return "", err
+		}
+		return raw, nil
+	}
+	return s.normalizeDestination(ctx, raw)
+}
+
+func routingRuleToProto(rule *dataModel.RoutingRule) *proto.RoutingRule {
+	return &proto.RoutingRule{
+		Id:          uint64(rule.ID),
+		Priority:    int32(rule.Priority),
+		Os:          rule.OS,
+		Device:      rule.Device,
+		Browser:     rule.Browser,
+		Country:     rule.Country,
+		Region:      rule.Region,
+		Destination: rule.Destination,
+		CreatedAt:   timestamppb.New(rule.CreatedAt),
+	}
+}
+
+// visitorDestination returns where the visitor of click goes instead of the
+// long URL of mapping: the destination of the first matching routing rule,
+// else of the visitor's variant, which is set on click. It returns "" when
+// the long URL applies.
+func (s *UrlShortenerService) visitorDestination(w http.ResponseWriter, r *http.Request, mapping *dataModel.URLMapping, click *events.Click) (string, error) {
+	if mapping.HasRoutingRules {
+		// The destination depends on the visitor's device and location,
+		// and shared caches cannot tell locations apart.
+		w.Header().Add("Vary", "User-Agent")
+		w.Header().Set("Cache-Control", "private")
+		destination, err := s.routedDestination(mapping, *click)
+		if err != nil || destination != "" {
+			return destination, err
+		}
+	}
+	if mapping.HasVariants {
+		// Cached redirects would neither be counted nor spread.
+		w.Header().Set("Cache-Control", "no-store")
+		variant, err := s.pickVariant(w, r, mapping)
+		if err != nil || variant == nil {
+			return "", err
+		}
+		click.VariantID = variant.ID
+		return variant.Destination, nil
+	}
+	return "", nil
+}
+
+// routedDestination returns the destination of the first routing rule of
+// mapping matching the visitor of click, or "" when there is none.
+func (s *UrlShortenerService) routedDestination(mapping *dataModel.URLMapping, click events.Click) (string, error) {
+	rules, err := s.db.ListRoutingRules(mapping.ID)
+	if err != nil {
+		return "", err
+	}
+	agent := useragent.Parse(click.UserAgent)
+	for _, rule := range rules {
+		if matchesRule(rule, agent, click.Location) {
+			return rule.Destination, nil
+		}
+	}
+	return "", nil
+}
+
+// matchesRule reports whether a visitor meets every condition of rule.
+func matchesRule(rule dataModel.RoutingRule, agent useragent.Agent, location geoip.Location) bool {
+	return (rule.OS == "" || rule.OS == agent.OS) &&
+		(rule.Device == "" || rule.Device == agent.Device) &&
+		(rule.Browser == "" || rule.Browser == agent.Browser) &&
+		(rule.Country == "" || rule.Country == location.Country) &&
+		(rule.Region == "" || rule.Region == location.Region)
+}
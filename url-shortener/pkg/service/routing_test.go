@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const (
+	iPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+	androidUA = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Mobile Safari/537.36"
+	desktopUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36"
+)
+
+func TestAddRoutingRule(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	mapping := &dataModel.URLMapping{Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1}
+
+	t.Run("Deep link", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		mockDb.On("ListRoutingRules", uint(7)).Return([]dataModel.RoutingRule{}, nil).Once()
+		mockDb.On("CreateRoutingRule", mock.MatchedBy(func(r *dataModel.RoutingRule) bool {
+			return r.URLMappingID == 7 && r.OS == "ios" && r.Device == "" && r.Destination == "myapp://item/42"
+		})).Return(nil).Once()
+
+		resp, err := s.AddRoutingRule(ctx, &proto.AddRoutingRuleRequest{
+			ApiKey: "key", ShortUrl: "abc",
+			Rule: &proto.RoutingRule{Os: " iOS ", Destination: "myapp://item/42"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), resp.Rule.Id)
+		assert.Equal(t, "ios", resp.Rule.Os)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Blocked deep link", func(t *testing.T) {
+		mockDb := new(MockDB)
+		mockDb.On("ListPolicyRules").Return([]dataModel.PolicyRule{{Kind: "prefix", Pattern: "myapp://pay/", Action: "block"}}, nil).Once()
+		s := &UrlShortenerService{db: mockDb, policy: newPolicyEngine(Config{}, mockDb, nil)}
+		assert.NoError(t, s.policy.Reload())
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+
+		_, err := s.AddRoutingRule(ctx, &proto.AddRoutingRuleRequest{
+			ApiKey: "key", ShortUrl: "abc",
+			Rule: &proto.RoutingRule{Os: "ios", Destination: "myapp://pay/42"},
+		})
+		assert.ErrorIs(t, err, ErrBlockedDestination)
+		mockDb.AssertNotCalled(t, "CreateRoutingRule", mock.Anything)
+	})
+
+	t.Run("Web destination is normalized", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		mockDb.On("ListRoutingRules", uint(7)).Return([]dataModel.RoutingRule{}, nil).Once()
+		mockDb.On("CreateRoutingRule", mock.MatchedBy(func(r *dataModel.RoutingRule) bool {
+			return r.Device == "desktop" && r.Destination == "https://example.com/"
+		})).Return(nil).Once()
+
+		_, err := s.AddRoutingRule(ctx, &proto.AddRoutingRuleRequest{
+			ApiKey: "key", ShortUrl: "abc",
+			Rule: &proto.RoutingRule{Device: "desktop", Destination: "HTTPS://Example.com"},
+		})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	for name, rule := range map[string]*proto.RoutingRule{
+		"Missing rule":         nil,
+		"No condition":         {Destination: "https://example.com"},
+		"Unknown OS":           {Os: "symbian", Destination: "https://example.com"},
+		"Unknown browser":      {Browser: "netscape", Destination: "https://example.com"},
+		"Relative URL":         {Os: "ios", Destination: "/somewhere"},
+		"Javascript":           {Os: "ios", Destination: "javascript:alert(1)"},
+		"Data URL":             {Os: "android", Destination: "data:text/html,hi"},
+		"Web URL without host": {Os: "android", Destination: "https://"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb}
+			mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+			mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+
+			_, err := s.AddRoutingRule(ctx, &proto.AddRoutingRuleRequest{ApiKey: "key", ShortUrl: "abc", Rule: rule})
+			assert.Error(t, err)
+			mockDb.AssertNotCalled(t, "CreateRoutingRule", mock.Anything)
+		})
+	}
+
+	t.Run("Link of another user", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		other := &dataModel.URLMapping{Model: gorm.Model{ID: 8}, ShortURLID: "xyz", UserID: 2, WorkspaceID: 2}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "xyz").Return(other, nil).Once()
+
+		_, err := s.AddRoutingRule(ctx, &proto.AddRoutingRuleRequest{
+			ApiKey: "key", ShortUrl: "xyz",
+			Rule: &proto.RoutingRule{Os: "ios", Destination: "myapp://"},
+		})
+		assert.ErrorIs(t, err, ErrLinkNotFound)
+	})
+
+	t.Run("Too many rules", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		mockDb.On("ListRoutingRules", uint(7)).Return(make([]dataModel.RoutingRule, MaxRoutingRulesPerLink), nil).Once()
+
+		_, err := s.AddRoutingRule(ctx, &proto.AddRoutingRuleRequest{
+			ApiKey: "key", ShortUrl: "abc",
+			Rule: &proto.RoutingRule{Os: "ios", Destination: "myapp://"},
+		})
+		assert.ErrorIs(t, err, ErrTooManyRoutingRules)
+	})
+}
+
+func TestDeleteRoutingRule(t *testing.T) {
+	user := testUser(1)
+	mapping := &dataModel.URLMapping{Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1}
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(user, nil)
+	mockDb.On("GetURLMapping", "abc").Return(mapping, nil)
+	mockDb.On("DeleteRoutingRule", uint(7), uint(3)).Return(gorm.ErrRecordNotFound).Once()
+
+	_, err := s.DeleteRoutingRule(context.Background(), &proto.DeleteRoutingRuleRequest{ApiKey: "key", ShortUrl: "abc", Id: 3})
+	assert.ErrorIs(t, err, ErrRoutingRuleNotFound)
+}
+
+func TestRoutedRedirect(t *testing.T) {
+	rules := []dataModel.RoutingRule{
+		{URLMappingID: 7, OS: "ios", Destination: "https://apps.apple.com/app/id123"},
+		{URLMappingID: 7, OS: "android", Device: "mobile", Destination: "myapp://item/42"},
+	}
+	tests := []struct {
+		name         string
+		userAgent    string
+		wantLocation string
+	}{
+		{"iOS", iPhoneUA, "https://apps.apple.com/app/id123"},
+		{"Android phone", androidUA, "myapp://item/42"},
+		{"Desktop falls back", desktopUA, "https://example.com/item/42"},
+		{"No user agent falls back", "", "https://example.com/item/42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb}
+			mapping := &dataModel.URLMapping{
+				Model: gorm.Model{ID: 7}, ShortURLID: "abc",
+				LongURL: "https://example.com/item/42", HasRoutingRules: true,
+			}
+			mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+			mockDb.On("ListRoutingRules", uint(7)).Return(rules, nil).Once()
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/d/abc", nil), map[string]string{"shortChar": "abc"})
+			req.Header.Set("User-Agent", tt.userAgent)
+			rr := httptest.NewRecorder()
+			s.redirectHandler(rr, req)
+
+			assert.Equal(t, http.StatusFound, rr.Code)
+			assert.Equal(t, tt.wantLocation, rr.Header().Get("Location"))
+			assert.Equal(t, "User-Agent", rr.Header().Get("Vary"))
+			mockDb.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Destinations are checked again", func(t *testing.T) {
+		for name, s := range map[string]func(*MockDB) *UrlShortenerService{
+			"Blocked since saved": func(mockDb *MockDB) *UrlShortenerService {
+				mockDb.On("ListPolicyRules").Return([]dataModel.PolicyRule{{Kind: "domain", Pattern: "apps.apple.com", Action: "block"}}, nil).Once()
+				s := &UrlShortenerService{db: mockDb, policy: newPolicyEngine(Config{}, mockDb, nil)}
+				assert.NoError(t, s.policy.Reload())
+				return s
+			},
+			"Host now internal": func(mockDb *MockDB) *UrlShortenerService {
+				guard, err := newNetGuard(Config{}, staticResolver("10.0.0.1"))
+				assert.NoError(t, err)
+				return &UrlShortenerService{db: mockDb, netGuard: guard}
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				mockDb := new(MockDB)
+				s := s(mockDb)
+				mapping := &dataModel.URLMapping{
+					Model: gorm.Model{ID: 7}, ShortURLID: "abc",
+					LongURL: "https://example.com/item/42", HasRoutingRules: true,
+				}
+				mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+				mockDb.On("ListRoutingRules", uint(7)).Return(rules, nil).Once()
+
+				req := mux.SetURLVars(httptest.NewRequest("GET", "/d/abc", nil), map[string]string{"shortChar": "abc"})
+				req.Header.Set("User-Agent", iPhoneUA)
+				rr := httptest.NewRecorder()
+				s.redirectHandler(rr, req)
+
+				assert.Equal(t, http.StatusGone, rr.Code)
+				assert.Empty(t, rr.Header().Get("Location"))
+				mockDb.AssertNotCalled(t, "ConsumeClick", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Links without rules skip the lookup", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mapping := &dataModel.URLMapping{Model: gorm.Model{ID: 7}, ShortURLID: "abc", LongURL: "https://example.com/"}
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/d/abc", nil), map[string]string{"shortChar": "abc"})
+		req.Header.Set("User-Agent", iPhoneUA)
+		rr := httptest.NewRecorder()
+		s.redirectHandler(rr, req)
+
+		assert.Equal(t, "https://example.com/", rr.Header().Get("Location"))
+		assert.Empty(t, rr.Header().Get("Vary"))
+		mockDb.AssertNotCalled(t, "ListRoutingRules", mock.Anything)
+	})
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	// Schedules name IANA time zones, which the service image has no
+	// zoneinfo files for.
+	_ "time/tzdata"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// localTimeLayouts are the layouts of activation times read in the time zone
+// given with them.
+var localTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+// SetLinkSchedule replaces the destinations a link of the caller switches to
+// over time.
+func (s *UrlShortenerService) SetLinkSchedule(ctx context.Context, req *proto.SetLinkScheduleRequest) (*proto.SetLinkScheduleResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.userLink(user, req.Domain, req.ShortUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Schedule) > MaxScheduledDestinations {
+		return nil, fmt.Errorf("%w: a link may have %d scheduled destinations", ErrInvalidSchedule, MaxScheduledDestinations)
+	}
+	schedule := make([]dataModel.ScheduledDestination, 0, len(req.Schedule))
+	for _, p := range req.Schedule {
+		activatesAt, zone, err := parseActivationTime(p.ActivatesAt, p.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		destination, err := s.normalizeDestination(ctx, p.Destination)
+		if err != nil {
+			return nil, err
+		}
+		schedule = append(schedule, dataModel.ScheduledDestination{
+			ActivatesAt: activatesAt,
+			TimeZone:    zone,
+			Destination: destination,
+		})
+	}
+	slices.SortStableFunc(schedule, func(a, b dataModel.ScheduledDestination) int {
+		return a.ActivatesAt.Compare(b.ActivatesAt)
+	})
+	for i := 1; i < len(schedule); i++ {
+		if schedule[i].ActivatesAt.Equal(schedule[i-1].ActivatesAt) {
+			return nil, fmt.Errorf("%w: two destinations activate at %s", ErrInvalidSchedule, schedule[i].ActivatesAt.Format(time.RFC3339))
+		}
+	}
+
+	if err := s.db.ReplaceSchedule(mapping.ID, schedule); err != nil {
+		log.Printf("Error setting schedule of %s: %v", mapping.ShortURLID, err)
+		return nil, err
+	}
+	s.emitWebhook(user.Workspace.ID, EventLinkUpdated, newLinkEvent(mapping))
+	resp := &proto.SetLinkScheduleResponse{}
+	for i := range schedule {
+		resp.Schedule = append(resp.Schedule, scheduledDestinationToProto(&schedule[i]))
+	}
+	return resp, nil
+}
+
+// GetLinkSchedule returns the schedule of a link of the caller and where it
+// points now.
+func (s *UrlShortenerService) GetLinkSchedule(ctx context.Context, req *proto.GetLinkScheduleRequest) (*proto.GetLinkScheduleResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.userLink(user, req.Domain, req.ShortUrl)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.GetLinkScheduleResponse{CurrentDestination: mapping.LongURL}
+	if !mapping.HasSchedule {
+		return resp, nil
+	}
+	schedule, err := s.db.ListSchedule(mapping.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range schedule {
+		resp.Schedule = append(resp.Schedule, scheduledDestinationToProto(&schedule[i]))
+	}
+	destination, next := activeDestination(schedule, time.Now())
+	if destination != "" {
+		resp.CurrentDestination = destination
+	}
+	if !next.IsZero() {
+		resp.NextChange = timestamppb.New(next)
+	}
+	return resp, nil
+}
+
+// parseActivationTime reads the activation time of a scheduled destination,
+// a local time in zone or an RFC 3339 time. It returns the instant and the
+// name of the zone, UTC when none is given.
+func parseActivationTime(value, zone string) (time.Time, string, error) {
+	if zone == "" {
+		zone = "UTC"
+	}
+	// "Local" would depend on the replica serving the request.
+	if zone == "Local" {
+		return time.Time{}, "", fmt.Errorf("%w: unknown time zone %q", ErrInvalidSchedule, zone)
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("%w: unknown time zone %q", ErrInvalidSchedule, zone)
+	}
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), zone, nil
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), zone, nil
+		}
+	}
+	return time.Time{}, "", fmt.Errorf("%w: activation time %q is not like 2025-06-01T09:00", ErrInvalidSchedule, value)
+}
+
+func scheduledDestinationToProto(d *dataModel.ScheduledDestination) *proto.ScheduledDestination {
+	activatesAt := d.ActivatesAt
+	if loc, err := time.LoadLocation(d.TimeZone); err == nil {
+		activatesAt = activatesAt.In(loc)
+	}
+	return &proto.ScheduledDestination{
+		Id:             uint64(d.ID),
+		Destination:    d.Destination,
+		ActivatesAt:    activatesAt.Format(time.RFC3339),
+		TimeZone:       d.TimeZone,
+		ActivationTime: timestamppb.New(d.ActivatesAt),
+	}
+}
+
+// activeDestination returns the destination of schedule, sorted by
+// activation time, active at now and when the next one activates. The
+// destination is "" before the first activation, the next time zero after
+// the last.
+func activeDestination(schedule []dataModel.ScheduledDestination, now time.Time) (string, time.Time) {
+	destination := ""
+	for _, d := range schedule {
+		if d.ActivatesAt.After(now) {
+			return destination, d.ActivatesAt
+		}
+		destination = d.Destination
+	}
+	return destination, time.Time{}
+}
+
+// applySchedule points the long URL of a scheduled mapping at its
+// destination active at now. It returns when that destination changes,
+// zero when it no longer does.
+func (s *UrlShortenerService) applySchedule(mapping *dataModel.URLMapping, now time.Time) (time.Time, error) {
+	schedule, err := s.db.ListSchedule(mapping.ID)
+	if err != nil {
+		log.Printf("Error loading schedule of %s: %v", mapping.ShortURLID, err)
+		return time.Time{}, err
+	}
+	destination, next := activeDestination(schedule, now)
+	if destination != "" {
+		mapping.LongURL = destination
+		mapping.ResolvedURL = ""
+	}
+	return next, nil
+}
+
+// cacheUntil keeps caches from reusing a response after until, when the
+// destination of a scheduled link changes. Responses that may not be stored
+// at all are left alone.
+func cacheUntil(w http.ResponseWriter, until, now time.Time) {
+	if until.IsZero() {
+		return
+	}
+	cacheControl := w.Header().Get("Cache-Control")
+	if strings.Contains(cacheControl, "no-store") {
+		return
+	}
+	maxAge := "max-age=" + strconv.FormatInt(int64(until.Sub(now)/time.Second), 10)
+	if cacheControl != "" {
+		maxAge = cacheControl + ", " + maxAge
+	}
+	w.Header().Set("Cache-Control", maxAge)
+}
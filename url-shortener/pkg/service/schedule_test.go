@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestParseActivationTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		zone     string
+		want     time.Time
+		wantZone string
+		wantErr  bool
+	}{
+		{"UTC by default", "2025-06-01T09:00", "", time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC), "UTC", false},
+		{"Summer time", "2025-06-01T09:00", "Europe/Berlin", time.Date(2025, 6, 1, 7, 0, 0, 0, time.UTC), "Europe/Berlin", false},
+		{"Winter time", "2025-01-15 09:00:30", "Europe/Berlin", time.Date(2025, 1, 15, 8, 0, 30, 0, time.UTC), "Europe/Berlin", false},
+		{"Offset", "2025-06-01T09:00:00-04:00", "America/New_York", time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC), "America/New_York", false},
+		{"Unknown zone", "2025-06-01T09:00", "Mars/Olympus", time.Time{}, "", true},
+		{"Server zone", "2025-06-01T09:00", "Local", time.Time{}, "", true},
+		{"Bad time", "June 1st", "", time.Time{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, zone, err := parseActivationTime(tt.value, tt.zone)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSchedule)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %s", got)
+			assert.Equal(t, tt.wantZone, zone)
+		})
+	}
+}
+
+func TestSetLinkSchedule(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	mapping := &dataModel.URLMapping{Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1}
+	set := func(s *UrlShortenerService, schedule ...*proto.ScheduledDestination) (*proto.SetLinkScheduleResponse, error) {
+		return s.SetLinkSchedule(ctx, &proto.SetLinkScheduleRequest{ApiKey: "key", ShortUrl: "abc", Schedule: schedule})
+	}
+
+	t.Run("Sorted", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		mockDb.On("ReplaceSchedule", uint(7), []dataModel.ScheduledDestination{
+			{ActivatesAt: time.Date(2025, 6, 1, 7, 0, 0, 0, time.UTC), TimeZone: "Europe/Berlin", Destination: "https://example.com/product"},
+			{ActivatesAt: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), TimeZone: "UTC", Destination: "https://example.com/ended"},
+		}).Return(nil).Once()
+
+		resp, err := set(s,
+			&proto.ScheduledDestination{Destination: "https://example.com/ended", ActivatesAt: "2025-07-01T00:00"},
+			&proto.ScheduledDestination{Destination: "HTTPS://example.com/product", ActivatesAt: "2025-06-01T09:00", TimeZone: "Europe/Berlin"})
+		require.NoError(t, err)
+		require.Len(t, resp.Schedule, 2)
+		assert.Equal(t, "2025-06-01T09:00:00+02:00", resp.Schedule[0].ActivatesAt)
+		assert.Equal(t, "2025-07-01T00:00:00Z", resp.Schedule[1].ActivatesAt)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		mockDb.On("ReplaceSchedule", uint(7), []dataModel.ScheduledDestination{}).Return(nil).Once()
+
+		_, err := set(s)
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	for name, schedule := range map[string][]*proto.ScheduledDestination{
+		"Same instant": {
+			{Destination: "https://example.com/a", ActivatesAt: "2025-06-01T09:00", TimeZone: "Europe/Berlin"},
+			{Destination: "https://example.com/b", ActivatesAt: "2025-06-01T07:00"},
+		},
+		"Bad time":    {{Destination: "https://example.com/a", ActivatesAt: "tomorrow"}},
+		"Invalid URL": {{Destination: "javascript:alert(1)", ActivatesAt: "2025-06-01T09:00"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb}
+			mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+			mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+
+			_, err := set(s, schedule...)
+			assert.Error(t, err)
+			mockDb.AssertNotCalled(t, "ReplaceSchedule", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestScheduledRedirect(t *testing.T) {
+	now := time.Now()
+	schedule := []dataModel.ScheduledDestination{
+		{ActivatesAt: now.Add(-time.Hour), Destination: "https://example.com/product"},
+		{ActivatesAt: now.Add(time.Hour), Destination: "https://example.com/ended"},
+	}
+	tests := []struct {
+		name         string
+		schedule     []dataModel.ScheduledDestination
+		maxClicks    int64
+		status       int
+		wantLocation string
+		wantMaxAge   bool
+		wantCache    string
+	}{
+		{"Before launch", schedule[1:], 0, http.StatusFound, "https://example.com/soon", true, ""},
+		{"Launched", schedule, 0, http.StatusFound, "https://example.com/product", true, ""},
+		{"Permanent redirect", schedule, 0, http.StatusMovedPermanently, "https://example.com/product", true, ""},
+		{"Last destination", schedule[:1], 0, http.StatusFound, "https://example.com/product", false, ""},
+		{"Click limited", schedule, 5, http.StatusFound, "https://example.com/product", false, "no-store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb}
+			mapping := &dataModel.URLMapping{
+				Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1, LongURL: "https://example.com/soon",
+				RedirectStatus: tt.status, MaxClicks: tt.maxClicks, ClicksRemaining: tt.maxClicks, HasSchedule: true,
+			}
+			mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+			mockDb.On("ListSchedule", uint(7)).Return(tt.schedule, nil).Once()
+			mockDb.On("ConsumeClick", "", "abc").Return(int64(4), nil).Maybe()
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/d/abc", nil), map[string]string{"shortChar": "abc"})
+			rr := httptest.NewRecorder()
+			s.redirectHandler(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			assert.Equal(t, tt.wantLocation, rr.Header().Get("Location"))
+			cacheControl := rr.Header().Get("Cache-Control")
+			if tt.wantMaxAge {
+				assert.Regexp(t, `^max-age=(3599|3600)$`, cacheControl)
+			} else {
+				assert.Equal(t, tt.wantCache, cacheControl)
+			}
+		})
+	}
+}
+
+func TestCacheUntil(t *testing.T) {
+	now := time.Now()
+	rr := httptest.NewRecorder()
+	rr.Header().Set("Cache-Control", "private")
+	cacheUntil(rr, now.Add(90*time.Second+500*time.Millisecond), now)
+	assert.Equal(t, "private, max-age=90", rr.Header().Get("Cache-Control"))
+
+	rr = httptest.NewRecorder()
+	cacheUntil(rr, time.Time{}, now)
+	assert.Empty(t, rr.Header().Get("Cache-Control"))
+}
+
+func TestGetScheduledURL(t *testing.T) {
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mapping := &dataModel.URLMapping{
+		Model: gorm.Model{ID: 7}, ShortURLID: "abc", LongURL: "https://example.com/soon",
+		ResolvedURL: "https://example.com/soon/", HasSchedule: true,
+	}
+	mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+	mockDb.On("ListSchedule", uint(7)).Return([]dataModel.ScheduledDestination{
+		{ActivatesAt: time.Now().Add(-time.Minute), Destination: "https://example.com/product"},
+	}, nil).Once()
+
+	resp, err := s.GetURL(context.Background(), &proto.GetURLRequest{ShortUrl: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/product", resp.LongUrl)
+	assert.Empty(t, resp.ResolvedUrl)
+}
+
+func TestGetLinkSchedule(t *testing.T) {
+	user := testUser(1)
+	mapping := &dataModel.URLMapping{
+		Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1, LongURL: "https://example.com/soon", HasSchedule: true,
+	}
+	launch := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+	mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+	mockDb.On("ListSchedule", uint(7)).Return([]dataModel.ScheduledDestination{
+		{ActivatesAt: launch, TimeZone: "Asia/Tokyo", Destination: "https://example.com/product"},
+	}, nil).Once()
+
+	resp, err := s.GetLinkSchedule(context.Background(), &proto.GetLinkScheduleRequest{ApiKey: "key", ShortUrl: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/soon", resp.CurrentDestination)
+	assert.True(t, launch.Equal(resp.NextChange.AsTime()))
+	require.Len(t, resp.Schedule, 1)
+	assert.Equal(t, launch.In(time.FixedZone("JST", 9*3600)).Format(time.RFC3339), resp.Schedule[0].ActivatesAt)
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
@@ -11,6 +13,9 @@ import (
 	"time"
 
 	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/events"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/geoip"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/linkcheck"
 
 	base "github.com/alt-coder/url-shortener/base/go"
 	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
@@ -20,11 +25,15 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
 )
 
 var (
 	// requestCounterFunc will be used for mocking.
 	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return s.requestCounter() }
+	// requestCounterRangeFunc will be used for mocking.
+	requestCounterRangeFunc = func(s *UrlShortenerService, n int64) (int64, error) { return s.requestCounterRange(n) }
 )
 
 // NewUrlShortnerService creates and initializes a new UrlShortenerService.
@@ -44,6 +53,127 @@ func NewUrlShortnerService() (*UrlShortenerService, error) {
 		RedisPassword:    os.Getenv(RedisPassword),
 		ZookeeperHost:    os.Getenv(ZookeeperHost),
 		ZookeeperPort:    os.Getenv(ZookeeperPort),
+		EnforceQuotas:    os.Getenv(EnforceQuotas) != "false",
+		AllowedSchemes:   splitList(os.Getenv(AllowedSchemes)),
+		KeepFragments:    os.Getenv(KeepURLFragments) == "true",
+		PolicyFile:       os.Getenv(PolicyFile),
+
+		PublicHosts:           splitList(os.Getenv(PublicHosts)),
+		BlockedHostCategories: splitList(os.Getenv(BlockedHostCategories)),
+		DestinationExceptions: splitList(os.Getenv(DestinationExceptions)),
+
+		SelfLinkPolicy:  os.Getenv(SelfLinkPolicy),
+		KnownShorteners: splitList(os.Getenv(KnownShorteners)),
+
+		InterstitialDomains: splitList(os.Getenv(InterstitialDomains)),
+		CrawlerUserAgents:   splitList(os.Getenv(CrawlerUserAgents)),
+
+		LinkCookieSecret: []byte(os.Getenv(LinkCookieSecret)),
+	}
+	if v := os.Getenv(PublicBaseURL); v != "" {
+		base, host, err := parsePublicBaseURL(v)
+		if err != nil {
+			log.Printf("Invalid %s %q", PublicBaseURL, v)
+			return nil, err
+		}
+		cfg.PublicBaseURL = base
+		if !cfg.isPublicHost(host) {
+			cfg.PublicHosts = append(cfg.PublicHosts, host)
+		}
+	}
+	if v := os.Getenv(MaxURLLength); v != "" {
+		maxLength, err := strconv.Atoi(v)
+		if err != nil {
+			log.Printf("Invalid %s %q", MaxURLLength, v)
+			return nil, err
+		}
+		cfg.MaxURLLength = maxLength
+	}
+	if v := os.Getenv(MaxRedirectChain); v != "" {
+		maxChain, err := strconv.Atoi(v)
+		if err != nil {
+			log.Printf("Invalid %s %q", MaxRedirectChain, v)
+			return nil, err
+		}
+		cfg.MaxRedirectChain = maxChain
+	}
+	if v := os.Getenv(MaxPasswordAttempts); v != "" {
+		maxAttempts, err := strconv.Atoi(v)
+		if err != nil {
+			log.Printf("Invalid %s %q", MaxPasswordAttempts, v)
+			return nil, err
+		}
+		cfg.MaxPasswordAttempts = maxAttempts
+	}
+	if v := os.Getenv(WebhookMaxAttempts); v != "" {
+		maxAttempts, err := strconv.Atoi(v)
+		if err != nil {
+			log.Printf("Invalid %s %q", WebhookMaxAttempts, v)
+			return nil, err
+		}
+		cfg.WebhookMaxAttempts = maxAttempts
+	}
+	if v := os.Getenv(WebhookTimeout); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			log.Printf("Invalid %s %q", WebhookTimeout, v)
+			return nil, err
+		}
+		cfg.WebhookTimeout = timeout
+	}
+	if v := os.Getenv(LinkCookieTTL); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil {
+			log.Printf("Invalid %s %q", LinkCookieTTL, v)
+			return nil, err
+		}
+		cfg.LinkCookieTTL = ttl
+	}
+	cfg.EventPublisher = os.Getenv(EventPublisher)
+	cfg.EventStream = os.Getenv(EventStream)
+	cfg.EventFile = os.Getenv(EventFile)
+	cfg.GeoIPDatabase = os.Getenv(GeoIPDatabase)
+	if v := os.Getenv(TrustedProxies); v != "" {
+		proxies, err := parsePrefixes(splitList(v))
+		if err != nil {
+			log.Printf("Invalid %s %q", TrustedProxies, v)
+			return nil, err
+		}
+		cfg.TrustedProxies = proxies
+	}
+	cfg.PolicyReloadInterval = DefaultPolicyReloadInterval
+	if v := os.Getenv(PolicyReloadInterval); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			log.Printf("Invalid %s %q", PolicyReloadInterval, v)
+			return nil, err
+		}
+		cfg.PolicyReloadInterval = interval
+	}
+	cfg.LinkCheckInterval = linkcheck.DefaultInterval
+	if v := os.Getenv(LinkCheckInterval); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil {
+			log.Printf("Invalid %s %q", LinkCheckInterval, v)
+			return nil, err
+		}
+		cfg.LinkCheckInterval = interval
+	}
+	if v := os.Getenv(LinkCheckConcurrency); v != "" {
+		concurrency, err := strconv.Atoi(v)
+		if err != nil {
+			log.Printf("Invalid %s %q", LinkCheckConcurrency, v)
+			return nil, err
+		}
+		cfg.LinkCheckConcurrency = concurrency
+	}
+	if v := os.Getenv(LinkCheckDomainDelay); v != "" {
+		delay, err := time.ParseDuration(v)
+		if err != nil {
+			log.Printf("Invalid %s %q", LinkCheckDomainDelay, v)
+			return nil, err
+		}
+		cfg.LinkCheckDomainDelay = delay
 	}
 
 	log.Printf("Connecting to PostgreSQL: %s:%s@%s/%s", cfg.PostgresUser, cfg.PostgresPassword, cfg.PostgresHost, cfg.PostgresDBName)
@@ -93,7 +223,27 @@ func NewUrlShortnerService() (*UrlShortenerService, error) {
 	}
 	datamodelDB := dataModel.NewDB(db)
 
-	return &UrlShortenerService{
+	guard, err := newNetGuard(cfg, nil)
+	if err != nil {
+		log.Printf("Error configuring destination host checks: %v", err)
+		return nil, err
+	}
+
+	relay, err := newEventRelay(cfg, datamodelDB, redisClient)
+	if err != nil {
+		log.Printf("Error configuring event publisher: %v", err)
+		return nil, err
+	}
+
+	var geo geoip.Database
+	if cfg.GeoIPDatabase != "" {
+		if geo, err = geoip.Open(cfg.GeoIPDatabase); err != nil {
+			log.Printf("Error loading GeoIP database: %v", err)
+			return nil, err
+		}
+	}
+
+	s := &UrlShortenerService{
 		Config:            cfg,
 		db:                datamodelDB,
 		RedisClient:       redisClient, // base.NewRedisClient returns *redis.Client which implements RedisClientInterface
@@ -101,60 +251,292 @@ func NewUrlShortnerService() (*UrlShortenerService, error) {
 		currentCounterVal: 0,
 		uppLimitVal:       0,
 		mu:                sync.Mutex{}, // Initialize the mutex
-	}, nil
+		netGuard:          guard,
+		webhooks:          newWebhookDispatcher(cfg, datamodelDB, guard),
+		eventRelay:        relay,
+		geo:               geo,
+	}
+	// Webhook URLs are checked again on delivery, the addresses dialed are
+	// checked by the dispatcher's transport.
+	s.webhooks.CheckURL = s.checkDestinationHost
+	// Links the policy disables or enables again are reported to webhooks.
+	s.policy = newPolicyEngine(cfg, datamodelDB, s.emitLinksChanged)
+	s.linkChecker = s.newLinkChecker(datamodelDB)
+	return s, nil
 }
 
 // ShortenURL takes a long URL and an API key, generates a unique short URL,
 // stores the mapping, and returns the short URL.
 // It validates the API key and uses a distributed counter (via Zookeeper) to generate unique IDs.
+// A custom alias, when given, is used as the short URL instead. Both count
+// against the quotas of the caller's plan.
+// The long URL is validated and normalized first; if an equivalent URL was
+// already shortened its existing short URL is returned. Destinations going
+// through our own or other short links are resolved, see resolveChain.
 func (s *UrlShortenerService) ShortenURL(ctx context.Context, req *proto.ShortenURLRequest) (*proto.ShortenURLResponse, error) {
-	apiKey := req.ApiKey
-	originalURL := req.LongUrl
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
 
-	if apiKey == "" {
-		return nil, ErrMissingApiKey
+	urlMapping, reused, err := s.shortenURL(ctx, user, req)
+	if err != nil {
+		return nil, err
 	}
+	return s.shortenURLResponse(urlMapping, reused), nil
+}
 
-	//check if api key exists
-	isValidApiKey, err := s.db.CheckAPIKey(apiKey)
+// shortenURLResponse describes mapping to the client that shortened it.
+func (s *UrlShortenerService) shortenURLResponse(mapping *dataModel.URLMapping, reused bool) *proto.ShortenURLResponse {
+	resp := &proto.ShortenURLResponse{
+		ShortUrl:        displayShortURL(mapping),
+		Code:            mapping.ShortURLID,
+		LongUrl:         mapping.LongURL,
+		MaxClicks:       mapping.MaxClicks,
+		ClicksRemaining: mapping.ClicksRemaining,
+		Reused:          reused,
+		Utm:             utmToProto(parseUTM(mapping.LongURL)),
+	}
+	if !mapping.CreatedAt.IsZero() {
+		resp.CreatedAt = timestamppb.New(mapping.CreatedAt)
+	}
+	// Without a public host clients still get the code.
+	resp.QrCodeUrl, _ = s.Config.qrCodeURL(mapping)
+	if mapping.Host != "" {
+		resp.Url = resp.ShortUrl
+		return resp
+	}
+	resp.Url, _ = s.Config.shortLinkURL(mapping.ShortURLID)
+	return resp
+}
 
+// shortenURL shortens req on behalf of an authenticated user and returns the
+// created mapping, or the existing one for an equivalent link with reused set.
+func (s *UrlShortenerService) shortenURL(ctx context.Context, user *dataModel.User, req *proto.ShortenURLRequest) (*dataModel.URLMapping, bool, error) {
+	urlMapping, existing, err := s.prepareURLMapping(ctx, user, req)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	if !isValidApiKey {
-		return nil, ErrInvalidApiKey
+	if existing {
+		return urlMapping, true, nil
 	}
 
-	counter, err := requestCounterFunc(s)
+	release, err := s.consumeQuota(user, usageDelta(urlMapping))
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	shortURL := base62Encode(counter)
+	if urlMapping.ShortURLID == "" {
+		counter, err := requestCounterFunc(s)
+		if err != nil {
+			release()
+			return nil, false, err
+		}
+		urlMapping.ShortURLID = base62Encode(counter)
+	}
 
+	if err := s.db.CreateURLMapping(urlMapping, s.outboxEvents(events.LinkCreated(urlMapping))...); err != nil {
+		release()
+		return nil, false, err
+	}
+	s.notifyEvents()
+	s.emitWebhook(user.Workspace.ID, EventLinkCreated, newLinkEvent(urlMapping))
+
+	return urlMapping, false, nil
+}
+
+// prepareURLMapping validates a shorten request and builds the mapping to
+// create for it. The ShortURLID is left empty unless a custom alias was
+// requested. When an equivalent link already exists it is returned instead,
+// with existing set.
+func (s *UrlShortenerService) prepareURLMapping(ctx context.Context, user *dataModel.User, req *proto.ShortenURLRequest) (*dataModel.URLMapping, bool, error) {
+	normalizedURL, err := s.Config.normalizeURL(req.LongUrl)
+	if err != nil {
+		return nil, false, err
+	}
+	normalizedURL, campaign, err := s.applyUTM(user, req, normalizedURL)
+	if err != nil {
+		return nil, false, err
+	}
+	originalURL, resolvedURL, err := s.resolveChain(ctx, normalizedURL)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.checkUTMRules(user, originalURL); err != nil {
+		return nil, false, err
+	}
+	for _, destination := range uniqueStrings(originalURL, resolvedURL) {
+		if err := s.checkDestination(destination); err != nil {
+			return nil, false, err
+		}
+		if err := s.checkDestinationHost(ctx, destination); err != nil {
+			return nil, false, err
+		}
+	}
+	if resolvedURL == originalURL {
+		resolvedURL = ""
+	}
+
+	if req.CustomAlias != "" && !isValidCustomAlias(req.CustomAlias) {
+		return nil, false, ErrInvalidCustomAlias
+	}
+	status, ok := redirectStatus(req.RedirectType)
+	if !ok {
+		return nil, false, fmt.Errorf("%w: unknown redirect type %d", ErrInvalidRedirectType, req.RedirectType)
+	}
+	if len(req.Password) > MaxLinkPasswordLength {
+		return nil, false, ErrInvalidLinkPassword
+	}
+	if req.MaxClicks < 0 {
+		return nil, false, ErrInvalidMaxClicks
+	}
+	og, err := openGraphFromProto(req.Og)
+	if err != nil {
+		return nil, false, err
+	}
+	host := ""
+	if req.Domain != "" {
+		if host, err = s.userHost(user, req.Domain); err != nil {
+			return nil, false, err
+		}
+	}
 	urlMapping := &dataModel.URLMapping{
-		ShortURLID: shortURL,
-		LongURL:    originalURL,
+		Host:             host,
+		ShortURLID:       req.CustomAlias,
+		LongURL:          originalURL,
+		UserID:           user.ID,
+		WorkspaceID:      user.Workspace.ID,
+		IsCustomAlias:    req.CustomAlias != "",
+		ResolvedURL:      resolvedURL,
+		RedirectStatus:   status,
+		QueryPassthrough: req.QueryPassthrough,
+		ForwardPath:      req.ForwardPath,
+		MaxClicks:        req.MaxClicks,
+		ClicksRemaining:  req.MaxClicks,
+		Interstitial:     req.Interstitial,
+		OG:               og,
+	}
+	if campaign != nil {
+		urlMapping.CampaignID = campaign.ID
+	}
+	utm := parseUTM(originalURL)
+	urlMapping.UTMSource, urlMapping.UTMMedium = utm.Source, utm.Medium
+
+	// Click limited links, links on branded domains, links created in a
+	// campaign, links with OpenGraph metadata and password protected links
+	// are never shared, each request gets its own.
+	if req.MaxClicks == 0 && host == "" && campaign == nil && og.IsZero() && req.Password == "" {
+		existing, err := s.db.GetURLMappingByLongURL(user.Workspace.ID, originalURL)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Error looking up existing mapping for %s: %v", originalURL, err)
+			return nil, false, err
+		}
+		if existing != nil {
+			if req.CustomAlias != "" && req.CustomAlias != existing.ShortURLID {
+				return nil, false, ErrLongURLExists
+			}
+			if !sameRedirectSettings(existing, urlMapping) {
+				return nil, false, fmt.Errorf("%w with different settings", ErrLongURLExists)
+			}
+			return existing, true, nil
+		}
 	}
 
-	if err := s.db.CreateURLMapping(urlMapping); err != nil {
-		return nil, err
+	if req.Password != "" {
+		if urlMapping.PasswordHash, err = hashLinkPassword(req.Password); err != nil {
+			return nil, false, err
+		}
 	}
+	return urlMapping, false, nil
+}
 
-	return &proto.ShortenURLResponse{ShortUrl: shortURL}, nil
+// usageDelta is the quota a new mapping consumes.
+func usageDelta(mapping *dataModel.URLMapping) dataModel.UsageDelta {
+	delta := dataModel.UsageDelta{Links: 1}
+	if mapping.IsCustomAlias {
+		delta.CustomAliases = 1
+	}
+	return delta
 }
 
 // GetURL retrieves the original long URL corresponding to a given short URL.
 // It queries the database for the URL mapping.
+// Destinations blocked since the link was created are not returned, and
+// scheduled links return their current destination. Looking a link up is
+// not a visit, it uses up none of the clicks of click limited links.
 func (s *UrlShortenerService) GetURL(ctx context.Context, req *proto.GetURLRequest) (*proto.GetURLResponse, error) {
-	shortURL := req.ShortUrl
-
-	longURL, err := s.db.GetLongURL(shortURL)
+	mapping, _, err := s.lookupURL("", req.ShortUrl)
 	if err != nil {
 		return nil, err
 	}
+	if mapping.PasswordHash != "" {
+		if err := s.checkLinkPassword(mapping, grpcClientAddr(ctx), req.Password); err != nil {
+			return nil, err
+		}
+	}
+	return getURLResponse(mapping), nil
+}
+
+// lookupURL returns the mapping of a short URL that may be followed, on a
+// branded host or, when host is empty, the service's own hosts. The long URL
+// of a scheduled mapping is its destination active now, and until is when
+// that changes; it is zero for mappings whose destination stays.
+func (s *UrlShortenerService) lookupURL(host, shortURL string) (mapping *dataModel.URLMapping, until time.Time, err error) {
+	if host == "" {
+		mapping, err = s.db.GetURLMapping(shortURL)
+	} else {
+		mapping, err = s.db.GetURLMappingOnHost(host, shortURL)
+	}
+	if err != nil {
+		return nil, until, err
+	}
+	if mapping.Disabled {
+		return nil, until, dataModel.ErrLinkDisabled
+	}
+	if mapping.Exhausted() {
+		return nil, until, dataModel.ErrLinkExhausted
+	}
+	if mapping.HasSchedule {
+		if until, err = s.applySchedule(mapping, time.Now()); err != nil {
+			return nil, until, err
+		}
+	}
+	if err := s.checkDestination(mapping.LongURL); err != nil {
+		return nil, until, err
+	}
+	return mapping, until, nil
+}
+
+// consumeClick uses up a click of a click limited mapping.
+func (s *UrlShortenerService) consumeClick(mapping *dataModel.URLMapping) error {
+	if mapping.MaxClicks == 0 {
+		return nil
+	}
+	remaining, err := s.db.ConsumeClick(mapping.Host, mapping.ShortURLID)
+	if err != nil {
+		if !errors.Is(err, dataModel.ErrLinkExhausted) {
+			log.Printf("Error consuming click of %s: %v", mapping.ShortURLID, err)
+		}
+		return err
+	}
+	mapping.ClicksRemaining = remaining
+	if remaining == 0 {
+		s.emitWebhook(mapping.WorkspaceID, EventLinkExpired, newLinkEvent(mapping))
+	}
+	return nil
+}
 
-	return &proto.GetURLResponse{LongUrl: longURL}, nil
+func getURLResponse(mapping *dataModel.URLMapping) *proto.GetURLResponse {
+	return &proto.GetURLResponse{
+		LongUrl:          mapping.LongURL,
+		ResolvedUrl:      mapping.ResolvedURL,
+		RedirectType:     redirectType(mapping.RedirectStatus),
+		QueryPassthrough: mapping.QueryPassthrough,
+		ForwardPath:      mapping.ForwardPath,
+		MaxClicks:        mapping.MaxClicks,
+		ClicksRemaining:  mapping.ClicksRemaining,
+		Interstitial:     mapping.Interstitial,
+	}
 }
 
 // CreateUser creates a new user in the system with the provided first name, last name, and email.
@@ -216,11 +598,42 @@ func (s *UrlShortenerService) GetTopDomains(ctx context.Context, req *proto.GetT
 // and sets up the HTTP gateway (proxy) to handle RESTful API calls.
 func (s *UrlShortenerService) Start() error {
 	// Auto migrate the database tables
-	err := s.db.AutoMigrate(&dataModel.URLMapping{}, &dataModel.User{})
+	err := s.db.AutoMigrate(&dataModel.URLMapping{}, &dataModel.User{}, &dataModel.UsageCounter{}, &dataModel.PolicyRule{},
+		&dataModel.WebhookSubscription{}, &dataModel.WebhookDelivery{}, &dataModel.WebhookDeadLetter{},
+		&dataModel.OutboxEvent{}, &dataModel.Domain{}, &dataModel.RoutingRule{}, &dataModel.LinkVariant{},
+		&dataModel.ScheduledDestination{}, &dataModel.LinkGroup{}, &dataModel.LinkTag{}, &dataModel.UTMRule{}, &dataModel.LinkClickDay{})
 	if err != nil {
 		log.Fatalf("failed to automigrate: %v", err)
 		return err
 	}
+
+	// Load the destination policy and keep it fresh
+	if s.policy != nil {
+		if err := s.policy.Reload(); err != nil {
+			log.Fatalf("failed to load policy rules: %v", err)
+			return err
+		}
+		go s.policy.Watch(context.Background(), s.Config.PolicyReloadInterval)
+	}
+
+	// Deliver queued webhooks in the background
+	if s.webhooks != nil {
+		go s.webhooks.Run(context.Background(), WebhookPollInterval)
+	}
+
+	// Add up link clicks in the background
+	go s.flushClickCounts(context.Background(), ClickCountFlushInterval)
+	go s.pruneLinkClicks(context.Background(), ClickPruneInterval)
+
+	// Publish domain events from the outbox in the background
+	if s.eventRelay != nil {
+		go s.eventRelay.Run(context.Background(), EventPollInterval)
+	}
+
+	// Check link destinations for being broken in the background
+	if s.linkChecker != nil {
+		go s.linkChecker.Run(context.Background(), LinkCheckPollInterval)
+	}
 	//taking a mutex lock
 	lis, err := net.Listen("tcp", ":"+s.Config.GrpcPort)
 	if err != nil {
@@ -272,7 +685,17 @@ func (s *UrlShortenerService) Start() error {
 	}
 
 	// Add a handler for /d/{shortChar} to redirect to the full URL
+	r.HandleFunc("/shorten/batch.csv", s.batchCSVHandler).Methods(http.MethodPost)
+	r.HandleFunc("/p/{shortChar}", s.previewHandler)
+	r.HandleFunc("/d/{shortChar:[^/+]+}+", s.previewHandler)
 	r.HandleFunc("/d/{shortChar}", s.redirectHandler)
+	r.HandleFunc("/d/{shortChar}/{extraPath:.*}", s.redirectHandler)
+
+	// Branded domains serve their links at the root
+	branded := r.MatcherFunc(s.isBrandedRequest).Methods(http.MethodGet, http.MethodHead).Subrouter()
+	branded.HandleFunc("/{shortChar:[^/+]+}+", s.previewHandler)
+	branded.HandleFunc("/{shortChar}", s.redirectHandler)
+	branded.HandleFunc("/{shortChar}/{extraPath:.*}", s.redirectHandler)
 
 	// Serve the gRPC gateway
 	apiRouter := r.PathPrefix("/").Subrouter()
@@ -291,21 +714,117 @@ func (s *UrlShortenerService) Start() error {
 
 // redirectHandler is an HTTP handler that takes a short URL character code from the path,
 // retrieves the corresponding long URL using the GetURL service method,
-// and then redirects the client to the long URL.
+// and then redirects the client to the long URL with the link's redirect status.
+// Any path after the short code and the query string are forwarded when the
+// link enables it. Password protected links ask for the password first, and
+// click limited links answer 410 Gone once all clicks are used up.
 func (s *UrlShortenerService) redirectHandler(w http.ResponseWriter, r *http.Request) {
+	s.followLink(w, r, false)
+}
+
+// followLink serves a short link. With preview, or when the link or its
+// destination calls for an interstitial, the destination is shown on a page
+// instead of redirecting to it, until the visitor continues from that page.
+// Redirects are recorded as clicks. Destinations of routing rules and
+// variants are checked against the policy and host checks again first.
+func (s *UrlShortenerService) followLink(w http.ResponseWriter, r *http.Request, preview bool) {
 	vars := mux.Vars(r)
 	shortChar := vars["shortChar"]
 
-	// Use GetURL to get the full URL
-	getURLRequest := &proto.GetURLRequest{ShortUrl: shortChar}
-	resp, err := s.GetURL(context.Background(), getURLRequest)
+	mapping, until, err := s.lookupURL(s.linkHost(r), shortChar)
+	if errors.Is(err, dataModel.ErrLinkDisabled) || errors.Is(err, ErrBlockedDestination) {
+		http.Error(w, "This link has been disabled", http.StatusGone)
+		return
+	}
+	if errors.Is(err, dataModel.ErrLinkExhausted) {
+		http.Error(w, "This link has expired", http.StatusGone)
+		return
+	}
 	if err != nil {
 		http.Error(w, "URL not found", http.StatusNotFound)
 		return
 	}
 
+	extraPath := vars["extraPath"]
+	if extraPath != "" && !mapping.ForwardPath {
+		http.Error(w, "URL not found", http.StatusNotFound)
+		return
+	}
+	if mapping.PasswordHash != "" && !s.serveLinkPassword(w, r, mapping) {
+		return
+	}
+	if !preview && !mapping.OG.IsZero() {
+		// Caches must not hand the page crawlers get to visitors.
+		w.Header().Add("Vary", "User-Agent")
+		// Unfurls are not clicks and do not use up click limited links.
+		if s.Config.isCrawler(r.UserAgent()) {
+			s.renderOpenGraph(w, mapping)
+			return
+		}
+	}
+
+	visit := s.newClick(r)
+	resp := getURLResponse(mapping)
+	destination, err := s.visitorDestination(w, r, mapping, &visit)
+	if err != nil {
+		log.Printf("Error choosing the destination of %s: %v", shortChar, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if destination != "" {
+		resp.LongUrl = destination
+	}
+	target, err := redirectTarget(resp, extraPath, r.URL.Query())
+	if err != nil {
+		log.Printf("Error building redirect target for %s: %v", shortChar, err)
+		http.Error(w, "URL not found", http.StatusNotFound)
+		return
+	}
+	if destination != "" {
+		if err := s.checkVisitorDestination(r.Context(), target); err != nil {
+			if errors.Is(err, ErrBlockedDestination) || errors.Is(err, ErrInternalDestination) {
+				log.Printf("Not redirecting %s to %s: %v", shortChar, target, err)
+				http.Error(w, "This link has been disabled", http.StatusGone)
+				return
+			}
+			http.Error(w, "URL not found", http.StatusNotFound)
+			return
+		}
+	}
+	// Showing the destination is not a visit, continuing to it is.
+	interstitial := preview || mapping.Interstitial || s.Config.isInterstitialDomain(target)
+	if interstitial && !continued(r) {
+		s.renderInterstitial(w, mapping, target)
+		return
+	}
+	if mapping.MaxClicks > 0 {
+		err := s.consumeClick(mapping)
+		if errors.Is(err, dataModel.ErrLinkExhausted) {
+			http.Error(w, "This link has expired", http.StatusGone)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		// Cached redirects would not be counted.
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	if visit.VariantID != 0 {
+		if err := s.db.RecordVariantClick(visit.VariantID); err != nil {
+			log.Printf("Error counting click of variant %d of %s: %v", visit.VariantID, shortChar, err)
+		}
+	}
+	s.recordClick(mapping, visit)
+	cacheUntil(w, until, time.Now())
+
 	// Redirect to the full URL
-	http.Redirect(w, r, resp.LongUrl, http.StatusFound)
+	status, _ := redirectStatus(resp.RedirectType)
+	if interstitial {
+		// The continue button posted, the destination is fetched with GET.
+		status = http.StatusSeeOther
+	}
+	http.Redirect(w, r, target, status)
 }
 
 // requestCounter provides a unique, incrementing counter value.
@@ -314,9 +833,16 @@ func (s *UrlShortenerService) redirectHandler(w http.ResponseWriter, r *http.Req
 // This helps in reducing frequent calls to Zookeeper for every request.
 // It ensures thread safety using a mutex.
 func (s *UrlShortenerService) requestCounter() (int64, error) {
+	return s.requestCounterRange(1)
+}
+
+// requestCounterRange reserves n consecutive counter values and returns the
+// first one. A new batch is fetched from Zookeeper when the local one cannot
+// hold all n values; it is at least n large.
+func (s *UrlShortenerService) requestCounterRange(n int64) (int64, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if s.currentCounterVal >= s.uppLimitVal {
+	if s.currentCounterVal+n > s.uppLimitVal {
 		//connect to zk and fetch the current count from zk
 		conn := s.ZookeeperClient
 		if !s.isCounterExists {
@@ -339,8 +865,8 @@ func (s *UrlShortenerService) requestCounter() (int64, error) {
 			return -1, err
 
 		}
-		//increment the zk counter by 10000
-		newCounter := int64(counter + 10000)
+		//increment the zk counter by 10000, or by n for larger ranges
+		newCounter := int64(counter) + max(10000, n)
 
 		_, err = conn.Set("/counter", []byte(strconv.FormatInt(newCounter, 10)), stat.Version)
 		if err != nil {
@@ -353,6 +879,7 @@ func (s *UrlShortenerService) requestCounter() (int64, error) {
 		s.uppLimitVal = newCounter
 		log.Printf("Updated currentCounterVal to %d and uppLimitVal to %d", s.currentCounterVal, s.uppLimitVal)
 	}
-	s.currentCounterVal += 1
-	return s.currentCounterVal, nil
+	first := s.currentCounterVal + 1
+	s.currentCounterVal += n
+	return first, nil
 }
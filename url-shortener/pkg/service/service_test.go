@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
 	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 const (
@@ -73,7 +75,8 @@ func TestShortenURL(t *testing.T) {
 			LongUrl: "http://example.com/very/long/url",
 		}
 
-		mockDb.On("CheckAPIKey", "valid-api-key").Return(true, nil).Once()
+		mockDb.On("GetUserByAPIKey", "valid-api-key").Return(testUser(1), nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), "http://example.com/very/long/url").Return(nil, gorm.ErrRecordNotFound).Once()
 		mockDb.On("CreateURLMapping", mock.AnythingOfType("*dataModel.URLMapping")).Run(func(args mock.Arguments) {
 		}).Return(nil).Once()
 		requestCounterFunc = func(s *UrlShortenerService) (int64, error) {
@@ -101,7 +104,7 @@ func TestShortenURL(t *testing.T) {
 
 	t.Run("Invalid API Key", func(t *testing.T) {
 		req := &proto.ShortenURLRequest{ApiKey: "invalid-api-key", LongUrl: "http://example.com/another/url"}
-		mockDb.On("CheckAPIKey", "invalid-api-key").Return(false, nil).Once()
+		mockDb.On("GetUserByAPIKey", "invalid-api-key").Return(nil, gorm.ErrRecordNotFound).Once()
 		resp, err := s.ShortenURL(ctx, req)
 		assert.Error(t, err)
 		assert.Equal(t, ErrInvalidApiKey, err)
@@ -109,10 +112,10 @@ func TestShortenURL(t *testing.T) {
 		mockDb.AssertExpectations(t)
 	})
 
-	t.Run("DB Error on CheckAPIKey", func(t *testing.T) {
+	t.Run("DB Error on GetUserByAPIKey", func(t *testing.T) {
 		req := &proto.ShortenURLRequest{ApiKey: "any-api-key", LongUrl: "http://example.com/some/url"}
 		dbErr := errors.New("db error checking api key")
-		mockDb.On("CheckAPIKey", "any-api-key").Return(false, dbErr).Once()
+		mockDb.On("GetUserByAPIKey", "any-api-key").Return(nil, dbErr).Once()
 		resp, err := s.ShortenURL(ctx, req)
 		assert.Error(t, err)
 		assert.Equal(t, dbErr, err)
@@ -125,7 +128,8 @@ func TestShortenURL(t *testing.T) {
 		s.uppLimitVal = 0
 		s.isCounterExists = false
 		req := &proto.ShortenURLRequest{ApiKey: "valid-api-key", LongUrl: "http://example.com/long/url"}
-		mockDb.On("CheckAPIKey", "valid-api-key").Return(true, nil).Once()
+		mockDb.On("GetUserByAPIKey", "valid-api-key").Return(testUser(1), nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), "http://example.com/long/url").Return(nil, gorm.ErrRecordNotFound).Once()
 		requestCounterFunc = func(s *UrlShortenerService) (int64, error) {
 			return 12345, nil
 		}
@@ -149,7 +153,8 @@ func TestShortenURL(t *testing.T) {
 			ApiKey:  "valid-api-key",
 			LongUrl: "http://example.com/another/long/url",
 		}
-		mockDb.On("CheckAPIKey", "valid-api-key").Return(true, nil).Once()
+		mockDb.On("GetUserByAPIKey", "valid-api-key").Return(testUser(1), nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), "http://example.com/another/long/url").Return(nil, gorm.ErrRecordNotFound).Once()
 
 		// Simulate an error from Zookeeper Exits
 		requestCounterFunc = func(s *UrlShortenerService) (int64, error) {
@@ -165,6 +170,84 @@ func TestShortenURL(t *testing.T) {
 	})
 }
 
+func TestShortenURLResponse(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	created := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return 12345, nil }
+	t.Cleanup(func() {
+		requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return s.requestCounter() }
+	})
+
+	t.Run("New link", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, Config: Config{PublicBaseURL: "https://sho.rt"}}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("CreateURLMapping", mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(0).(*dataModel.URLMapping).CreatedAt = created
+		}).Return(nil).Once()
+
+		resp, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "HTTP://Example.com:80/a#top", MaxClicks: 3})
+		assert.NoError(t, err)
+		code := base62Encode(12345)
+		assert.Equal(t, code, resp.ShortUrl)
+		assert.Equal(t, code, resp.Code)
+		assert.Equal(t, "https://sho.rt/d/"+code, resp.Url)
+		assert.Equal(t, "https://sho.rt/qr/"+code, resp.QrCodeUrl)
+		assert.Equal(t, "http://example.com/a", resp.LongUrl)
+		assert.Equal(t, created, resp.CreatedAt.AsTime())
+		assert.Equal(t, int64(3), resp.MaxClicks)
+		assert.Equal(t, int64(3), resp.ClicksRemaining)
+		assert.False(t, resp.Reused)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Reused link", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb, Config: Config{PublicHosts: []string{"sho.rt"}}}
+		existing := &dataModel.URLMapping{Model: gorm.Model{CreatedAt: created}, ShortURLID: "abc", LongURL: "http://example.com/", RedirectStatus: 302}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), "http://example.com/").Return(existing, nil).Once()
+		mockDb.On("GetVerifiedDomain", "example.com").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		resp, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "http://example.com/"})
+		assert.NoError(t, err)
+		assert.True(t, resp.Reused)
+		assert.Equal(t, "https://sho.rt/d/abc", resp.Url)
+		assert.Equal(t, created, resp.CreatedAt.AsTime())
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("No public host", func(t *testing.T) {
+		s := &UrlShortenerService{}
+		resp := s.shortenURLResponse(&dataModel.URLMapping{ShortURLID: "abc"}, false)
+		assert.Equal(t, "abc", resp.ShortUrl)
+		assert.Empty(t, resp.Url)
+		assert.Empty(t, resp.QrCodeUrl)
+		assert.Nil(t, resp.CreatedAt)
+	})
+
+	t.Run("Branded domain", func(t *testing.T) {
+		s := &UrlShortenerService{Config: Config{PublicBaseURL: "https://sho.rt"}}
+		resp := s.shortenURLResponse(&dataModel.URLMapping{Host: "go.acme.com", ShortURLID: "launch"}, false)
+		assert.Equal(t, "https://go.acme.com/launch", resp.Url)
+		assert.Equal(t, "launch", resp.Code)
+		assert.Equal(t, "https://sho.rt/qr/launch?domain=go.acme.com", resp.QrCodeUrl)
+	})
+}
+
+func TestParsePublicBaseURL(t *testing.T) {
+	base, host, err := parsePublicBaseURL("https://Sho.rt/links/")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://Sho.rt/links", base)
+	assert.Equal(t, "sho.rt", host)
+
+	for _, v := range []string{"sho.rt", "ftp://sho.rt", "https://sho.rt/?x=1", "https://user@sho.rt"} {
+		_, _, err := parsePublicBaseURL(v)
+		assert.ErrorIs(t, err, ErrInvalidURL, v)
+	}
+}
+
 func TestGetURL(t *testing.T) {
 	mockDb := new(MockDB)
 	s := &UrlShortenerService{db: mockDb} // ZK and Redis not directly used by GetURL
@@ -173,7 +256,7 @@ func TestGetURL(t *testing.T) {
 	t.Run("Successful GetURL", func(t *testing.T) {
 		req := &proto.GetURLRequest{ShortUrl: "testShort"}
 		expectedLongURL := "http://example.com/original/long/url"
-		mockDb.On("GetLongURL", "testShort").Return(expectedLongURL, nil).Once()
+		mockDb.On("GetURLMapping", "testShort").Return(&dataModel.URLMapping{LongURL: expectedLongURL}, nil).Once()
 		resp, err := s.GetURL(ctx, req)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
@@ -184,7 +267,7 @@ func TestGetURL(t *testing.T) {
 	t.Run("GetURL DB Error", func(t *testing.T) {
 		req := &proto.GetURLRequest{ShortUrl: "testShort"}
 		dbErr := errors.New("db error getting long url")
-		mockDb.On("GetLongURL", "testShort").Return("", dbErr).Once()
+		mockDb.On("GetURLMapping", "testShort").Return(nil, dbErr).Once()
 		resp, err := s.GetURL(ctx, req)
 		assert.Error(t, err)
 		assert.Equal(t, dbErr, err)
@@ -270,7 +353,7 @@ func TestRedirectHandler(t *testing.T) {
 		rr := httptest.NewRecorder()
 		req = mux.SetURLVars(req, map[string]string{"shortChar": "testShort"})
 		expectedLongURL := "http://example.com/redirected/url"
-		mockDb.On("GetLongURL", "testShort").Return(expectedLongURL, nil).Once()
+		mockDb.On("GetURLMapping", "testShort").Return(&dataModel.URLMapping{LongURL: expectedLongURL}, nil).Once()
 		http.HandlerFunc(s.redirectHandler).ServeHTTP(rr, req)
 		assert.Equal(t, http.StatusFound, rr.Code)
 		assert.Equal(t, expectedLongURL, rr.Header().Get("Location"))
@@ -282,7 +365,7 @@ func TestRedirectHandler(t *testing.T) {
 		rr := httptest.NewRecorder()
 		req = mux.SetURLVars(req, map[string]string{"shortChar": "errorShort"})
 		dbErr := errors.New("db error for GetURL in redirect")
-		mockDb.On("GetLongURL", "errorShort").Return("", dbErr).Once()
+		mockDb.On("GetURLMapping", "errorShort").Return(nil, dbErr).Once()
 		http.HandlerFunc(s.redirectHandler).ServeHTTP(rr, req)
 		assert.Equal(t, http.StatusNotFound, rr.Code)
 		mockDb.AssertExpectations(t)
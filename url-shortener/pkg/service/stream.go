@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// StreamShorten shortens the URLs sent over a long-lived stream. The api_key
+// of the first request authenticates the whole stream.
+//
+// Up to StreamConcurrency items are shortened at once and answered as they
+// complete. No further requests are read until a response is sent, so
+// clients sending faster than they are served are held back by gRPC flow
+// control. The stream ends when the client closes it and all its items are
+// answered, when the client's deadline passes or after StreamIdleTimeout
+// without requests.
+func (s *UrlShortenerService) StreamShorten(stream proto.URLShortener_StreamShortenServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	// A slot is taken before every read and freed once its response is sent.
+	slots := make(chan struct{}, StreamConcurrency)
+	requests := make(chan *proto.StreamShortenRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case requests <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Holding at most one response per slot, workers never block on it.
+	responses := make(chan *proto.StreamShortenResponse, StreamConcurrency)
+	sent := make(chan struct{})
+	var sendErr error
+	go func() {
+		defer close(sent)
+		for resp := range responses {
+			if sendErr != nil {
+				continue
+			}
+			if sendErr = stream.Send(resp); sendErr != nil {
+				cancel()
+				continue
+			}
+			<-slots
+		}
+	}()
+
+	var user *dataModel.User
+	var workers sync.WaitGroup
+	var sequence int64
+	idle := time.NewTimer(StreamIdleTimeout)
+	defer idle.Stop()
+
+	var err error
+loop:
+	for {
+		select {
+		case req := <-requests:
+			idle.Reset(StreamIdleTimeout)
+			if user == nil {
+				if user, err = s.authorize(req.ApiKey, dataModel.RoleEditor); err != nil {
+					break loop
+				}
+			}
+			workers.Add(1)
+			go func(sequence int64) {
+				defer workers.Done()
+				responses <- s.streamShortenItem(ctx, user, sequence, req)
+			}(sequence)
+			sequence++
+		case err = <-recvErr:
+			if err == io.EOF {
+				err = nil
+			}
+			break loop
+		case <-idle.C:
+			err = status.Error(codes.DeadlineExceeded, ErrStreamIdle.Error())
+			break loop
+		case <-ctx.Done():
+			err = status.FromContextError(ctx.Err()).Err()
+			break loop
+		}
+	}
+
+	if err != nil {
+		cancel()
+	}
+	workers.Wait()
+	close(responses)
+	<-sent
+	if sendErr != nil {
+		return sendErr
+	}
+	return err
+}
+
+// streamShortenItem shortens the item of a StreamShorten request. The item
+// gets StreamItemTimeout to resolve and check its destination.
+func (s *UrlShortenerService) streamShortenItem(ctx context.Context, user *dataModel.User, sequence int64, req *proto.StreamShortenRequest) *proto.StreamShortenResponse {
+	ctx, cancel := context.WithTimeout(ctx, StreamItemTimeout)
+	defer cancel()
+
+	item := req.Item
+	if item == nil {
+		item = &proto.ShortenURLRequest{}
+	}
+	resp := &proto.StreamShortenResponse{Sequence: sequence, RequestId: req.RequestId, LongUrl: item.LongUrl}
+	mapping, _, err := s.shortenURL(ctx, user, item)
+	if err != nil {
+		resp.Error = errorMessage(err)
+		return resp
+	}
+	resp.ShortUrl = displayShortURL(mapping)
+	return resp
+}
+
+// WatchClicks streams the clicks on the caller's links, or on the requested
+// ones among them, until the client goes away or its deadline passes. Clicks
+// are pushed as they are served by this replica; when the client reads too
+// slowly to keep ClickWatchBuffer events queued, clicks are dropped and
+// counted in the next event sent.
+func (s *UrlShortenerService) WatchClicks(req *proto.WatchClicksRequest, stream proto.URLShortener_WatchClicksServer) error {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return err
+	}
+
+	watcher := s.clicks.watch(user.Workspace.ID, req.ShortUrls)
+	defer s.clicks.stop(user.Workspace.ID, watcher)
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case c := <-watcher.events:
+			event := &proto.ClickEvent{
+				ShortUrl:        c.ShortURL,
+				ClickedAt:       timestamppb.New(c.At),
+				Referrer:        c.Referrer,
+				UserAgent:       c.UserAgent,
+				ClicksRemaining: c.ClicksRemaining,
+				Dropped:         watcher.dropped.Swap(0),
+				Country:         c.Location.Country,
+				Region:          c.Location.Region,
+				VariantId:       uint64(c.VariantID),
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
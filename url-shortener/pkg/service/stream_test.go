@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+)
+
+// streamClient serves s over an in-memory connection and returns a client of it.
+func streamClient(t *testing.T, s *UrlShortenerService) proto.URLShortenerClient {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	proto.RegisterURLShortenerServer(server, s)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return proto.NewURLShortenerClient(conn)
+}
+
+func TestStreamShorten(t *testing.T) {
+	var counter atomic.Int64
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return counter.Add(1), nil }
+	t.Cleanup(func() {
+		requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return s.requestCounter() }
+	})
+
+	t.Run("Every request is answered", func(t *testing.T) {
+		mockDb := new(MockDB)
+		client := streamClient(t, &UrlShortenerService{db: mockDb})
+		mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+		mockDb.On("CreateURLMapping", mock.Anything).Return(nil)
+
+		stream, err := client.StreamShorten(context.Background())
+		require.NoError(t, err)
+		items := map[string]string{
+			"a": "http://example.com/a",
+			"b": "ftp://example.com/b",
+			"c": "http://example.com/c",
+		}
+		require.NoError(t, stream.Send(&proto.StreamShortenRequest{ApiKey: "key", RequestId: "a", Item: &proto.ShortenURLRequest{LongUrl: items["a"]}}))
+		require.NoError(t, stream.Send(&proto.StreamShortenRequest{RequestId: "b", Item: &proto.ShortenURLRequest{LongUrl: items["b"]}}))
+		require.NoError(t, stream.Send(&proto.StreamShortenRequest{RequestId: "c", Item: &proto.ShortenURLRequest{LongUrl: items["c"]}}))
+		require.NoError(t, stream.CloseSend())
+
+		got := map[string]*proto.StreamShortenResponse{}
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			got[resp.RequestId] = resp
+		}
+		require.Len(t, got, 3)
+		assert.Equal(t, int64(0), got["a"].Sequence)
+		assert.Equal(t, int64(2), got["c"].Sequence)
+		assert.NotEmpty(t, got["a"].ShortUrl)
+		assert.NotEmpty(t, got["c"].ShortUrl)
+		assert.NotEqual(t, got["a"].ShortUrl, got["c"].ShortUrl)
+		assert.Empty(t, got["b"].ShortUrl)
+		assert.Contains(t, got["b"].Error, ErrInvalidURL.Error())
+		assert.Equal(t, items["b"], got["b"].LongUrl)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Invalid API key ends the stream", func(t *testing.T) {
+		mockDb := new(MockDB)
+		client := streamClient(t, &UrlShortenerService{db: mockDb})
+		mockDb.On("GetUserByAPIKey", "bad").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		stream, err := client.StreamShorten(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&proto.StreamShortenRequest{ApiKey: "bad", Item: &proto.ShortenURLRequest{LongUrl: "http://example.com"}}))
+		_, err = stream.Recv()
+		assert.ErrorContains(t, err, ErrInvalidApiKey.Error())
+	})
+}
+
+func TestWatchClicks(t *testing.T) {
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	client := streamClient(t, s)
+	mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil).Once()
+	mockDb.On("GetURLMapping", "mine").Return(&dataModel.URLMapping{ShortURLID: "mine", LongURL: "http://example.com", UserID: 1, WorkspaceID: 1}, nil)
+	mockDb.On("GetURLMapping", "theirs").Return(&dataModel.URLMapping{ShortURLID: "theirs", LongURL: "http://example.com", UserID: 2, WorkspaceID: 2}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchClicks(ctx, &proto.WatchClicksRequest{ApiKey: "key"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		s.clicks.mu.Lock()
+		defer s.clicks.mu.Unlock()
+		return len(s.clicks.watchers[1]) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	for _, code := range []string{"theirs", "mine"} {
+		req := httptest.NewRequest("GET", "/d/"+code, nil)
+		req.Header.Set("Referer", "https://news.example/")
+		req.Header.Set("User-Agent", "test-agent")
+		rr := httptest.NewRecorder()
+		s.redirectHandler(rr, mux.SetURLVars(req, map[string]string{"shortChar": code}))
+		assert.Equal(t, http.StatusFound, rr.Code)
+	}
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "mine", event.ShortUrl)
+	assert.Equal(t, "https://news.example/", event.Referrer)
+	assert.Equal(t, "test-agent", event.UserAgent)
+	assert.WithinDuration(t, time.Now(), event.ClickedAt.AsTime(), 5*time.Second)
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	assert.Eventually(t, func() bool {
+		s.clicks.mu.Lock()
+		defer s.clicks.mu.Unlock()
+		return len(s.clicks.watchers) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestClickFeed(t *testing.T) {
+	var feed clickFeed
+	all := feed.watch(1, nil)
+	some := feed.watch(1, []string{"b"})
+
+	for i := 0; i < ClickWatchBuffer+2; i++ {
+		feed.publish(click{ShortURL: "a", WorkspaceID: 1})
+	}
+	feed.publish(click{ShortURL: "b", WorkspaceID: 1})
+	feed.publish(click{ShortURL: "b", WorkspaceID: 2})
+
+	assert.Len(t, all.events, ClickWatchBuffer)
+	assert.Equal(t, uint64(3), all.dropped.Load())
+	assert.Len(t, some.events, 1)
+	assert.Equal(t, uint64(0), some.dropped.Load())
+
+	feed.stop(1, all)
+	feed.stop(1, some)
+	assert.Empty(t, feed.watchers)
+}
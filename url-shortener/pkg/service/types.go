@@ -1,9 +1,17 @@
 package service
 
 import (
+	"net/http"
+	"net/netip"
 	"sync"
 
 	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/events"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/geoip"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/linkcheck"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/netguard"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/policy"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/webhook"
 	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
 	"context"
 	"time"
@@ -21,11 +29,17 @@ type ZkClientInterface interface {
 	Close()
 }
 
+// TXTResolver looks up DNS TXT records. *net.Resolver implements it.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
 // RedisClientInterface defines the methods needed from a Redis client.
 type RedisClientInterface interface {
 	Get(ctx context.Context, key string) *redis.StringCmd
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
 	Ping(ctx context.Context) *redis.StatusCmd
+	XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd
 	Close() error
 }
 
@@ -42,6 +56,43 @@ type Config struct {
 	RedisPassword    string
 	ZookeeperHost    string
 	ZookeeperPort    string
+	EnforceQuotas    bool
+	AllowedSchemes   []string
+	MaxURLLength     int
+	KeepFragments    bool
+
+	PolicyFile           string
+	PolicyReloadInterval time.Duration
+
+	PublicHosts           []string
+	PublicBaseURL         string
+	BlockedHostCategories []string
+	DestinationExceptions []string
+
+	SelfLinkPolicy   string
+	KnownShorteners  []string
+	MaxRedirectChain int
+
+	LinkCookieSecret    []byte
+	LinkCookieTTL       time.Duration
+	MaxPasswordAttempts int
+
+	InterstitialDomains []string
+	CrawlerUserAgents   []string
+
+	WebhookMaxAttempts int
+	WebhookTimeout     time.Duration
+
+	EventPublisher string
+	EventStream    string
+	EventFile      string
+
+	GeoIPDatabase  string
+	TrustedProxies []netip.Prefix
+
+	LinkCheckInterval    time.Duration
+	LinkCheckConcurrency int
+	LinkCheckDomainDelay time.Duration
 }
 
 // UrlShortenerService encapsulates varies clients and counters for the service to work.
@@ -55,4 +106,18 @@ type UrlShortenerService struct {
 	mu                sync.Mutex
 	isCounterExists   bool
 	db                dataModel.DataAccessLayer
+	policy            *policy.Engine
+	netGuard          *netguard.Guard
+	httpClient        *http.Client
+	cookieSecretOnce  sync.Once
+	passwordAttempts  attemptLimiter
+	clicks            clickFeed
+	webhooks          *webhook.Dispatcher
+	webhookSubs       webhookSubscriptionCache
+	eventRelay        *events.Relay
+	linkChecker       *linkcheck.Checker
+	resolver          TXTResolver
+	brandedHosts      brandedHostCache
+	geo               geoip.Database
+	clickCounts       clickCounter
 }
@@ -1,11 +1,17 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"net/url"
+	"regexp"
 
 	"strings"
 
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
 	"github.com/go-zookeeper/zk"
+	"gorm.io/gorm"
 )
 
 // 
@@ -44,3 +50,125 @@ func base62Encode(number int64) string {
 	}
 	return encodedBuilder.String()
 }
+
+// authenticate resolves an API key to the user owning it.
+func (s *UrlShortenerService) authenticate(apiKey string) (*dataModel.User, error) {
+	if apiKey == "" {
+		return nil, ErrMissingApiKey
+	}
+	user, err := s.db.GetUserByAPIKey(apiKey)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidApiKey
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// authorize authenticates apiKey and checks the caller has at least role
+// in the workspace of the key.
+func (s *UrlShortenerService) authorize(apiKey, role string) (*dataModel.User, error) {
+	user, err := s.authenticate(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if !dataModel.RoleAtLeast(user.Role, role) {
+		return nil, fmt.Errorf("%w: %s role required", ErrPermissionDenied, role)
+	}
+	return user, nil
+}
+
+var customAliasPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{4,32}$`)
+
+// isValidCustomAlias reports whether alias may be used as a short URL ID.
+// Seven character base62 strings are reserved for codes from base62Encode.
+func isValidCustomAlias(alias string) bool {
+	if !customAliasPattern.MatchString(alias) {
+		return false
+	}
+	return len(alias) != 7 || strings.ContainsAny(alias, "-_")
+}
+
+// splitList parses a comma separated environment value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// uniqueStrings returns its arguments without duplicates, keeping their order.
+func uniqueStrings(values ...string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// inDomains reports whether host is one of domains or a subdomain of one.
+func inDomains(host string, domains []string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, d := range domains {
+		d = strings.ToLower(d)
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePublicBaseURL validates a PUBLIC_BASE_URL and returns it without a
+// trailing slash, along with its host.
+func parsePublicBaseURL(v string) (string, string, error) {
+	u, err := url.Parse(v)
+	if err != nil {
+		return "", "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", "", fmt.Errorf("%w: base URL must be an http or https URL without query", ErrInvalidURL)
+	}
+	return strings.TrimSuffix(u.String(), "/"), strings.ToLower(u.Hostname()), nil
+}
+
+// publicBaseURL returns the URL short links are served under: PublicBaseURL,
+// or https on the first of PublicHosts.
+func (c Config) publicBaseURL() (string, error) {
+	if c.PublicBaseURL != "" {
+		return c.PublicBaseURL, nil
+	}
+	if len(c.PublicHosts) == 0 {
+		return "", ErrNoPublicHost
+	}
+	return "https://" + c.PublicHosts[0], nil
+}
+
+// shortLinkURL returns the public URL of a short link.
+func (c Config) shortLinkURL(shortURL string) (string, error) {
+	base, err := c.publicBaseURL()
+	if err != nil {
+		return "", err
+	}
+	return base + "/d/" + shortURL, nil
+}
+
+// qrCodeURL returns the public URL of the QR code of a short link, naming
+// its branded domain if it has one.
+func (c Config) qrCodeURL(mapping *dataModel.URLMapping) (string, error) {
+	base, err := c.publicBaseURL()
+	if err != nil {
+		return "", err
+	}
+	if mapping.Host != "" {
+		return base + "/qr/" + mapping.ShortURLID + "?domain=" + url.QueryEscape(mapping.Host), nil
+	}
+	return base + "/qr/" + mapping.ShortURLID, nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+)
+
+// utmParamNames lists the UTM query parameters in the order they are added
+// to destinations.
+var utmParamNames = []string{"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content"}
+
+// utmFields returns the fields of utm in the order of utmParamNames.
+func utmFields(utm *dataModel.UTMParams) []*string {
+	return []*string{&utm.Source, &utm.Medium, &utm.Campaign, &utm.Term, &utm.Content}
+}
+
+// utmFromProto validates UTM parameters sent by a client. Values are
+// trimmed, and sources and mediums lowercased since analytics tell "Email"
+// and "email" apart.
+func utmFromProto(p *proto.UTMParams) (dataModel.UTMParams, error) {
+	var utm dataModel.UTMParams
+	if p == nil {
+		return utm, nil
+	}
+	utm = dataModel.UTMParams{
+		Source:   strings.ToLower(strings.TrimSpace(p.Source)),
+		Medium:   strings.ToLower(strings.TrimSpace(p.Medium)),
+		Campaign: strings.TrimSpace(p.Campaign),
+		Term:     strings.TrimSpace(p.Term),
+		Content:  strings.TrimSpace(p.Content),
+	}
+	for i, v := range utmFields(&utm) {
+		if utf8.RuneCountInString(*v) > MaxUTMValueLength {
+			return utm, fmt.Errorf("%w: %s is longer than %d characters", ErrInvalidUTM, utmParamNames[i], MaxUTMValueLength)
+		}
+		if !utf8.ValidString(*v) || strings.ContainsFunc(*v, unicode.IsControl) {
+			return utm, fmt.Errorf("%w: %s has control characters", ErrInvalidUTM, utmParamNames[i])
+		}
+	}
+	return utm, nil
+}
+
+// utmToProto returns nil when utm is empty.
+func utmToProto(utm dataModel.UTMParams) *proto.UTMParams {
+	if utm == (dataModel.UTMParams{}) {
+		return nil
+	}
+	return &proto.UTMParams{
+		Source:   utm.Source,
+		Medium:   utm.Medium,
+		Campaign: utm.Campaign,
+		Term:     utm.Term,
+		Content:  utm.Content,
+	}
+}
+
+// parseUTM reads the UTM parameters of a destination.
+func parseUTM(rawURL string) dataModel.UTMParams {
+	var utm dataModel.UTMParams
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return utm
+	}
+	query := u.Query()
+	for i, v := range utmFields(&utm) {
+		*v = query.Get(utmParamNames[i])
+	}
+	return utm
+}
+
+// addUTM sets the parameters of utm on rawURL, replacing those it has, and
+// those of defaults it has neither in utm nor itself. Its other parameters
+// keep their order.
+func addUTM(rawURL string, utm, defaults dataModel.UTMParams) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	existing := u.Query()
+	set := make(map[string]string)
+	explicit, inherited := utmFields(&utm), utmFields(&defaults)
+	for i, name := range utmParamNames {
+		switch {
+		case *explicit[i] != "":
+			set[name] = *explicit[i]
+		case *inherited[i] != "" && existing.Get(name) == "":
+			set[name] = *inherited[i]
+		}
+	}
+	if len(set) == 0 {
+		return rawURL, nil
+	}
+
+	var params []string
+	for _, param := range strings.Split(u.RawQuery, "&") {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); param == "" || (err == nil && set[name] != "") {
+			continue
+		}
+		params = append(params, param)
+	}
+	for _, name := range utmParamNames {
+		if v := set[name]; v != "" {
+			params = append(params, name+"="+url.QueryEscape(v))
+		}
+	}
+	u.RawQuery = strings.Join(params, "&")
+	return u.String(), nil
+}
+
+// applyUTM adds the UTM parameters of req, and of the campaign it names, to
+// the normalized long URL. It returns the URL and the campaign, nil when
+// none was named.
+func (s *UrlShortenerService) applyUTM(user *dataModel.User, req *proto.ShortenURLRequest, longURL string) (string, *dataModel.LinkGroup, error) {
+	utm, err := utmFromProto(req.Utm)
+	if err != nil {
+		return "", nil, err
+	}
+	var campaign *dataModel.LinkGroup
+	var defaults dataModel.UTMParams
+	if req.CampaignId != 0 {
+		if campaign, err = s.userLinkGroup(user, req.CampaignId); err != nil {
+			return "", nil, err
+		}
+		if campaign.Kind != dataModel.GroupKindCampaign {
+			return "", nil, fmt.Errorf("%w: %d is a %s, not a campaign", ErrInvalidLinkGroup, campaign.ID, campaign.Kind)
+		}
+		defaults = campaign.UTM
+	}
+	if longURL, err = addUTM(longURL, utm, defaults); err != nil {
+		return "", nil, err
+	}
+	// The parameters may make it too long.
+	if longURL, err = s.Config.normalizeURL(longURL); err != nil {
+		return "", nil, err
+	}
+	return longURL, campaign, nil
+}
+
+// checkUTMRules rejects destinations lacking UTM parameters the rules of
+// user require for their domain.
+func (s *UrlShortenerService) checkUTMRules(user *dataModel.User, destination string) error {
+	if !user.Workspace.HasUTMRules {
+		return nil
+	}
+	rules, err := s.db.ListUTMRules(user.Workspace.ID)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	u, err := url.Parse(destination)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	query := u.Query()
+	for _, rule := range rules {
+		if !inDomains(u.Hostname(), []string{rule.Domain}) {
+			continue
+		}
+		var missing []string
+		for _, name := range rule.RequiredParams() {
+			if query.Get(name) == "" {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%w: links to %s need %s", ErrMissingUTM, rule.Domain, strings.Join(missing, ", "))
+		}
+	}
+	return nil
+}
+
+// SetCampaignUTM sets the UTM parameters links created in a campaign of the
+// caller inherit.
+func (s *UrlShortenerService) SetCampaignUTM(ctx context.Context, req *proto.SetCampaignUTMRequest) (*proto.SetCampaignUTMResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	group, err := s.userLinkGroup(user, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if group.Kind != dataModel.GroupKindCampaign {
+		return nil, fmt.Errorf("%w: only campaigns have UTM parameters", ErrInvalidLinkGroup)
+	}
+	utm, err := utmFromProto(req.Utm)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.SetCampaignUTM(group, utm); err != nil {
+		log.Printf("Error setting UTM parameters of campaign %d: %v", group.ID, err)
+		return nil, err
+	}
+	return &proto.SetCampaignUTMResponse{Group: linkGroupToProto(group)}, nil
+}
+
+// SetUTMRules replaces the UTM rules of the caller.
+func (s *UrlShortenerService) SetUTMRules(ctx context.Context, req *proto.SetUTMRulesRequest) (*proto.SetUTMRulesResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Rules) > MaxUTMRules {
+		return nil, fmt.Errorf("%w: at most %d rules", ErrInvalidUTMRule, MaxUTMRules)
+	}
+	rules := make([]dataModel.UTMRule, 0, len(req.Rules))
+	for _, r := range req.Rules {
+		rule, err := utmRuleFromProto(r)
+		if err != nil {
+			return nil, err
+		}
+		rule.UserID = user.ID
+		rules = append(rules, rule)
+	}
+	if err := s.db.ReplaceUTMRules(user.Workspace.ID, rules); err != nil {
+		log.Printf("Error setting UTM rules of workspace %d: %v", user.Workspace.ID, err)
+		return nil, err
+	}
+	resp := &proto.SetUTMRulesResponse{}
+	for i := range rules {
+		resp.Rules = append(resp.Rules, utmRuleToProto(&rules[i]))
+	}
+	return resp, nil
+}
+
+// ListUTMRules returns the UTM rules of the caller.
+func (s *UrlShortenerService) ListUTMRules(ctx context.Context, req *proto.ListUTMRulesRequest) (*proto.ListUTMRulesResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.db.ListUTMRules(user.Workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListUTMRulesResponse{}
+	for i := range rules {
+		resp.Rules = append(resp.Rules, utmRuleToProto(&rules[i]))
+	}
+	return resp, nil
+}
+
+// GetUTMStats counts the links and clicks of the caller, or of one of their
+// campaigns, by UTM source and medium. Clicks are counted over the analytics
+// retention of the caller's plan.
+func (s *UrlShortenerService) GetUTMStats(ctx context.Context, req *proto.GetUTMStatsRequest) (*proto.GetUTMStatsResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	if req.CampaignId != 0 {
+		if _, err := s.userLinkGroup(user, req.CampaignId); err != nil {
+			return nil, err
+		}
+	}
+	stats, err := s.db.GetUTMStats(user.Workspace.ID, uint(req.CampaignId), analyticsSince(user, time.Now()))
+	if err != nil {
+		log.Printf("Error counting links of workspace %d by UTM parameters: %v", user.Workspace.ID, err)
+		return nil, err
+	}
+	resp := &proto.GetUTMStatsResponse{}
+	for _, st := range stats {
+		resp.Stats = append(resp.Stats, &proto.UTMStat{Source: st.Source, Medium: st.Medium, Links: st.Links, Clicks: st.Clicks})
+	}
+	return resp, nil
+}
+
+// utmRuleFromProto validates a UTM rule sent by a client. Parameter names
+// may leave out the utm_ prefix.
+func utmRuleFromProto(r *proto.UTMRule) (dataModel.UTMRule, error) {
+	domain, err := normalizeHost(strings.TrimSpace(r.Domain))
+	if err != nil || !strings.Contains(domain, ".") {
+		return dataModel.UTMRule{}, fmt.Errorf("%w: %q is not a domain", ErrInvalidUTMRule, r.Domain)
+	}
+	var required []string
+	for _, name := range r.Required {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !strings.HasPrefix(name, "utm_") {
+			name = "utm_" + name
+		}
+		if !slices.Contains(utmParamNames, name) {
+			return dataModel.UTMRule{}, fmt.Errorf("%w: %q is not one of %s", ErrInvalidUTMRule, name, strings.Join(utmParamNames, ", "))
+		}
+		if !slices.Contains(required, name) {
+			required = append(required, name)
+		}
+	}
+	if len(required) == 0 {
+		return dataModel.UTMRule{}, fmt.Errorf("%w: rule for %s requires no parameters", ErrInvalidUTMRule, domain)
+	}
+	return dataModel.UTMRule{Domain: domain, Required: strings.Join(required, ",")}, nil
+}
+
+func utmRuleToProto(r *dataModel.UTMRule) *proto.UTMRule {
+	return &proto.UTMRule{Domain: r.Domain, Required: r.RequiredParams()}
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestAddUTM(t *testing.T) {
+	campaign := dataModel.UTMParams{Source: "newsletter", Medium: "email", Campaign: "spring"}
+	tests := []struct {
+		name     string
+		url      string
+		utm      dataModel.UTMParams
+		defaults dataModel.UTMParams
+		want     string
+	}{
+		{"Nothing to add", "https://example.com/a?b=1", dataModel.UTMParams{}, dataModel.UTMParams{}, "https://example.com/a?b=1"},
+		{"Appended in order", "https://example.com/a?b=1", dataModel.UTMParams{Medium: "social", Source: "twitter"}, dataModel.UTMParams{},
+			"https://example.com/a?b=1&utm_source=twitter&utm_medium=social"},
+		{"Escaped", "https://example.com/", dataModel.UTMParams{Campaign: "spring sale & more"}, dataModel.UTMParams{},
+			"https://example.com/?utm_campaign=spring+sale+%26+more"},
+		{"Explicit replaces URL", "https://example.com/?utm_source=old&b=1", dataModel.UTMParams{Source: "new"}, dataModel.UTMParams{},
+			"https://example.com/?b=1&utm_source=new"},
+		{"URL beats campaign", "https://example.com/?utm_source=blog", dataModel.UTMParams{}, campaign,
+			"https://example.com/?utm_source=blog&utm_medium=email&utm_campaign=spring"},
+		{"Explicit beats campaign", "https://example.com/", dataModel.UTMParams{Campaign: "summer"}, campaign,
+			"https://example.com/?utm_source=newsletter&utm_medium=email&utm_campaign=summer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := addUTM(tt.url, tt.utm, tt.defaults)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUTMFromProto(t *testing.T) {
+	utm, err := utmFromProto(&proto.UTMParams{Source: " Newsletter ", Medium: "EMAIL", Campaign: "Spring Sale"})
+	require.NoError(t, err)
+	assert.Equal(t, dataModel.UTMParams{Source: "newsletter", Medium: "email", Campaign: "Spring Sale"}, utm)
+
+	_, err = utmFromProto(&proto.UTMParams{Term: strings.Repeat("a", MaxUTMValueLength+1)})
+	assert.ErrorIs(t, err, ErrInvalidUTM)
+	_, err = utmFromProto(&proto.UTMParams{Content: "a\nb"})
+	assert.ErrorIs(t, err, ErrInvalidUTM)
+}
+
+func TestShortenURLWithUTM(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	campaign := &dataModel.LinkGroup{
+		Model: gorm.Model{ID: 5}, UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindCampaign, Name: "Spring",
+		UTM: dataModel.UTMParams{Source: "newsletter", Medium: "email", Campaign: "spring"},
+	}
+	requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return 12345, nil }
+	t.Cleanup(func() {
+		requestCounterFunc = func(s *UrlShortenerService) (int64, error) { return s.requestCounter() }
+	})
+
+	t.Run("Campaign defaults", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetLinkGroup", uint(1), uint(5)).Return(campaign, nil).Once()
+		var created *dataModel.URLMapping
+		mockDb.On("CreateURLMapping", mock.Anything).Run(func(args mock.Arguments) {
+			created = args.Get(0).(*dataModel.URLMapping)
+		}).Return(nil).Once()
+
+		resp, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{
+			ApiKey: "key", LongUrl: "https://example.com/sale?utm_medium=banner", CampaignId: 5,
+			Utm: &proto.UTMParams{Content: "hero"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/sale?utm_medium=banner&utm_source=newsletter&utm_campaign=spring&utm_content=hero", resp.LongUrl)
+		assert.Equal(t, &proto.UTMParams{Source: "newsletter", Medium: "banner", Campaign: "spring", Content: "hero"}, resp.Utm)
+		require.NotNil(t, created)
+		assert.Equal(t, uint(5), created.CampaignID)
+		assert.Equal(t, "newsletter", created.UTMSource)
+		assert.Equal(t, "banner", created.UTMMedium)
+		mockDb.AssertNotCalled(t, "GetURLMappingByLongURL", mock.Anything, mock.Anything)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Not a campaign", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetLinkGroup", uint(1), uint(3)).Return(&dataModel.LinkGroup{
+			Model: gorm.Model{ID: 3}, UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindTag, Name: "q3",
+		}, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "https://example.com/", CampaignId: 3})
+		assert.ErrorIs(t, err, ErrInvalidLinkGroup)
+	})
+
+	ruled := testUser(2)
+	ruled.Workspace.HasUTMRules = true
+	rules := []dataModel.UTMRule{{UserID: 2, WorkspaceID: 2, Domain: "shop.example", Required: "utm_source,utm_medium"}}
+
+	t.Run("Missing required parameters", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(ruled, nil).Once()
+		mockDb.On("ListUTMRules", uint(2)).Return(rules, nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{
+			ApiKey: "key", LongUrl: "https://www.shop.example/item", Utm: &proto.UTMParams{Source: "ads"},
+		})
+		assert.ErrorIs(t, err, ErrMissingUTM)
+		assert.ErrorContains(t, err, "utm_medium")
+		mockDb.AssertNotCalled(t, "CreateURLMapping", mock.Anything)
+	})
+
+	t.Run("Required parameters present", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(ruled, nil).Once()
+		mockDb.On("ListUTMRules", uint(2)).Return(rules, nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+		mockDb.On("CreateURLMapping", mock.Anything).Return(nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{
+			ApiKey: "key", LongUrl: "https://shop.example/item?utm_source=ads&utm_medium=cpc",
+		})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Other domains", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(ruled, nil).Once()
+		mockDb.On("ListUTMRules", uint(2)).Return(rules, nil).Once()
+		mockDb.On("GetURLMappingByLongURL", mock.AnythingOfType("uint"), mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+		mockDb.On("CreateURLMapping", mock.Anything).Return(nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "https://notshop.example/item"})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+}
+
+func TestSetUTMRules(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+
+	t.Run("Normalized", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("ReplaceUTMRules", uint(1), []dataModel.UTMRule{
+			{UserID: 1, Domain: "shop.example", Required: "utm_source,utm_medium"},
+		}).Return(nil).Once()
+
+		resp, err := s.SetUTMRules(ctx, &proto.SetUTMRulesRequest{ApiKey: "key", Rules: []*proto.UTMRule{
+			{Domain: "Shop.Example.", Required: []string{"source", "UTM_MEDIUM", "utm_source"}},
+		}})
+		require.NoError(t, err)
+		require.Len(t, resp.Rules, 1)
+		assert.Equal(t, []string{"utm_source", "utm_medium"}, resp.Rules[0].Required)
+		mockDb.AssertExpectations(t)
+	})
+
+	for name, rule := range map[string]*proto.UTMRule{
+		"Unknown parameter": {Domain: "shop.example", Required: []string{"utm_id"}},
+		"Nothing required":  {Domain: "shop.example"},
+		"Not a domain":      {Domain: "localhost", Required: []string{"source"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb}
+			mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+
+			_, err := s.SetUTMRules(ctx, &proto.SetUTMRulesRequest{ApiKey: "key", Rules: []*proto.UTMRule{rule}})
+			assert.ErrorIs(t, err, ErrInvalidUTMRule)
+			mockDb.AssertNotCalled(t, "ReplaceUTMRules", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSetCampaignUTM(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(user, nil)
+	mockDb.On("GetLinkGroup", uint(1), uint(5)).Return(&dataModel.LinkGroup{
+		Model: gorm.Model{ID: 5}, UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindCampaign, Name: "Spring",
+	}, nil).Once()
+	mockDb.On("GetLinkGroup", uint(1), uint(6)).Return(&dataModel.LinkGroup{
+		Model: gorm.Model{ID: 6}, UserID: 1, WorkspaceID: 1, Kind: dataModel.GroupKindFolder, Name: "Docs",
+	}, nil).Once()
+	mockDb.On("SetCampaignUTM", mock.Anything, dataModel.UTMParams{Source: "newsletter", Medium: "email"}).Return(nil).Once()
+
+	resp, err := s.SetCampaignUTM(ctx, &proto.SetCampaignUTMRequest{
+		ApiKey: "key", Id: 5, Utm: &proto.UTMParams{Source: "Newsletter", Medium: "email"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &proto.UTMParams{Source: "newsletter", Medium: "email"}, resp.Group.Utm)
+
+	_, err = s.SetCampaignUTM(ctx, &proto.SetCampaignUTMRequest{ApiKey: "key", Id: 6, Utm: &proto.UTMParams{Source: "x"}})
+	assert.ErrorIs(t, err, ErrInvalidLinkGroup)
+	mockDb.AssertExpectations(t)
+}
+
+func TestGetUTMStats(t *testing.T) {
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil).Once()
+	mockDb.On("GetUTMStats", uint(1), uint(0), mock.AnythingOfType("time.Time")).Return([]dataModel.UTMStats{
+		{Source: "newsletter", Medium: "email", Links: 3, Clicks: 120},
+		{Links: 10, Clicks: 40},
+	}, nil).Once()
+
+	resp, err := s.GetUTMStats(context.Background(), &proto.GetUTMStatsRequest{ApiKey: "key"})
+	require.NoError(t, err)
+	require.Len(t, resp.Stats, 2)
+	assert.Equal(t, "newsletter", resp.Stats[0].Source)
+	assert.Equal(t, int64(120), resp.Stats[0].Clicks)
+	assert.Equal(t, int64(10), resp.Stats[1].Links)
+	mockDb.AssertExpectations(t)
+}
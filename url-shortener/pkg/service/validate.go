@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// defaultPorts maps schemes to the port that is implied when none is given.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ftp":   "21",
+}
+
+// normalizeURL validates a destination URL against the configured policy and
+// returns its canonical form, so that equivalent URLs map to one string:
+//   - the scheme must be one of AllowedSchemes and a host must be present
+//   - scheme and host are lowercased, IDN hosts converted to punycode and a
+//     trailing dot dropped
+//   - the scheme's default port is stripped and an empty path becomes "/"
+//   - the fragment is dropped unless KeepFragments is set
+//
+// URLs carrying credentials are rejected as they are a common phishing trick.
+func (c Config) normalizeURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	maxLength := c.MaxURLLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxURLLength
+	}
+	if raw == "" {
+		return "", fmt.Errorf("%w: empty URL", ErrInvalidURL)
+	}
+	if len(raw) > maxLength {
+		return "", fmt.Errorf("%w: longer than %d characters", ErrInvalidURL, maxLength)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if !c.isAllowedScheme(u.Scheme) {
+		return "", fmt.Errorf("%w: scheme %q is not allowed", ErrInvalidURL, u.Scheme)
+	}
+	if u.Opaque != "" || u.Host == "" {
+		return "", fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+	if u.User != nil {
+		return "", fmt.Errorf("%w: credentials are not allowed", ErrInvalidURL)
+	}
+
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return "", err
+	}
+	port := u.Port()
+	if port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", fmt.Errorf("%w: invalid port %q", ErrInvalidURL, port)
+		}
+		if port == defaultPorts[u.Scheme] {
+			port = ""
+		}
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+
+	if u.Path == "" {
+		u.Path = "/"
+		u.RawPath = ""
+	}
+	if !c.KeepFragments {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+
+	normalized := u.String()
+	if len(normalized) > maxLength {
+		return "", fmt.Errorf("%w: longer than %d characters", ErrInvalidURL, maxLength)
+	}
+	return normalized, nil
+}
+
+// isAllowedScheme reports whether scheme may be shortened. Only http and https
+// are allowed unless Config.AllowedSchemes says otherwise.
+func (c Config) isAllowedScheme(scheme string) bool {
+	allowed := c.AllowedSchemes
+	if len(allowed) == 0 {
+		allowed = DefaultAllowedSchemes
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(a, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeHost lowercases a hostname and converts internationalized names to
+// their ASCII (punycode) form. IP literals are returned in canonical notation.
+func normalizeHost(host string) (string, error) {
+	host = strings.TrimSuffix(host, ".")
+	if host == "" {
+		return "", fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid host %q: %v", ErrInvalidURL, host, err)
+	}
+	return strings.ToLower(ascii), nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// SetLinkVariants replaces the variants a link of the caller splits its
+// traffic across. Their destinations are held to the checks of long URLs,
+// here and again when visitors are sent to them.
+func (s *UrlShortenerService) SetLinkVariants(ctx context.Context, req *proto.SetLinkVariantsRequest) (*proto.SetLinkVariantsResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.userLink(user, req.Domain, req.ShortUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	stickiness := req.Stickiness
+	if stickiness == "" {
+		stickiness = VariantStickinessCookie
+	}
+	if stickiness != VariantStickinessCookie && stickiness != VariantStickinessIP {
+		return nil, fmt.Errorf("%w: stickiness must be %q or %q", ErrInvalidVariants, VariantStickinessCookie, VariantStickinessIP)
+	}
+	if len(req.Variants) == 1 || len(req.Variants) > MaxLinkVariants {
+		return nil, fmt.Errorf("%w: a link splits across 2 to %d variants", ErrInvalidVariants, MaxLinkVariants)
+	}
+	variants := make([]dataModel.LinkVariant, 0, len(req.Variants))
+	seen := make(map[string]bool, len(req.Variants))
+	for _, v := range req.Variants {
+		if v.Weight < 1 || v.Weight > MaxVariantWeight {
+			return nil, fmt.Errorf("%w: weights must be between 1 and %d", ErrInvalidVariants, MaxVariantWeight)
+		}
+		destination, err := s.normalizeDestination(ctx, v.Destination)
+		if err != nil {
+			return nil, err
+		}
+		if seen[destination] {
+			return nil, fmt.Errorf("%w: %s is given twice", ErrInvalidVariants, destination)
+		}
+		seen[destination] = true
+		variants = append(variants, dataModel.LinkVariant{Destination: destination, Weight: int(v.Weight)})
+	}
+
+	if err := s.db.ReplaceLinkVariants(mapping.ID, stickiness, variants); err != nil {
+		log.Printf("Error setting variants of %s: %v", mapping.ShortURLID, err)
+		return nil, err
+	}
+	s.emitWebhook(user.Workspace.ID, EventLinkUpdated, newLinkEvent(mapping))
+	resp := &proto.SetLinkVariantsResponse{}
+	for i := range variants {
+		resp.Variants = append(resp.Variants, variantToProto(&variants[i]))
+	}
+	return resp, nil
+}
+
+// RecordConversion counts a conversion of a variant of a link of the caller.
+func (s *UrlShortenerService) RecordConversion(ctx context.Context, req *proto.RecordConversionRequest) (*proto.RecordConversionResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.userLink(user, req.Domain, req.ShortUrl)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.RecordVariantConversion(mapping.ID, uint(req.VariantId)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVariantNotFound
+		}
+		return nil, err
+	}
+	return &proto.RecordConversionResponse{}, nil
+}
+
+// GetLinkStats returns the clicks and groups of a link of the caller, and
+// the clicks and conversions of its variants.
+func (s *UrlShortenerService) GetLinkStats(ctx context.Context, req *proto.GetLinkStatsRequest) (*proto.GetLinkStatsResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.userLink(user, req.Domain, req.ShortUrl)
+	if err != nil {
+		return nil, err
+	}
+	retained := []dataModel.URLMapping{*mapping}
+	if err := s.retainClicks(user, retained); err != nil {
+		return nil, err
+	}
+	mapping = &retained[0]
+	resp := &proto.GetLinkStatsResponse{
+		ShortUrl:        displayShortURL(mapping),
+		LongUrl:         mapping.LongURL,
+		CreatedAt:       timestamppb.New(mapping.CreatedAt),
+		ClicksRemaining: mapping.ClicksRemaining,
+		Clicks:          mapping.Clicks,
+		Utm:             utmToProto(parseUTM(mapping.LongURL)),
+		Og:              openGraphToProto(mapping.OG),
+		Health:          linkHealthToProto(mapping.Health),
+	}
+	groups, err := s.mappingGroups(user, []dataModel.URLMapping{*mapping})
+	if err != nil {
+		return nil, err
+	}
+	resp.Campaign, resp.Folder, resp.Tags = groups[0].campaign, groups[0].folder, groups[0].tags
+	if !mapping.HasVariants {
+		return resp, nil
+	}
+	variants, err := s.db.ListLinkVariants(mapping.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp.Stickiness = mapping.VariantStickiness
+	for i := range variants {
+		resp.Variants = append(resp.Variants, variantToProto(&variants[i]))
+	}
+	return resp, nil
+}
+
+func variantToProto(v *dataModel.LinkVariant) *proto.LinkVariant {
+	p := &proto.LinkVariant{
+		Id:          uint64(v.ID),
+		Destination: v.Destination,
+		Weight:      int32(v.Weight),
+		Clicks:      v.Clicks,
+		Conversions: v.Conversions,
+	}
+	if v.Clicks > 0 {
+		p.ConversionRate = float64(v.Conversions) / float64(v.Clicks)
+	}
+	return p
+}
+
+// pickVariant returns the variant of mapping the visitor of r is sent to,
+// or nil when it has none. Visitors keep their variant through a cookie, or
+// a hash of their address with IP stickiness.
+func (s *UrlShortenerService) pickVariant(w http.ResponseWriter, r *http.Request, mapping *dataModel.URLMapping) (*dataModel.LinkVariant, error) {
+	variants, err := s.db.ListLinkVariants(mapping.ID)
+	if err != nil || len(variants) == 0 {
+		return nil, err
+	}
+	if mapping.VariantStickiness == VariantStickinessIP {
+		h := fnv.New64a()
+		fmt.Fprintf(h, "%d/%s", mapping.ID, s.Config.httpClientAddr(r))
+		return weightedVariant(variants, h.Sum64()), nil
+	}
+
+	name := variantCookieName(mapping.ShortURLID)
+	if cookie, err := r.Cookie(name); err == nil {
+		for i := range variants {
+			if strconv.FormatUint(uint64(variants[i].ID), 10) == cookie.Value {
+				return &variants[i], nil
+			}
+		}
+	}
+	variant := weightedVariant(variants, rand.Uint64())
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    strconv.FormatUint(uint64(variant.ID), 10),
+		Path:     "/",
+		MaxAge:   int(VariantCookieTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return variant, nil
+}
+
+// weightedVariant maps n onto variants, each taking a share of the range
+// by its weight.
+func weightedVariant(variants []dataModel.LinkVariant, n uint64) *dataModel.LinkVariant {
+	var total uint64
+	for _, v := range variants {
+		total += uint64(v.Weight)
+	}
+	if total == 0 {
+		return &variants[0]
+	}
+	n %= total
+	for i := range variants {
+		if n < uint64(variants[i].Weight) {
+			return &variants[i]
+		}
+		n -= uint64(variants[i].Weight)
+	}
+	return &variants[len(variants)-1]
+}
+
+func variantCookieName(shortURL string) string {
+	return "variant_" + shortURL
+}
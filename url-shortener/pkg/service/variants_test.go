@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSetLinkVariants(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+	mapping := &dataModel.URLMapping{Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1}
+	set := func(s *UrlShortenerService, stickiness string, variants ...*proto.LinkVariant) (*proto.SetLinkVariantsResponse, error) {
+		return s.SetLinkVariants(ctx, &proto.SetLinkVariantsRequest{
+			ApiKey: "key", ShortUrl: "abc", Variants: variants, Stickiness: stickiness,
+		})
+	}
+
+	t.Run("Split", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		mockDb.On("ReplaceLinkVariants", uint(7), VariantStickinessCookie, []dataModel.LinkVariant{
+			{Destination: "https://example.com/a", Weight: 70},
+			{Destination: "https://example.com/b", Weight: 30},
+		}).Return(nil).Once()
+
+		resp, err := set(s, "",
+			&proto.LinkVariant{Destination: "HTTPS://example.com/a", Weight: 70},
+			&proto.LinkVariant{Destination: "https://example.com/b", Weight: 30})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Variants, 2)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		mockDb.On("ReplaceLinkVariants", uint(7), VariantStickinessIP, []dataModel.LinkVariant{}).Return(nil).Once()
+
+		_, err := set(s, VariantStickinessIP)
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Checked like long URLs", func(t *testing.T) {
+		mockDb := new(MockDB)
+		mockDb.On("ListPolicyRules").Return([]dataModel.PolicyRule{{Kind: "domain", Pattern: "evil.com", Action: "block"}}, nil).Once()
+		guard, err := newNetGuard(Config{}, staticResolver("10.0.0.1"))
+		require.NoError(t, err)
+		s := &UrlShortenerService{db: mockDb, policy: newPolicyEngine(Config{}, mockDb, nil), netGuard: guard}
+		require.NoError(t, s.policy.Reload())
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Twice()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Twice()
+
+		_, err = set(s, "", &proto.LinkVariant{Destination: "https://93.184.215.14/a", Weight: 1},
+			&proto.LinkVariant{Destination: "https://evil.com/b", Weight: 1})
+		assert.ErrorIs(t, err, ErrBlockedDestination)
+		_, err = set(s, "", &proto.LinkVariant{Destination: "https://93.184.215.14/a", Weight: 1},
+			&proto.LinkVariant{Destination: "https://intranet.example/b", Weight: 1})
+		assert.ErrorIs(t, err, ErrInternalDestination)
+		mockDb.AssertNotCalled(t, "ReplaceLinkVariants", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	a := &proto.LinkVariant{Destination: "https://example.com/a", Weight: 1}
+	b := &proto.LinkVariant{Destination: "https://example.com/b", Weight: 1}
+	for name, tt := range map[string]struct {
+		stickiness string
+		variants   []*proto.LinkVariant
+	}{
+		"Single variant":     {"", []*proto.LinkVariant{a}},
+		"Zero weight":        {"", []*proto.LinkVariant{a, {Destination: "https://example.com/b"}}},
+		"Duplicate":          {"", []*proto.LinkVariant{a, {Destination: "https://EXAMPLE.com/a", Weight: 2}}},
+		"Unknown stickiness": {"session", []*proto.LinkVariant{a, b}},
+		"Invalid URL":        {"", []*proto.LinkVariant{a, {Destination: "javascript:alert(1)", Weight: 1}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb}
+			mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+			mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+
+			_, err := set(s, tt.stickiness, tt.variants...)
+			assert.Error(t, err)
+			mockDb.AssertNotCalled(t, "ReplaceLinkVariants", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWeightedVariant(t *testing.T) {
+	variants := []dataModel.LinkVariant{
+		{Model: gorm.Model{ID: 1}, Weight: 70},
+		{Model: gorm.Model{ID: 2}, Weight: 30},
+	}
+	counts := map[uint]int{}
+	for n := uint64(0); n < 1000; n++ {
+		counts[weightedVariant(variants, n).ID]++
+	}
+	assert.Equal(t, map[uint]int{1: 700, 2: 300}, counts)
+}
+
+func TestVariantRedirect(t *testing.T) {
+	variants := []dataModel.LinkVariant{
+		{Model: gorm.Model{ID: 11}, URLMappingID: 7, Destination: "https://example.com/a", Weight: 1},
+		{Model: gorm.Model{ID: 12}, URLMappingID: 7, Destination: "https://example.com/b", Weight: 1},
+	}
+	follow := func(s *UrlShortenerService, stickiness string, prepare func(*http.Request)) *httptest.ResponseRecorder {
+		mockDb := s.db.(*MockDB)
+		mapping := &dataModel.URLMapping{
+			Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1, LongURL: "https://example.com/",
+			HasVariants: true, VariantStickiness: stickiness,
+		}
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		mockDb.On("ListLinkVariants", uint(7)).Return(variants, nil).Once()
+
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/d/abc", nil), map[string]string{"shortChar": "abc"})
+		prepare(req)
+		rr := httptest.NewRecorder()
+		s.redirectHandler(rr, req)
+		return rr
+	}
+
+	t.Run("Cookie", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("RecordVariantClick", mock.Anything).Return(nil)
+		watcher := s.clicks.watch(1, nil)
+		defer s.clicks.stop(1, watcher)
+
+		first := follow(s, VariantStickinessCookie, func(*http.Request) {})
+		assert.Equal(t, http.StatusFound, first.Code)
+		assert.Equal(t, "no-store", first.Header().Get("Cache-Control"))
+		cookies := first.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, "variant_abc", cookies[0].Name)
+		clicked := (<-watcher.events).VariantID
+		assert.Contains(t, []uint{11, 12}, clicked)
+		mockDb.AssertCalled(t, "RecordVariantClick", clicked)
+
+		for i := 0; i < 5; i++ {
+			again := follow(s, VariantStickinessCookie, func(r *http.Request) { r.AddCookie(cookies[0]) })
+			assert.Equal(t, first.Header().Get("Location"), again.Header().Get("Location"))
+			assert.Empty(t, again.Result().Cookies())
+			assert.Equal(t, clicked, (<-watcher.events).VariantID)
+		}
+	})
+
+	t.Run("Stale cookie", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("RecordVariantClick", mock.Anything).Return(nil)
+
+		rr := follow(s, VariantStickinessCookie, func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: "variant_abc", Value: "99"})
+		})
+		assert.Contains(t, []string{"https://example.com/a", "https://example.com/b"}, rr.Header().Get("Location"))
+		assert.Len(t, rr.Result().Cookies(), 1)
+	})
+
+	t.Run("IP", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("RecordVariantClick", mock.Anything).Return(nil)
+
+		seen := map[string]bool{}
+		for i := 0; i < 5; i++ {
+			rr := follow(s, VariantStickinessIP, func(r *http.Request) { r.RemoteAddr = "81.2.69.1:4000" })
+			assert.Empty(t, rr.Result().Cookies())
+			seen[rr.Header().Get("Location")] = true
+		}
+		assert.Len(t, seen, 1)
+	})
+
+	t.Run("Blocked since saved", func(t *testing.T) {
+		mockDb := new(MockDB)
+		mockDb.On("ListPolicyRules").Return([]dataModel.PolicyRule{
+			{Kind: "prefix", Pattern: "https://example.com/a", Action: "block"},
+			{Kind: "prefix", Pattern: "https://example.com/b", Action: "block"},
+		}, nil).Once()
+		s := &UrlShortenerService{db: mockDb, policy: newPolicyEngine(Config{}, mockDb, nil)}
+		require.NoError(t, s.policy.Reload())
+
+		rr := follow(s, VariantStickinessIP, func(*http.Request) {})
+		assert.Equal(t, http.StatusGone, rr.Code)
+		assert.Empty(t, rr.Header().Get("Location"))
+		mockDb.AssertNotCalled(t, "RecordVariantClick", mock.Anything)
+	})
+}
+
+func TestGetLinkStats(t *testing.T) {
+	user := testUser(1)
+	mapping := &dataModel.URLMapping{
+		Model: gorm.Model{ID: 7}, ShortURLID: "abc", UserID: 1, WorkspaceID: 1, LongURL: "https://example.com/",
+		HasVariants: true, VariantStickiness: VariantStickinessCookie,
+	}
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(user, nil)
+	mockDb.On("GetURLMapping", "abc").Return(mapping, nil)
+	mockDb.On("ListLinkVariants", uint(7)).Return([]dataModel.LinkVariant{
+		{Model: gorm.Model{ID: 11}, Destination: "https://example.com/a", Weight: 70, Clicks: 200, Conversions: 50},
+		{Model: gorm.Model{ID: 12}, Destination: "https://example.com/b", Weight: 30},
+	}, nil).Once()
+	mockDb.On("GetLinkClicks", []uint{7}, mock.AnythingOfType("time.Time")).Return(map[uint]int64{7: 230}, nil).Once()
+	mockDb.On("ListLinkTags", []uint{7}).Return([]dataModel.LinkTag{}, nil).Once()
+
+	resp, err := s.GetLinkStats(context.Background(), &proto.GetLinkStatsRequest{ApiKey: "key", ShortUrl: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(230), resp.Clicks)
+	assert.Equal(t, VariantStickinessCookie, resp.Stickiness)
+	require.Len(t, resp.Variants, 2)
+	assert.Equal(t, int64(50), resp.Variants[0].Conversions)
+	assert.InDelta(t, 0.25, resp.Variants[0].ConversionRate, 1e-9)
+	assert.Zero(t, resp.Variants[1].ConversionRate)
+
+	mockDb.On("RecordVariantConversion", uint(7), uint(13)).Return(gorm.ErrRecordNotFound).Once()
+	_, err = s.RecordConversion(context.Background(), &proto.RecordConversionRequest{ApiKey: "key", ShortUrl: "abc", VariantId: 13})
+	assert.ErrorIs(t, err, ErrVariantNotFound)
+}
@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/netguard"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/webhook"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// newWebhookDispatcher builds the webhook dispatcher from the config. With a
+// guard, deliveries only connect to addresses it allows, whatever the
+// subscription host resolves to by then.
+func newWebhookDispatcher(cfg Config, store webhook.Store, guard *netguard.Guard) *webhook.Dispatcher {
+	dispatcher := webhook.NewDispatcher(store)
+	if guard != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		// A proxy would be dialed instead of the subscriber.
+		transport.Proxy = nil
+		transport.DialContext = guard.DialContext(&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second})
+		dispatcher.Client.Transport = transport
+	}
+	if cfg.WebhookMaxAttempts > 0 {
+		dispatcher.MaxAttempts = cfg.WebhookMaxAttempts
+	}
+	if cfg.WebhookTimeout > 0 {
+		dispatcher.Client.Timeout = cfg.WebhookTimeout
+	}
+	return dispatcher
+}
+
+// CreateWebhook subscribes a URL to events of the caller's links. The URL
+// must be publicly reachable like link destinations.
+func (s *UrlShortenerService) CreateWebhook(ctx context.Context, req *proto.CreateWebhookRequest) (*proto.CreateWebhookResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return nil, fmt.Errorf("%w: url must be an http or https URL", ErrInvalidWebhook)
+	}
+	if err := s.checkDestinationHost(ctx, req.Url); err != nil {
+		return nil, err
+	}
+	if len(req.Events) == 0 {
+		return nil, fmt.Errorf("%w: no events", ErrInvalidWebhook)
+	}
+	var events []string
+	for _, event := range req.Events {
+		if !slices.Contains(WebhookEvents, event) {
+			return nil, fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	existing, err := s.db.ListWebhookSubscriptions(user.Workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxWebhooksPerWorkspace {
+		return nil, fmt.Errorf("%w: at most %d allowed", ErrTooManyWebhooks, MaxWebhooksPerWorkspace)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	sub := &dataModel.WebhookSubscription{
+		UserID:      user.ID,
+		WorkspaceID: user.Workspace.ID,
+		URL:         req.Url,
+		Events:      strings.Join(events, ","),
+		Secret:      "whsec_" + hex.EncodeToString(secret),
+	}
+	if err := s.db.CreateWebhookSubscription(sub); err != nil {
+		log.Printf("Error creating webhook for user %d: %v", user.ID, err)
+		return nil, err
+	}
+	s.webhookSubs.forget(user.Workspace.ID)
+	return &proto.CreateWebhookResponse{Webhook: webhookToProto(sub), Secret: sub.Secret}, nil
+}
+
+// ListWebhooks returns the webhook subscriptions of the caller.
+func (s *UrlShortenerService) ListWebhooks(ctx context.Context, req *proto.ListWebhooksRequest) (*proto.ListWebhooksResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := s.db.ListWebhookSubscriptions(user.Workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListWebhooksResponse{}
+	for i := range subs {
+		resp.Webhooks = append(resp.Webhooks, webhookToProto(&subs[i]))
+	}
+	return resp, nil
+}
+
+// DeleteWebhook removes a webhook subscription of the caller. Its pending
+// deliveries are canceled.
+func (s *UrlShortenerService) DeleteWebhook(ctx context.Context, req *proto.DeleteWebhookRequest) (*proto.DeleteWebhookResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.DeleteWebhookSubscription(user.Workspace.ID, uint(req.Id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	s.webhookSubs.forget(user.Workspace.ID)
+	return &proto.DeleteWebhookResponse{}, nil
+}
+
+// ListWebhookDeliveries returns the latest webhook deliveries of the caller.
+func (s *UrlShortenerService) ListWebhookDeliveries(ctx context.Context, req *proto.ListWebhookDeliveriesRequest) (*proto.ListWebhookDeliveriesResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := s.db.ListWebhookDeliveries(user.Workspace.ID, uint(req.WebhookId), req.Status, webhookListLimit(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListWebhookDeliveriesResponse{}
+	for _, d := range deliveries {
+		delivery := &proto.WebhookDelivery{
+			Id:             uint64(d.ID),
+			WebhookId:      uint64(d.SubscriptionID),
+			Event:          d.Event,
+			Payload:        d.Payload,
+			Status:         d.Status,
+			Attempts:       int32(d.Attempts),
+			LastStatusCode: int32(d.LastStatusCode),
+			LastError:      d.LastError,
+			CreatedAt:      timestamppb.New(d.CreatedAt),
+		}
+		if d.Status == dataModel.WebhookPending {
+			delivery.NextAttemptAt = timestamppb.New(d.NextAttemptAt)
+		}
+		if d.DeliveredAt != nil {
+			delivery.DeliveredAt = timestamppb.New(*d.DeliveredAt)
+		}
+		resp.Deliveries = append(resp.Deliveries, delivery)
+	}
+	return resp, nil
+}
+
+// ListWebhookDeadLetters returns the latest webhook deliveries of the caller
+// that failed all their attempts.
+func (s *UrlShortenerService) ListWebhookDeadLetters(ctx context.Context, req *proto.ListWebhookDeadLettersRequest) (*proto.ListWebhookDeadLettersResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	letters, err := s.db.ListWebhookDeadLetters(user.Workspace.ID, webhookListLimit(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListWebhookDeadLettersResponse{}
+	for _, l := range letters {
+		resp.DeadLetters = append(resp.DeadLetters, &proto.WebhookDelivery{
+			Id:             uint64(l.DeliveryID),
+			WebhookId:      uint64(l.SubscriptionID),
+			Event:          l.Event,
+			Payload:        l.Payload,
+			Status:         dataModel.WebhookDead,
+			Attempts:       int32(l.Attempts),
+			LastStatusCode: int32(l.LastStatusCode),
+			LastError:      l.LastError,
+			CreatedAt:      timestamppb.New(l.CreatedAt),
+		})
+	}
+	return resp, nil
+}
+
+func webhookListLimit(limit int32) int {
+	if limit <= 0 {
+		return DefaultWebhookListLimit
+	}
+	return min(int(limit), MaxWebhookListLimit)
+}
+
+func webhookToProto(sub *dataModel.WebhookSubscription) *proto.Webhook {
+	return &proto.Webhook{
+		Id:        uint64(sub.ID),
+		Url:       sub.URL,
+		Events:    sub.EventList(),
+		CreatedAt: timestamppb.New(sub.CreatedAt),
+	}
+}
+
+// webhookPayload is the JSON body of a webhook delivery.
+type webhookPayload struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       linkEvent `json:"data"`
+}
+
+// linkEvent describes the link an event happened to.
+type linkEvent struct {
+	ShortURL        string `json:"short_url"`
+	LongURL         string `json:"long_url"`
+	MaxClicks       int64  `json:"max_clicks,omitempty"`
+	ClicksRemaining int64  `json:"clicks_remaining,omitempty"`
+	// Set for clicks.
+	Referrer  string `json:"referrer,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	Country   string `json:"country,omitempty"`
+	Region    string `json:"region,omitempty"`
+	VariantID uint   `json:"variant_id,omitempty"`
+	// Set for broken links.
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func newLinkEvent(mapping *dataModel.URLMapping) linkEvent {
+	return linkEvent{
+		ShortURL:        mapping.ShortURLID,
+		LongURL:         mapping.LongURL,
+		MaxClicks:       mapping.MaxClicks,
+		ClicksRemaining: mapping.ClicksRemaining,
+	}
+}
+
+// emitLinksChanged queues a delivery of event, for each of mappings, to the
+// webhooks of the workspace owning it.
+func (s *UrlShortenerService) emitLinksChanged(event string, mappings []dataModel.URLMapping) {
+	byWorkspace := make(map[uint][]linkEvent)
+	for i := range mappings {
+		m := &mappings[i]
+		byWorkspace[m.WorkspaceID] = append(byWorkspace[m.WorkspaceID], newLinkEvent(m))
+	}
+	for workspaceID, data := range byWorkspace {
+		s.emitWebhook(workspaceID, event, data...)
+	}
+}
+
+// emitWebhook queues a delivery of event, for each of data, to the webhooks
+// of workspaceID subscribed to it. Failing to queue them is logged, it does
+// not fail the operation the event is about.
+func (s *UrlShortenerService) emitWebhook(workspaceID uint, event string, data ...linkEvent) {
+	if s.webhooks == nil || workspaceID == 0 || len(data) == 0 {
+		return
+	}
+	subs, err := s.webhookSubs.lookup(workspaceID, func() ([]dataModel.WebhookSubscription, error) {
+		return s.db.ListWebhookSubscriptions(workspaceID)
+	})
+	if err != nil {
+		log.Printf("Error looking up webhooks of workspace %d: %v", workspaceID, err)
+		return
+	}
+
+	now := time.Now()
+	var deliveries []*dataModel.WebhookDelivery
+	for _, sub := range subs {
+		if !sub.Subscribed(event) {
+			continue
+		}
+		for _, d := range data {
+			payload, err := json.Marshal(webhookPayload{Event: event, OccurredAt: now, Data: d})
+			if err != nil {
+				log.Printf("Error encoding %s webhook of %s: %v", event, d.ShortURL, err)
+				continue
+			}
+			deliveries = append(deliveries, &dataModel.WebhookDelivery{
+				SubscriptionID: sub.ID,
+				UserID:         sub.UserID,
+				WorkspaceID:    workspaceID,
+				Event:          event,
+				Payload:        string(payload),
+				Status:         dataModel.WebhookPending,
+				NextAttemptAt:  now,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+	if err := s.db.CreateWebhookDeliveries(deliveries); err != nil {
+		log.Printf("Error queueing %d %s webhooks of workspace %d: %v", len(deliveries), event, workspaceID, err)
+		return
+	}
+	s.webhooks.Notify()
+}
+
+// webhookSubscriptionCache remembers the webhook subscriptions of a
+// workspace for WebhookCacheTTL, sparing a query per redirect of workspaces
+// with or without webhooks. The zero value is ready to use.
+type webhookSubscriptionCache struct {
+	mu         sync.Mutex
+	workspaces map[uint]cachedSubscriptions
+}
+
+// maxCachedWorkspaces is how many workspaces are cached before expired ones
+// are dropped.
+const maxCachedWorkspaces = 1024
+
+type cachedSubscriptions struct {
+	subs    []dataModel.WebhookSubscription
+	expires time.Time
+}
+
+// lookup returns the subscriptions of workspaceID, asking load when they
+// are not cached. Failed loads are not cached.
+func (c *webhookSubscriptionCache) lookup(workspaceID uint, load func() ([]dataModel.WebhookSubscription, error)) ([]dataModel.WebhookSubscription, error) {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.workspaces[workspaceID]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.subs, nil
+	}
+
+	subs, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.workspaces == nil {
+		c.workspaces = make(map[uint]cachedSubscriptions)
+	}
+	if len(c.workspaces) >= maxCachedWorkspaces {
+		for id, e := range c.workspaces {
+			if !now.Before(e.expires) {
+				delete(c.workspaces, id)
+			}
+		}
+	}
+	c.workspaces[workspaceID] = cachedSubscriptions{subs: subs, expires: now.Add(WebhookCacheTTL)}
+	return subs, nil
+}
+
+// forget drops the subscriptions of workspaceID after they changed.
+func (c *webhookSubscriptionCache) forget(workspaceID uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.workspaces, workspaceID)
+}
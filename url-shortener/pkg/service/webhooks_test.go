@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/netguard"
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/webhook"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	ctx := context.Background()
+	user := testUser(1)
+
+	t.Run("Created", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("ListWebhookSubscriptions", uint(1)).Return([]dataModel.WebhookSubscription{}, nil).Once()
+		mockDb.On("CreateWebhookSubscription", mock.MatchedBy(func(sub *dataModel.WebhookSubscription) bool {
+			return sub.UserID == 1 && sub.URL == "https://hooks.example.com/in" &&
+				sub.Events == "link.created,link.clicked" && strings.HasPrefix(sub.Secret, "whsec_")
+		})).Return(nil).Once()
+
+		resp, err := s.CreateWebhook(ctx, &proto.CreateWebhookRequest{
+			ApiKey: "key",
+			Url:    "https://hooks.example.com/in",
+			Events: []string{EventLinkCreated, EventLinkClicked, EventLinkCreated},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), resp.Webhook.Id)
+		assert.Equal(t, []string{EventLinkCreated, EventLinkClicked}, resp.Webhook.Events)
+		assert.Len(t, resp.Secret, len("whsec_")+64)
+		mockDb.AssertExpectations(t)
+	})
+
+	for name, req := range map[string]*proto.CreateWebhookRequest{
+		"Invalid URL":   {Url: "ftp://hooks.example.com", Events: []string{EventLinkCreated}},
+		"No events":     {Url: "https://hooks.example.com"},
+		"Unknown event": {Url: "https://hooks.example.com", Events: []string{"link.renamed"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb}
+			mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+			req.ApiKey = "key"
+			_, err := s.CreateWebhook(ctx, req)
+			assert.ErrorIs(t, err, ErrInvalidWebhook)
+		})
+	}
+
+	t.Run("Too many webhooks", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(user, nil).Once()
+		mockDb.On("ListWebhookSubscriptions", uint(1)).Return(make([]dataModel.WebhookSubscription, MaxWebhooksPerWorkspace), nil).Once()
+		_, err := s.CreateWebhook(ctx, &proto.CreateWebhookRequest{ApiKey: "key", Url: "https://hooks.example.com", Events: []string{EventLinkCreated}})
+		assert.ErrorIs(t, err, ErrTooManyWebhooks)
+	})
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil)
+	mockDb.On("DeleteWebhookSubscription", uint(1), uint(3)).Return(nil).Once()
+	mockDb.On("DeleteWebhookSubscription", uint(1), uint(4)).Return(gorm.ErrRecordNotFound).Once()
+
+	_, err := s.DeleteWebhook(context.Background(), &proto.DeleteWebhookRequest{ApiKey: "key", Id: 3})
+	assert.NoError(t, err)
+	_, err = s.DeleteWebhook(context.Background(), &proto.DeleteWebhookRequest{ApiKey: "key", Id: 4})
+	assert.Equal(t, ErrWebhookNotFound, err)
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil)
+	mockDb.On("ListWebhookDeliveries", uint(1), uint(2), "dead", MaxWebhookListLimit).Return([]dataModel.WebhookDelivery{
+		{Model: gorm.Model{ID: 9}, SubscriptionID: 2, Event: EventLinkClicked, Status: dataModel.WebhookDead, Attempts: 8, LastError: "timeout"},
+	}, nil).Once()
+
+	resp, err := s.ListWebhookDeliveries(context.Background(), &proto.ListWebhookDeliveriesRequest{ApiKey: "key", WebhookId: 2, Status: "dead", Limit: 10000})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Deliveries, 1)
+	assert.Equal(t, uint64(9), resp.Deliveries[0].Id)
+	assert.Equal(t, int32(8), resp.Deliveries[0].Attempts)
+	assert.Nil(t, resp.Deliveries[0].NextAttemptAt)
+	mockDb.AssertExpectations(t)
+}
+
+func TestWebhookEvents(t *testing.T) {
+	subs := []dataModel.WebhookSubscription{
+		{Model: gorm.Model{ID: 1}, Events: "link.created,link.clicked"},
+		{Model: gorm.Model{ID: 2}, Events: "link.expired"},
+	}
+
+	t.Run("Clicks are queued for subscribed webhooks", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		s.webhooks = webhook.NewDispatcher(mockDb)
+		mapping := &dataModel.URLMapping{ShortURLID: "abc", LongURL: "http://example.com", UserID: 5, WorkspaceID: 5}
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		mockDb.On("ListWebhookSubscriptions", uint(5)).Return(subs, nil).Once()
+		var queued []*dataModel.WebhookDelivery
+		mockDb.On("CreateWebhookDeliveries", mock.Anything).Run(func(args mock.Arguments) {
+			queued = args.Get(0).([]*dataModel.WebhookDelivery)
+		}).Return(nil).Once()
+
+		req := httptest.NewRequest("GET", "/d/abc", nil)
+		req.Header.Set("Referer", "https://news.example/")
+		rr := httptest.NewRecorder()
+		s.redirectHandler(rr, mux.SetURLVars(req, map[string]string{"shortChar": "abc"}))
+		assert.Equal(t, http.StatusFound, rr.Code)
+
+		mockDb.AssertExpectations(t)
+		assert.Len(t, queued, 1)
+		assert.Equal(t, uint(1), queued[0].SubscriptionID)
+		assert.Equal(t, EventLinkClicked, queued[0].Event)
+		assert.Equal(t, dataModel.WebhookPending, queued[0].Status)
+
+		var payload map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(queued[0].Payload), &payload))
+		assert.Equal(t, EventLinkClicked, payload["event"])
+		assert.Equal(t, map[string]interface{}{
+			"short_url": "abc",
+			"long_url":  "http://example.com",
+			"referrer":  "https://news.example/",
+		}, payload["data"])
+	})
+
+	t.Run("Last click expires the link", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		s.webhooks = webhook.NewDispatcher(mockDb)
+		mapping := &dataModel.URLMapping{ShortURLID: "abc", UserID: 5, WorkspaceID: 5, MaxClicks: 1, ClicksRemaining: 1}
+		mockDb.On("ConsumeClick", "", "abc").Return(int64(0), nil).Once()
+		mockDb.On("ListWebhookSubscriptions", uint(5)).Return(subs, nil).Once()
+		mockDb.On("CreateWebhookDeliveries", mock.MatchedBy(func(d []*dataModel.WebhookDelivery) bool {
+			return len(d) == 1 && d[0].SubscriptionID == 2 && d[0].Event == EventLinkExpired
+		})).Return(nil).Once()
+
+		assert.NoError(t, s.consumeClick(mapping))
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Link changes are queued as updates", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		s.webhooks = webhook.NewDispatcher(mockDb)
+		mapping := &dataModel.URLMapping{Model: gorm.Model{ID: 7}, ShortURLID: "abc", LongURL: "http://example.com", UserID: 5, WorkspaceID: 5}
+		mockDb.On("GetUserByAPIKey", "key").Return(testUser(5), nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(mapping, nil).Once()
+		mockDb.On("ReplaceSchedule", uint(7), []dataModel.ScheduledDestination{}).Return(nil).Once()
+		mockDb.On("ListWebhookSubscriptions", uint(5)).
+			Return([]dataModel.WebhookSubscription{{Model: gorm.Model{ID: 3}, Events: "link.updated"}}, nil).Once()
+		mockDb.On("CreateWebhookDeliveries", mock.MatchedBy(func(d []*dataModel.WebhookDelivery) bool {
+			return len(d) == 1 && d[0].SubscriptionID == 3 && d[0].Event == EventLinkUpdated
+		})).Return(nil).Once()
+
+		_, err := s.SetLinkSchedule(context.Background(), &proto.SetLinkScheduleRequest{ApiKey: "key", ShortUrl: "abc"})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Links are reported to the webhooks of their workspace", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		s.webhooks = webhook.NewDispatcher(mockDb)
+		deleted := []dataModel.WebhookSubscription{{Model: gorm.Model{ID: 3}, Events: "link.deleted"}}
+		mockDb.On("ListWebhookSubscriptions", uint(5)).Return(deleted, nil).Once()
+		mockDb.On("ListWebhookSubscriptions", uint(6)).Return(deleted, nil).Once()
+		mockDb.On("CreateWebhookDeliveries", mock.MatchedBy(func(d []*dataModel.WebhookDelivery) bool {
+			return len(d) == 2 && d[0].WorkspaceID == 5 && d[1].WorkspaceID == 5
+		})).Return(nil).Once()
+		mockDb.On("CreateWebhookDeliveries", mock.MatchedBy(func(d []*dataModel.WebhookDelivery) bool {
+			return len(d) == 1 && d[0].WorkspaceID == 6 && d[0].Event == EventLinkDeleted
+		})).Return(nil).Once()
+
+		s.emitLinksChanged(EventLinkDeleted, []dataModel.URLMapping{
+			{ShortURLID: "a", WorkspaceID: 5}, {ShortURLID: "b", WorkspaceID: 6}, {ShortURLID: "c", WorkspaceID: 5},
+		})
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Nothing queued without subscribers", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		s.webhooks = webhook.NewDispatcher(mockDb)
+		mockDb.On("ListWebhookSubscriptions", uint(5)).Return(subs[1:], nil).Once()
+		s.emitWebhook(5, EventLinkCreated, linkEvent{ShortURL: "abc"})
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Subscriptions are cached until they change", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		s.webhooks = webhook.NewDispatcher(mockDb)
+		mockDb.On("GetUserByAPIKey", "key").Return(testUser(5), nil).Once()
+		mockDb.On("ListWebhookSubscriptions", uint(5)).Return(subs[1:], nil).Twice()
+		mockDb.On("DeleteWebhookSubscription", uint(5), uint(2)).Return(nil).Once()
+
+		s.emitWebhook(5, EventLinkCreated, linkEvent{ShortURL: "abc"})
+		s.emitWebhook(5, EventLinkCreated, linkEvent{ShortURL: "abc"})
+		_, err := s.DeleteWebhook(context.Background(), &proto.DeleteWebhookRequest{ApiKey: "key", Id: 2})
+		assert.NoError(t, err)
+		s.emitWebhook(5, EventLinkCreated, linkEvent{ShortURL: "abc"})
+		mockDb.AssertExpectations(t)
+	})
+}
+
+func TestWebhookDispatcherChecksDialedAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// No CheckURL runs here, the loopback address is refused when dialed.
+	guard, err := newNetGuard(Config{}, nil)
+	assert.NoError(t, err)
+	dispatcher := newWebhookDispatcher(Config{}, new(MockDB), guard)
+	_, err = dispatcher.Client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	var blocked *netguard.BlockedError
+	assert.ErrorAs(t, err, &blocked)
+
+	dispatcher = newWebhookDispatcher(Config{}, new(MockDB), nil)
+	resp, err := dispatcher.Client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+	}
+}
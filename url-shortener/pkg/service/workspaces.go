@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"gorm.io/gorm"
+)
+
+// CreateWorkspace creates a shared workspace owned by the caller.
+func (s *UrlShortenerService) CreateWorkspace(ctx context.Context, req *proto.CreateWorkspaceRequest) (*proto.CreateWorkspaceResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	name, err := workspaceName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkWorkspaceCount(user.ID); err != nil {
+		return nil, err
+	}
+
+	workspace := &dataModel.Workspace{Name: name}
+	member, err := s.db.CreateWorkspace(workspace, user.ID)
+	if err != nil {
+		log.Printf("Error creating workspace %q for user %d: %v", name, user.ID, err)
+		return nil, err
+	}
+	return &proto.CreateWorkspaceResponse{Workspace: workspaceToProto(workspace, member.Role, member.APIKey.String())}, nil
+}
+
+// ListWorkspaces lists the workspaces of the caller with their API key for
+// each.
+func (s *UrlShortenerService) ListWorkspaces(ctx context.Context, req *proto.ListWorkspacesRequest) (*proto.ListWorkspacesResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := s.db.ListUserWorkspaces(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListWorkspacesResponse{}
+	for i := range memberships {
+		m := &memberships[i]
+		resp.Workspaces = append(resp.Workspaces, workspaceToProto(&m.Workspace, m.Role, m.APIKey.String()))
+	}
+	return resp, nil
+}
+
+// ListMembers lists the members of the caller's workspace.
+func (s *UrlShortenerService) ListMembers(ctx context.Context, req *proto.ListMembersRequest) (*proto.ListMembersResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.db.ListWorkspaceMembers(user.Workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &proto.ListMembersResponse{}
+	for i := range members {
+		resp.Members = append(resp.Members, memberToProto(&members[i].User, members[i].Role))
+	}
+	return resp, nil
+}
+
+// InviteMember adds a registered user to the caller's workspace. They find
+// their API key for it through ListWorkspaces.
+func (s *UrlShortenerService) InviteMember(ctx context.Context, req *proto.InviteMemberRequest) (*proto.InviteMemberResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRoleGrant(user, req.Role); err != nil {
+		return nil, err
+	}
+	invitee, err := s.db.GetUserByEmail(strings.TrimSpace(req.Email))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: no user with email %q", ErrUserNotFound, req.Email)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkWorkspaceCount(invitee.ID); err != nil {
+		return nil, err
+	}
+
+	member := &dataModel.WorkspaceMember{WorkspaceID: user.Workspace.ID, UserID: invitee.ID, Role: req.Role}
+	if err := s.db.AddWorkspaceMember(member); err != nil {
+		if errors.Is(err, dataModel.ErrAlreadyMember) {
+			return nil, fmt.Errorf("%w: %s", ErrAlreadyMember, invitee.Email)
+		}
+		log.Printf("Error adding user %d to workspace %d: %v", invitee.ID, user.Workspace.ID, err)
+		return nil, err
+	}
+	return &proto.InviteMemberResponse{Member: memberToProto(invitee, member.Role)}, nil
+}
+
+// ChangeMemberRole changes the role of a member of the caller's workspace.
+// Only owners grant or take away the owner and admin roles.
+func (s *UrlShortenerService) ChangeMemberRole(ctx context.Context, req *proto.ChangeMemberRoleRequest) (*proto.ChangeMemberRoleResponse, error) {
+	user, err := s.authorize(req.ApiKey, dataModel.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRoleGrant(user, req.Role); err != nil {
+		return nil, err
+	}
+	member, err := s.workspaceMember(user, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMemberManaged(user, member); err != nil {
+		return nil, err
+	}
+	target, err := s.db.GetUserByID(member.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.SetWorkspaceMemberRole(user.Workspace.ID, member.UserID, req.Role); err != nil {
+		if errors.Is(err, dataModel.ErrLastOwner) {
+			return nil, ErrLastOwner
+		}
+		return nil, err
+	}
+	return &proto.ChangeMemberRoleResponse{Member: memberToProto(target, req.Role)}, nil
+}
+
+// RemoveMember removes a member from the caller's workspace, revoking their
+// API key for it. Any member may leave; removing others takes the same
+// rights as changing their role.
+func (s *UrlShortenerService) RemoveMember(ctx context.Context, req *proto.RemoveMemberRequest) (*proto.RemoveMemberResponse, error) {
+	user, err := s.authenticate(req.ApiKey)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(user.ID) != req.UserId {
+		if !dataModel.RoleAtLeast(user.Role, dataModel.RoleAdmin) {
+			return nil, fmt.Errorf("%w: %s role required", ErrPermissionDenied, dataModel.RoleAdmin)
+		}
+		member, err := s.workspaceMember(user, req.UserId)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkMemberManaged(user, member); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.db.RemoveWorkspaceMember(user.Workspace.ID, uint(req.UserId))
+	if errors.Is(err, dataModel.ErrLastOwner) {
+		return nil, ErrLastOwner
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &proto.RemoveMemberResponse{}, nil
+}
+
+// workspaceMember retrieves the membership of userID in the caller's
+// workspace, ErrMemberNotFound when there is none.
+func (s *UrlShortenerService) workspaceMember(user *dataModel.User, userID uint64) (*dataModel.WorkspaceMember, error) {
+	member, err := s.db.GetWorkspaceMember(user.Workspace.ID, uint(userID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// checkWorkspaceCount rejects users already in MaxWorkspacesPerUser
+// workspaces.
+func (s *UrlShortenerService) checkWorkspaceCount(userID uint) error {
+	memberships, err := s.db.ListUserWorkspaces(userID)
+	if err != nil {
+		return err
+	}
+	if len(memberships) >= MaxWorkspacesPerUser {
+		return fmt.Errorf("%w: at most %d allowed", ErrTooManyWorkspaces, MaxWorkspacesPerUser)
+	}
+	return nil
+}
+
+// checkRoleGrant rejects unknown roles, and the owner and admin roles
+// unless the caller is an owner.
+func checkRoleGrant(user *dataModel.User, role string) error {
+	if !dataModel.ValidRole(role) {
+		return fmt.Errorf("%w: %q, must be one of %s, %s, %s or %s", ErrInvalidRole, role,
+			dataModel.RoleOwner, dataModel.RoleAdmin, dataModel.RoleEditor, dataModel.RoleViewer)
+	}
+	if dataModel.RoleAtLeast(role, dataModel.RoleAdmin) && user.Role != dataModel.RoleOwner {
+		return fmt.Errorf("%w: only owners grant the %s role", ErrPermissionDenied, role)
+	}
+	return nil
+}
+
+// checkMemberManaged rejects changes to owners and admins unless the caller
+// is an owner.
+func checkMemberManaged(user *dataModel.User, member *dataModel.WorkspaceMember) error {
+	if dataModel.RoleAtLeast(member.Role, dataModel.RoleAdmin) && user.Role != dataModel.RoleOwner {
+		return fmt.Errorf("%w: only owners manage members with the %s role", ErrPermissionDenied, member.Role)
+	}
+	return nil
+}
+
+func workspaceName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxWorkspaceName {
+		return "", fmt.Errorf("%w: names have 1 to %d characters", ErrInvalidWorkspace, MaxWorkspaceName)
+	}
+	if !utf8.ValidString(name) || strings.ContainsFunc(name, unicode.IsControl) {
+		return "", fmt.Errorf("%w: name %q has control characters", ErrInvalidWorkspace, name)
+	}
+	return name, nil
+}
+
+func workspaceToProto(w *dataModel.Workspace, role, apiKey string) *proto.Workspace {
+	return &proto.Workspace{
+		Id:       uint64(w.ID),
+		Name:     w.Name,
+		Personal: w.PersonalUserID != 0,
+		Role:     role,
+		ApiKey:   apiKey,
+	}
+}
+
+func memberToProto(u *dataModel.User, role string) *proto.WorkspaceMember {
+	return &proto.WorkspaceMember{
+		UserId:    uint64(u.ID),
+		Email:     u.Email,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Role:      role,
+	}
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	proto "github.com/alt-coder/url-shortener/url-shortener/proto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// testUser returns a user authenticated as owner of their personal
+// workspace, which has the same ID.
+func testUser(id uint) *dataModel.User {
+	return &dataModel.User{
+		Model:     gorm.Model{ID: id},
+		Workspace: dataModel.Workspace{Model: gorm.Model{ID: id}, PersonalUserID: id},
+		Role:      dataModel.RoleOwner,
+	}
+}
+
+// teamMember returns a user authenticated with role in the shared
+// workspace 10.
+func teamMember(id uint, role string) *dataModel.User {
+	return &dataModel.User{
+		Model:     gorm.Model{ID: id},
+		Workspace: dataModel.Workspace{Model: gorm.Model{ID: 10}, Name: "Team"},
+		Role:      role,
+	}
+}
+
+func TestRoles(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Viewers cannot create links", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleViewer), nil).Once()
+
+		_, err := s.ShortenURL(ctx, &proto.ShortenURLRequest{ApiKey: "key", LongUrl: "https://example.com"})
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Editors cannot add domains", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleEditor), nil).Once()
+
+		_, err := s.CreateDomain(ctx, &proto.CreateDomainRequest{ApiKey: "key", Host: "go.acme.com"})
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Viewers list the workspace's links", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleViewer), nil).Once()
+		mockDb.On("ListWorkspaceURLMappings", uint(10), dataModel.LinkFilter{Limit: DefaultLinksPageSize}).Return([]dataModel.URLMapping{
+			{Model: gorm.Model{ID: 4}, ShortURLID: "abc", LongURL: "https://example.com", UserID: 3, WorkspaceID: 10},
+		}, nil).Once()
+		mockDb.On("GetLinkClicks", []uint{4}, mock.AnythingOfType("time.Time")).Return(map[uint]int64{}, nil).Once()
+		mockDb.On("ListLinkTags", []uint{4}).Return([]dataModel.LinkTag{}, nil).Once()
+
+		resp, err := s.ListLinks(ctx, &proto.ListLinksRequest{ApiKey: "key"})
+		require.NoError(t, err)
+		assert.Len(t, resp.Links, 1)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Links of other workspaces are not found", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleOwner), nil).Once()
+		mockDb.On("GetURLMapping", "abc").Return(&dataModel.URLMapping{ShortURLID: "abc", UserID: 2, WorkspaceID: 2}, nil).Once()
+
+		_, err := s.GetLinkStats(ctx, &proto.GetLinkStatsRequest{ApiKey: "key", ShortUrl: "abc"})
+		assert.ErrorIs(t, err, ErrLinkNotFound)
+	})
+}
+
+func TestCreateWorkspace(t *testing.T) {
+	ctx := context.Background()
+	key := uuid.New()
+
+	t.Run("Created", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil).Once()
+		mockDb.On("ListUserWorkspaces", uint(1)).Return([]dataModel.WorkspaceMembership{{}}, nil).Once()
+		mockDb.On("CreateWorkspace", &dataModel.Workspace{Name: "Marketing"}, uint(1)).
+			Return(&dataModel.WorkspaceMember{UserID: 1, Role: dataModel.RoleOwner, APIKey: key}, nil).Once()
+
+		resp, err := s.CreateWorkspace(ctx, &proto.CreateWorkspaceRequest{ApiKey: "key", Name: " Marketing "})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), resp.Workspace.Id)
+		assert.Equal(t, "Marketing", resp.Workspace.Name)
+		assert.Equal(t, dataModel.RoleOwner, resp.Workspace.Role)
+		assert.Equal(t, key.String(), resp.Workspace.ApiKey)
+		assert.False(t, resp.Workspace.Personal)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Invalid name", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil).Once()
+
+		_, err := s.CreateWorkspace(ctx, &proto.CreateWorkspaceRequest{ApiKey: "key", Name: " "})
+		assert.ErrorIs(t, err, ErrInvalidWorkspace)
+	})
+
+	t.Run("Too many", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil).Once()
+		mockDb.On("ListUserWorkspaces", uint(1)).Return(make([]dataModel.WorkspaceMembership, MaxWorkspacesPerUser), nil).Once()
+
+		_, err := s.CreateWorkspace(ctx, &proto.CreateWorkspaceRequest{ApiKey: "key", Name: "Marketing"})
+		assert.ErrorIs(t, err, ErrTooManyWorkspaces)
+	})
+}
+
+func TestListWorkspaces(t *testing.T) {
+	personal, team := uuid.New(), uuid.New()
+	mockDb := new(MockDB)
+	s := &UrlShortenerService{db: mockDb}
+	mockDb.On("GetUserByAPIKey", "key").Return(testUser(1), nil).Once()
+	mockDb.On("ListUserWorkspaces", uint(1)).Return([]dataModel.WorkspaceMembership{
+		{Workspace: dataModel.Workspace{Model: gorm.Model{ID: 1}, Name: "Ada Lovelace", PersonalUserID: 1}, Role: dataModel.RoleOwner, APIKey: personal},
+		{Workspace: dataModel.Workspace{Model: gorm.Model{ID: 10}, Name: "Team"}, Role: dataModel.RoleEditor, APIKey: team},
+	}, nil).Once()
+
+	resp, err := s.ListWorkspaces(context.Background(), &proto.ListWorkspacesRequest{ApiKey: "key"})
+	require.NoError(t, err)
+	require.Len(t, resp.Workspaces, 2)
+	assert.True(t, resp.Workspaces[0].Personal)
+	assert.Equal(t, personal.String(), resp.Workspaces[0].ApiKey)
+	assert.Equal(t, "Team", resp.Workspaces[1].Name)
+	assert.Equal(t, dataModel.RoleEditor, resp.Workspaces[1].Role)
+	assert.Equal(t, team.String(), resp.Workspaces[1].ApiKey)
+}
+
+func TestInviteMember(t *testing.T) {
+	ctx := context.Background()
+	invitee := &dataModel.User{Model: gorm.Model{ID: 3}, Email: "grace@example.com", FirstName: "Grace"}
+
+	t.Run("Invited", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleAdmin), nil).Once()
+		mockDb.On("GetUserByEmail", "grace@example.com").Return(invitee, nil).Once()
+		mockDb.On("ListUserWorkspaces", uint(3)).Return([]dataModel.WorkspaceMembership{{}}, nil).Once()
+		mockDb.On("AddWorkspaceMember", &dataModel.WorkspaceMember{WorkspaceID: 10, UserID: 3, Role: dataModel.RoleEditor}).Return(nil).Once()
+
+		resp, err := s.InviteMember(ctx, &proto.InviteMemberRequest{ApiKey: "key", Email: "grace@example.com", Role: dataModel.RoleEditor})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), resp.Member.UserId)
+		assert.Equal(t, "grace@example.com", resp.Member.Email)
+		assert.Equal(t, dataModel.RoleEditor, resp.Member.Role)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Already a member", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleOwner), nil).Once()
+		mockDb.On("GetUserByEmail", "grace@example.com").Return(invitee, nil).Once()
+		mockDb.On("ListUserWorkspaces", uint(3)).Return([]dataModel.WorkspaceMembership{{}}, nil).Once()
+		mockDb.On("AddWorkspaceMember", mock.Anything).Return(dataModel.ErrAlreadyMember).Once()
+
+		_, err := s.InviteMember(ctx, &proto.InviteMemberRequest{ApiKey: "key", Email: "grace@example.com", Role: dataModel.RoleAdmin})
+		assert.ErrorIs(t, err, ErrAlreadyMember)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleAdmin), nil).Once()
+		mockDb.On("GetUserByEmail", "nobody@example.com").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := s.InviteMember(ctx, &proto.InviteMemberRequest{ApiKey: "key", Email: "nobody@example.com", Role: dataModel.RoleViewer})
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	for name, tt := range map[string]struct {
+		caller string
+		role   string
+		want   error
+	}{
+		"Invalid role":             {dataModel.RoleOwner, "superuser", ErrInvalidRole},
+		"Admins cannot add owners": {dataModel.RoleAdmin, dataModel.RoleOwner, ErrPermissionDenied},
+		"Admins cannot add admins": {dataModel.RoleAdmin, dataModel.RoleAdmin, ErrPermissionDenied},
+		"Editors cannot invite":    {dataModel.RoleEditor, dataModel.RoleViewer, ErrPermissionDenied},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockDb := new(MockDB)
+			s := &UrlShortenerService{db: mockDb}
+			mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, tt.caller), nil).Once()
+
+			_, err := s.InviteMember(ctx, &proto.InviteMemberRequest{ApiKey: "key", Email: "grace@example.com", Role: tt.role})
+			assert.ErrorIs(t, err, tt.want)
+			mockDb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestChangeMemberRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Changed", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleAdmin), nil).Once()
+		mockDb.On("GetWorkspaceMember", uint(10), uint(3)).Return(&dataModel.WorkspaceMember{WorkspaceID: 10, UserID: 3, Role: dataModel.RoleViewer}, nil).Once()
+		mockDb.On("GetUserByID", uint(3)).Return(&dataModel.User{Model: gorm.Model{ID: 3}, Email: "grace@example.com"}, nil).Once()
+		mockDb.On("SetWorkspaceMemberRole", uint(10), uint(3), dataModel.RoleEditor).Return(nil).Once()
+
+		resp, err := s.ChangeMemberRole(ctx, &proto.ChangeMemberRoleRequest{ApiKey: "key", UserId: 3, Role: dataModel.RoleEditor})
+		require.NoError(t, err)
+		assert.Equal(t, dataModel.RoleEditor, resp.Member.Role)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Admins cannot demote admins", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleAdmin), nil).Once()
+		mockDb.On("GetWorkspaceMember", uint(10), uint(3)).Return(&dataModel.WorkspaceMember{WorkspaceID: 10, UserID: 3, Role: dataModel.RoleAdmin}, nil).Once()
+
+		_, err := s.ChangeMemberRole(ctx, &proto.ChangeMemberRoleRequest{ApiKey: "key", UserId: 3, Role: dataModel.RoleViewer})
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+		mockDb.AssertNotCalled(t, "SetWorkspaceMemberRole", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Last owner", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleOwner), nil).Once()
+		mockDb.On("GetWorkspaceMember", uint(10), uint(2)).Return(&dataModel.WorkspaceMember{WorkspaceID: 10, UserID: 2, Role: dataModel.RoleOwner}, nil).Once()
+		mockDb.On("GetUserByID", uint(2)).Return(&dataModel.User{Model: gorm.Model{ID: 2}}, nil).Once()
+		mockDb.On("SetWorkspaceMemberRole", uint(10), uint(2), dataModel.RoleAdmin).Return(dataModel.ErrLastOwner).Once()
+
+		_, err := s.ChangeMemberRole(ctx, &proto.ChangeMemberRoleRequest{ApiKey: "key", UserId: 2, Role: dataModel.RoleAdmin})
+		assert.ErrorIs(t, err, ErrLastOwner)
+	})
+
+	t.Run("Not a member", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleOwner), nil).Once()
+		mockDb.On("GetWorkspaceMember", uint(10), uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := s.ChangeMemberRole(ctx, &proto.ChangeMemberRoleRequest{ApiKey: "key", UserId: 9, Role: dataModel.RoleViewer})
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+	})
+}
+
+func TestRemoveMember(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Members leave", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleViewer), nil).Once()
+		mockDb.On("RemoveWorkspaceMember", uint(10), uint(2)).Return(nil).Once()
+
+		_, err := s.RemoveMember(ctx, &proto.RemoveMemberRequest{ApiKey: "key", UserId: 2})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Admins remove editors", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleAdmin), nil).Once()
+		mockDb.On("GetWorkspaceMember", uint(10), uint(3)).Return(&dataModel.WorkspaceMember{WorkspaceID: 10, UserID: 3, Role: dataModel.RoleEditor}, nil).Once()
+		mockDb.On("RemoveWorkspaceMember", uint(10), uint(3)).Return(nil).Once()
+
+		_, err := s.RemoveMember(ctx, &proto.RemoveMemberRequest{ApiKey: "key", UserId: 3})
+		assert.NoError(t, err)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Editors cannot remove others", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleEditor), nil).Once()
+
+		_, err := s.RemoveMember(ctx, &proto.RemoveMemberRequest{ApiKey: "key", UserId: 3})
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+		mockDb.AssertExpectations(t)
+	})
+
+	t.Run("Admins cannot remove owners", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleAdmin), nil).Once()
+		mockDb.On("GetWorkspaceMember", uint(10), uint(3)).Return(&dataModel.WorkspaceMember{WorkspaceID: 10, UserID: 3, Role: dataModel.RoleOwner}, nil).Once()
+
+		_, err := s.RemoveMember(ctx, &proto.RemoveMemberRequest{ApiKey: "key", UserId: 3})
+		assert.ErrorIs(t, err, ErrPermissionDenied)
+		mockDb.AssertNotCalled(t, "RemoveWorkspaceMember", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Last owner cannot leave", func(t *testing.T) {
+		mockDb := new(MockDB)
+		s := &UrlShortenerService{db: mockDb}
+		mockDb.On("GetUserByAPIKey", "key").Return(teamMember(2, dataModel.RoleOwner), nil).Once()
+		mockDb.On("RemoveWorkspaceMember", uint(10), uint(2)).Return(dataModel.ErrLastOwner).Once()
+
+		_, err := s.RemoveMember(ctx, &proto.RemoveMemberRequest{ApiKey: "key", UserId: 2})
+		assert.ErrorIs(t, err, ErrLastOwner)
+	})
+}
@@ -0,0 +1,120 @@
+// Package useragent tells the operating system, device class and browser
+// of a visitor from their User-Agent header. It works offline from a small
+// set of substring rules, good enough to route visitors to an app store or
+// the web, not to fingerprint them.
+package useragent
+
+import "strings"
+
+// Operating systems.
+const (
+	IOS      = "ios"
+	Android  = "android"
+	Windows  = "windows"
+	MacOS    = "macos"
+	Linux    = "linux"
+	ChromeOS = "chromeos"
+)
+
+// Device classes.
+const (
+	Mobile  = "mobile"
+	Tablet  = "tablet"
+	Desktop = "desktop"
+	Bot     = "bot"
+)
+
+// Browsers.
+const (
+	Chrome  = "chrome"
+	Safari  = "safari"
+	Firefox = "firefox"
+	Edge    = "edge"
+	Opera   = "opera"
+	Samsung = "samsung"
+)
+
+// OSes, Devices and Browsers list the values Parse may return, besides ""
+// for unknown.
+var (
+	OSes     = []string{IOS, Android, Windows, MacOS, Linux, ChromeOS}
+	Devices  = []string{Mobile, Tablet, Desktop, Bot}
+	Browsers = []string{Chrome, Safari, Firefox, Edge, Opera, Samsung}
+)
+
+// Agent is what Parse tells about a User-Agent. Unknown fields are empty.
+type Agent struct {
+	OS      string
+	Device  string
+	Browser string
+}
+
+// rule sets value when the User-Agent contains any of its substrings.
+type rule struct {
+	value      string
+	substrings []string
+}
+
+// Rules are tried in order, the first match wins. Lower case.
+var (
+	botRules = []string{
+		"bot", "crawler", "spider", "slurp", "facebookexternalhit", "embedly",
+		"curl/", "wget/", "python-requests", "go-http-client", "okhttp", "headless",
+	}
+	osRules = []rule{
+		{IOS, []string{"iphone", "ipad", "ipod"}},
+		{Android, []string{"android"}},
+		{ChromeOS, []string{"cros"}},
+		{Windows, []string{"windows"}},
+		{MacOS, []string{"macintosh", "mac os x"}},
+		{Linux, []string{"linux", "x11"}},
+	}
+	browserRules = []rule{
+		{Edge, []string{"edg/", "edga/", "edgios/", "edge/"}},
+		{Opera, []string{"opr/", "opera", "opios/"}},
+		{Samsung, []string{"samsungbrowser/"}},
+		{Firefox, []string{"firefox/", "fxios/"}},
+		{Chrome, []string{"crios/", "chrome/", "chromium/"}},
+		{Safari, []string{"safari/"}},
+	}
+)
+
+// Parse tells what it can about the visitor sending userAgent.
+func Parse(userAgent string) Agent {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return Agent{}
+	}
+	agent := Agent{OS: match(ua, osRules), Browser: match(ua, browserRules)}
+	switch {
+	case containsAny(ua, botRules):
+		agent.Device = Bot
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(agent.OS == Android && !strings.Contains(ua, "mobile")):
+		agent.Device = Tablet
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") ||
+		strings.Contains(ua, "ipod") || strings.Contains(ua, "windows phone"):
+		agent.Device = Mobile
+	case agent.OS == Windows || agent.OS == MacOS || agent.OS == Linux || agent.OS == ChromeOS:
+		agent.Device = Desktop
+	}
+	return agent
+}
+
+func match(ua string, rules []rule) string {
+	for _, r := range rules {
+		if containsAny(ua, r.substrings) {
+			return r.value
+		}
+	}
+	return ""
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
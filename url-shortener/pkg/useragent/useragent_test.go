@@ -0,0 +1,74 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want Agent
+	}{
+		{
+			"iPhone Safari",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+			Agent{IOS, Mobile, Safari},
+		},
+		{
+			"iPad Chrome",
+			"Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/123.0.6312.52 Mobile/15E148 Safari/604.1",
+			Agent{IOS, Tablet, Chrome},
+		},
+		{
+			"Android phone Chrome",
+			"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Mobile Safari/537.36",
+			Agent{Android, Mobile, Chrome},
+		},
+		{
+			"Android tablet",
+			"Mozilla/5.0 (Linux; Android 13; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36",
+			Agent{Android, Tablet, Chrome},
+		},
+		{
+			"Samsung Internet",
+			"Mozilla/5.0 (Linux; Android 14; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/24.0 Chrome/117.0.0.0 Mobile Safari/537.36",
+			Agent{Android, Mobile, Samsung},
+		},
+		{
+			"Windows Edge",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36 Edg/123.0.2420.65",
+			Agent{Windows, Desktop, Edge},
+		},
+		{
+			"macOS Firefox",
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 14.4; rv:124.0) Gecko/20100101 Firefox/124.0",
+			Agent{MacOS, Desktop, Firefox},
+		},
+		{
+			"Linux Opera",
+			"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36 OPR/108.0.0.0",
+			Agent{Linux, Desktop, Opera},
+		},
+		{
+			"ChromeOS",
+			"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36",
+			Agent{ChromeOS, Desktop, Chrome},
+		},
+		{
+			"Googlebot",
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			Agent{Device: Bot},
+		},
+		{"curl", "curl/8.5.0", Agent{Device: Bot}},
+		{"Empty", "", Agent{}},
+		{"Unknown", "SomeClient/1.0", Agent{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Parse(tt.ua))
+		})
+	}
+}
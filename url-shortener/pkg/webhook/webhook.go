@@ -0,0 +1,272 @@
+// Package webhook posts queued events to subscriber URLs. Every request is
+// signed with HMAC-SHA256 using the subscription's secret, and failed
+// deliveries are retried with exponential backoff until they run out of
+// attempts and are dead lettered.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+)
+
+// Headers sent with every delivery.
+const (
+	// EventHeader names the event.
+	EventHeader = "X-Webhook-Event"
+	// DeliveryHeader is the delivery ID, the same for every retry.
+	DeliveryHeader = "X-Webhook-Delivery"
+	// TimestampHeader is the Unix time the request was signed at.
+	TimestampHeader = "X-Webhook-Timestamp"
+	// SignatureHeader is "sha256=" followed by the hex encoded HMAC-SHA256
+	// of the timestamp, a dot and the body.
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Defaults of the Dispatcher settings.
+const (
+	DefaultMaxAttempts = 8
+	DefaultBaseBackoff = 30 * time.Second
+	DefaultMaxBackoff  = time.Hour
+	DefaultTimeout     = 10 * time.Second
+	DefaultBatchSize   = 100
+	DefaultConcurrency = 8
+)
+
+// maxErrorBody caps how much of a failed response is kept in the log.
+const maxErrorBody = 512
+
+// Sign returns the SignatureHeader value of body signed at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of body signed at
+// timestamp with secret. Receivers should also reject old timestamps.
+func Verify(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// Backoff returns how long to wait after attempts failed attempts: base
+// doubled for every attempt after the first, at most max.
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+// Store holds the queued deliveries. *dataModel.DB implements it.
+type Store interface {
+	ClaimWebhookDeliveries(now time.Time, limit int, lease time.Duration) ([]dataModel.WebhookDelivery, error)
+	UpdateWebhookDelivery(d *dataModel.WebhookDelivery) error
+}
+
+// Dispatcher delivers the due deliveries of a Store.
+type Dispatcher struct {
+	Store Store
+	// Client sends the requests. Redirects are not followed.
+	Client *http.Client
+	// CheckURL, when set, vets the subscription URL before every attempt.
+	// An error fails the attempt.
+	CheckURL func(ctx context.Context, url string) error
+
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// BatchSize is how many deliveries are claimed at once, Concurrency
+	// how many of them are sent in parallel.
+	BatchSize   int
+	Concurrency int
+
+	// Now returns the current time, time.Now when nil.
+	Now func() time.Time
+
+	wakeOnce sync.Once
+	wake     chan struct{}
+}
+
+// NewDispatcher returns a dispatcher of store with the default settings.
+func NewDispatcher(store Store) *Dispatcher {
+	return &Dispatcher{
+		Store: store,
+		Client: &http.Client{
+			Timeout: DefaultTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		MaxAttempts: DefaultMaxAttempts,
+		BaseBackoff: DefaultBaseBackoff,
+		MaxBackoff:  DefaultMaxBackoff,
+		BatchSize:   DefaultBatchSize,
+		Concurrency: DefaultConcurrency,
+	}
+}
+
+func (d *Dispatcher) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
+
+func (d *Dispatcher) wakeChan() chan struct{} {
+	d.wakeOnce.Do(func() { d.wake = make(chan struct{}, 1) })
+	return d.wake
+}
+
+// Notify tells a running dispatcher that new deliveries were queued, so they
+// are sent without waiting for the next poll.
+func (d *Dispatcher) Notify() {
+	select {
+	case d.wakeChan() <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due deliveries every interval, and when notified, until ctx
+// is done.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// Keep going while full batches come back, there may be more due.
+		for {
+			n, err := d.DeliverDue(ctx)
+			if err != nil {
+				log.Printf("Error delivering webhooks: %v", err)
+			}
+			if err != nil || n < d.BatchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wakeChan():
+		}
+	}
+}
+
+// DeliverDue claims one batch of due deliveries, attempts each of them and
+// records the outcomes. It returns how many deliveries were attempted.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	lease := d.Client.Timeout
+	if lease <= 0 {
+		lease = DefaultTimeout
+	}
+	// Outcomes are recorded before the lease ends unless the store is down.
+	deliveries, err := d.Store.ClaimWebhookDeliveries(d.now(), d.BatchSize, 2*lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(d.Concurrency, 1))
+	for i := range deliveries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			delivery := &deliveries[i]
+			d.attempt(ctx, delivery)
+			if err := d.Store.UpdateWebhookDelivery(delivery); err != nil {
+				log.Printf("Error recording webhook delivery %d: %v", delivery.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// attempt sends a delivery once and updates it with the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *dataModel.WebhookDelivery) {
+	sub := delivery.Subscription
+	if sub.ID == 0 {
+		delivery.Status = dataModel.WebhookCanceled
+		return
+	}
+
+	delivery.Attempts++
+	code, err := d.send(ctx, delivery)
+	delivery.LastStatusCode = code
+	now := d.now()
+	if err == nil {
+		delivery.Status = dataModel.WebhookDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.MaxAttempts {
+		delivery.Status = dataModel.WebhookDead
+		log.Printf("Webhook delivery %d to %s failed %d times, dead lettered: %v", delivery.ID, sub.URL, delivery.Attempts, err)
+		return
+	}
+	delivery.NextAttemptAt = now.Add(Backoff(delivery.Attempts, d.BaseBackoff, d.MaxBackoff))
+}
+
+// send posts a delivery and returns the response status. Anything but a 2xx
+// response is an error.
+func (d *Dispatcher) send(ctx context.Context, delivery *dataModel.WebhookDelivery) (int, error) {
+	sub := delivery.Subscription
+	if d.CheckURL != nil {
+		if err := d.CheckURL(ctx, sub.URL); err != nil {
+			return 0, err
+		}
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "url-shortener-webhooks")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, timestamp, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	msg := fmt.Sprintf("unexpected status %s", resp.Status)
+	if s := strings.TrimSpace(string(snippet)); s != "" {
+		msg += ": " + s
+	}
+	return resp.StatusCode, errors.New(msg)
+}
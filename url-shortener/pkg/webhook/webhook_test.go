@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alt-coder/url-shortener/url-shortener/pkg/dataModel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// memStore keeps deliveries in memory.
+type memStore struct {
+	mu         sync.Mutex
+	deliveries []dataModel.WebhookDelivery
+	dead       []uint
+}
+
+func (m *memStore) ClaimWebhookDeliveries(now time.Time, limit int, lease time.Duration) ([]dataModel.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []dataModel.WebhookDelivery
+	for i := range m.deliveries {
+		d := &m.deliveries[i]
+		if len(due) < limit && d.Status == dataModel.WebhookPending && !d.NextAttemptAt.After(now) {
+			d.NextAttemptAt = now.Add(lease)
+			due = append(due, *d)
+		}
+	}
+	return due, nil
+}
+
+func (m *memStore) UpdateWebhookDelivery(d *dataModel.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.deliveries {
+		if m.deliveries[i].ID == d.ID {
+			m.deliveries[i] = *d
+		}
+	}
+	if d.Status == dataModel.WebhookDead {
+		m.dead = append(m.dead, d.ID)
+	}
+	return nil
+}
+
+func (m *memStore) get(id uint) dataModel.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, d := range m.deliveries {
+		if d.ID == id {
+			return d
+		}
+	}
+	return dataModel.WebhookDelivery{}
+}
+
+func newDelivery(id uint, url string) dataModel.WebhookDelivery {
+	return dataModel.WebhookDelivery{
+		Model:          gorm.Model{ID: id},
+		SubscriptionID: 7,
+		Subscription:   dataModel.WebhookSubscription{Model: gorm.Model{ID: 7}, URL: url, Secret: "s3cret"},
+		Event:          "link.clicked",
+		Payload:        `{"event":"link.clicked"}`,
+		Status:         dataModel.WebhookPending,
+	}
+}
+
+func TestDeliverDue(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Signed delivery", func(t *testing.T) {
+		var got *http.Request
+		var body []byte
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer receiver.Close()
+
+		store := &memStore{deliveries: []dataModel.WebhookDelivery{newDelivery(1, receiver.URL)}}
+		d := NewDispatcher(store)
+		d.Now = func() time.Time { return now }
+		n, err := d.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		require.NotNil(t, got)
+		assert.Equal(t, http.MethodPost, got.Method)
+		assert.Equal(t, `{"event":"link.clicked"}`, string(body))
+		assert.Equal(t, "link.clicked", got.Header.Get(EventHeader))
+		assert.Equal(t, "1", got.Header.Get(DeliveryHeader))
+		timestamp, err := strconv.ParseInt(got.Header.Get(TimestampHeader), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, now.Unix(), timestamp)
+		assert.True(t, Verify("s3cret", got.Header.Get(SignatureHeader), timestamp, body))
+		assert.False(t, Verify("other", got.Header.Get(SignatureHeader), timestamp, body))
+
+		delivered := store.get(1)
+		assert.Equal(t, dataModel.WebhookDelivered, delivered.Status)
+		assert.Equal(t, 1, delivered.Attempts)
+		assert.Equal(t, http.StatusOK, delivered.LastStatusCode)
+		require.NotNil(t, delivered.DeliveredAt)
+	})
+
+	t.Run("Retries with backoff, then dead letters", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+		}))
+		defer receiver.Close()
+
+		store := &memStore{deliveries: []dataModel.WebhookDelivery{newDelivery(1, receiver.URL)}}
+		d := NewDispatcher(store)
+		d.MaxAttempts = 3
+		clock := now
+		d.Now = func() time.Time { return clock }
+
+		_, err := d.DeliverDue(context.Background())
+		require.NoError(t, err)
+		delivery := store.get(1)
+		assert.Equal(t, dataModel.WebhookPending, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, http.StatusServiceUnavailable, delivery.LastStatusCode)
+		assert.Equal(t, "unexpected status 503 Service Unavailable: try later", delivery.LastError)
+		assert.Equal(t, clock.Add(DefaultBaseBackoff), delivery.NextAttemptAt)
+
+		// Not due before the backoff passed.
+		n, err := d.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+
+		clock = delivery.NextAttemptAt
+		_, err = d.DeliverDue(context.Background())
+		require.NoError(t, err)
+		delivery = store.get(1)
+		assert.Equal(t, 2, delivery.Attempts)
+		assert.Equal(t, clock.Add(2*DefaultBaseBackoff), delivery.NextAttemptAt)
+
+		clock = delivery.NextAttemptAt
+		_, err = d.DeliverDue(context.Background())
+		require.NoError(t, err)
+		delivery = store.get(1)
+		assert.Equal(t, dataModel.WebhookDead, delivery.Status)
+		assert.Equal(t, 3, delivery.Attempts)
+		assert.Equal(t, []uint{1}, store.dead)
+	})
+
+	t.Run("Redirects are failures", func(t *testing.T) {
+		receiver := httptest.NewServer(http.RedirectHandler("http://example.com", http.StatusFound))
+		defer receiver.Close()
+
+		store := &memStore{deliveries: []dataModel.WebhookDelivery{newDelivery(1, receiver.URL)}}
+		_, err := NewDispatcher(store).DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusFound, store.get(1).LastStatusCode)
+		assert.Equal(t, dataModel.WebhookPending, store.get(1).Status)
+	})
+
+	t.Run("Rejected URL", func(t *testing.T) {
+		store := &memStore{deliveries: []dataModel.WebhookDelivery{newDelivery(1, "http://10.0.0.1/hook")}}
+		d := NewDispatcher(store)
+		d.CheckURL = func(ctx context.Context, url string) error { return errors.New("internal address") }
+		_, err := d.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "internal address", store.get(1).LastError)
+	})
+
+	t.Run("Deleted subscription", func(t *testing.T) {
+		delivery := newDelivery(1, "")
+		delivery.Subscription = dataModel.WebhookSubscription{}
+		store := &memStore{deliveries: []dataModel.WebhookDelivery{delivery}}
+		_, err := NewDispatcher(store).DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, dataModel.WebhookCanceled, store.get(1).Status)
+		assert.Equal(t, 0, store.get(1).Attempts)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := 30*time.Second, 5*time.Minute
+	assert.Equal(t, 30*time.Second, Backoff(1, base, max))
+	assert.Equal(t, time.Minute, Backoff(2, base, max))
+	assert.Equal(t, 4*time.Minute, Backoff(4, base, max))
+	assert.Equal(t, max, Backoff(5, base, max))
+	assert.Equal(t, max, Backoff(100, base, max))
+}
@@ -8,8 +8,10 @@ package proto
 
 import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -22,10 +24,95 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type RedirectType int32
+
+const (
+	RedirectType_REDIRECT_TYPE_UNSPECIFIED RedirectType = 0
+	// 301 Moved Permanently.
+	RedirectType_REDIRECT_TYPE_PERMANENT RedirectType = 1
+	// 302 Found.
+	RedirectType_REDIRECT_TYPE_TEMPORARY RedirectType = 2
+	// 307 Temporary Redirect, keeps the request method and body.
+	RedirectType_REDIRECT_TYPE_METHOD_PRESERVING_TEMPORARY RedirectType = 3
+	// 308 Permanent Redirect, keeps the request method and body.
+	RedirectType_REDIRECT_TYPE_METHOD_PRESERVING_PERMANENT RedirectType = 4
+)
+
+// Enum value maps for RedirectType.
+var (
+	RedirectType_name = map[int32]string{
+		0: "REDIRECT_TYPE_UNSPECIFIED",
+		1: "REDIRECT_TYPE_PERMANENT",
+		2: "REDIRECT_TYPE_TEMPORARY",
+		3: "REDIRECT_TYPE_METHOD_PRESERVING_TEMPORARY",
+		4: "REDIRECT_TYPE_METHOD_PRESERVING_PERMANENT",
+	}
+	RedirectType_value = map[string]int32{
+		"REDIRECT_TYPE_UNSPECIFIED":                 0,
+		"REDIRECT_TYPE_PERMANENT":                   1,
+		"REDIRECT_TYPE_TEMPORARY":                   2,
+		"REDIRECT_TYPE_METHOD_PRESERVING_TEMPORARY": 3,
+		"REDIRECT_TYPE_METHOD_PRESERVING_PERMANENT": 4,
+	}
+)
+
+func (x RedirectType) Enum() *RedirectType {
+	p := new(RedirectType)
+	*p = x
+	return p
+}
+
+func (x RedirectType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RedirectType) Descriptor() protoreflect.EnumDescriptor {
+	return file_url_shortener_proto_enumTypes[0].Descriptor()
+}
+
+func (RedirectType) Type() protoreflect.EnumType {
+	return &file_url_shortener_proto_enumTypes[0]
+}
+
+func (x RedirectType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RedirectType.Descriptor instead.
+func (RedirectType) EnumDescriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{0}
+}
+
 type ShortenURLRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	LongUrl       string                 `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
-	ApiKey        string                 `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	LongUrl string                 `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	ApiKey  string                 `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Optional code to use instead of a generated one. Counts against the
+	// plan's custom alias quota.
+	CustomAlias string `protobuf:"bytes,3,opt,name=custom_alias,json=customAlias,proto3" json:"custom_alias,omitempty"`
+	// HTTP status used when following the link. Defaults to 302.
+	RedirectType RedirectType `protobuf:"varint,4,opt,name=redirect_type,json=redirectType,proto3,enum=url_shortener.RedirectType" json:"redirect_type,omitempty"`
+	// Merge the query string of the incoming request into the destination.
+	QueryPassthrough bool `protobuf:"varint,5,opt,name=query_passthrough,json=queryPassthrough,proto3" json:"query_passthrough,omitempty"`
+	// Append any path after the short code to the destination path.
+	ForwardPath bool `protobuf:"varint,6,opt,name=forward_path,json=forwardPath,proto3" json:"forward_path,omitempty"`
+	// Optional password visitors have to enter before being redirected.
+	Password string `protobuf:"bytes,7,opt,name=password,proto3" json:"password,omitempty"`
+	// Number of times the link may be followed before it expires, 0 for no
+	// limit. Use 1 for one-time links.
+	MaxClicks int64 `protobuf:"varint,8,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	// Always show the preview page instead of redirecting.
+	Interstitial bool `protobuf:"varint,9,opt,name=interstitial,proto3" json:"interstitial,omitempty"`
+	// Verified branded domain of the caller to serve the link on, e.g.
+	// "go.acme.com". Custom aliases only need to be unique on that domain.
+	Domain string `protobuf:"bytes,10,opt,name=domain,proto3" json:"domain,omitempty"`
+	// UTM parameters to add to long_url, replacing any it has.
+	Utm *UTMParams `protobuf:"bytes,11,opt,name=utm,proto3" json:"utm,omitempty"`
+	// Campaign of the caller to put the link in. Its UTM parameters are
+	// added to long_url where neither utm nor long_url set them.
+	CampaignId uint64 `protobuf:"varint,12,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	// Metadata link unfurls show instead of the destination's.
+	Og            *OpenGraph `protobuf:"bytes,13,opt,name=og,proto3" json:"og,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -74,9 +161,107 @@ func (x *ShortenURLRequest) GetApiKey() string {
 	return ""
 }
 
+func (x *ShortenURLRequest) GetCustomAlias() string {
+	if x != nil {
+		return x.CustomAlias
+	}
+	return ""
+}
+
+func (x *ShortenURLRequest) GetRedirectType() RedirectType {
+	if x != nil {
+		return x.RedirectType
+	}
+	return RedirectType_REDIRECT_TYPE_UNSPECIFIED
+}
+
+func (x *ShortenURLRequest) GetQueryPassthrough() bool {
+	if x != nil {
+		return x.QueryPassthrough
+	}
+	return false
+}
+
+func (x *ShortenURLRequest) GetForwardPath() bool {
+	if x != nil {
+		return x.ForwardPath
+	}
+	return false
+}
+
+func (x *ShortenURLRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *ShortenURLRequest) GetMaxClicks() int64 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *ShortenURLRequest) GetInterstitial() bool {
+	if x != nil {
+		return x.Interstitial
+	}
+	return false
+}
+
+func (x *ShortenURLRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ShortenURLRequest) GetUtm() *UTMParams {
+	if x != nil {
+		return x.Utm
+	}
+	return nil
+}
+
+func (x *ShortenURLRequest) GetCampaignId() uint64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *ShortenURLRequest) GetOg() *OpenGraph {
+	if x != nil {
+		return x.Og
+	}
+	return nil
+}
+
 type ShortenURLResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The short code, or the full URL for links on a branded domain, e.g.
+	// "https://go.acme.com/abc".
+	ShortUrl string `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// The full short link, e.g. "https://sho.rt/d/abc". Empty when neither
+	// PUBLIC_BASE_URL nor PUBLIC_HOSTS is configured.
+	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// The short code alone.
+	Code string `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	// The destination, as normalized when the link was created.
+	LongUrl   string                 `protobuf:"bytes,4,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// The link expires after max_clicks redirects, 0 when it does not expire.
+	MaxClicks       int64 `protobuf:"varint,6,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	ClicksRemaining int64 `protobuf:"varint,7,opt,name=clicks_remaining,json=clicksRemaining,proto3" json:"clicks_remaining,omitempty"`
+	// Where the QR code of the link is served. Empty for links on branded
+	// domains.
+	QrCodeUrl string `protobuf:"bytes,8,opt,name=qr_code_url,json=qrCodeUrl,proto3" json:"qr_code_url,omitempty"`
+	// Set when an existing link for an equivalent URL was returned instead of
+	// creating a new one.
+	Reused bool `protobuf:"varint,9,opt,name=reused,proto3" json:"reused,omitempty"`
+	// The UTM parameters of long_url.
+	Utm           *UTMParams `protobuf:"bytes,10,opt,name=utm,proto3" json:"utm,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -118,9 +303,74 @@ func (x *ShortenURLResponse) GetShortUrl() string {
 	return ""
 }
 
+func (x *ShortenURLResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ShortenURLResponse) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ShortenURLResponse) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *ShortenURLResponse) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ShortenURLResponse) GetMaxClicks() int64 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *ShortenURLResponse) GetClicksRemaining() int64 {
+	if x != nil {
+		return x.ClicksRemaining
+	}
+	return 0
+}
+
+func (x *ShortenURLResponse) GetQrCodeUrl() string {
+	if x != nil {
+		return x.QrCodeUrl
+	}
+	return ""
+}
+
+func (x *ShortenURLResponse) GetReused() bool {
+	if x != nil {
+		return x.Reused
+	}
+	return false
+}
+
+func (x *ShortenURLResponse) GetUtm() *UTMParams {
+	if x != nil {
+		return x.Utm
+	}
+	return nil
+}
+
 type GetURLRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Required for password protected links.
+	Password      string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -162,11 +412,28 @@ func (x *GetURLRequest) GetShortUrl() string {
 	return ""
 }
 
+func (x *GetURLRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
 type GetURLResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	LongUrl       string                 `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	LongUrl string                 `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	// Final destination when long_url goes through another short link.
+	ResolvedUrl      string       `protobuf:"bytes,2,opt,name=resolved_url,json=resolvedUrl,proto3" json:"resolved_url,omitempty"`
+	RedirectType     RedirectType `protobuf:"varint,3,opt,name=redirect_type,json=redirectType,proto3,enum=url_shortener.RedirectType" json:"redirect_type,omitempty"`
+	QueryPassthrough bool         `protobuf:"varint,4,opt,name=query_passthrough,json=queryPassthrough,proto3" json:"query_passthrough,omitempty"`
+	ForwardPath      bool         `protobuf:"varint,5,opt,name=forward_path,json=forwardPath,proto3" json:"forward_path,omitempty"`
+	// Set for click limited links. Redirects use up a click, GetURL does
+	// not; clicks_remaining is the count left.
+	MaxClicks       int64 `protobuf:"varint,6,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	ClicksRemaining int64 `protobuf:"varint,7,opt,name=clicks_remaining,json=clicksRemaining,proto3" json:"clicks_remaining,omitempty"`
+	Interstitial    bool  `protobuf:"varint,8,opt,name=interstitial,proto3" json:"interstitial,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *GetURLResponse) Reset() {
@@ -206,6 +473,55 @@ func (x *GetURLResponse) GetLongUrl() string {
 	return ""
 }
 
+func (x *GetURLResponse) GetResolvedUrl() string {
+	if x != nil {
+		return x.ResolvedUrl
+	}
+	return ""
+}
+
+func (x *GetURLResponse) GetRedirectType() RedirectType {
+	if x != nil {
+		return x.RedirectType
+	}
+	return RedirectType_REDIRECT_TYPE_UNSPECIFIED
+}
+
+func (x *GetURLResponse) GetQueryPassthrough() bool {
+	if x != nil {
+		return x.QueryPassthrough
+	}
+	return false
+}
+
+func (x *GetURLResponse) GetForwardPath() bool {
+	if x != nil {
+		return x.ForwardPath
+	}
+	return false
+}
+
+func (x *GetURLResponse) GetMaxClicks() int64 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *GetURLResponse) GetClicksRemaining() int64 {
+	if x != nil {
+		return x.ClicksRemaining
+	}
+	return 0
+}
+
+func (x *GetURLResponse) GetInterstitial() bool {
+	if x != nil {
+		return x.Interstitial
+	}
+	return false
+}
+
 type CreateUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FirstName     string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
@@ -538,110 +854,7417 @@ func (x *GetTopDomainsResponse) GetTopDomains() []*DomainMetric {
 	return nil
 }
 
-var File_url_shortener_proto protoreflect.FileDescriptor
+type GetUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_url_shortener_proto_rawDesc = "" +
-	"\n" +
-	"\x13url_shortener.proto\x12\rurl_shortener\x1a\x1cgoogle/api/annotations.proto\"G\n" +
-	"\x11ShortenURLRequest\x12\x19\n" +
-	"\blong_url\x18\x01 \x01(\tR\alongUrl\x12\x17\n" +
-	"\aapi_key\x18\x02 \x01(\tR\x06apiKey\"1\n" +
-	"\x12ShortenURLResponse\x12\x1b\n" +
-	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\",\n" +
-	"\rGetURLRequest\x12\x1b\n" +
-	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\"+\n" +
-	"\x0eGetURLResponse\x12\x19\n" +
-	"\blong_url\x18\x01 \x01(\tR\alongUrl\"e\n" +
-	"\x11CreateUserRequest\x12\x1d\n" +
-	"\n" +
-	"first_name\x18\x01 \x01(\tR\tfirstName\x12\x1b\n" +
-	"\tlast_name\x18\x02 \x01(\tR\blastName\x12\x14\n" +
-	"\x05email\x18\x03 \x01(\tR\x05email\"F\n" +
-	"\x12CreateUserResponse\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
-	"\aapi_key\x18\x02 \x01(\tR\x06apiKey\"*\n" +
-	"\x12FetchApiKeyRequest\x12\x14\n" +
-	"\x05email\x18\x01 \x01(\tR\x05email\".\n" +
-	"\x13FetchApiKeyResponse\x12\x17\n" +
-	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"<\n" +
-	"\fDomainMetric\x12\x16\n" +
-	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x14\n" +
-	"\x05count\x18\x02 \x01(\x03R\x05count\"\x16\n" +
-	"\x14GetTopDomainsRequest\"U\n" +
-	"\x15GetTopDomainsResponse\x12<\n" +
-	"\vtop_domains\x18\x01 \x03(\v2\x1b.url_shortener.DomainMetricR\n" +
-	"topDomains2\xa3\x04\n" +
-	"\fURLShortener\x12f\n" +
-	"\n" +
-	"ShortenURL\x12 .url_shortener.ShortenURLRequest\x1a!.url_shortener.ShortenURLResponse\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/shorten\x12[\n" +
-	"\x06GetURL\x12\x1c.url_shortener.GetURLRequest\x1a\x1d.url_shortener.GetURLResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/{short_url}\x12d\n" +
-	"\n" +
-	"CreateUser\x12 .url_shortener.CreateUserRequest\x1a!.url_shortener.CreateUserResponse\"\x11\x82\xd3\xe4\x93\x02\v:\x01*\"\x06/users\x12n\n" +
-	"\vFetchApiKey\x12!.url_shortener.FetchApiKeyRequest\x1a\".url_shortener.FetchApiKeyResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/api_key/{email}\x12x\n" +
-	"\rGetTopDomains\x12#.url_shortener.GetTopDomainsRequest\x1a$.url_shortener.GetTopDomainsResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/metrics/top_domainsB7Z5github.com/alt-coder/url-shortner/url-shortener/protob\x06proto3"
+func (x *GetUsageRequest) Reset() {
+	*x = GetUsageRequest{}
+	mi := &file_url_shortener_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-var (
-	file_url_shortener_proto_rawDescOnce sync.Once
-	file_url_shortener_proto_rawDescData []byte
-)
+func (x *GetUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-func file_url_shortener_proto_rawDescGZIP() []byte {
-	file_url_shortener_proto_rawDescOnce.Do(func() {
-		file_url_shortener_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_url_shortener_proto_rawDesc), len(file_url_shortener_proto_rawDesc)))
-	})
-	return file_url_shortener_proto_rawDescData
+func (*GetUsageRequest) ProtoMessage() {}
+
+func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var file_url_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
-var file_url_shortener_proto_goTypes = []any{
-	(*ShortenURLRequest)(nil),     // 0: url_shortener.ShortenURLRequest
-	(*ShortenURLResponse)(nil),    // 1: url_shortener.ShortenURLResponse
-	(*GetURLRequest)(nil),         // 2: url_shortener.GetURLRequest
-	(*GetURLResponse)(nil),        // 3: url_shortener.GetURLResponse
-	(*CreateUserRequest)(nil),     // 4: url_shortener.CreateUserRequest
-	(*CreateUserResponse)(nil),    // 5: url_shortener.CreateUserResponse
-	(*FetchApiKeyRequest)(nil),    // 6: url_shortener.FetchApiKeyRequest
-	(*FetchApiKeyResponse)(nil),   // 7: url_shortener.FetchApiKeyResponse
-	(*DomainMetric)(nil),          // 8: url_shortener.DomainMetric
-	(*GetTopDomainsRequest)(nil),  // 9: url_shortener.GetTopDomainsRequest
-	(*GetTopDomainsResponse)(nil), // 10: url_shortener.GetTopDomainsResponse
+// Deprecated: Use GetUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{11}
 }
-var file_url_shortener_proto_depIdxs = []int32{
-	8,  // 0: url_shortener.GetTopDomainsResponse.top_domains:type_name -> url_shortener.DomainMetric
-	0,  // 1: url_shortener.URLShortener.ShortenURL:input_type -> url_shortener.ShortenURLRequest
-	2,  // 2: url_shortener.URLShortener.GetURL:input_type -> url_shortener.GetURLRequest
-	4,  // 3: url_shortener.URLShortener.CreateUser:input_type -> url_shortener.CreateUserRequest
-	6,  // 4: url_shortener.URLShortener.FetchApiKey:input_type -> url_shortener.FetchApiKeyRequest
-	9,  // 5: url_shortener.URLShortener.GetTopDomains:input_type -> url_shortener.GetTopDomainsRequest
-	1,  // 6: url_shortener.URLShortener.ShortenURL:output_type -> url_shortener.ShortenURLResponse
-	3,  // 7: url_shortener.URLShortener.GetURL:output_type -> url_shortener.GetURLResponse
-	5,  // 8: url_shortener.URLShortener.CreateUser:output_type -> url_shortener.CreateUserResponse
-	7,  // 9: url_shortener.URLShortener.FetchApiKey:output_type -> url_shortener.FetchApiKeyResponse
-	10, // 10: url_shortener.URLShortener.GetTopDomains:output_type -> url_shortener.GetTopDomainsResponse
-	6,  // [6:11] is the sub-list for method output_type
-	1,  // [1:6] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+
+func (x *GetUsageRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
 }
 
-func init() { file_url_shortener_proto_init() }
-func file_url_shortener_proto_init() {
-	if File_url_shortener_proto != nil {
-		return
+type GetUsageResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Plan         string                 `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	CycleStart   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=cycle_start,json=cycleStart,proto3" json:"cycle_start,omitempty"`
+	CycleEnd     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=cycle_end,json=cycleEnd,proto3" json:"cycle_end,omitempty"`
+	LinksCreated int64                  `protobuf:"varint,4,opt,name=links_created,json=linksCreated,proto3" json:"links_created,omitempty"`
+	// Zero means unlimited.
+	MaxLinks             int64 `protobuf:"varint,5,opt,name=max_links,json=maxLinks,proto3" json:"max_links,omitempty"`
+	CustomAliasesCreated int64 `protobuf:"varint,6,opt,name=custom_aliases_created,json=customAliasesCreated,proto3" json:"custom_aliases_created,omitempty"`
+	// Zero means unlimited.
+	MaxCustomAliases       int64 `protobuf:"varint,7,opt,name=max_custom_aliases,json=maxCustomAliases,proto3" json:"max_custom_aliases,omitempty"`
+	AnalyticsRetentionDays int32 `protobuf:"varint,8,opt,name=analytics_retention_days,json=analyticsRetentionDays,proto3" json:"analytics_retention_days,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *GetUsageResponse) Reset() {
+	*x = GetUsageResponse{}
+	mi := &file_url_shortener_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageResponse) ProtoMessage() {}
+
+func (x *GetUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetUsageResponse) GetPlan() string {
+	if x != nil {
+		return x.Plan
+	}
+	return ""
+}
+
+func (x *GetUsageResponse) GetCycleStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CycleStart
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetCycleEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CycleEnd
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetLinksCreated() int64 {
+	if x != nil {
+		return x.LinksCreated
+	}
+	return 0
+}
+
+func (x *GetUsageResponse) GetMaxLinks() int64 {
+	if x != nil {
+		return x.MaxLinks
+	}
+	return 0
+}
+
+func (x *GetUsageResponse) GetCustomAliasesCreated() int64 {
+	if x != nil {
+		return x.CustomAliasesCreated
+	}
+	return 0
+}
+
+func (x *GetUsageResponse) GetMaxCustomAliases() int64 {
+	if x != nil {
+		return x.MaxCustomAliases
+	}
+	return 0
+}
+
+func (x *GetUsageResponse) GetAnalyticsRetentionDays() int32 {
+	if x != nil {
+		return x.AnalyticsRetentionDays
+	}
+	return 0
+}
+
+// PolicyRule blocks or allows destinations. kind is one of "domain",
+// "suffix", "regex" or "prefix"; action is "block" or "allow".
+type PolicyRule struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind    string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Pattern string                 `protobuf:"bytes,3,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Action  string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Reason  string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Where the rule was loaded from, e.g. "database" or "file:/etc/policy.txt".
+	Source        string `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyRule) Reset() {
+	*x = PolicyRule{}
+	mi := &file_url_shortener_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyRule) ProtoMessage() {}
+
+func (x *PolicyRule) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyRule.ProtoReflect.Descriptor instead.
+func (*PolicyRule) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PolicyRule) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PolicyRule) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *PolicyRule) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *PolicyRule) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *PolicyRule) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PolicyRule) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type AddPolicyRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Pattern       string                 `protobuf:"bytes,3,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPolicyRuleRequest) Reset() {
+	*x = AddPolicyRuleRequest{}
+	mi := &file_url_shortener_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPolicyRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPolicyRuleRequest) ProtoMessage() {}
+
+func (x *AddPolicyRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPolicyRuleRequest.ProtoReflect.Descriptor instead.
+func (*AddPolicyRuleRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AddPolicyRuleRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *AddPolicyRuleRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *AddPolicyRuleRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *AddPolicyRuleRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AddPolicyRuleRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type AddPolicyRuleResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Rule  *PolicyRule            `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	// Number of existing links disabled by a new block rule.
+	DisabledLinks int64 `protobuf:"varint,2,opt,name=disabled_links,json=disabledLinks,proto3" json:"disabled_links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPolicyRuleResponse) Reset() {
+	*x = AddPolicyRuleResponse{}
+	mi := &file_url_shortener_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPolicyRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPolicyRuleResponse) ProtoMessage() {}
+
+func (x *AddPolicyRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPolicyRuleResponse.ProtoReflect.Descriptor instead.
+func (*AddPolicyRuleResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AddPolicyRuleResponse) GetRule() *PolicyRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *AddPolicyRuleResponse) GetDisabledLinks() int64 {
+	if x != nil {
+		return x.DisabledLinks
+	}
+	return 0
+}
+
+type RemovePolicyRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePolicyRuleRequest) Reset() {
+	*x = RemovePolicyRuleRequest{}
+	mi := &file_url_shortener_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePolicyRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePolicyRuleRequest) ProtoMessage() {}
+
+func (x *RemovePolicyRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePolicyRuleRequest.ProtoReflect.Descriptor instead.
+func (*RemovePolicyRuleRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RemovePolicyRuleRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *RemovePolicyRuleRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RemovePolicyRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePolicyRuleResponse) Reset() {
+	*x = RemovePolicyRuleResponse{}
+	mi := &file_url_shortener_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePolicyRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePolicyRuleResponse) ProtoMessage() {}
+
+func (x *RemovePolicyRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePolicyRuleResponse.ProtoReflect.Descriptor instead.
+func (*RemovePolicyRuleResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{17}
+}
+
+type ListPolicyRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPolicyRulesRequest) Reset() {
+	*x = ListPolicyRulesRequest{}
+	mi := &file_url_shortener_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPolicyRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPolicyRulesRequest) ProtoMessage() {}
+
+func (x *ListPolicyRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPolicyRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListPolicyRulesRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListPolicyRulesRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type ListPolicyRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*PolicyRule          `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPolicyRulesResponse) Reset() {
+	*x = ListPolicyRulesResponse{}
+	mi := &file_url_shortener_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPolicyRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPolicyRulesResponse) ProtoMessage() {}
+
+func (x *ListPolicyRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPolicyRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListPolicyRulesResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListPolicyRulesResponse) GetRules() []*PolicyRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type GetQRCodeRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// "png" (default) or "svg".
+	Format string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	// Width and height in pixels, 256 by default.
+	Size int32 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// Error correction level: "L", "M" (default), "Q" or "H".
+	ErrorCorrection string `protobuf:"bytes,4,opt,name=error_correction,json=errorCorrection,proto3" json:"error_correction,omitempty"`
+	// Quiet zone around the code in modules, 4 by default.
+	Margin *int32 `protobuf:"varint,5,opt,name=margin,proto3,oneof" json:"margin,omitempty"`
+	// "#rrggbb" colours, black on white by default.
+	Foreground string `protobuf:"bytes,6,opt,name=foreground,proto3" json:"foreground,omitempty"`
+	Background string `protobuf:"bytes,7,opt,name=background,proto3" json:"background,omitempty"`
+	// Verified branded domain the link is on, empty for the service's own
+	// hosts.
+	Domain        string `protobuf:"bytes,8,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQRCodeRequest) Reset() {
+	*x = GetQRCodeRequest{}
+	mi := &file_url_shortener_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQRCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQRCodeRequest) ProtoMessage() {}
+
+func (x *GetQRCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQRCodeRequest.ProtoReflect.Descriptor instead.
+func (*GetQRCodeRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetQRCodeRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GetQRCodeRequest) GetErrorCorrection() string {
+	if x != nil {
+		return x.ErrorCorrection
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) GetMargin() int32 {
+	if x != nil && x.Margin != nil {
+		return *x.Margin
+	}
+	return 0
+}
+
+func (x *GetQRCodeRequest) GetForeground() string {
+	if x != nil {
+		return x.Foreground
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) GetBackground() string {
+	if x != nil {
+		return x.Background
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type BatchShortenURLsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// The api_key of the items is ignored.
+	Items         []*ShortenURLRequest `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchShortenURLsRequest) Reset() {
+	*x = BatchShortenURLsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchShortenURLsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchShortenURLsRequest) ProtoMessage() {}
+
+func (x *BatchShortenURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchShortenURLsRequest.ProtoReflect.Descriptor instead.
+func (*BatchShortenURLsRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BatchShortenURLsRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *BatchShortenURLsRequest) GetItems() []*ShortenURLRequest {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type BatchShortenResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the item in the request.
+	Index   int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	LongUrl string `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	// Set when the item was shortened.
+	ShortUrl string `protobuf:"bytes,3,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Set when the item failed.
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchShortenResult) Reset() {
+	*x = BatchShortenResult{}
+	mi := &file_url_shortener_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchShortenResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchShortenResult) ProtoMessage() {}
+
+func (x *BatchShortenResult) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchShortenResult.ProtoReflect.Descriptor instead.
+func (*BatchShortenResult) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BatchShortenResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchShortenResult) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *BatchShortenResult) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *BatchShortenResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchShortenURLsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchShortenResult  `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchShortenURLsResponse) Reset() {
+	*x = BatchShortenURLsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchShortenURLsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchShortenURLsResponse) ProtoMessage() {}
+
+func (x *BatchShortenURLsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchShortenURLsResponse.ProtoReflect.Descriptor instead.
+func (*BatchShortenURLsResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BatchShortenURLsResponse) GetResults() []*BatchShortenResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type StreamShortenRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Authenticates the stream, only read from the first message.
+	ApiKey string `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Echoed in the response to correlate it with this request.
+	RequestId string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// The api_key of the item is ignored.
+	Item          *ShortenURLRequest `protobuf:"bytes,3,opt,name=item,proto3" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamShortenRequest) Reset() {
+	*x = StreamShortenRequest{}
+	mi := &file_url_shortener_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamShortenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamShortenRequest) ProtoMessage() {}
+
+func (x *StreamShortenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamShortenRequest.ProtoReflect.Descriptor instead.
+func (*StreamShortenRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *StreamShortenRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *StreamShortenRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *StreamShortenRequest) GetItem() *ShortenURLRequest {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+type StreamShortenResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the request in the stream, counting from 0. Responses may
+	// arrive in a different order than the requests.
+	Sequence  int64  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	RequestId string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	LongUrl   string `protobuf:"bytes,3,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	// Set when the item was shortened.
+	ShortUrl string `protobuf:"bytes,4,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Set when the item failed.
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamShortenResponse) Reset() {
+	*x = StreamShortenResponse{}
+	mi := &file_url_shortener_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamShortenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamShortenResponse) ProtoMessage() {}
+
+func (x *StreamShortenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamShortenResponse.ProtoReflect.Descriptor instead.
+func (*StreamShortenResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *StreamShortenResponse) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *StreamShortenResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *StreamShortenResponse) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *StreamShortenResponse) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *StreamShortenResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type WatchClicksRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Only watch these links, all links of the caller when empty.
+	ShortUrls     []string `protobuf:"bytes,2,rep,name=short_urls,json=shortUrls,proto3" json:"short_urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchClicksRequest) Reset() {
+	*x = WatchClicksRequest{}
+	mi := &file_url_shortener_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchClicksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchClicksRequest) ProtoMessage() {}
+
+func (x *WatchClicksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchClicksRequest.ProtoReflect.Descriptor instead.
+func (*WatchClicksRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *WatchClicksRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *WatchClicksRequest) GetShortUrls() []string {
+	if x != nil {
+		return x.ShortUrls
+	}
+	return nil
+}
+
+type ClickEvent struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl  string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	ClickedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=clicked_at,json=clickedAt,proto3" json:"clicked_at,omitempty"`
+	Referrer  string                 `protobuf:"bytes,3,opt,name=referrer,proto3" json:"referrer,omitempty"`
+	UserAgent string                 `protobuf:"bytes,4,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	// Clicks left on click limited links.
+	ClicksRemaining int64 `protobuf:"varint,5,opt,name=clicks_remaining,json=clicksRemaining,proto3" json:"clicks_remaining,omitempty"`
+	// Events skipped since the previous one because the watcher fell behind.
+	Dropped uint64 `protobuf:"varint,6,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	// Location of the visitor when known.
+	Country string `protobuf:"bytes,7,opt,name=country,proto3" json:"country,omitempty"`
+	Region  string `protobuf:"bytes,8,opt,name=region,proto3" json:"region,omitempty"`
+	// Variant the visitor was sent to, 0 for links without variants.
+	VariantId     uint64 `protobuf:"varint,9,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClickEvent) Reset() {
+	*x = ClickEvent{}
+	mi := &file_url_shortener_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClickEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClickEvent) ProtoMessage() {}
+
+func (x *ClickEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClickEvent.ProtoReflect.Descriptor instead.
+func (*ClickEvent) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ClickEvent) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *ClickEvent) GetClickedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClickedAt
+	}
+	return nil
+}
+
+func (x *ClickEvent) GetReferrer() string {
+	if x != nil {
+		return x.Referrer
+	}
+	return ""
+}
+
+func (x *ClickEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *ClickEvent) GetClicksRemaining() int64 {
+	if x != nil {
+		return x.ClicksRemaining
+	}
+	return 0
+}
+
+func (x *ClickEvent) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+func (x *ClickEvent) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *ClickEvent) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *ClickEvent) GetVariantId() uint64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+type Webhook struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url   string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// Any of "link.created", "link.updated", "link.deleted", "link.expired"
+	// and "link.clicked".
+	Events        []string               `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_url_shortener_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Webhook) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Webhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Events        []string               `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_url_shortener_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookRequest) ProtoMessage() {}
+
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CreateWebhookRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type CreateWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhook       *Webhook               `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookResponse) Reset() {
+	*x = CreateWebhookResponse{}
+	mi := &file_url_shortener_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookResponse) ProtoMessage() {}
+
+func (x *CreateWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookResponse.ProtoReflect.Descriptor instead.
+func (*CreateWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CreateWebhookResponse) GetWebhook() *Webhook {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+func (x *CreateWebhookResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_url_shortener_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksRequest) ProtoMessage() {}
+
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListWebhooksRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_url_shortener_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_url_shortener_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *DeleteWebhookRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *DeleteWebhookRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookResponse) Reset() {
+	*x = DeleteWebhookResponse{}
+	mi := &file_url_shortener_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{34}
+}
+
+type WebhookDelivery struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId uint64                 `protobuf:"varint,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Event     string                 `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	// The JSON body posted.
+	Payload string `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	// "pending", "delivered", "dead" or "canceled".
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts       int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastStatusCode int32                  `protobuf:"varint,7,opt,name=last_status_code,json=lastStatusCode,proto3" json:"last_status_code,omitempty"`
+	LastError      string                 `protobuf:"bytes,8,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// When a pending delivery is attempted next.
+	NextAttemptAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	DeliveredAt   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_url_shortener_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *WebhookDelivery) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetWebhookId() uint64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastStatusCode() int32 {
+	if x != nil {
+		return x.LastStatusCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetNextAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetDeliveredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return nil
+}
+
+type ListWebhookDeliveriesRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Only deliveries of this webhook when set.
+	WebhookId uint64 `protobuf:"varint,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	// Only deliveries with this status when set.
+	Status string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	// At most this many deliveries, 50 by default.
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_url_shortener_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListWebhookDeliveriesRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetWebhookId() uint64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_url_shortener_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+type ListWebhookDeadLettersRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// At most this many dead letters, 50 by default.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeadLettersRequest) Reset() {
+	*x = ListWebhookDeadLettersRequest{}
+	mi := &file_url_shortener_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListWebhookDeadLettersRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *ListWebhookDeadLettersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListWebhookDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeadLetters   []*WebhookDelivery     `protobuf:"bytes,1,rep,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeadLettersResponse) Reset() {
+	*x = ListWebhookDeadLettersResponse{}
+	mi := &file_url_shortener_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeadLettersResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListWebhookDeadLettersResponse) GetDeadLetters() []*WebhookDelivery {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return nil
+}
+
+// Event is a domain event published to the event bus, see pkg/events.
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique ID, consumers use it to drop duplicates.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// "link.created" or "link.clicked".
+	Type       string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*Event_LinkCreated
+	//	*Event_LinkClicked
+	Payload       isEvent_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_url_shortener_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *Event) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *Event) GetPayload() isEvent_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Event) GetLinkCreated() *LinkCreated {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_LinkCreated); ok {
+			return x.LinkCreated
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetLinkClicked() *LinkClicked {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_LinkClicked); ok {
+			return x.LinkClicked
+		}
+	}
+	return nil
+}
+
+type isEvent_Payload interface {
+	isEvent_Payload()
+}
+
+type Event_LinkCreated struct {
+	LinkCreated *LinkCreated `protobuf:"bytes,10,opt,name=link_created,json=linkCreated,proto3,oneof"`
+}
+
+type Event_LinkClicked struct {
+	LinkClicked *LinkClicked `protobuf:"bytes,11,opt,name=link_clicked,json=linkClicked,proto3,oneof"`
+}
+
+func (*Event_LinkCreated) isEvent_Payload() {}
+
+func (*Event_LinkClicked) isEvent_Payload() {}
+
+type LinkCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	LongUrl       string                 `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	UserId        uint64                 `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CustomAlias   bool                   `protobuf:"varint,4,opt,name=custom_alias,json=customAlias,proto3" json:"custom_alias,omitempty"`
+	MaxClicks     int64                  `protobuf:"varint,5,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkCreated) Reset() {
+	*x = LinkCreated{}
+	mi := &file_url_shortener_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkCreated) ProtoMessage() {}
+
+func (x *LinkCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkCreated.ProtoReflect.Descriptor instead.
+func (*LinkCreated) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *LinkCreated) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *LinkCreated) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *LinkCreated) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *LinkCreated) GetCustomAlias() bool {
+	if x != nil {
+		return x.CustomAlias
+	}
+	return false
+}
+
+func (x *LinkCreated) GetMaxClicks() int64 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+type LinkClicked struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl  string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	LongUrl   string                 `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	UserId    uint64                 `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Referrer  string                 `protobuf:"bytes,4,opt,name=referrer,proto3" json:"referrer,omitempty"`
+	UserAgent string                 `protobuf:"bytes,5,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	// Clicks left on click limited links.
+	ClicksRemaining int64 `protobuf:"varint,6,opt,name=clicks_remaining,json=clicksRemaining,proto3" json:"clicks_remaining,omitempty"`
+	// Location of the visitor when known.
+	Country string `protobuf:"bytes,7,opt,name=country,proto3" json:"country,omitempty"`
+	Region  string `protobuf:"bytes,8,opt,name=region,proto3" json:"region,omitempty"`
+	// Variant the visitor was sent to, 0 for links without variants.
+	VariantId     uint64 `protobuf:"varint,9,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkClicked) Reset() {
+	*x = LinkClicked{}
+	mi := &file_url_shortener_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkClicked) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkClicked) ProtoMessage() {}
+
+func (x *LinkClicked) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkClicked.ProtoReflect.Descriptor instead.
+func (*LinkClicked) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *LinkClicked) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *LinkClicked) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *LinkClicked) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *LinkClicked) GetReferrer() string {
+	if x != nil {
+		return x.Referrer
+	}
+	return ""
+}
+
+func (x *LinkClicked) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *LinkClicked) GetClicksRemaining() int64 {
+	if x != nil {
+		return x.ClicksRemaining
+	}
+	return 0
+}
+
+func (x *LinkClicked) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *LinkClicked) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *LinkClicked) GetVariantId() uint64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+type Domain struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Host     string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Verified bool                   `protobuf:"varint,3,opt,name=verified,proto3" json:"verified,omitempty"`
+	// Publish a TXT record named verification_record with the value
+	// verification_value to verify the domain.
+	VerificationRecord string                 `protobuf:"bytes,4,opt,name=verification_record,json=verificationRecord,proto3" json:"verification_record,omitempty"`
+	VerificationValue  string                 `protobuf:"bytes,5,opt,name=verification_value,json=verificationValue,proto3" json:"verification_value,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	VerifiedAt         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=verified_at,json=verifiedAt,proto3" json:"verified_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Domain) Reset() {
+	*x = Domain{}
+	mi := &file_url_shortener_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Domain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Domain) ProtoMessage() {}
+
+func (x *Domain) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Domain.ProtoReflect.Descriptor instead.
+func (*Domain) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *Domain) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Domain) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *Domain) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *Domain) GetVerificationRecord() string {
+	if x != nil {
+		return x.VerificationRecord
+	}
+	return ""
+}
+
+func (x *Domain) GetVerificationValue() string {
+	if x != nil {
+		return x.VerificationValue
+	}
+	return ""
+}
+
+func (x *Domain) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Domain) GetVerifiedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.VerifiedAt
+	}
+	return nil
+}
+
+type CreateDomainRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Host name to serve links on, e.g. "go.acme.com". Point it at this
+	// service with a CNAME record.
+	Host          string `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDomainRequest) Reset() {
+	*x = CreateDomainRequest{}
+	mi := &file_url_shortener_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDomainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDomainRequest) ProtoMessage() {}
+
+func (x *CreateDomainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDomainRequest.ProtoReflect.Descriptor instead.
+func (*CreateDomainRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *CreateDomainRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *CreateDomainRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+type CreateDomainResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDomainResponse) Reset() {
+	*x = CreateDomainResponse{}
+	mi := &file_url_shortener_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDomainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDomainResponse) ProtoMessage() {}
+
+func (x *CreateDomainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDomainResponse.ProtoReflect.Descriptor instead.
+func (*CreateDomainResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *CreateDomainResponse) GetDomain() *Domain {
+	if x != nil {
+		return x.Domain
+	}
+	return nil
+}
+
+type ListDomainsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDomainsRequest) Reset() {
+	*x = ListDomainsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDomainsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDomainsRequest) ProtoMessage() {}
+
+func (x *ListDomainsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDomainsRequest.ProtoReflect.Descriptor instead.
+func (*ListDomainsRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ListDomainsRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type ListDomainsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domains       []*Domain              `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDomainsResponse) Reset() {
+	*x = ListDomainsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDomainsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDomainsResponse) ProtoMessage() {}
+
+func (x *ListDomainsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDomainsResponse.ProtoReflect.Descriptor instead.
+func (*ListDomainsResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ListDomainsResponse) GetDomains() []*Domain {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+type VerifyDomainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyDomainRequest) Reset() {
+	*x = VerifyDomainRequest{}
+	mi := &file_url_shortener_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyDomainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyDomainRequest) ProtoMessage() {}
+
+func (x *VerifyDomainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyDomainRequest.ProtoReflect.Descriptor instead.
+func (*VerifyDomainRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *VerifyDomainRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *VerifyDomainRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type VerifyDomainResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Domain        *Domain                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyDomainResponse) Reset() {
+	*x = VerifyDomainResponse{}
+	mi := &file_url_shortener_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyDomainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyDomainResponse) ProtoMessage() {}
+
+func (x *VerifyDomainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyDomainResponse.ProtoReflect.Descriptor instead.
+func (*VerifyDomainResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *VerifyDomainResponse) GetDomain() *Domain {
+	if x != nil {
+		return x.Domain
+	}
+	return nil
+}
+
+type DeleteDomainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDomainRequest) Reset() {
+	*x = DeleteDomainRequest{}
+	mi := &file_url_shortener_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDomainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDomainRequest) ProtoMessage() {}
+
+func (x *DeleteDomainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDomainRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDomainRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *DeleteDomainRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *DeleteDomainRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteDomainResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDomainResponse) Reset() {
+	*x = DeleteDomainResponse{}
+	mi := &file_url_shortener_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDomainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDomainResponse) ProtoMessage() {}
+
+func (x *DeleteDomainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDomainResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDomainResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{51}
+}
+
+// A RoutingRule matches visitors by their User-Agent and location. Empty
+// conditions match anything; at least one is set.
+type RoutingRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Rules are tried by ascending priority, then age. The first matching
+	// one is applied; visitors matching none get the long URL.
+	Priority int32 `protobuf:"varint,2,opt,name=priority,proto3" json:"priority,omitempty"`
+	// One of "ios", "android", "windows", "macos", "linux", "chromeos".
+	Os string `protobuf:"bytes,3,opt,name=os,proto3" json:"os,omitempty"`
+	// One of "mobile", "tablet", "desktop", "bot".
+	Device string `protobuf:"bytes,4,opt,name=device,proto3" json:"device,omitempty"`
+	// One of "chrome", "safari", "firefox", "edge", "opera", "samsung".
+	Browser string `protobuf:"bytes,5,opt,name=browser,proto3" json:"browser,omitempty"`
+	// An http(s) URL or an app deep link such as "myapp://item/42".
+	Destination string                 `protobuf:"bytes,6,opt,name=destination,proto3" json:"destination,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// ISO 3166-1 alpha-2 country code of the visitor, e.g. "DE".
+	Country string `protobuf:"bytes,8,opt,name=country,proto3" json:"country,omitempty"`
+	// ISO 3166-2 region code of the visitor, e.g. "US-CA".
+	Region        string `protobuf:"bytes,9,opt,name=region,proto3" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoutingRule) Reset() {
+	*x = RoutingRule{}
+	mi := &file_url_shortener_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoutingRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingRule) ProtoMessage() {}
+
+func (x *RoutingRule) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingRule.ProtoReflect.Descriptor instead.
+func (*RoutingRule) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *RoutingRule) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RoutingRule) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *RoutingRule) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetBrowser() string {
+	if x != nil {
+		return x.Browser
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *RoutingRule) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *RoutingRule) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+type AddRoutingRuleRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey   string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	ShortUrl string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Branded domain of the link, empty for links on this service's hosts.
+	Domain        string       `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	Rule          *RoutingRule `protobuf:"bytes,4,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRoutingRuleRequest) Reset() {
+	*x = AddRoutingRuleRequest{}
+	mi := &file_url_shortener_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRoutingRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRoutingRuleRequest) ProtoMessage() {}
+
+func (x *AddRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*AddRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *AddRoutingRuleRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *AddRoutingRuleRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *AddRoutingRuleRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *AddRoutingRuleRequest) GetRule() *RoutingRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type AddRoutingRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *RoutingRule           `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRoutingRuleResponse) Reset() {
+	*x = AddRoutingRuleResponse{}
+	mi := &file_url_shortener_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRoutingRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRoutingRuleResponse) ProtoMessage() {}
+
+func (x *AddRoutingRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRoutingRuleResponse.ProtoReflect.Descriptor instead.
+func (*AddRoutingRuleResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *AddRoutingRuleResponse) GetRule() *RoutingRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type ListRoutingRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoutingRulesRequest) Reset() {
+	*x = ListRoutingRulesRequest{}
+	mi := &file_url_shortener_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoutingRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoutingRulesRequest) ProtoMessage() {}
+
+func (x *ListRoutingRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoutingRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListRoutingRulesRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ListRoutingRulesRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *ListRoutingRulesRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *ListRoutingRulesRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type ListRoutingRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*RoutingRule         `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoutingRulesResponse) Reset() {
+	*x = ListRoutingRulesResponse{}
+	mi := &file_url_shortener_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoutingRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoutingRulesResponse) ProtoMessage() {}
+
+func (x *ListRoutingRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoutingRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListRoutingRulesResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ListRoutingRulesResponse) GetRules() []*RoutingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type DeleteRoutingRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	Id            uint64                 `protobuf:"varint,4,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRoutingRuleRequest) Reset() {
+	*x = DeleteRoutingRuleRequest{}
+	mi := &file_url_shortener_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoutingRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoutingRuleRequest) ProtoMessage() {}
+
+func (x *DeleteRoutingRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoutingRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRoutingRuleRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *DeleteRoutingRuleRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *DeleteRoutingRuleRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *DeleteRoutingRuleRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *DeleteRoutingRuleRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteRoutingRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRoutingRuleResponse) Reset() {
+	*x = DeleteRoutingRuleResponse{}
+	mi := &file_url_shortener_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoutingRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoutingRuleResponse) ProtoMessage() {}
+
+func (x *DeleteRoutingRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoutingRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRoutingRuleResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{58}
+}
+
+// A LinkVariant is one of the destinations a link splits its traffic
+// across.
+type LinkVariant struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Destination string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	// Share of the visitors relative to the other variants, e.g. 70 and 30.
+	Weight int32 `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	// Visitors sent to the variant.
+	Clicks      int64 `protobuf:"varint,4,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	Conversions int64 `protobuf:"varint,5,opt,name=conversions,proto3" json:"conversions,omitempty"`
+	// conversions / clicks, 0 before the first click.
+	ConversionRate float64 `protobuf:"fixed64,6,opt,name=conversion_rate,json=conversionRate,proto3" json:"conversion_rate,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *LinkVariant) Reset() {
+	*x = LinkVariant{}
+	mi := &file_url_shortener_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkVariant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkVariant) ProtoMessage() {}
+
+func (x *LinkVariant) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkVariant.ProtoReflect.Descriptor instead.
+func (*LinkVariant) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *LinkVariant) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LinkVariant) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *LinkVariant) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *LinkVariant) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *LinkVariant) GetConversions() int64 {
+	if x != nil {
+		return x.Conversions
+	}
+	return 0
+}
+
+func (x *LinkVariant) GetConversionRate() float64 {
+	if x != nil {
+		return x.ConversionRate
+	}
+	return 0
+}
+
+type SetLinkVariantsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey   string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	ShortUrl string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Branded domain of the link, empty for links on this service's hosts.
+	Domain string `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Only destination and weight are read. A variant with the destination
+	// of an existing one keeps its ID and counters.
+	Variants []*LinkVariant `protobuf:"bytes,4,rep,name=variants,proto3" json:"variants,omitempty"`
+	// How visitors keep their variant: "cookie" (default) or "ip".
+	Stickiness    string `protobuf:"bytes,5,opt,name=stickiness,proto3" json:"stickiness,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkVariantsRequest) Reset() {
+	*x = SetLinkVariantsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkVariantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkVariantsRequest) ProtoMessage() {}
+
+func (x *SetLinkVariantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLinkVariantsRequest.ProtoReflect.Descriptor instead.
+func (*SetLinkVariantsRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *SetLinkVariantsRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *SetLinkVariantsRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *SetLinkVariantsRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *SetLinkVariantsRequest) GetVariants() []*LinkVariant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *SetLinkVariantsRequest) GetStickiness() string {
+	if x != nil {
+		return x.Stickiness
+	}
+	return ""
+}
+
+type SetLinkVariantsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variants      []*LinkVariant         `protobuf:"bytes,1,rep,name=variants,proto3" json:"variants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkVariantsResponse) Reset() {
+	*x = SetLinkVariantsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkVariantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkVariantsResponse) ProtoMessage() {}
+
+func (x *SetLinkVariantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLinkVariantsResponse.ProtoReflect.Descriptor instead.
+func (*SetLinkVariantsResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *SetLinkVariantsResponse) GetVariants() []*LinkVariant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+type RecordConversionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	VariantId     uint64                 `protobuf:"varint,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordConversionRequest) Reset() {
+	*x = RecordConversionRequest{}
+	mi := &file_url_shortener_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordConversionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordConversionRequest) ProtoMessage() {}
+
+func (x *RecordConversionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordConversionRequest.ProtoReflect.Descriptor instead.
+func (*RecordConversionRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *RecordConversionRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *RecordConversionRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *RecordConversionRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *RecordConversionRequest) GetVariantId() uint64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+type RecordConversionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecordConversionResponse) Reset() {
+	*x = RecordConversionResponse{}
+	mi := &file_url_shortener_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordConversionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordConversionResponse) ProtoMessage() {}
+
+func (x *RecordConversionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordConversionResponse.ProtoReflect.Descriptor instead.
+func (*RecordConversionResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{63}
+}
+
+type GetLinkStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkStatsRequest) Reset() {
+	*x = GetLinkStatsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkStatsRequest) ProtoMessage() {}
+
+func (x *GetLinkStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetLinkStatsRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *GetLinkStatsRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *GetLinkStatsRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *GetLinkStatsRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type GetLinkStatsResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl  string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	LongUrl   string                 `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Clicks left on click limited links.
+	ClicksRemaining int64          `protobuf:"varint,4,opt,name=clicks_remaining,json=clicksRemaining,proto3" json:"clicks_remaining,omitempty"`
+	Stickiness      string         `protobuf:"bytes,5,opt,name=stickiness,proto3" json:"stickiness,omitempty"`
+	Variants        []*LinkVariant `protobuf:"bytes,6,rep,name=variants,proto3" json:"variants,omitempty"`
+	// Redirects served, including those continued from a preview page.
+	// Counted in batches, so the last few seconds may be missing.
+	Clicks   int64        `protobuf:"varint,7,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	Campaign *LinkGroup   `protobuf:"bytes,8,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	Folder   *LinkGroup   `protobuf:"bytes,9,opt,name=folder,proto3" json:"folder,omitempty"`
+	Tags     []*LinkGroup `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	// The UTM parameters of long_url.
+	Utm *UTMParams `protobuf:"bytes,11,opt,name=utm,proto3" json:"utm,omitempty"`
+	Og  *OpenGraph `protobuf:"bytes,12,opt,name=og,proto3" json:"og,omitempty"`
+	// Unset until the destination was checked.
+	Health        *LinkHealth `protobuf:"bytes,13,opt,name=health,proto3" json:"health,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkStatsResponse) Reset() {
+	*x = GetLinkStatsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkStatsResponse) ProtoMessage() {}
+
+func (x *GetLinkStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetLinkStatsResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *GetLinkStatsResponse) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *GetLinkStatsResponse) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *GetLinkStatsResponse) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetClicksRemaining() int64 {
+	if x != nil {
+		return x.ClicksRemaining
+	}
+	return 0
+}
+
+func (x *GetLinkStatsResponse) GetStickiness() string {
+	if x != nil {
+		return x.Stickiness
+	}
+	return ""
+}
+
+func (x *GetLinkStatsResponse) GetVariants() []*LinkVariant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *GetLinkStatsResponse) GetCampaign() *LinkGroup {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetFolder() *LinkGroup {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetTags() []*LinkGroup {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetUtm() *UTMParams {
+	if x != nil {
+		return x.Utm
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetOg() *OpenGraph {
+	if x != nil {
+		return x.Og
+	}
+	return nil
+}
+
+func (x *GetLinkStatsResponse) GetHealth() *LinkHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+// A ScheduledDestination is where a link points from its activation time
+// until the next one of its schedule.
+type ScheduledDestination struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Destination string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	// Local date and time the destination takes over, e.g.
+	// "2025-06-01T09:00", read in time_zone. An RFC 3339 time with an offset
+	// is accepted as well.
+	ActivatesAt string `protobuf:"bytes,3,opt,name=activates_at,json=activatesAt,proto3" json:"activates_at,omitempty"`
+	// IANA time zone of activates_at, e.g. "Europe/Berlin". Defaults to UTC.
+	TimeZone string `protobuf:"bytes,4,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// The instant the destination takes over. Output only.
+	ActivationTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=activation_time,json=activationTime,proto3" json:"activation_time,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ScheduledDestination) Reset() {
+	*x = ScheduledDestination{}
+	mi := &file_url_shortener_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledDestination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledDestination) ProtoMessage() {}
+
+func (x *ScheduledDestination) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledDestination.ProtoReflect.Descriptor instead.
+func (*ScheduledDestination) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ScheduledDestination) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ScheduledDestination) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *ScheduledDestination) GetActivatesAt() string {
+	if x != nil {
+		return x.ActivatesAt
+	}
+	return ""
+}
+
+func (x *ScheduledDestination) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *ScheduledDestination) GetActivationTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActivationTime
+	}
+	return nil
+}
+
+type SetLinkScheduleRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey   string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	ShortUrl string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Branded domain of the link, empty for links on this service's hosts.
+	Domain string `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Only destination, activates_at and time_zone are read.
+	Schedule      []*ScheduledDestination `protobuf:"bytes,4,rep,name=schedule,proto3" json:"schedule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkScheduleRequest) Reset() {
+	*x = SetLinkScheduleRequest{}
+	mi := &file_url_shortener_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkScheduleRequest) ProtoMessage() {}
+
+func (x *SetLinkScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLinkScheduleRequest.ProtoReflect.Descriptor instead.
+func (*SetLinkScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *SetLinkScheduleRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *SetLinkScheduleRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *SetLinkScheduleRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *SetLinkScheduleRequest) GetSchedule() []*ScheduledDestination {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+type SetLinkScheduleResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The schedule by activation time.
+	Schedule      []*ScheduledDestination `protobuf:"bytes,1,rep,name=schedule,proto3" json:"schedule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkScheduleResponse) Reset() {
+	*x = SetLinkScheduleResponse{}
+	mi := &file_url_shortener_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkScheduleResponse) ProtoMessage() {}
+
+func (x *SetLinkScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLinkScheduleResponse.ProtoReflect.Descriptor instead.
+func (*SetLinkScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *SetLinkScheduleResponse) GetSchedule() []*ScheduledDestination {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+type GetLinkScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkScheduleRequest) Reset() {
+	*x = GetLinkScheduleRequest{}
+	mi := &file_url_shortener_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkScheduleRequest) ProtoMessage() {}
+
+func (x *GetLinkScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetLinkScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *GetLinkScheduleRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *GetLinkScheduleRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *GetLinkScheduleRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type GetLinkScheduleResponse struct {
+	state    protoimpl.MessageState  `protogen:"open.v1"`
+	Schedule []*ScheduledDestination `protobuf:"bytes,1,rep,name=schedule,proto3" json:"schedule,omitempty"`
+	// Where the link points now: the last activated destination, else the
+	// long URL.
+	CurrentDestination string `protobuf:"bytes,2,opt,name=current_destination,json=currentDestination,proto3" json:"current_destination,omitempty"`
+	// When the destination changes next, unset when it no longer does.
+	NextChange    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=next_change,json=nextChange,proto3" json:"next_change,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkScheduleResponse) Reset() {
+	*x = GetLinkScheduleResponse{}
+	mi := &file_url_shortener_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkScheduleResponse) ProtoMessage() {}
+
+func (x *GetLinkScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkScheduleResponse.ProtoReflect.Descriptor instead.
+func (*GetLinkScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *GetLinkScheduleResponse) GetSchedule() []*ScheduledDestination {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+func (x *GetLinkScheduleResponse) GetCurrentDestination() string {
+	if x != nil {
+		return x.CurrentDestination
+	}
+	return ""
+}
+
+func (x *GetLinkScheduleResponse) GetNextChange() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextChange
+	}
+	return nil
+}
+
+// A LinkGroup organizes links. A link may have any number of tags, but is
+// in at most one campaign and one folder.
+type LinkGroup struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// "tag", "campaign" or "folder".
+	Kind      string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name      string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// UTM parameters links created in a campaign inherit.
+	Utm           *UTMParams `protobuf:"bytes,5,opt,name=utm,proto3" json:"utm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkGroup) Reset() {
+	*x = LinkGroup{}
+	mi := &file_url_shortener_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkGroup) ProtoMessage() {}
+
+func (x *LinkGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkGroup.ProtoReflect.Descriptor instead.
+func (*LinkGroup) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *LinkGroup) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LinkGroup) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *LinkGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LinkGroup) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *LinkGroup) GetUtm() *UTMParams {
+	if x != nil {
+		return x.Utm
+	}
+	return nil
+}
+
+type CreateLinkGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLinkGroupRequest) Reset() {
+	*x = CreateLinkGroupRequest{}
+	mi := &file_url_shortener_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLinkGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLinkGroupRequest) ProtoMessage() {}
+
+func (x *CreateLinkGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLinkGroupRequest.ProtoReflect.Descriptor instead.
+func (*CreateLinkGroupRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *CreateLinkGroupRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *CreateLinkGroupRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *CreateLinkGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateLinkGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *LinkGroup             `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLinkGroupResponse) Reset() {
+	*x = CreateLinkGroupResponse{}
+	mi := &file_url_shortener_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLinkGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLinkGroupResponse) ProtoMessage() {}
+
+func (x *CreateLinkGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLinkGroupResponse.ProtoReflect.Descriptor instead.
+func (*CreateLinkGroupResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *CreateLinkGroupResponse) GetGroup() *LinkGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type RenameLinkGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameLinkGroupRequest) Reset() {
+	*x = RenameLinkGroupRequest{}
+	mi := &file_url_shortener_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameLinkGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameLinkGroupRequest) ProtoMessage() {}
+
+func (x *RenameLinkGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameLinkGroupRequest.ProtoReflect.Descriptor instead.
+func (*RenameLinkGroupRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *RenameLinkGroupRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *RenameLinkGroupRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RenameLinkGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RenameLinkGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *LinkGroup             `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameLinkGroupResponse) Reset() {
+	*x = RenameLinkGroupResponse{}
+	mi := &file_url_shortener_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameLinkGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameLinkGroupResponse) ProtoMessage() {}
+
+func (x *RenameLinkGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameLinkGroupResponse.ProtoReflect.Descriptor instead.
+func (*RenameLinkGroupResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *RenameLinkGroupResponse) GetGroup() *LinkGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type ListLinkGroupsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Only list groups of this kind, all when empty.
+	Kind          string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinkGroupsRequest) Reset() {
+	*x = ListLinkGroupsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinkGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinkGroupsRequest) ProtoMessage() {}
+
+func (x *ListLinkGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinkGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListLinkGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ListLinkGroupsRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *ListLinkGroupsRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type ListLinkGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*LinkGroup           `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinkGroupsResponse) Reset() {
+	*x = ListLinkGroupsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinkGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinkGroupsResponse) ProtoMessage() {}
+
+func (x *ListLinkGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinkGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListLinkGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *ListLinkGroupsResponse) GetGroups() []*LinkGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type AssignLinksRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// The group to assign the links to.
+	Id uint64 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Branded domain of the links, empty for links on this service's hosts.
+	Domain    string   `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	ShortUrls []string `protobuf:"bytes,4,rep,name=short_urls,json=shortUrls,proto3" json:"short_urls,omitempty"`
+	// Remove the links from the group instead. Links in another campaign or
+	// folder are left there.
+	Unassign      bool `protobuf:"varint,5,opt,name=unassign,proto3" json:"unassign,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignLinksRequest) Reset() {
+	*x = AssignLinksRequest{}
+	mi := &file_url_shortener_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignLinksRequest) ProtoMessage() {}
+
+func (x *AssignLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignLinksRequest.ProtoReflect.Descriptor instead.
+func (*AssignLinksRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *AssignLinksRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *AssignLinksRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AssignLinksRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *AssignLinksRequest) GetShortUrls() []string {
+	if x != nil {
+		return x.ShortUrls
+	}
+	return nil
+}
+
+func (x *AssignLinksRequest) GetUnassign() bool {
+	if x != nil {
+		return x.Unassign
+	}
+	return false
+}
+
+type AssignLinksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Links whose group changed.
+	Updated       int64 `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignLinksResponse) Reset() {
+	*x = AssignLinksResponse{}
+	mi := &file_url_shortener_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignLinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignLinksResponse) ProtoMessage() {}
+
+func (x *AssignLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignLinksResponse.ProtoReflect.Descriptor instead.
+func (*AssignLinksResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *AssignLinksResponse) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+// A Link is a short link of the caller as listed by ListLinks.
+type Link struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The short code, or the full URL for links on a branded domain.
+	ShortUrl  string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	LongUrl   string                 `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Clicks    int64                  `protobuf:"varint,4,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	Campaign  *LinkGroup             `protobuf:"bytes,5,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	Folder    *LinkGroup             `protobuf:"bytes,6,opt,name=folder,proto3" json:"folder,omitempty"`
+	Tags      []*LinkGroup           `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	Utm       *UTMParams             `protobuf:"bytes,8,opt,name=utm,proto3" json:"utm,omitempty"`
+	// Unset until the destination was checked.
+	Health        *LinkHealth `protobuf:"bytes,9,opt,name=health,proto3" json:"health,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Link) Reset() {
+	*x = Link{}
+	mi := &file_url_shortener_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Link) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Link) ProtoMessage() {}
+
+func (x *Link) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Link.ProtoReflect.Descriptor instead.
+func (*Link) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *Link) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *Link) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *Link) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Link) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *Link) GetCampaign() *LinkGroup {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *Link) GetFolder() *LinkGroup {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+func (x *Link) GetTags() []*LinkGroup {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Link) GetUtm() *UTMParams {
+	if x != nil {
+		return x.Utm
+	}
+	return nil
+}
+
+func (x *Link) GetHealth() *LinkHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+type ListLinksRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Only list links with all of the given groups.
+	TagId      uint64 `protobuf:"varint,2,opt,name=tag_id,json=tagId,proto3" json:"tag_id,omitempty"`
+	CampaignId uint64 `protobuf:"varint,3,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	FolderId   uint64 `protobuf:"varint,4,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	// At most 1000, 50 when unset.
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page.
+	PageToken     string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinksRequest) Reset() {
+	*x = ListLinksRequest{}
+	mi := &file_url_shortener_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinksRequest) ProtoMessage() {}
+
+func (x *ListLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinksRequest.ProtoReflect.Descriptor instead.
+func (*ListLinksRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *ListLinksRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *ListLinksRequest) GetTagId() uint64 {
+	if x != nil {
+		return x.TagId
+	}
+	return 0
+}
+
+func (x *ListLinksRequest) GetCampaignId() uint64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+func (x *ListLinksRequest) GetFolderId() uint64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *ListLinksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListLinksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListLinksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Links []*Link                `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	// Pass as page_token to get the next page, empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinksResponse) Reset() {
+	*x = ListLinksResponse{}
+	mi := &file_url_shortener_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinksResponse) ProtoMessage() {}
+
+func (x *ListLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinksResponse.ProtoReflect.Descriptor instead.
+func (*ListLinksResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *ListLinksResponse) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *ListLinksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetLinkGroupStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkGroupStatsRequest) Reset() {
+	*x = GetLinkGroupStatsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkGroupStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkGroupStatsRequest) ProtoMessage() {}
+
+func (x *GetLinkGroupStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkGroupStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetLinkGroupStatsRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *GetLinkGroupStatsRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *GetLinkGroupStatsRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetLinkGroupStatsResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Group  *LinkGroup             `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Links  int64                  `protobuf:"varint,2,opt,name=links,proto3" json:"links,omitempty"`
+	Clicks int64                  `protobuf:"varint,3,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	// Conversions reported for the variants of the links.
+	Conversions int64 `protobuf:"varint,4,opt,name=conversions,proto3" json:"conversions,omitempty"`
+	// The most clicked links of the group.
+	TopLinks []*Link `protobuf:"bytes,5,rep,name=top_links,json=topLinks,proto3" json:"top_links,omitempty"`
+	// The destination domains of the group's links by clicks.
+	TopDomains    []*DomainMetric `protobuf:"bytes,6,rep,name=top_domains,json=topDomains,proto3" json:"top_domains,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkGroupStatsResponse) Reset() {
+	*x = GetLinkGroupStatsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkGroupStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkGroupStatsResponse) ProtoMessage() {}
+
+func (x *GetLinkGroupStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkGroupStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetLinkGroupStatsResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *GetLinkGroupStatsResponse) GetGroup() *LinkGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+func (x *GetLinkGroupStatsResponse) GetLinks() int64 {
+	if x != nil {
+		return x.Links
+	}
+	return 0
+}
+
+func (x *GetLinkGroupStatsResponse) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *GetLinkGroupStatsResponse) GetConversions() int64 {
+	if x != nil {
+		return x.Conversions
+	}
+	return 0
+}
+
+func (x *GetLinkGroupStatsResponse) GetTopLinks() []*Link {
+	if x != nil {
+		return x.TopLinks
+	}
+	return nil
+}
+
+func (x *GetLinkGroupStatsResponse) GetTopDomains() []*DomainMetric {
+	if x != nil {
+		return x.TopDomains
+	}
+	return nil
+}
+
+// UTMParams are the utm_* query parameters of a destination URL.
+type UTMParams struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Medium        string                 `protobuf:"bytes,2,opt,name=medium,proto3" json:"medium,omitempty"`
+	Campaign      string                 `protobuf:"bytes,3,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	Term          string                 `protobuf:"bytes,4,opt,name=term,proto3" json:"term,omitempty"`
+	Content       string                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UTMParams) Reset() {
+	*x = UTMParams{}
+	mi := &file_url_shortener_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UTMParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UTMParams) ProtoMessage() {}
+
+func (x *UTMParams) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UTMParams.ProtoReflect.Descriptor instead.
+func (*UTMParams) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *UTMParams) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *UTMParams) GetMedium() string {
+	if x != nil {
+		return x.Medium
+	}
+	return ""
+}
+
+func (x *UTMParams) GetCampaign() string {
+	if x != nil {
+		return x.Campaign
+	}
+	return ""
+}
+
+func (x *UTMParams) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *UTMParams) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type SetCampaignUTMRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// The campaign.
+	Id uint64 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Empty fields are not inherited.
+	Utm           *UTMParams `protobuf:"bytes,3,opt,name=utm,proto3" json:"utm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCampaignUTMRequest) Reset() {
+	*x = SetCampaignUTMRequest{}
+	mi := &file_url_shortener_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCampaignUTMRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCampaignUTMRequest) ProtoMessage() {}
+
+func (x *SetCampaignUTMRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCampaignUTMRequest.ProtoReflect.Descriptor instead.
+func (*SetCampaignUTMRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *SetCampaignUTMRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *SetCampaignUTMRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SetCampaignUTMRequest) GetUtm() *UTMParams {
+	if x != nil {
+		return x.Utm
+	}
+	return nil
+}
+
+type SetCampaignUTMResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *LinkGroup             `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCampaignUTMResponse) Reset() {
+	*x = SetCampaignUTMResponse{}
+	mi := &file_url_shortener_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCampaignUTMResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCampaignUTMResponse) ProtoMessage() {}
+
+func (x *SetCampaignUTMResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCampaignUTMResponse.ProtoReflect.Descriptor instead.
+func (*SetCampaignUTMResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *SetCampaignUTMResponse) GetGroup() *LinkGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+// A UTMRule requires links to a domain, or its subdomains, to have some
+// UTM parameters.
+type UTMRule struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Domain string                 `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Parameter names, e.g. "utm_source" or "source".
+	Required      []string `protobuf:"bytes,2,rep,name=required,proto3" json:"required,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UTMRule) Reset() {
+	*x = UTMRule{}
+	mi := &file_url_shortener_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UTMRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UTMRule) ProtoMessage() {}
+
+func (x *UTMRule) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UTMRule.ProtoReflect.Descriptor instead.
+func (*UTMRule) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *UTMRule) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *UTMRule) GetRequired() []string {
+	if x != nil {
+		return x.Required
+	}
+	return nil
+}
+
+type SetUTMRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Rules         []*UTMRule             `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUTMRulesRequest) Reset() {
+	*x = SetUTMRulesRequest{}
+	mi := &file_url_shortener_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUTMRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUTMRulesRequest) ProtoMessage() {}
+
+func (x *SetUTMRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUTMRulesRequest.ProtoReflect.Descriptor instead.
+func (*SetUTMRulesRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *SetUTMRulesRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *SetUTMRulesRequest) GetRules() []*UTMRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type SetUTMRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*UTMRule             `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUTMRulesResponse) Reset() {
+	*x = SetUTMRulesResponse{}
+	mi := &file_url_shortener_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUTMRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUTMRulesResponse) ProtoMessage() {}
+
+func (x *SetUTMRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUTMRulesResponse.ProtoReflect.Descriptor instead.
+func (*SetUTMRulesResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *SetUTMRulesResponse) GetRules() []*UTMRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type ListUTMRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUTMRulesRequest) Reset() {
+	*x = ListUTMRulesRequest{}
+	mi := &file_url_shortener_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUTMRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUTMRulesRequest) ProtoMessage() {}
+
+func (x *ListUTMRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUTMRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListUTMRulesRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *ListUTMRulesRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type ListUTMRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*UTMRule             `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUTMRulesResponse) Reset() {
+	*x = ListUTMRulesResponse{}
+	mi := &file_url_shortener_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUTMRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUTMRulesResponse) ProtoMessage() {}
+
+func (x *ListUTMRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUTMRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListUTMRulesResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *ListUTMRulesResponse) GetRules() []*UTMRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type GetUTMStatsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Only count links of this campaign, all when unset.
+	CampaignId    uint64 `protobuf:"varint,2,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUTMStatsRequest) Reset() {
+	*x = GetUTMStatsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUTMStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUTMStatsRequest) ProtoMessage() {}
+
+func (x *GetUTMStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUTMStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetUTMStatsRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *GetUTMStatsRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *GetUTMStatsRequest) GetCampaignId() uint64 {
+	if x != nil {
+		return x.CampaignId
+	}
+	return 0
+}
+
+// UTMStat counts the links with a UTM source and medium, either empty for
+// links without it.
+type UTMStat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Medium        string                 `protobuf:"bytes,2,opt,name=medium,proto3" json:"medium,omitempty"`
+	Links         int64                  `protobuf:"varint,3,opt,name=links,proto3" json:"links,omitempty"`
+	Clicks        int64                  `protobuf:"varint,4,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UTMStat) Reset() {
+	*x = UTMStat{}
+	mi := &file_url_shortener_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UTMStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UTMStat) ProtoMessage() {}
+
+func (x *UTMStat) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UTMStat.ProtoReflect.Descriptor instead.
+func (*UTMStat) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *UTMStat) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *UTMStat) GetMedium() string {
+	if x != nil {
+		return x.Medium
+	}
+	return ""
+}
+
+func (x *UTMStat) GetLinks() int64 {
+	if x != nil {
+		return x.Links
+	}
+	return 0
+}
+
+func (x *UTMStat) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+type GetUTMStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// By clicks, most clicked first.
+	Stats         []*UTMStat `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUTMStatsResponse) Reset() {
+	*x = GetUTMStatsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUTMStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUTMStatsResponse) ProtoMessage() {}
+
+func (x *GetUTMStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUTMStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetUTMStatsResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *GetUTMStatsResponse) GetStats() []*UTMStat {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// OpenGraph is the metadata crawlers of chat apps and social networks get
+// for a link instead of a redirect.
+type OpenGraph struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Absolute http or https URL of the preview image.
+	ImageUrl      string `protobuf:"bytes,3,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenGraph) Reset() {
+	*x = OpenGraph{}
+	mi := &file_url_shortener_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenGraph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenGraph) ProtoMessage() {}
+
+func (x *OpenGraph) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenGraph.ProtoReflect.Descriptor instead.
+func (*OpenGraph) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *OpenGraph) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *OpenGraph) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *OpenGraph) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+type SetLinkOpenGraphRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey   string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	ShortUrl string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Branded domain of the link, empty for links on this service's hosts.
+	Domain        string     `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	Og            *OpenGraph `protobuf:"bytes,4,opt,name=og,proto3" json:"og,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkOpenGraphRequest) Reset() {
+	*x = SetLinkOpenGraphRequest{}
+	mi := &file_url_shortener_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkOpenGraphRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkOpenGraphRequest) ProtoMessage() {}
+
+func (x *SetLinkOpenGraphRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLinkOpenGraphRequest.ProtoReflect.Descriptor instead.
+func (*SetLinkOpenGraphRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *SetLinkOpenGraphRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *SetLinkOpenGraphRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *SetLinkOpenGraphRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *SetLinkOpenGraphRequest) GetOg() *OpenGraph {
+	if x != nil {
+		return x.Og
+	}
+	return nil
+}
+
+type SetLinkOpenGraphResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Og            *OpenGraph             `protobuf:"bytes,1,opt,name=og,proto3" json:"og,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkOpenGraphResponse) Reset() {
+	*x = SetLinkOpenGraphResponse{}
+	mi := &file_url_shortener_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkOpenGraphResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkOpenGraphResponse) ProtoMessage() {}
+
+func (x *SetLinkOpenGraphResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLinkOpenGraphResponse.ProtoReflect.Descriptor instead.
+func (*SetLinkOpenGraphResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *SetLinkOpenGraphResponse) GetOg() *OpenGraph {
+	if x != nil {
+		return x.Og
+	}
+	return nil
+}
+
+// LinkHealth is the outcome of the last health check of a link destination.
+type LinkHealth struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	CheckedAt *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	// HTTP status of the final response, 0 when none was received.
+	StatusCode int32 `protobuf:"varint,2,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	// Why the check failed, e.g. a DNS or connection error.
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// Where the destination ended up after following redirects.
+	FinalUrl  string `protobuf:"bytes,4,opt,name=final_url,json=finalUrl,proto3" json:"final_url,omitempty"`
+	LatencyMs int64  `protobuf:"varint,5,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	// Set after consecutive failed checks.
+	Broken bool `protobuf:"varint,6,opt,name=broken,proto3" json:"broken,omitempty"`
+	// Set when robots.txt of the destination disallows checking it.
+	Skipped       bool `protobuf:"varint,7,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkHealth) Reset() {
+	*x = LinkHealth{}
+	mi := &file_url_shortener_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkHealth) ProtoMessage() {}
+
+func (x *LinkHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkHealth.ProtoReflect.Descriptor instead.
+func (*LinkHealth) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *LinkHealth) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+func (x *LinkHealth) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *LinkHealth) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *LinkHealth) GetFinalUrl() string {
+	if x != nil {
+		return x.FinalUrl
+	}
+	return ""
+}
+
+func (x *LinkHealth) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *LinkHealth) GetBroken() bool {
+	if x != nil {
+		return x.Broken
+	}
+	return false
+}
+
+func (x *LinkHealth) GetSkipped() bool {
+	if x != nil {
+		return x.Skipped
+	}
+	return false
+}
+
+type ListBrokenLinksRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// At most 1000, 50 when unset.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page.
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBrokenLinksRequest) Reset() {
+	*x = ListBrokenLinksRequest{}
+	mi := &file_url_shortener_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBrokenLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBrokenLinksRequest) ProtoMessage() {}
+
+func (x *ListBrokenLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBrokenLinksRequest.ProtoReflect.Descriptor instead.
+func (*ListBrokenLinksRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *ListBrokenLinksRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *ListBrokenLinksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListBrokenLinksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListBrokenLinksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Links []*Link                `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	// Pass as page_token to get the next page, empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBrokenLinksResponse) Reset() {
+	*x = ListBrokenLinksResponse{}
+	mi := &file_url_shortener_proto_msgTypes[101]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBrokenLinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBrokenLinksResponse) ProtoMessage() {}
+
+func (x *ListBrokenLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[101]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBrokenLinksResponse.ProtoReflect.Descriptor instead.
+func (*ListBrokenLinksResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{101}
+}
+
+func (x *ListBrokenLinksResponse) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *ListBrokenLinksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type Workspace struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Set for the workspace every user gets, which holds their links from
+	// before workspaces.
+	Personal bool `protobuf:"varint,3,opt,name=personal,proto3" json:"personal,omitempty"`
+	// Role of the caller: "owner", "admin", "editor" or "viewer".
+	Role string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	// API key of the caller for this workspace.
+	ApiKey        string `protobuf:"bytes,5,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Workspace) Reset() {
+	*x = Workspace{}
+	mi := &file_url_shortener_proto_msgTypes[102]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Workspace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workspace) ProtoMessage() {}
+
+func (x *Workspace) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[102]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workspace.ProtoReflect.Descriptor instead.
+func (*Workspace) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *Workspace) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Workspace) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Workspace) GetPersonal() bool {
+	if x != nil {
+		return x.Personal
+	}
+	return false
+}
+
+func (x *Workspace) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Workspace) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type CreateWorkspaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWorkspaceRequest) Reset() {
+	*x = CreateWorkspaceRequest{}
+	mi := &file_url_shortener_proto_msgTypes[103]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWorkspaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorkspaceRequest) ProtoMessage() {}
+
+func (x *CreateWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[103]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*CreateWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{103}
+}
+
+func (x *CreateWorkspaceRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *CreateWorkspaceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateWorkspaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workspace     *Workspace             `protobuf:"bytes,1,opt,name=workspace,proto3" json:"workspace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWorkspaceResponse) Reset() {
+	*x = CreateWorkspaceResponse{}
+	mi := &file_url_shortener_proto_msgTypes[104]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWorkspaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorkspaceResponse) ProtoMessage() {}
+
+func (x *CreateWorkspaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[104]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorkspaceResponse.ProtoReflect.Descriptor instead.
+func (*CreateWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{104}
+}
+
+func (x *CreateWorkspaceResponse) GetWorkspace() *Workspace {
+	if x != nil {
+		return x.Workspace
+	}
+	return nil
+}
+
+type ListWorkspacesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkspacesRequest) Reset() {
+	*x = ListWorkspacesRequest{}
+	mi := &file_url_shortener_proto_msgTypes[105]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkspacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkspacesRequest) ProtoMessage() {}
+
+func (x *ListWorkspacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[105]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkspacesRequest.ProtoReflect.Descriptor instead.
+func (*ListWorkspacesRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{105}
+}
+
+func (x *ListWorkspacesRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type ListWorkspacesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workspaces    []*Workspace           `protobuf:"bytes,1,rep,name=workspaces,proto3" json:"workspaces,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorkspacesResponse) Reset() {
+	*x = ListWorkspacesResponse{}
+	mi := &file_url_shortener_proto_msgTypes[106]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorkspacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorkspacesResponse) ProtoMessage() {}
+
+func (x *ListWorkspacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[106]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorkspacesResponse.ProtoReflect.Descriptor instead.
+func (*ListWorkspacesResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{106}
+}
+
+func (x *ListWorkspacesResponse) GetWorkspaces() []*Workspace {
+	if x != nil {
+		return x.Workspaces
+	}
+	return nil
+}
+
+type WorkspaceMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint64                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	FirstName     string                 `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Role          string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkspaceMember) Reset() {
+	*x = WorkspaceMember{}
+	mi := &file_url_shortener_proto_msgTypes[107]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkspaceMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceMember) ProtoMessage() {}
+
+func (x *WorkspaceMember) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[107]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceMember.ProtoReflect.Descriptor instead.
+func (*WorkspaceMember) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{107}
+}
+
+func (x *WorkspaceMember) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *WorkspaceMember) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *WorkspaceMember) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *WorkspaceMember) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *WorkspaceMember) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type ListMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMembersRequest) Reset() {
+	*x = ListMembersRequest{}
+	mi := &file_url_shortener_proto_msgTypes[108]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMembersRequest) ProtoMessage() {}
+
+func (x *ListMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[108]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMembersRequest.ProtoReflect.Descriptor instead.
+func (*ListMembersRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{108}
+}
+
+func (x *ListMembersRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type ListMembersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*WorkspaceMember     `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMembersResponse) Reset() {
+	*x = ListMembersResponse{}
+	mi := &file_url_shortener_proto_msgTypes[109]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMembersResponse) ProtoMessage() {}
+
+func (x *ListMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[109]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMembersResponse.ProtoReflect.Descriptor instead.
+func (*ListMembersResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{109}
+}
+
+func (x *ListMembersResponse) GetMembers() []*WorkspaceMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type InviteMemberRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Email of a registered user.
+	Email string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	// "admin", "editor" or "viewer", or "owner" when invited by an owner.
+	Role          string `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteMemberRequest) Reset() {
+	*x = InviteMemberRequest{}
+	mi := &file_url_shortener_proto_msgTypes[110]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteMemberRequest) ProtoMessage() {}
+
+func (x *InviteMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[110]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteMemberRequest.ProtoReflect.Descriptor instead.
+func (*InviteMemberRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{110}
+}
+
+func (x *InviteMemberRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *InviteMemberRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *InviteMemberRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type InviteMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Member        *WorkspaceMember       `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteMemberResponse) Reset() {
+	*x = InviteMemberResponse{}
+	mi := &file_url_shortener_proto_msgTypes[111]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteMemberResponse) ProtoMessage() {}
+
+func (x *InviteMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[111]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteMemberResponse.ProtoReflect.Descriptor instead.
+func (*InviteMemberResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{111}
+}
+
+func (x *InviteMemberResponse) GetMember() *WorkspaceMember {
+	if x != nil {
+		return x.Member
+	}
+	return nil
+}
+
+type ChangeMemberRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	UserId        uint64                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeMemberRoleRequest) Reset() {
+	*x = ChangeMemberRoleRequest{}
+	mi := &file_url_shortener_proto_msgTypes[112]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeMemberRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeMemberRoleRequest) ProtoMessage() {}
+
+func (x *ChangeMemberRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[112]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeMemberRoleRequest.ProtoReflect.Descriptor instead.
+func (*ChangeMemberRoleRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{112}
+}
+
+func (x *ChangeMemberRoleRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *ChangeMemberRoleRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ChangeMemberRoleRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type ChangeMemberRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Member        *WorkspaceMember       `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeMemberRoleResponse) Reset() {
+	*x = ChangeMemberRoleResponse{}
+	mi := &file_url_shortener_proto_msgTypes[113]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeMemberRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeMemberRoleResponse) ProtoMessage() {}
+
+func (x *ChangeMemberRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[113]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeMemberRoleResponse.ProtoReflect.Descriptor instead.
+func (*ChangeMemberRoleResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{113}
+}
+
+func (x *ChangeMemberRoleResponse) GetMember() *WorkspaceMember {
+	if x != nil {
+		return x.Member
+	}
+	return nil
+}
+
+type RemoveMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	UserId        uint64                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveMemberRequest) Reset() {
+	*x = RemoveMemberRequest{}
+	mi := &file_url_shortener_proto_msgTypes[114]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveMemberRequest) ProtoMessage() {}
+
+func (x *RemoveMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[114]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveMemberRequest.ProtoReflect.Descriptor instead.
+func (*RemoveMemberRequest) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{114}
+}
+
+func (x *RemoveMemberRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *RemoveMemberRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type RemoveMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveMemberResponse) Reset() {
+	*x = RemoveMemberResponse{}
+	mi := &file_url_shortener_proto_msgTypes[115]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveMemberResponse) ProtoMessage() {}
+
+func (x *RemoveMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[115]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveMemberResponse.ProtoReflect.Descriptor instead.
+func (*RemoveMemberResponse) Descriptor() ([]byte, []int) {
+	return file_url_shortener_proto_rawDescGZIP(), []int{115}
+}
+
+var File_url_shortener_proto protoreflect.FileDescriptor
+
+const file_url_shortener_proto_rawDesc = "" +
+	"\n" +
+	"\x13url_shortener.proto\x12\rurl_shortener\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/httpbody.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xea\x03\n" +
+	"\x11ShortenURLRequest\x12\x19\n" +
+	"\blong_url\x18\x01 \x01(\tR\alongUrl\x12\x17\n" +
+	"\aapi_key\x18\x02 \x01(\tR\x06apiKey\x12!\n" +
+	"\fcustom_alias\x18\x03 \x01(\tR\vcustomAlias\x12@\n" +
+	"\rredirect_type\x18\x04 \x01(\x0e2\x1b.url_shortener.RedirectTypeR\fredirectType\x12+\n" +
+	"\x11query_passthrough\x18\x05 \x01(\bR\x10queryPassthrough\x12!\n" +
+	"\fforward_path\x18\x06 \x01(\bR\vforwardPath\x12\x1a\n" +
+	"\bpassword\x18\a \x01(\tR\bpassword\x12\x1d\n" +
+	"\n" +
+	"max_clicks\x18\b \x01(\x03R\tmaxClicks\x12\"\n" +
+	"\finterstitial\x18\t \x01(\bR\finterstitial\x12\x16\n" +
+	"\x06domain\x18\n" +
+	" \x01(\tR\x06domain\x12*\n" +
+	"\x03utm\x18\v \x01(\v2\x18.url_shortener.UTMParamsR\x03utm\x12\x1f\n" +
+	"\vcampaign_id\x18\f \x01(\x04R\n" +
+	"campaignId\x12(\n" +
+	"\x02og\x18\r \x01(\v2\x18.url_shortener.OpenGraphR\x02og\"\xdb\x02\n" +
+	"\x12ShortenURLResponse\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12\x19\n" +
+	"\blong_url\x18\x04 \x01(\tR\alongUrl\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"max_clicks\x18\x06 \x01(\x03R\tmaxClicks\x12)\n" +
+	"\x10clicks_remaining\x18\a \x01(\x03R\x0fclicksRemaining\x12\x1e\n" +
+	"\vqr_code_url\x18\b \x01(\tR\tqrCodeUrl\x12\x16\n" +
+	"\x06reused\x18\t \x01(\bR\x06reused\x12*\n" +
+	"\x03utm\x18\n" +
+	" \x01(\v2\x18.url_shortener.UTMParamsR\x03utm\"H\n" +
+	"\rGetURLRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\xce\x02\n" +
+	"\x0eGetURLResponse\x12\x19\n" +
+	"\blong_url\x18\x01 \x01(\tR\alongUrl\x12!\n" +
+	"\fresolved_url\x18\x02 \x01(\tR\vresolvedUrl\x12@\n" +
+	"\rredirect_type\x18\x03 \x01(\x0e2\x1b.url_shortener.RedirectTypeR\fredirectType\x12+\n" +
+	"\x11query_passthrough\x18\x04 \x01(\bR\x10queryPassthrough\x12!\n" +
+	"\fforward_path\x18\x05 \x01(\bR\vforwardPath\x12\x1d\n" +
+	"\n" +
+	"max_clicks\x18\x06 \x01(\x03R\tmaxClicks\x12)\n" +
+	"\x10clicks_remaining\x18\a \x01(\x03R\x0fclicksRemaining\x12\"\n" +
+	"\finterstitial\x18\b \x01(\bR\finterstitial\"e\n" +
+	"\x11CreateUserRequest\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x01 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x02 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\"F\n" +
+	"\x12CreateUserResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\aapi_key\x18\x02 \x01(\tR\x06apiKey\"*\n" +
+	"\x12FetchApiKeyRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\".\n" +
+	"\x13FetchApiKeyResponse\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"<\n" +
+	"\fDomainMetric\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\x16\n" +
+	"\x14GetTopDomainsRequest\"U\n" +
+	"\x15GetTopDomainsResponse\x12<\n" +
+	"\vtop_domains\x18\x01 \x03(\v2\x1b.url_shortener.DomainMetricR\n" +
+	"topDomains\"*\n" +
+	"\x0fGetUsageRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"\xfc\x02\n" +
+	"\x10GetUsageResponse\x12\x12\n" +
+	"\x04plan\x18\x01 \x01(\tR\x04plan\x12;\n" +
+	"\vcycle_start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"cycleStart\x127\n" +
+	"\tcycle_end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bcycleEnd\x12#\n" +
+	"\rlinks_created\x18\x04 \x01(\x03R\flinksCreated\x12\x1b\n" +
+	"\tmax_links\x18\x05 \x01(\x03R\bmaxLinks\x124\n" +
+	"\x16custom_aliases_created\x18\x06 \x01(\x03R\x14customAliasesCreated\x12,\n" +
+	"\x12max_custom_aliases\x18\a \x01(\x03R\x10maxCustomAliases\x128\n" +
+	"\x18analytics_retention_days\x18\b \x01(\x05R\x16analyticsRetentionDays\"\x92\x01\n" +
+	"\n" +
+	"PolicyRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x18\n" +
+	"\apattern\x18\x03 \x01(\tR\apattern\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\tR\x06source\"\x8d\x01\n" +
+	"\x14AddPolicyRuleRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x18\n" +
+	"\apattern\x18\x03 \x01(\tR\apattern\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\"m\n" +
+	"\x15AddPolicyRuleResponse\x12-\n" +
+	"\x04rule\x18\x01 \x01(\v2\x19.url_shortener.PolicyRuleR\x04rule\x12%\n" +
+	"\x0edisabled_links\x18\x02 \x01(\x03R\rdisabledLinks\"B\n" +
+	"\x17RemovePolicyRuleRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\"\x1a\n" +
+	"\x18RemovePolicyRuleResponse\"1\n" +
+	"\x16ListPolicyRulesRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"J\n" +
+	"\x17ListPolicyRulesResponse\x12/\n" +
+	"\x05rules\x18\x01 \x03(\v2\x19.url_shortener.PolicyRuleR\x05rules\"\x86\x02\n" +
+	"\x10GetQRCodeRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\x12)\n" +
+	"\x10error_correction\x18\x04 \x01(\tR\x0ferrorCorrection\x12\x1b\n" +
+	"\x06margin\x18\x05 \x01(\x05H\x00R\x06margin\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"foreground\x18\x06 \x01(\tR\n" +
+	"foreground\x12\x1e\n" +
+	"\n" +
+	"background\x18\a \x01(\tR\n" +
+	"background\x12\x16\n" +
+	"\x06domain\x18\b \x01(\tR\x06domainB\t\n" +
+	"\a_margin\"j\n" +
+	"\x17BatchShortenURLsRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x126\n" +
+	"\x05items\x18\x02 \x03(\v2 .url_shortener.ShortenURLRequestR\x05items\"x\n" +
+	"\x12BatchShortenResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x19\n" +
+	"\blong_url\x18\x02 \x01(\tR\alongUrl\x12\x1b\n" +
+	"\tshort_url\x18\x03 \x01(\tR\bshortUrl\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"W\n" +
+	"\x18BatchShortenURLsResponse\x12;\n" +
+	"\aresults\x18\x01 \x03(\v2!.url_shortener.BatchShortenResultR\aresults\"\x84\x01\n" +
+	"\x14StreamShortenRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\x124\n" +
+	"\x04item\x18\x03 \x01(\v2 .url_shortener.ShortenURLRequestR\x04item\"\xa0\x01\n" +
+	"\x15StreamShortenResponse\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\x12\x19\n" +
+	"\blong_url\x18\x03 \x01(\tR\alongUrl\x12\x1b\n" +
+	"\tshort_url\x18\x04 \x01(\tR\bshortUrl\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"L\n" +
+	"\x12WatchClicksRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1d\n" +
+	"\n" +
+	"short_urls\x18\x02 \x03(\tR\tshortUrls\"\xb5\x02\n" +
+	"\n" +
+	"ClickEvent\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x129\n" +
+	"\n" +
+	"clicked_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tclickedAt\x12\x1a\n" +
+	"\breferrer\x18\x03 \x01(\tR\breferrer\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x04 \x01(\tR\tuserAgent\x12)\n" +
+	"\x10clicks_remaining\x18\x05 \x01(\x03R\x0fclicksRemaining\x12\x18\n" +
+	"\adropped\x18\x06 \x01(\x04R\adropped\x12\x18\n" +
+	"\acountry\x18\a \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\b \x01(\tR\x06region\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\t \x01(\x04R\tvariantId\"~\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x03 \x03(\tR\x06events\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"Y\n" +
+	"\x14CreateWebhookRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x03 \x03(\tR\x06events\"a\n" +
+	"\x15CreateWebhookResponse\x120\n" +
+	"\awebhook\x18\x01 \x01(\v2\x16.url_shortener.WebhookR\awebhook\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\".\n" +
+	"\x13ListWebhooksRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"J\n" +
+	"\x14ListWebhooksResponse\x122\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x16.url_shortener.WebhookR\bwebhooks\"?\n" +
+	"\x14DeleteWebhookRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\"\x17\n" +
+	"\x15DeleteWebhookResponse\"\xab\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\x04R\twebhookId\x12\x14\n" +
+	"\x05event\x18\x03 \x01(\tR\x05event\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\tR\apayload\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12(\n" +
+	"\x10last_status_code\x18\a \x01(\x05R\x0elastStatusCode\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\b \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12B\n" +
+	"\x0fnext_attempt_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12=\n" +
+	"\fdelivered_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vdeliveredAt\"\x84\x01\n" +
+	"\x1cListWebhookDeliveriesRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\x04R\twebhookId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"_\n" +
+	"\x1dListWebhookDeliveriesResponse\x12>\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x1e.url_shortener.WebhookDeliveryR\n" +
+	"deliveries\"N\n" +
+	"\x1dListWebhookDeadLettersRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"c\n" +
+	"\x1eListWebhookDeadLettersResponse\x12A\n" +
+	"\fdead_letters\x18\x01 \x03(\v2\x1e.url_shortener.WebhookDeliveryR\vdeadLetters\"\xf5\x01\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12;\n" +
+	"\voccurred_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12?\n" +
+	"\flink_created\x18\n" +
+	" \x01(\v2\x1a.url_shortener.LinkCreatedH\x00R\vlinkCreated\x12?\n" +
+	"\flink_clicked\x18\v \x01(\v2\x1a.url_shortener.LinkClickedH\x00R\vlinkClickedB\t\n" +
+	"\apayload\"\xa0\x01\n" +
+	"\vLinkCreated\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x19\n" +
+	"\blong_url\x18\x02 \x01(\tR\alongUrl\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x04R\x06userId\x12!\n" +
+	"\fcustom_alias\x18\x04 \x01(\bR\vcustomAlias\x12\x1d\n" +
+	"\n" +
+	"max_clicks\x18\x05 \x01(\x03R\tmaxClicks\"\x95\x02\n" +
+	"\vLinkClicked\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x19\n" +
+	"\blong_url\x18\x02 \x01(\tR\alongUrl\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x04R\x06userId\x12\x1a\n" +
+	"\breferrer\x18\x04 \x01(\tR\breferrer\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x05 \x01(\tR\tuserAgent\x12)\n" +
+	"\x10clicks_remaining\x18\x06 \x01(\x03R\x0fclicksRemaining\x12\x18\n" +
+	"\acountry\x18\a \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\b \x01(\tR\x06region\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\t \x01(\x04R\tvariantId\"\xa0\x02\n" +
+	"\x06Domain\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\x12\x1a\n" +
+	"\bverified\x18\x03 \x01(\bR\bverified\x12/\n" +
+	"\x13verification_record\x18\x04 \x01(\tR\x12verificationRecord\x12-\n" +
+	"\x12verification_value\x18\x05 \x01(\tR\x11verificationValue\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12;\n" +
+	"\vverified_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"verifiedAt\"B\n" +
+	"\x13CreateDomainRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x12\n" +
+	"\x04host\x18\x02 \x01(\tR\x04host\"E\n" +
+	"\x14CreateDomainResponse\x12-\n" +
+	"\x06domain\x18\x01 \x01(\v2\x15.url_shortener.DomainR\x06domain\"-\n" +
+	"\x12ListDomainsRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"F\n" +
+	"\x13ListDomainsResponse\x12/\n" +
+	"\adomains\x18\x01 \x03(\v2\x15.url_shortener.DomainR\adomains\">\n" +
+	"\x13VerifyDomainRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\"E\n" +
+	"\x14VerifyDomainResponse\x12-\n" +
+	"\x06domain\x18\x01 \x01(\v2\x15.url_shortener.DomainR\x06domain\">\n" +
+	"\x13DeleteDomainRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\"\x16\n" +
+	"\x14DeleteDomainResponse\"\x8a\x02\n" +
+	"\vRoutingRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
+	"\bpriority\x18\x02 \x01(\x05R\bpriority\x12\x0e\n" +
+	"\x02os\x18\x03 \x01(\tR\x02os\x12\x16\n" +
+	"\x06device\x18\x04 \x01(\tR\x06device\x12\x18\n" +
+	"\abrowser\x18\x05 \x01(\tR\abrowser\x12 \n" +
+	"\vdestination\x18\x06 \x01(\tR\vdestination\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x18\n" +
+	"\acountry\x18\b \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\t \x01(\tR\x06region\"\x95\x01\n" +
+	"\x15AddRoutingRuleRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12.\n" +
+	"\x04rule\x18\x04 \x01(\v2\x1a.url_shortener.RoutingRuleR\x04rule\"H\n" +
+	"\x16AddRoutingRuleResponse\x12.\n" +
+	"\x04rule\x18\x01 \x01(\v2\x1a.url_shortener.RoutingRuleR\x04rule\"g\n" +
+	"\x17ListRoutingRulesRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\"L\n" +
+	"\x18ListRoutingRulesResponse\x120\n" +
+	"\x05rules\x18\x01 \x03(\v2\x1a.url_shortener.RoutingRuleR\x05rules\"x\n" +
+	"\x18DeleteRoutingRuleRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\x04R\x02id\"\x1b\n" +
+	"\x19DeleteRoutingRuleResponse\"\xba\x01\n" +
+	"\vLinkVariant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x05R\x06weight\x12\x16\n" +
+	"\x06clicks\x18\x04 \x01(\x03R\x06clicks\x12 \n" +
+	"\vconversions\x18\x05 \x01(\x03R\vconversions\x12'\n" +
+	"\x0fconversion_rate\x18\x06 \x01(\x01R\x0econversionRate\"\xbe\x01\n" +
+	"\x16SetLinkVariantsRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x126\n" +
+	"\bvariants\x18\x04 \x03(\v2\x1a.url_shortener.LinkVariantR\bvariants\x12\x1e\n" +
+	"\n" +
+	"stickiness\x18\x05 \x01(\tR\n" +
+	"stickiness\"Q\n" +
+	"\x17SetLinkVariantsResponse\x126\n" +
+	"\bvariants\x18\x01 \x03(\v2\x1a.url_shortener.LinkVariantR\bvariants\"\x86\x01\n" +
+	"\x17RecordConversionRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\x04R\tvariantId\"\x1a\n" +
+	"\x18RecordConversionResponse\"c\n" +
+	"\x13GetLinkStatsRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\"\xc3\x04\n" +
+	"\x14GetLinkStatsResponse\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x19\n" +
+	"\blong_url\x18\x02 \x01(\tR\alongUrl\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12)\n" +
+	"\x10clicks_remaining\x18\x04 \x01(\x03R\x0fclicksRemaining\x12\x1e\n" +
+	"\n" +
+	"stickiness\x18\x05 \x01(\tR\n" +
+	"stickiness\x126\n" +
+	"\bvariants\x18\x06 \x03(\v2\x1a.url_shortener.LinkVariantR\bvariants\x12\x16\n" +
+	"\x06clicks\x18\a \x01(\x03R\x06clicks\x124\n" +
+	"\bcampaign\x18\b \x01(\v2\x18.url_shortener.LinkGroupR\bcampaign\x120\n" +
+	"\x06folder\x18\t \x01(\v2\x18.url_shortener.LinkGroupR\x06folder\x12,\n" +
+	"\x04tags\x18\n" +
+	" \x03(\v2\x18.url_shortener.LinkGroupR\x04tags\x12*\n" +
+	"\x03utm\x18\v \x01(\v2\x18.url_shortener.UTMParamsR\x03utm\x12(\n" +
+	"\x02og\x18\f \x01(\v2\x18.url_shortener.OpenGraphR\x02og\x121\n" +
+	"\x06health\x18\r \x01(\v2\x19.url_shortener.LinkHealthR\x06health\"\xcd\x01\n" +
+	"\x14ScheduledDestination\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12!\n" +
+	"\factivates_at\x18\x03 \x01(\tR\vactivatesAt\x12\x1b\n" +
+	"\ttime_zone\x18\x04 \x01(\tR\btimeZone\x12C\n" +
+	"\x0factivation_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0eactivationTime\"\xa7\x01\n" +
+	"\x16SetLinkScheduleRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12?\n" +
+	"\bschedule\x18\x04 \x03(\v2#.url_shortener.ScheduledDestinationR\bschedule\"Z\n" +
+	"\x17SetLinkScheduleResponse\x12?\n" +
+	"\bschedule\x18\x01 \x03(\v2#.url_shortener.ScheduledDestinationR\bschedule\"f\n" +
+	"\x16GetLinkScheduleRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\"\xc8\x01\n" +
+	"\x17GetLinkScheduleResponse\x12?\n" +
+	"\bschedule\x18\x01 \x03(\v2#.url_shortener.ScheduledDestinationR\bschedule\x12/\n" +
+	"\x13current_destination\x18\x02 \x01(\tR\x12currentDestination\x12;\n" +
+	"\vnext_change\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"nextChange\"\xaa\x01\n" +
+	"\tLinkGroup\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12*\n" +
+	"\x03utm\x18\x05 \x01(\v2\x18.url_shortener.UTMParamsR\x03utm\"Y\n" +
+	"\x16CreateLinkGroupRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"I\n" +
+	"\x17CreateLinkGroupResponse\x12.\n" +
+	"\x05group\x18\x01 \x01(\v2\x18.url_shortener.LinkGroupR\x05group\"U\n" +
+	"\x16RenameLinkGroupRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"I\n" +
+	"\x17RenameLinkGroupResponse\x12.\n" +
+	"\x05group\x18\x01 \x01(\v2\x18.url_shortener.LinkGroupR\x05group\"D\n" +
+	"\x15ListLinkGroupsRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\"J\n" +
+	"\x16ListLinkGroupsResponse\x120\n" +
+	"\x06groups\x18\x01 \x03(\v2\x18.url_shortener.LinkGroupR\x06groups\"\x90\x01\n" +
+	"\x12AssignLinksRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x1d\n" +
+	"\n" +
+	"short_urls\x18\x04 \x03(\tR\tshortUrls\x12\x1a\n" +
+	"\bunassign\x18\x05 \x01(\bR\bunassign\"/\n" +
+	"\x13AssignLinksResponse\x12\x18\n" +
+	"\aupdated\x18\x01 \x01(\x03R\aupdated\"\x86\x03\n" +
+	"\x04Link\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x19\n" +
+	"\blong_url\x18\x02 \x01(\tR\alongUrl\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x16\n" +
+	"\x06clicks\x18\x04 \x01(\x03R\x06clicks\x124\n" +
+	"\bcampaign\x18\x05 \x01(\v2\x18.url_shortener.LinkGroupR\bcampaign\x120\n" +
+	"\x06folder\x18\x06 \x01(\v2\x18.url_shortener.LinkGroupR\x06folder\x12,\n" +
+	"\x04tags\x18\a \x03(\v2\x18.url_shortener.LinkGroupR\x04tags\x12*\n" +
+	"\x03utm\x18\b \x01(\v2\x18.url_shortener.UTMParamsR\x03utm\x121\n" +
+	"\x06health\x18\t \x01(\v2\x19.url_shortener.LinkHealthR\x06health\"\xbc\x01\n" +
+	"\x10ListLinksRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x15\n" +
+	"\x06tag_id\x18\x02 \x01(\x04R\x05tagId\x12\x1f\n" +
+	"\vcampaign_id\x18\x03 \x01(\x04R\n" +
+	"campaignId\x12\x1b\n" +
+	"\tfolder_id\x18\x04 \x01(\x04R\bfolderId\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\"f\n" +
+	"\x11ListLinksResponse\x12)\n" +
+	"\x05links\x18\x01 \x03(\v2\x13.url_shortener.LinkR\x05links\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"C\n" +
+	"\x18GetLinkGroupStatsRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\"\x8b\x02\n" +
+	"\x19GetLinkGroupStatsResponse\x12.\n" +
+	"\x05group\x18\x01 \x01(\v2\x18.url_shortener.LinkGroupR\x05group\x12\x14\n" +
+	"\x05links\x18\x02 \x01(\x03R\x05links\x12\x16\n" +
+	"\x06clicks\x18\x03 \x01(\x03R\x06clicks\x12 \n" +
+	"\vconversions\x18\x04 \x01(\x03R\vconversions\x120\n" +
+	"\ttop_links\x18\x05 \x03(\v2\x13.url_shortener.LinkR\btopLinks\x12<\n" +
+	"\vtop_domains\x18\x06 \x03(\v2\x1b.url_shortener.DomainMetricR\n" +
+	"topDomains\"\x85\x01\n" +
+	"\tUTMParams\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x16\n" +
+	"\x06medium\x18\x02 \x01(\tR\x06medium\x12\x1a\n" +
+	"\bcampaign\x18\x03 \x01(\tR\bcampaign\x12\x12\n" +
+	"\x04term\x18\x04 \x01(\tR\x04term\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\"l\n" +
+	"\x15SetCampaignUTMRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\x12*\n" +
+	"\x03utm\x18\x03 \x01(\v2\x18.url_shortener.UTMParamsR\x03utm\"H\n" +
+	"\x16SetCampaignUTMResponse\x12.\n" +
+	"\x05group\x18\x01 \x01(\v2\x18.url_shortener.LinkGroupR\x05group\"=\n" +
+	"\aUTMRule\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\x12\x1a\n" +
+	"\brequired\x18\x02 \x03(\tR\brequired\"[\n" +
+	"\x12SetUTMRulesRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12,\n" +
+	"\x05rules\x18\x02 \x03(\v2\x16.url_shortener.UTMRuleR\x05rules\"C\n" +
+	"\x13SetUTMRulesResponse\x12,\n" +
+	"\x05rules\x18\x01 \x03(\v2\x16.url_shortener.UTMRuleR\x05rules\".\n" +
+	"\x13ListUTMRulesRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"D\n" +
+	"\x14ListUTMRulesResponse\x12,\n" +
+	"\x05rules\x18\x01 \x03(\v2\x16.url_shortener.UTMRuleR\x05rules\"N\n" +
+	"\x12GetUTMStatsRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1f\n" +
+	"\vcampaign_id\x18\x02 \x01(\x04R\n" +
+	"campaignId\"g\n" +
+	"\aUTMStat\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x16\n" +
+	"\x06medium\x18\x02 \x01(\tR\x06medium\x12\x14\n" +
+	"\x05links\x18\x03 \x01(\x03R\x05links\x12\x16\n" +
+	"\x06clicks\x18\x04 \x01(\x03R\x06clicks\"C\n" +
+	"\x13GetUTMStatsResponse\x12,\n" +
+	"\x05stats\x18\x01 \x03(\v2\x16.url_shortener.UTMStatR\x05stats\"`\n" +
+	"\tOpenGraph\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1b\n" +
+	"\timage_url\x18\x03 \x01(\tR\bimageUrl\"\x91\x01\n" +
+	"\x17SetLinkOpenGraphRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12(\n" +
+	"\x02og\x18\x04 \x01(\v2\x18.url_shortener.OpenGraphR\x02og\"D\n" +
+	"\x18SetLinkOpenGraphResponse\x12(\n" +
+	"\x02og\x18\x01 \x01(\v2\x18.url_shortener.OpenGraphR\x02og\"\xec\x01\n" +
+	"\n" +
+	"LinkHealth\x129\n" +
+	"\n" +
+	"checked_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\x12\x1f\n" +
+	"\vstatus_code\x18\x02 \x01(\x05R\n" +
+	"statusCode\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1b\n" +
+	"\tfinal_url\x18\x04 \x01(\tR\bfinalUrl\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x05 \x01(\x03R\tlatencyMs\x12\x16\n" +
+	"\x06broken\x18\x06 \x01(\bR\x06broken\x12\x18\n" +
+	"\askipped\x18\a \x01(\bR\askipped\"m\n" +
+	"\x16ListBrokenLinksRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"l\n" +
+	"\x17ListBrokenLinksResponse\x12)\n" +
+	"\x05links\x18\x01 \x03(\v2\x13.url_shortener.LinkR\x05links\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"x\n" +
+	"\tWorkspace\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bpersonal\x18\x03 \x01(\bR\bpersonal\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x17\n" +
+	"\aapi_key\x18\x05 \x01(\tR\x06apiKey\"E\n" +
+	"\x16CreateWorkspaceRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"Q\n" +
+	"\x17CreateWorkspaceResponse\x126\n" +
+	"\tworkspace\x18\x01 \x01(\v2\x18.url_shortener.WorkspaceR\tworkspace\"0\n" +
+	"\x15ListWorkspacesRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"R\n" +
+	"\x16ListWorkspacesResponse\x128\n" +
+	"\n" +
+	"workspaces\x18\x01 \x03(\v2\x18.url_shortener.WorkspaceR\n" +
+	"workspaces\"\x90\x01\n" +
+	"\x0fWorkspaceMember\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x04R\x06userId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x03 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x04 \x01(\tR\blastName\x12\x12\n" +
+	"\x04role\x18\x05 \x01(\tR\x04role\"-\n" +
+	"\x12ListMembersRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"O\n" +
+	"\x13ListMembersResponse\x128\n" +
+	"\amembers\x18\x01 \x03(\v2\x1e.url_shortener.WorkspaceMemberR\amembers\"X\n" +
+	"\x13InviteMemberRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\"N\n" +
+	"\x14InviteMemberResponse\x126\n" +
+	"\x06member\x18\x01 \x01(\v2\x1e.url_shortener.WorkspaceMemberR\x06member\"_\n" +
+	"\x17ChangeMemberRoleRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x04R\x06userId\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\"R\n" +
+	"\x18ChangeMemberRoleResponse\x126\n" +
+	"\x06member\x18\x01 \x01(\v2\x1e.url_shortener.WorkspaceMemberR\x06member\"G\n" +
+	"\x13RemoveMemberRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x04R\x06userId\"\x16\n" +
+	"\x14RemoveMemberResponse*\xc5\x01\n" +
+	"\fRedirectType\x12\x1d\n" +
+	"\x19REDIRECT_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17REDIRECT_TYPE_PERMANENT\x10\x01\x12\x1b\n" +
+	"\x17REDIRECT_TYPE_TEMPORARY\x10\x02\x12-\n" +
+	")REDIRECT_TYPE_METHOD_PRESERVING_TEMPORARY\x10\x03\x12-\n" +
+	")REDIRECT_TYPE_METHOD_PRESERVING_PERMANENT\x10\x042\x8e-\n" +
+	"\fURLShortener\x12f\n" +
+	"\n" +
+	"ShortenURL\x12 .url_shortener.ShortenURLRequest\x1a!.url_shortener.ShortenURLResponse\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/shorten\x12[\n" +
+	"\x06GetURL\x12\x1c.url_shortener.GetURLRequest\x1a\x1d.url_shortener.GetURLResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/{short_url}\x12d\n" +
+	"\n" +
+	"CreateUser\x12 .url_shortener.CreateUserRequest\x1a!.url_shortener.CreateUserResponse\"\x11\x82\xd3\xe4\x93\x02\v:\x01*\"\x06/users\x12n\n" +
+	"\vFetchApiKey\x12!.url_shortener.FetchApiKeyRequest\x1a\".url_shortener.FetchApiKeyResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/api_key/{email}\x12x\n" +
+	"\rGetTopDomains\x12#.url_shortener.GetTopDomainsRequest\x1a$.url_shortener.GetTopDomainsResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/metrics/top_domains\x12[\n" +
+	"\bGetUsage\x12\x1e.url_shortener.GetUsageRequest\x1a\x1f.url_shortener.GetUsageResponse\"\x0e\x82\xd3\xe4\x93\x02\b\x12\x06/usage\x12z\n" +
+	"\rAddPolicyRule\x12#.url_shortener.AddPolicyRuleRequest\x1a$.url_shortener.AddPolicyRuleResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/admin/policy_rules\x12\x85\x01\n" +
+	"\x10RemovePolicyRule\x12&.url_shortener.RemovePolicyRuleRequest\x1a'.url_shortener.RemovePolicyRuleResponse\" \x82\xd3\xe4\x93\x02\x1a*\x18/admin/policy_rules/{id}\x12}\n" +
+	"\x0fListPolicyRules\x12%.url_shortener.ListPolicyRulesRequest\x1a&.url_shortener.ListPolicyRulesResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/admin/policy_rules\x12~\n" +
+	"\x10BatchShortenURLs\x12&.url_shortener.BatchShortenURLsRequest\x1a'.url_shortener.BatchShortenURLsResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/shorten/batch\x12[\n" +
+	"\tGetQRCode\x12\x1f.url_shortener.GetQRCodeRequest\x1a\x14.google.api.HttpBody\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/qr/{short_url}\x12`\n" +
+	"\rStreamShorten\x12#.url_shortener.StreamShortenRequest\x1a$.url_shortener.StreamShortenResponse\"\x00(\x010\x01\x12d\n" +
+	"\vWatchClicks\x12!.url_shortener.WatchClicksRequest\x1a\x19.url_shortener.ClickEvent\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/clicks/watch0\x01\x12p\n" +
+	"\rCreateWebhook\x12#.url_shortener.CreateWebhookRequest\x1a$.url_shortener.CreateWebhookResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/webhooks\x12j\n" +
+	"\fListWebhooks\x12\".url_shortener.ListWebhooksRequest\x1a#.url_shortener.ListWebhooksResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/webhooks\x12r\n" +
+	"\rDeleteWebhook\x12#.url_shortener.DeleteWebhookRequest\x1a$.url_shortener.DeleteWebhookResponse\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/webhooks/{id}\x12\x90\x01\n" +
+	"\x15ListWebhookDeliveries\x12+.url_shortener.ListWebhookDeliveriesRequest\x1a,.url_shortener.ListWebhookDeliveriesResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/webhooks/deliveries\x12\x95\x01\n" +
+	"\x16ListWebhookDeadLetters\x12,.url_shortener.ListWebhookDeadLettersRequest\x1a-.url_shortener.ListWebhookDeadLettersResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/webhooks/dead-letters\x12l\n" +
+	"\fCreateDomain\x12\".url_shortener.CreateDomainRequest\x1a#.url_shortener.CreateDomainResponse\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/domains\x12f\n" +
+	"\vListDomains\x12!.url_shortener.ListDomainsRequest\x1a\".url_shortener.ListDomainsResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
+	"\x12\b/domains\x12x\n" +
+	"\fVerifyDomain\x12\".url_shortener.VerifyDomainRequest\x1a#.url_shortener.VerifyDomainResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/domains/{id}/verify\x12n\n" +
+	"\fDeleteDomain\x12\".url_shortener.DeleteDomainRequest\x1a#.url_shortener.DeleteDomainResponse\"\x15\x82\xd3\xe4\x93\x02\x0f*\r/domains/{id}\x12\x82\x01\n" +
+	"\x0eAddRoutingRule\x12$.url_shortener.AddRoutingRuleRequest\x1a%.url_shortener.AddRoutingRuleResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/links/{short_url}/rules\x12\x85\x01\n" +
+	"\x10ListRoutingRules\x12&.url_shortener.ListRoutingRulesRequest\x1a'.url_shortener.ListRoutingRulesResponse\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/links/{short_url}/rules\x12\x8d\x01\n" +
+	"\x11DeleteRoutingRule\x12'.url_shortener.DeleteRoutingRuleRequest\x1a(.url_shortener.DeleteRoutingRuleResponse\"%\x82\xd3\xe4\x93\x02\x1f*\x1d/links/{short_url}/rules/{id}\x12\x88\x01\n" +
+	"\x0fSetLinkVariants\x12%.url_shortener.SetLinkVariantsRequest\x1a&.url_shortener.SetLinkVariantsResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\x1a\x1b/links/{short_url}/variants\x12\x8e\x01\n" +
+	"\x10RecordConversion\x12&.url_shortener.RecordConversionRequest\x1a'.url_shortener.RecordConversionResponse\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/links/{short_url}/conversions\x12y\n" +
+	"\fGetLinkStats\x12\".url_shortener.GetLinkStatsRequest\x1a#.url_shortener.GetLinkStatsResponse\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/links/{short_url}/stats\x12\x88\x01\n" +
+	"\x0fSetLinkSchedule\x12%.url_shortener.SetLinkScheduleRequest\x1a&.url_shortener.SetLinkScheduleResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\x1a\x1b/links/{short_url}/schedule\x12\x85\x01\n" +
+	"\x0fGetLinkSchedule\x12%.url_shortener.GetLinkScheduleRequest\x1a&.url_shortener.GetLinkScheduleResponse\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/links/{short_url}/schedule\x12t\n" +
+	"\x0fCreateLinkGroup\x12%.url_shortener.CreateLinkGroupRequest\x1a&.url_shortener.CreateLinkGroupResponse\"\x12\x82\xd3\xe4\x93\x02\f:\x01*\"\a/groups\x12y\n" +
+	"\x0fRenameLinkGroup\x12%.url_shortener.RenameLinkGroupRequest\x1a&.url_shortener.RenameLinkGroupResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*2\f/groups/{id}\x12n\n" +
+	"\x0eListLinkGroups\x12$.url_shortener.ListLinkGroupsRequest\x1a%.url_shortener.ListLinkGroupsResponse\"\x0f\x82\xd3\xe4\x93\x02\t\x12\a/groups\x12s\n" +
+	"\vAssignLinks\x12!.url_shortener.AssignLinksRequest\x1a\".url_shortener.AssignLinksResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/groups/{id}/links\x12^\n" +
+	"\tListLinks\x12\x1f.url_shortener.ListLinksRequest\x1a .url_shortener.ListLinksResponse\"\x0e\x82\xd3\xe4\x93\x02\b\x12\x06/links\x12\x82\x01\n" +
+	"\x11GetLinkGroupStats\x12'.url_shortener.GetLinkGroupStatsRequest\x1a(.url_shortener.GetLinkGroupStatsResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/groups/{id}/stats\x12z\n" +
+	"\x0eSetCampaignUTM\x12$.url_shortener.SetCampaignUTMRequest\x1a%.url_shortener.SetCampaignUTMResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\x1a\x10/groups/{id}/utm\x12k\n" +
+	"\vSetUTMRules\x12!.url_shortener.SetUTMRulesRequest\x1a\".url_shortener.SetUTMRulesResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\x1a\n" +
+	"/utm/rules\x12k\n" +
+	"\fListUTMRules\x12\".url_shortener.ListUTMRulesRequest\x1a#.url_shortener.ListUTMRulesResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/utm/rules\x12h\n" +
+	"\vGetUTMStats\x12!.url_shortener.GetUTMStatsRequest\x1a\".url_shortener.GetUTMStatsResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/utm/stats\x12\x85\x01\n" +
+	"\x10SetLinkOpenGraph\x12&.url_shortener.SetLinkOpenGraphRequest\x1a'.url_shortener.SetLinkOpenGraphResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\x1a\x15/links/{short_url}/og\x12w\n" +
+	"\x0fListBrokenLinks\x12%.url_shortener.ListBrokenLinksRequest\x1a&.url_shortener.ListBrokenLinksResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/links/broken\x12x\n" +
+	"\x0fCreateWorkspace\x12%.url_shortener.CreateWorkspaceRequest\x1a&.url_shortener.CreateWorkspaceResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/workspaces\x12r\n" +
+	"\x0eListWorkspaces\x12$.url_shortener.ListWorkspacesRequest\x1a%.url_shortener.ListWorkspacesResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/workspaces\x12p\n" +
+	"\vListMembers\x12!.url_shortener.ListMembersRequest\x1a\".url_shortener.ListMembersResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/workspace/members\x12v\n" +
+	"\fInviteMember\x12\".url_shortener.InviteMemberRequest\x1a#.url_shortener.InviteMemberResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/workspace/members\x12\x8c\x01\n" +
+	"\x10ChangeMemberRole\x12&.url_shortener.ChangeMemberRoleRequest\x1a'.url_shortener.ChangeMemberRoleResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\x1a\x1c/workspace/members/{user_id}\x12}\n" +
+	"\fRemoveMember\x12\".url_shortener.RemoveMemberRequest\x1a#.url_shortener.RemoveMemberResponse\"$\x82\xd3\xe4\x93\x02\x1e*\x1c/workspace/members/{user_id}B7Z5github.com/alt-coder/url-shortner/url-shortener/protob\x06proto3"
+
+var (
+	file_url_shortener_proto_rawDescOnce sync.Once
+	file_url_shortener_proto_rawDescData []byte
+)
+
+func file_url_shortener_proto_rawDescGZIP() []byte {
+	file_url_shortener_proto_rawDescOnce.Do(func() {
+		file_url_shortener_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_url_shortener_proto_rawDesc), len(file_url_shortener_proto_rawDesc)))
+	})
+	return file_url_shortener_proto_rawDescData
+}
+
+var file_url_shortener_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_url_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 116)
+var file_url_shortener_proto_goTypes = []any{
+	(RedirectType)(0),                      // 0: url_shortener.RedirectType
+	(*ShortenURLRequest)(nil),              // 1: url_shortener.ShortenURLRequest
+	(*ShortenURLResponse)(nil),             // 2: url_shortener.ShortenURLResponse
+	(*GetURLRequest)(nil),                  // 3: url_shortener.GetURLRequest
+	(*GetURLResponse)(nil),                 // 4: url_shortener.GetURLResponse
+	(*CreateUserRequest)(nil),              // 5: url_shortener.CreateUserRequest
+	(*CreateUserResponse)(nil),             // 6: url_shortener.CreateUserResponse
+	(*FetchApiKeyRequest)(nil),             // 7: url_shortener.FetchApiKeyRequest
+	(*FetchApiKeyResponse)(nil),            // 8: url_shortener.FetchApiKeyResponse
+	(*DomainMetric)(nil),                   // 9: url_shortener.DomainMetric
+	(*GetTopDomainsRequest)(nil),           // 10: url_shortener.GetTopDomainsRequest
+	(*GetTopDomainsResponse)(nil),          // 11: url_shortener.GetTopDomainsResponse
+	(*GetUsageRequest)(nil),                // 12: url_shortener.GetUsageRequest
+	(*GetUsageResponse)(nil),               // 13: url_shortener.GetUsageResponse
+	(*PolicyRule)(nil),                     // 14: url_shortener.PolicyRule
+	(*AddPolicyRuleRequest)(nil),           // 15: url_shortener.AddPolicyRuleRequest
+	(*AddPolicyRuleResponse)(nil),          // 16: url_shortener.AddPolicyRuleResponse
+	(*RemovePolicyRuleRequest)(nil),        // 17: url_shortener.RemovePolicyRuleRequest
+	(*RemovePolicyRuleResponse)(nil),       // 18: url_shortener.RemovePolicyRuleResponse
+	(*ListPolicyRulesRequest)(nil),         // 19: url_shortener.ListPolicyRulesRequest
+	(*ListPolicyRulesResponse)(nil),        // 20: url_shortener.ListPolicyRulesResponse
+	(*GetQRCodeRequest)(nil),               // 21: url_shortener.GetQRCodeRequest
+	(*BatchShortenURLsRequest)(nil),        // 22: url_shortener.BatchShortenURLsRequest
+	(*BatchShortenResult)(nil),             // 23: url_shortener.BatchShortenResult
+	(*BatchShortenURLsResponse)(nil),       // 24: url_shortener.BatchShortenURLsResponse
+	(*StreamShortenRequest)(nil),           // 25: url_shortener.StreamShortenRequest
+	(*StreamShortenResponse)(nil),          // 26: url_shortener.StreamShortenResponse
+	(*WatchClicksRequest)(nil),             // 27: url_shortener.WatchClicksRequest
+	(*ClickEvent)(nil),                     // 28: url_shortener.ClickEvent
+	(*Webhook)(nil),                        // 29: url_shortener.Webhook
+	(*CreateWebhookRequest)(nil),           // 30: url_shortener.CreateWebhookRequest
+	(*CreateWebhookResponse)(nil),          // 31: url_shortener.CreateWebhookResponse
+	(*ListWebhooksRequest)(nil),            // 32: url_shortener.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),           // 33: url_shortener.ListWebhooksResponse
+	(*DeleteWebhookRequest)(nil),           // 34: url_shortener.DeleteWebhookRequest
+	(*DeleteWebhookResponse)(nil),          // 35: url_shortener.DeleteWebhookResponse
+	(*WebhookDelivery)(nil),                // 36: url_shortener.WebhookDelivery
+	(*ListWebhookDeliveriesRequest)(nil),   // 37: url_shortener.ListWebhookDeliveriesRequest
+	(*ListWebhookDeliveriesResponse)(nil),  // 38: url_shortener.ListWebhookDeliveriesResponse
+	(*ListWebhookDeadLettersRequest)(nil),  // 39: url_shortener.ListWebhookDeadLettersRequest
+	(*ListWebhookDeadLettersResponse)(nil), // 40: url_shortener.ListWebhookDeadLettersResponse
+	(*Event)(nil),                          // 41: url_shortener.Event
+	(*LinkCreated)(nil),                    // 42: url_shortener.LinkCreated
+	(*LinkClicked)(nil),                    // 43: url_shortener.LinkClicked
+	(*Domain)(nil),                         // 44: url_shortener.Domain
+	(*CreateDomainRequest)(nil),            // 45: url_shortener.CreateDomainRequest
+	(*CreateDomainResponse)(nil),           // 46: url_shortener.CreateDomainResponse
+	(*ListDomainsRequest)(nil),             // 47: url_shortener.ListDomainsRequest
+	(*ListDomainsResponse)(nil),            // 48: url_shortener.ListDomainsResponse
+	(*VerifyDomainRequest)(nil),            // 49: url_shortener.VerifyDomainRequest
+	(*VerifyDomainResponse)(nil),           // 50: url_shortener.VerifyDomainResponse
+	(*DeleteDomainRequest)(nil),            // 51: url_shortener.DeleteDomainRequest
+	(*DeleteDomainResponse)(nil),           // 52: url_shortener.DeleteDomainResponse
+	(*RoutingRule)(nil),                    // 53: url_shortener.RoutingRule
+	(*AddRoutingRuleRequest)(nil),          // 54: url_shortener.AddRoutingRuleRequest
+	(*AddRoutingRuleResponse)(nil),         // 55: url_shortener.AddRoutingRuleResponse
+	(*ListRoutingRulesRequest)(nil),        // 56: url_shortener.ListRoutingRulesRequest
+	(*ListRoutingRulesResponse)(nil),       // 57: url_shortener.ListRoutingRulesResponse
+	(*DeleteRoutingRuleRequest)(nil),       // 58: url_shortener.DeleteRoutingRuleRequest
+	(*DeleteRoutingRuleResponse)(nil),      // 59: url_shortener.DeleteRoutingRuleResponse
+	(*LinkVariant)(nil),                    // 60: url_shortener.LinkVariant
+	(*SetLinkVariantsRequest)(nil),         // 61: url_shortener.SetLinkVariantsRequest
+	(*SetLinkVariantsResponse)(nil),        // 62: url_shortener.SetLinkVariantsResponse
+	(*RecordConversionRequest)(nil),        // 63: url_shortener.RecordConversionRequest
+	(*RecordConversionResponse)(nil),       // 64: url_shortener.RecordConversionResponse
+	(*GetLinkStatsRequest)(nil),            // 65: url_shortener.GetLinkStatsRequest
+	(*GetLinkStatsResponse)(nil),           // 66: url_shortener.GetLinkStatsResponse
+	(*ScheduledDestination)(nil),           // 67: url_shortener.ScheduledDestination
+	(*SetLinkScheduleRequest)(nil),         // 68: url_shortener.SetLinkScheduleRequest
+	(*SetLinkScheduleResponse)(nil),        // 69: url_shortener.SetLinkScheduleResponse
+	(*GetLinkScheduleRequest)(nil),         // 70: url_shortener.GetLinkScheduleRequest
+	(*GetLinkScheduleResponse)(nil),        // 71: url_shortener.GetLinkScheduleResponse
+	(*LinkGroup)(nil),                      // 72: url_shortener.LinkGroup
+	(*CreateLinkGroupRequest)(nil),         // 73: url_shortener.CreateLinkGroupRequest
+	(*CreateLinkGroupResponse)(nil),        // 74: url_shortener.CreateLinkGroupResponse
+	(*RenameLinkGroupRequest)(nil),         // 75: url_shortener.RenameLinkGroupRequest
+	(*RenameLinkGroupResponse)(nil),        // 76: url_shortener.RenameLinkGroupResponse
+	(*ListLinkGroupsRequest)(nil),          // 77: url_shortener.ListLinkGroupsRequest
+	(*ListLinkGroupsResponse)(nil),         // 78: url_shortener.ListLinkGroupsResponse
+	(*AssignLinksRequest)(nil),             // 79: url_shortener.AssignLinksRequest
+	(*AssignLinksResponse)(nil),            // 80: url_shortener.AssignLinksResponse
+	(*Link)(nil),                           // 81: url_shortener.Link
+	(*ListLinksRequest)(nil),               // 82: url_shortener.ListLinksRequest
+	(*ListLinksResponse)(nil),              // 83: url_shortener.ListLinksResponse
+	(*GetLinkGroupStatsRequest)(nil),       // 84: url_shortener.GetLinkGroupStatsRequest
+	(*GetLinkGroupStatsResponse)(nil),      // 85: url_shortener.GetLinkGroupStatsResponse
+	(*UTMParams)(nil),                      // 86: url_shortener.UTMParams
+	(*SetCampaignUTMRequest)(nil),          // 87: url_shortener.SetCampaignUTMRequest
+	(*SetCampaignUTMResponse)(nil),         // 88: url_shortener.SetCampaignUTMResponse
+	(*UTMRule)(nil),                        // 89: url_shortener.UTMRule
+	(*SetUTMRulesRequest)(nil),             // 90: url_shortener.SetUTMRulesRequest
+	(*SetUTMRulesResponse)(nil),            // 91: url_shortener.SetUTMRulesResponse
+	(*ListUTMRulesRequest)(nil),            // 92: url_shortener.ListUTMRulesRequest
+	(*ListUTMRulesResponse)(nil),           // 93: url_shortener.ListUTMRulesResponse
+	(*GetUTMStatsRequest)(nil),             // 94: url_shortener.GetUTMStatsRequest
+	(*UTMStat)(nil),                        // 95: url_shortener.UTMStat
+	(*GetUTMStatsResponse)(nil),            // 96: url_shortener.GetUTMStatsResponse
+	(*OpenGraph)(nil),                      // 97: url_shortener.OpenGraph
+	(*SetLinkOpenGraphRequest)(nil),        // 98: url_shortener.SetLinkOpenGraphRequest
+	(*SetLinkOpenGraphResponse)(nil),       // 99: url_shortener.SetLinkOpenGraphResponse
+	(*LinkHealth)(nil),                     // 100: url_shortener.LinkHealth
+	(*ListBrokenLinksRequest)(nil),         // 101: url_shortener.ListBrokenLinksRequest
+	(*ListBrokenLinksResponse)(nil),        // 102: url_shortener.ListBrokenLinksResponse
+	(*Workspace)(nil),                      // 103: url_shortener.Workspace
+	(*CreateWorkspaceRequest)(nil),         // 104: url_shortener.CreateWorkspaceRequest
+	(*CreateWorkspaceResponse)(nil),        // 105: url_shortener.CreateWorkspaceResponse
+	(*ListWorkspacesRequest)(nil),          // 106: url_shortener.ListWorkspacesRequest
+	(*ListWorkspacesResponse)(nil),         // 107: url_shortener.ListWorkspacesResponse
+	(*WorkspaceMember)(nil),                // 108: url_shortener.WorkspaceMember
+	(*ListMembersRequest)(nil),             // 109: url_shortener.ListMembersRequest
+	(*ListMembersResponse)(nil),            // 110: url_shortener.ListMembersResponse
+	(*InviteMemberRequest)(nil),            // 111: url_shortener.InviteMemberRequest
+	(*InviteMemberResponse)(nil),           // 112: url_shortener.InviteMemberResponse
+	(*ChangeMemberRoleRequest)(nil),        // 113: url_shortener.ChangeMemberRoleRequest
+	(*ChangeMemberRoleResponse)(nil),       // 114: url_shortener.ChangeMemberRoleResponse
+	(*RemoveMemberRequest)(nil),            // 115: url_shortener.RemoveMemberRequest
+	(*RemoveMemberResponse)(nil),           // 116: url_shortener.RemoveMemberResponse
+	(*timestamppb.Timestamp)(nil),          // 117: google.protobuf.Timestamp
+	(*httpbody.HttpBody)(nil),              // 118: google.api.HttpBody
+}
+var file_url_shortener_proto_depIdxs = []int32{
+	0,   // 0: url_shortener.ShortenURLRequest.redirect_type:type_name -> url_shortener.RedirectType
+	86,  // 1: url_shortener.ShortenURLRequest.utm:type_name -> url_shortener.UTMParams
+	97,  // 2: url_shortener.ShortenURLRequest.og:type_name -> url_shortener.OpenGraph
+	117, // 3: url_shortener.ShortenURLResponse.created_at:type_name -> google.protobuf.Timestamp
+	86,  // 4: url_shortener.ShortenURLResponse.utm:type_name -> url_shortener.UTMParams
+	0,   // 5: url_shortener.GetURLResponse.redirect_type:type_name -> url_shortener.RedirectType
+	9,   // 6: url_shortener.GetTopDomainsResponse.top_domains:type_name -> url_shortener.DomainMetric
+	117, // 7: url_shortener.GetUsageResponse.cycle_start:type_name -> google.protobuf.Timestamp
+	117, // 8: url_shortener.GetUsageResponse.cycle_end:type_name -> google.protobuf.Timestamp
+	14,  // 9: url_shortener.AddPolicyRuleResponse.rule:type_name -> url_shortener.PolicyRule
+	14,  // 10: url_shortener.ListPolicyRulesResponse.rules:type_name -> url_shortener.PolicyRule
+	1,   // 11: url_shortener.BatchShortenURLsRequest.items:type_name -> url_shortener.ShortenURLRequest
+	23,  // 12: url_shortener.BatchShortenURLsResponse.results:type_name -> url_shortener.BatchShortenResult
+	1,   // 13: url_shortener.StreamShortenRequest.item:type_name -> url_shortener.ShortenURLRequest
+	117, // 14: url_shortener.ClickEvent.clicked_at:type_name -> google.protobuf.Timestamp
+	117, // 15: url_shortener.Webhook.created_at:type_name -> google.protobuf.Timestamp
+	29,  // 16: url_shortener.CreateWebhookResponse.webhook:type_name -> url_shortener.Webhook
+	29,  // 17: url_shortener.ListWebhooksResponse.webhooks:type_name -> url_shortener.Webhook
+	117, // 18: url_shortener.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	117, // 19: url_shortener.WebhookDelivery.next_attempt_at:type_name -> google.protobuf.Timestamp
+	117, // 20: url_shortener.WebhookDelivery.delivered_at:type_name -> google.protobuf.Timestamp
+	36,  // 21: url_shortener.ListWebhookDeliveriesResponse.deliveries:type_name -> url_shortener.WebhookDelivery
+	36,  // 22: url_shortener.ListWebhookDeadLettersResponse.dead_letters:type_name -> url_shortener.WebhookDelivery
+	117, // 23: url_shortener.Event.occurred_at:type_name -> google.protobuf.Timestamp
+	42,  // 24: url_shortener.Event.link_created:type_name -> url_shortener.LinkCreated
+	43,  // 25: url_shortener.Event.link_clicked:type_name -> url_shortener.LinkClicked
+	117, // 26: url_shortener.Domain.created_at:type_name -> google.protobuf.Timestamp
+	117, // 27: url_shortener.Domain.verified_at:type_name -> google.protobuf.Timestamp
+	44,  // 28: url_shortener.CreateDomainResponse.domain:type_name -> url_shortener.Domain
+	44,  // 29: url_shortener.ListDomainsResponse.domains:type_name -> url_shortener.Domain
+	44,  // 30: url_shortener.VerifyDomainResponse.domain:type_name -> url_shortener.Domain
+	117, // 31: url_shortener.RoutingRule.created_at:type_name -> google.protobuf.Timestamp
+	53,  // 32: url_shortener.AddRoutingRuleRequest.rule:type_name -> url_shortener.RoutingRule
+	53,  // 33: url_shortener.AddRoutingRuleResponse.rule:type_name -> url_shortener.RoutingRule
+	53,  // 34: url_shortener.ListRoutingRulesResponse.rules:type_name -> url_shortener.RoutingRule
+	60,  // 35: url_shortener.SetLinkVariantsRequest.variants:type_name -> url_shortener.LinkVariant
+	60,  // 36: url_shortener.SetLinkVariantsResponse.variants:type_name -> url_shortener.LinkVariant
+	117, // 37: url_shortener.GetLinkStatsResponse.created_at:type_name -> google.protobuf.Timestamp
+	60,  // 38: url_shortener.GetLinkStatsResponse.variants:type_name -> url_shortener.LinkVariant
+	72,  // 39: url_shortener.GetLinkStatsResponse.campaign:type_name -> url_shortener.LinkGroup
+	72,  // 40: url_shortener.GetLinkStatsResponse.folder:type_name -> url_shortener.LinkGroup
+	72,  // 41: url_shortener.GetLinkStatsResponse.tags:type_name -> url_shortener.LinkGroup
+	86,  // 42: url_shortener.GetLinkStatsResponse.utm:type_name -> url_shortener.UTMParams
+	97,  // 43: url_shortener.GetLinkStatsResponse.og:type_name -> url_shortener.OpenGraph
+	100, // 44: url_shortener.GetLinkStatsResponse.health:type_name -> url_shortener.LinkHealth
+	117, // 45: url_shortener.ScheduledDestination.activation_time:type_name -> google.protobuf.Timestamp
+	67,  // 46: url_shortener.SetLinkScheduleRequest.schedule:type_name -> url_shortener.ScheduledDestination
+	67,  // 47: url_shortener.SetLinkScheduleResponse.schedule:type_name -> url_shortener.ScheduledDestination
+	67,  // 48: url_shortener.GetLinkScheduleResponse.schedule:type_name -> url_shortener.ScheduledDestination
+	117, // 49: url_shortener.GetLinkScheduleResponse.next_change:type_name -> google.protobuf.Timestamp
+	117, // 50: url_shortener.LinkGroup.created_at:type_name -> google.protobuf.Timestamp
+	86,  // 51: url_shortener.LinkGroup.utm:type_name -> url_shortener.UTMParams
+	72,  // 52: url_shortener.CreateLinkGroupResponse.group:type_name -> url_shortener.LinkGroup
+	72,  // 53: url_shortener.RenameLinkGroupResponse.group:type_name -> url_shortener.LinkGroup
+	72,  // 54: url_shortener.ListLinkGroupsResponse.groups:type_name -> url_shortener.LinkGroup
+	117, // 55: url_shortener.Link.created_at:type_name -> google.protobuf.Timestamp
+	72,  // 56: url_shortener.Link.campaign:type_name -> url_shortener.LinkGroup
+	72,  // 57: url_shortener.Link.folder:type_name -> url_shortener.LinkGroup
+	72,  // 58: url_shortener.Link.tags:type_name -> url_shortener.LinkGroup
+	86,  // 59: url_shortener.Link.utm:type_name -> url_shortener.UTMParams
+	100, // 60: url_shortener.Link.health:type_name -> url_shortener.LinkHealth
+	81,  // 61: url_shortener.ListLinksResponse.links:type_name -> url_shortener.Link
+	72,  // 62: url_shortener.GetLinkGroupStatsResponse.group:type_name -> url_shortener.LinkGroup
+	81,  // 63: url_shortener.GetLinkGroupStatsResponse.top_links:type_name -> url_shortener.Link
+	9,   // 64: url_shortener.GetLinkGroupStatsResponse.top_domains:type_name -> url_shortener.DomainMetric
+	86,  // 65: url_shortener.SetCampaignUTMRequest.utm:type_name -> url_shortener.UTMParams
+	72,  // 66: url_shortener.SetCampaignUTMResponse.group:type_name -> url_shortener.LinkGroup
+	89,  // 67: url_shortener.SetUTMRulesRequest.rules:type_name -> url_shortener.UTMRule
+	89,  // 68: url_shortener.SetUTMRulesResponse.rules:type_name -> url_shortener.UTMRule
+	89,  // 69: url_shortener.ListUTMRulesResponse.rules:type_name -> url_shortener.UTMRule
+	95,  // 70: url_shortener.GetUTMStatsResponse.stats:type_name -> url_shortener.UTMStat
+	97,  // 71: url_shortener.SetLinkOpenGraphRequest.og:type_name -> url_shortener.OpenGraph
+	97,  // 72: url_shortener.SetLinkOpenGraphResponse.og:type_name -> url_shortener.OpenGraph
+	117, // 73: url_shortener.LinkHealth.checked_at:type_name -> google.protobuf.Timestamp
+	81,  // 74: url_shortener.ListBrokenLinksResponse.links:type_name -> url_shortener.Link
+	103, // 75: url_shortener.CreateWorkspaceResponse.workspace:type_name -> url_shortener.Workspace
+	103, // 76: url_shortener.ListWorkspacesResponse.workspaces:type_name -> url_shortener.Workspace
+	108, // 77: url_shortener.ListMembersResponse.members:type_name -> url_shortener.WorkspaceMember
+	108, // 78: url_shortener.InviteMemberResponse.member:type_name -> url_shortener.WorkspaceMember
+	108, // 79: url_shortener.ChangeMemberRoleResponse.member:type_name -> url_shortener.WorkspaceMember
+	1,   // 80: url_shortener.URLShortener.ShortenURL:input_type -> url_shortener.ShortenURLRequest
+	3,   // 81: url_shortener.URLShortener.GetURL:input_type -> url_shortener.GetURLRequest
+	5,   // 82: url_shortener.URLShortener.CreateUser:input_type -> url_shortener.CreateUserRequest
+	7,   // 83: url_shortener.URLShortener.FetchApiKey:input_type -> url_shortener.FetchApiKeyRequest
+	10,  // 84: url_shortener.URLShortener.GetTopDomains:input_type -> url_shortener.GetTopDomainsRequest
+	12,  // 85: url_shortener.URLShortener.GetUsage:input_type -> url_shortener.GetUsageRequest
+	15,  // 86: url_shortener.URLShortener.AddPolicyRule:input_type -> url_shortener.AddPolicyRuleRequest
+	17,  // 87: url_shortener.URLShortener.RemovePolicyRule:input_type -> url_shortener.RemovePolicyRuleRequest
+	19,  // 88: url_shortener.URLShortener.ListPolicyRules:input_type -> url_shortener.ListPolicyRulesRequest
+	22,  // 89: url_shortener.URLShortener.BatchShortenURLs:input_type -> url_shortener.BatchShortenURLsRequest
+	21,  // 90: url_shortener.URLShortener.GetQRCode:input_type -> url_shortener.GetQRCodeRequest
+	25,  // 91: url_shortener.URLShortener.StreamShorten:input_type -> url_shortener.StreamShortenRequest
+	27,  // 92: url_shortener.URLShortener.WatchClicks:input_type -> url_shortener.WatchClicksRequest
+	30,  // 93: url_shortener.URLShortener.CreateWebhook:input_type -> url_shortener.CreateWebhookRequest
+	32,  // 94: url_shortener.URLShortener.ListWebhooks:input_type -> url_shortener.ListWebhooksRequest
+	34,  // 95: url_shortener.URLShortener.DeleteWebhook:input_type -> url_shortener.DeleteWebhookRequest
+	37,  // 96: url_shortener.URLShortener.ListWebhookDeliveries:input_type -> url_shortener.ListWebhookDeliveriesRequest
+	39,  // 97: url_shortener.URLShortener.ListWebhookDeadLetters:input_type -> url_shortener.ListWebhookDeadLettersRequest
+	45,  // 98: url_shortener.URLShortener.CreateDomain:input_type -> url_shortener.CreateDomainRequest
+	47,  // 99: url_shortener.URLShortener.ListDomains:input_type -> url_shortener.ListDomainsRequest
+	49,  // 100: url_shortener.URLShortener.VerifyDomain:input_type -> url_shortener.VerifyDomainRequest
+	51,  // 101: url_shortener.URLShortener.DeleteDomain:input_type -> url_shortener.DeleteDomainRequest
+	54,  // 102: url_shortener.URLShortener.AddRoutingRule:input_type -> url_shortener.AddRoutingRuleRequest
+	56,  // 103: url_shortener.URLShortener.ListRoutingRules:input_type -> url_shortener.ListRoutingRulesRequest
+	58,  // 104: url_shortener.URLShortener.DeleteRoutingRule:input_type -> url_shortener.DeleteRoutingRuleRequest
+	61,  // 105: url_shortener.URLShortener.SetLinkVariants:input_type -> url_shortener.SetLinkVariantsRequest
+	63,  // 106: url_shortener.URLShortener.RecordConversion:input_type -> url_shortener.RecordConversionRequest
+	65,  // 107: url_shortener.URLShortener.GetLinkStats:input_type -> url_shortener.GetLinkStatsRequest
+	68,  // 108: url_shortener.URLShortener.SetLinkSchedule:input_type -> url_shortener.SetLinkScheduleRequest
+	70,  // 109: url_shortener.URLShortener.GetLinkSchedule:input_type -> url_shortener.GetLinkScheduleRequest
+	73,  // 110: url_shortener.URLShortener.CreateLinkGroup:input_type -> url_shortener.CreateLinkGroupRequest
+	75,  // 111: url_shortener.URLShortener.RenameLinkGroup:input_type -> url_shortener.RenameLinkGroupRequest
+	77,  // 112: url_shortener.URLShortener.ListLinkGroups:input_type -> url_shortener.ListLinkGroupsRequest
+	79,  // 113: url_shortener.URLShortener.AssignLinks:input_type -> url_shortener.AssignLinksRequest
+	82,  // 114: url_shortener.URLShortener.ListLinks:input_type -> url_shortener.ListLinksRequest
+	84,  // 115: url_shortener.URLShortener.GetLinkGroupStats:input_type -> url_shortener.GetLinkGroupStatsRequest
+	87,  // 116: url_shortener.URLShortener.SetCampaignUTM:input_type -> url_shortener.SetCampaignUTMRequest
+	90,  // 117: url_shortener.URLShortener.SetUTMRules:input_type -> url_shortener.SetUTMRulesRequest
+	92,  // 118: url_shortener.URLShortener.ListUTMRules:input_type -> url_shortener.ListUTMRulesRequest
+	94,  // 119: url_shortener.URLShortener.GetUTMStats:input_type -> url_shortener.GetUTMStatsRequest
+	98,  // 120: url_shortener.URLShortener.SetLinkOpenGraph:input_type -> url_shortener.SetLinkOpenGraphRequest
+	101, // 121: url_shortener.URLShortener.ListBrokenLinks:input_type -> url_shortener.ListBrokenLinksRequest
+	104, // 122: url_shortener.URLShortener.CreateWorkspace:input_type -> url_shortener.CreateWorkspaceRequest
+	106, // 123: url_shortener.URLShortener.ListWorkspaces:input_type -> url_shortener.ListWorkspacesRequest
+	109, // 124: url_shortener.URLShortener.ListMembers:input_type -> url_shortener.ListMembersRequest
+	111, // 125: url_shortener.URLShortener.InviteMember:input_type -> url_shortener.InviteMemberRequest
+	113, // 126: url_shortener.URLShortener.ChangeMemberRole:input_type -> url_shortener.ChangeMemberRoleRequest
+	115, // 127: url_shortener.URLShortener.RemoveMember:input_type -> url_shortener.RemoveMemberRequest
+	2,   // 128: url_shortener.URLShortener.ShortenURL:output_type -> url_shortener.ShortenURLResponse
+	4,   // 129: url_shortener.URLShortener.GetURL:output_type -> url_shortener.GetURLResponse
+	6,   // 130: url_shortener.URLShortener.CreateUser:output_type -> url_shortener.CreateUserResponse
+	8,   // 131: url_shortener.URLShortener.FetchApiKey:output_type -> url_shortener.FetchApiKeyResponse
+	11,  // 132: url_shortener.URLShortener.GetTopDomains:output_type -> url_shortener.GetTopDomainsResponse
+	13,  // 133: url_shortener.URLShortener.GetUsage:output_type -> url_shortener.GetUsageResponse
+	16,  // 134: url_shortener.URLShortener.AddPolicyRule:output_type -> url_shortener.AddPolicyRuleResponse
+	18,  // 135: url_shortener.URLShortener.RemovePolicyRule:output_type -> url_shortener.RemovePolicyRuleResponse
+	20,  // 136: url_shortener.URLShortener.ListPolicyRules:output_type -> url_shortener.ListPolicyRulesResponse
+	24,  // 137: url_shortener.URLShortener.BatchShortenURLs:output_type -> url_shortener.BatchShortenURLsResponse
+	118, // 138: url_shortener.URLShortener.GetQRCode:output_type -> google.api.HttpBody
+	26,  // 139: url_shortener.URLShortener.StreamShorten:output_type -> url_shortener.StreamShortenResponse
+	28,  // 140: url_shortener.URLShortener.WatchClicks:output_type -> url_shortener.ClickEvent
+	31,  // 141: url_shortener.URLShortener.CreateWebhook:output_type -> url_shortener.CreateWebhookResponse
+	33,  // 142: url_shortener.URLShortener.ListWebhooks:output_type -> url_shortener.ListWebhooksResponse
+	35,  // 143: url_shortener.URLShortener.DeleteWebhook:output_type -> url_shortener.DeleteWebhookResponse
+	38,  // 144: url_shortener.URLShortener.ListWebhookDeliveries:output_type -> url_shortener.ListWebhookDeliveriesResponse
+	40,  // 145: url_shortener.URLShortener.ListWebhookDeadLetters:output_type -> url_shortener.ListWebhookDeadLettersResponse
+	46,  // 146: url_shortener.URLShortener.CreateDomain:output_type -> url_shortener.CreateDomainResponse
+	48,  // 147: url_shortener.URLShortener.ListDomains:output_type -> url_shortener.ListDomainsResponse
+	50,  // 148: url_shortener.URLShortener.VerifyDomain:output_type -> url_shortener.VerifyDomainResponse
+	52,  // 149: url_shortener.URLShortener.DeleteDomain:output_type -> url_shortener.DeleteDomainResponse
+	55,  // 150: url_shortener.URLShortener.AddRoutingRule:output_type -> url_shortener.AddRoutingRuleResponse
+	57,  // 151: url_shortener.URLShortener.ListRoutingRules:output_type -> url_shortener.ListRoutingRulesResponse
+	59,  // 152: url_shortener.URLShortener.DeleteRoutingRule:output_type -> url_shortener.DeleteRoutingRuleResponse
+	62,  // 153: url_shortener.URLShortener.SetLinkVariants:output_type -> url_shortener.SetLinkVariantsResponse
+	64,  // 154: url_shortener.URLShortener.RecordConversion:output_type -> url_shortener.RecordConversionResponse
+	66,  // 155: url_shortener.URLShortener.GetLinkStats:output_type -> url_shortener.GetLinkStatsResponse
+	69,  // 156: url_shortener.URLShortener.SetLinkSchedule:output_type -> url_shortener.SetLinkScheduleResponse
+	71,  // 157: url_shortener.URLShortener.GetLinkSchedule:output_type -> url_shortener.GetLinkScheduleResponse
+	74,  // 158: url_shortener.URLShortener.CreateLinkGroup:output_type -> url_shortener.CreateLinkGroupResponse
+	76,  // 159: url_shortener.URLShortener.RenameLinkGroup:output_type -> url_shortener.RenameLinkGroupResponse
+	78,  // 160: url_shortener.URLShortener.ListLinkGroups:output_type -> url_shortener.ListLinkGroupsResponse
+	80,  // 161: url_shortener.URLShortener.AssignLinks:output_type -> url_shortener.AssignLinksResponse
+	83,  // 162: url_shortener.URLShortener.ListLinks:output_type -> url_shortener.ListLinksResponse
+	85,  // 163: url_shortener.URLShortener.GetLinkGroupStats:output_type -> url_shortener.GetLinkGroupStatsResponse
+	88,  // 164: url_shortener.URLShortener.SetCampaignUTM:output_type -> url_shortener.SetCampaignUTMResponse
+	91,  // 165: url_shortener.URLShortener.SetUTMRules:output_type -> url_shortener.SetUTMRulesResponse
+	93,  // 166: url_shortener.URLShortener.ListUTMRules:output_type -> url_shortener.ListUTMRulesResponse
+	96,  // 167: url_shortener.URLShortener.GetUTMStats:output_type -> url_shortener.GetUTMStatsResponse
+	99,  // 168: url_shortener.URLShortener.SetLinkOpenGraph:output_type -> url_shortener.SetLinkOpenGraphResponse
+	102, // 169: url_shortener.URLShortener.ListBrokenLinks:output_type -> url_shortener.ListBrokenLinksResponse
+	105, // 170: url_shortener.URLShortener.CreateWorkspace:output_type -> url_shortener.CreateWorkspaceResponse
+	107, // 171: url_shortener.URLShortener.ListWorkspaces:output_type -> url_shortener.ListWorkspacesResponse
+	110, // 172: url_shortener.URLShortener.ListMembers:output_type -> url_shortener.ListMembersResponse
+	112, // 173: url_shortener.URLShortener.InviteMember:output_type -> url_shortener.InviteMemberResponse
+	114, // 174: url_shortener.URLShortener.ChangeMemberRole:output_type -> url_shortener.ChangeMemberRoleResponse
+	116, // 175: url_shortener.URLShortener.RemoveMember:output_type -> url_shortener.RemoveMemberResponse
+	128, // [128:176] is the sub-list for method output_type
+	80,  // [80:128] is the sub-list for method input_type
+	80,  // [80:80] is the sub-list for extension type_name
+	80,  // [80:80] is the sub-list for extension extendee
+	0,   // [0:80] is the sub-list for field type_name
+}
+
+func init() { file_url_shortener_proto_init() }
+func file_url_shortener_proto_init() {
+	if File_url_shortener_proto != nil {
+		return
+	}
+	file_url_shortener_proto_msgTypes[20].OneofWrappers = []any{}
+	file_url_shortener_proto_msgTypes[40].OneofWrappers = []any{
+		(*Event_LinkCreated)(nil),
+		(*Event_LinkClicked)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_url_shortener_proto_rawDesc), len(file_url_shortener_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      1,
+			NumMessages:   116,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_url_shortener_proto_goTypes,
 		DependencyIndexes: file_url_shortener_proto_depIdxs,
+		EnumInfos:         file_url_shortener_proto_enumTypes,
 		MessageInfos:      file_url_shortener_proto_msgTypes,
 	}.Build()
 	File_url_shortener_proto = out.File
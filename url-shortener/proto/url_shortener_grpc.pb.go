@@ -8,6 +8,7 @@ package proto
 
 import (
 	context "context"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -19,11 +20,54 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	URLShortener_ShortenURL_FullMethodName    = "/url_shortener.URLShortener/ShortenURL"
-	URLShortener_GetURL_FullMethodName        = "/url_shortener.URLShortener/GetURL"
-	URLShortener_CreateUser_FullMethodName    = "/url_shortener.URLShortener/CreateUser"
-	URLShortener_FetchApiKey_FullMethodName   = "/url_shortener.URLShortener/FetchApiKey"
-	URLShortener_GetTopDomains_FullMethodName = "/url_shortener.URLShortener/GetTopDomains"
+	URLShortener_ShortenURL_FullMethodName             = "/url_shortener.URLShortener/ShortenURL"
+	URLShortener_GetURL_FullMethodName                 = "/url_shortener.URLShortener/GetURL"
+	URLShortener_CreateUser_FullMethodName             = "/url_shortener.URLShortener/CreateUser"
+	URLShortener_FetchApiKey_FullMethodName            = "/url_shortener.URLShortener/FetchApiKey"
+	URLShortener_GetTopDomains_FullMethodName          = "/url_shortener.URLShortener/GetTopDomains"
+	URLShortener_GetUsage_FullMethodName               = "/url_shortener.URLShortener/GetUsage"
+	URLShortener_AddPolicyRule_FullMethodName          = "/url_shortener.URLShortener/AddPolicyRule"
+	URLShortener_RemovePolicyRule_FullMethodName       = "/url_shortener.URLShortener/RemovePolicyRule"
+	URLShortener_ListPolicyRules_FullMethodName        = "/url_shortener.URLShortener/ListPolicyRules"
+	URLShortener_BatchShortenURLs_FullMethodName       = "/url_shortener.URLShortener/BatchShortenURLs"
+	URLShortener_GetQRCode_FullMethodName              = "/url_shortener.URLShortener/GetQRCode"
+	URLShortener_StreamShorten_FullMethodName          = "/url_shortener.URLShortener/StreamShorten"
+	URLShortener_WatchClicks_FullMethodName            = "/url_shortener.URLShortener/WatchClicks"
+	URLShortener_CreateWebhook_FullMethodName          = "/url_shortener.URLShortener/CreateWebhook"
+	URLShortener_ListWebhooks_FullMethodName           = "/url_shortener.URLShortener/ListWebhooks"
+	URLShortener_DeleteWebhook_FullMethodName          = "/url_shortener.URLShortener/DeleteWebhook"
+	URLShortener_ListWebhookDeliveries_FullMethodName  = "/url_shortener.URLShortener/ListWebhookDeliveries"
+	URLShortener_ListWebhookDeadLetters_FullMethodName = "/url_shortener.URLShortener/ListWebhookDeadLetters"
+	URLShortener_CreateDomain_FullMethodName           = "/url_shortener.URLShortener/CreateDomain"
+	URLShortener_ListDomains_FullMethodName            = "/url_shortener.URLShortener/ListDomains"
+	URLShortener_VerifyDomain_FullMethodName           = "/url_shortener.URLShortener/VerifyDomain"
+	URLShortener_DeleteDomain_FullMethodName           = "/url_shortener.URLShortener/DeleteDomain"
+	URLShortener_AddRoutingRule_FullMethodName         = "/url_shortener.URLShortener/AddRoutingRule"
+	URLShortener_ListRoutingRules_FullMethodName       = "/url_shortener.URLShortener/ListRoutingRules"
+	URLShortener_DeleteRoutingRule_FullMethodName      = "/url_shortener.URLShortener/DeleteRoutingRule"
+	URLShortener_SetLinkVariants_FullMethodName        = "/url_shortener.URLShortener/SetLinkVariants"
+	URLShortener_RecordConversion_FullMethodName       = "/url_shortener.URLShortener/RecordConversion"
+	URLShortener_GetLinkStats_FullMethodName           = "/url_shortener.URLShortener/GetLinkStats"
+	URLShortener_SetLinkSchedule_FullMethodName        = "/url_shortener.URLShortener/SetLinkSchedule"
+	URLShortener_GetLinkSchedule_FullMethodName        = "/url_shortener.URLShortener/GetLinkSchedule"
+	URLShortener_CreateLinkGroup_FullMethodName        = "/url_shortener.URLShortener/CreateLinkGroup"
+	URLShortener_RenameLinkGroup_FullMethodName        = "/url_shortener.URLShortener/RenameLinkGroup"
+	URLShortener_ListLinkGroups_FullMethodName         = "/url_shortener.URLShortener/ListLinkGroups"
+	URLShortener_AssignLinks_FullMethodName            = "/url_shortener.URLShortener/AssignLinks"
+	URLShortener_ListLinks_FullMethodName              = "/url_shortener.URLShortener/ListLinks"
+	URLShortener_GetLinkGroupStats_FullMethodName      = "/url_shortener.URLShortener/GetLinkGroupStats"
+	URLShortener_SetCampaignUTM_FullMethodName         = "/url_shortener.URLShortener/SetCampaignUTM"
+	URLShortener_SetUTMRules_FullMethodName            = "/url_shortener.URLShortener/SetUTMRules"
+	URLShortener_ListUTMRules_FullMethodName           = "/url_shortener.URLShortener/ListUTMRules"
+	URLShortener_GetUTMStats_FullMethodName            = "/url_shortener.URLShortener/GetUTMStats"
+	URLShortener_SetLinkOpenGraph_FullMethodName       = "/url_shortener.URLShortener/SetLinkOpenGraph"
+	URLShortener_ListBrokenLinks_FullMethodName        = "/url_shortener.URLShortener/ListBrokenLinks"
+	URLShortener_CreateWorkspace_FullMethodName        = "/url_shortener.URLShortener/CreateWorkspace"
+	URLShortener_ListWorkspaces_FullMethodName         = "/url_shortener.URLShortener/ListWorkspaces"
+	URLShortener_ListMembers_FullMethodName            = "/url_shortener.URLShortener/ListMembers"
+	URLShortener_InviteMember_FullMethodName           = "/url_shortener.URLShortener/InviteMember"
+	URLShortener_ChangeMemberRole_FullMethodName       = "/url_shortener.URLShortener/ChangeMemberRole"
+	URLShortener_RemoveMember_FullMethodName           = "/url_shortener.URLShortener/RemoveMember"
 )
 
 // URLShortenerClient is the client API for URLShortener service.
@@ -35,6 +79,112 @@ type URLShortenerClient interface {
 	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
 	FetchApiKey(ctx context.Context, in *FetchApiKeyRequest, opts ...grpc.CallOption) (*FetchApiKeyResponse, error)
 	GetTopDomains(ctx context.Context, in *GetTopDomainsRequest, opts ...grpc.CallOption) (*GetTopDomainsResponse, error)
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error)
+	AddPolicyRule(ctx context.Context, in *AddPolicyRuleRequest, opts ...grpc.CallOption) (*AddPolicyRuleResponse, error)
+	RemovePolicyRule(ctx context.Context, in *RemovePolicyRuleRequest, opts ...grpc.CallOption) (*RemovePolicyRuleResponse, error)
+	ListPolicyRules(ctx context.Context, in *ListPolicyRulesRequest, opts ...grpc.CallOption) (*ListPolicyRulesResponse, error)
+	// BatchShortenURLs shortens up to 1000 URLs at once. Each item gets its own
+	// result; a failing item does not fail the others.
+	BatchShortenURLs(ctx context.Context, in *BatchShortenURLsRequest, opts ...grpc.CallOption) (*BatchShortenURLsResponse, error)
+	// GetQRCode renders a QR code of the public short URL. Over HTTP the image
+	// itself is returned.
+	GetQRCode(ctx context.Context, in *GetQRCodeRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+	// StreamShorten is a long-lived variant of ShortenURL for high-volume
+	// clients. Every request gets a response; a failing item does not end the
+	// stream.
+	StreamShorten(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamShortenRequest, StreamShortenResponse], error)
+	// WatchClicks pushes click events of the caller's links as they happen.
+	WatchClicks(ctx context.Context, in *WatchClicksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ClickEvent], error)
+	// CreateWebhook subscribes a URL to events of the caller's links. The
+	// secret signing the deliveries is only returned here.
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	// DeleteWebhook removes a subscription and cancels its pending deliveries.
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	// ListWebhookDeliveries returns the delivery log, newest first.
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	// ListWebhookDeadLetters returns deliveries that failed all attempts,
+	// newest first.
+	ListWebhookDeadLetters(ctx context.Context, in *ListWebhookDeadLettersRequest, opts ...grpc.CallOption) (*ListWebhookDeadLettersResponse, error)
+	// CreateDomain adds a branded domain for the caller's links. Links are
+	// only served on it once VerifyDomain found its DNS TXT record.
+	CreateDomain(ctx context.Context, in *CreateDomainRequest, opts ...grpc.CallOption) (*CreateDomainResponse, error)
+	ListDomains(ctx context.Context, in *ListDomainsRequest, opts ...grpc.CallOption) (*ListDomainsResponse, error)
+	// VerifyDomain looks up the verification TXT record of a domain.
+	VerifyDomain(ctx context.Context, in *VerifyDomainRequest, opts ...grpc.CallOption) (*VerifyDomainResponse, error)
+	// DeleteDomain removes a domain. Its links are no longer served.
+	DeleteDomain(ctx context.Context, in *DeleteDomainRequest, opts ...grpc.CallOption) (*DeleteDomainResponse, error)
+	// AddRoutingRule sends visitors of a link on a matching OS, device or
+	// browser to another destination, e.g. an app store or a deep link.
+	AddRoutingRule(ctx context.Context, in *AddRoutingRuleRequest, opts ...grpc.CallOption) (*AddRoutingRuleResponse, error)
+	// ListRoutingRules returns the routing rules of a link in the order they
+	// are tried.
+	ListRoutingRules(ctx context.Context, in *ListRoutingRulesRequest, opts ...grpc.CallOption) (*ListRoutingRulesResponse, error)
+	// DeleteRoutingRule removes a routing rule of a link.
+	DeleteRoutingRule(ctx context.Context, in *DeleteRoutingRuleRequest, opts ...grpc.CallOption) (*DeleteRoutingRuleResponse, error)
+	// SetLinkVariants splits the traffic of a link across weighted
+	// destinations, replacing its variants. An empty list stops the split.
+	SetLinkVariants(ctx context.Context, in *SetLinkVariantsRequest, opts ...grpc.CallOption) (*SetLinkVariantsResponse, error)
+	// RecordConversion counts a conversion of a variant, e.g. a signup on its
+	// landing page.
+	RecordConversion(ctx context.Context, in *RecordConversionRequest, opts ...grpc.CallOption) (*RecordConversionResponse, error)
+	// GetLinkStats returns the clicks and conversions of a link per variant.
+	GetLinkStats(ctx context.Context, in *GetLinkStatsRequest, opts ...grpc.CallOption) (*GetLinkStatsResponse, error)
+	// SetLinkSchedule replaces the destinations a link switches to at given
+	// times. An empty schedule sends visitors to the long URL again.
+	SetLinkSchedule(ctx context.Context, in *SetLinkScheduleRequest, opts ...grpc.CallOption) (*SetLinkScheduleResponse, error)
+	// GetLinkSchedule returns the schedule of a link and its current
+	// destination.
+	GetLinkSchedule(ctx context.Context, in *GetLinkScheduleRequest, opts ...grpc.CallOption) (*GetLinkScheduleResponse, error)
+	// CreateLinkGroup adds a tag, campaign or folder to organize links in.
+	CreateLinkGroup(ctx context.Context, in *CreateLinkGroupRequest, opts ...grpc.CallOption) (*CreateLinkGroupResponse, error)
+	// RenameLinkGroup renames a tag, campaign or folder.
+	RenameLinkGroup(ctx context.Context, in *RenameLinkGroupRequest, opts ...grpc.CallOption) (*RenameLinkGroupResponse, error)
+	// ListLinkGroups returns the tags, campaigns and folders of the caller.
+	ListLinkGroups(ctx context.Context, in *ListLinkGroupsRequest, opts ...grpc.CallOption) (*ListLinkGroupsResponse, error)
+	// AssignLinks adds links to a tag, campaign or folder, or removes them
+	// from it.
+	AssignLinks(ctx context.Context, in *AssignLinksRequest, opts ...grpc.CallOption) (*AssignLinksResponse, error)
+	// ListLinks returns the links of the caller, newest first, optionally
+	// only those with a tag, campaign or folder.
+	ListLinks(ctx context.Context, in *ListLinksRequest, opts ...grpc.CallOption) (*ListLinksResponse, error)
+	// GetLinkGroupStats aggregates the clicks and conversions of the links
+	// of a tag, campaign or folder.
+	GetLinkGroupStats(ctx context.Context, in *GetLinkGroupStatsRequest, opts ...grpc.CallOption) (*GetLinkGroupStatsResponse, error)
+	// SetCampaignUTM sets the UTM parameters links created in a campaign
+	// inherit.
+	SetCampaignUTM(ctx context.Context, in *SetCampaignUTMRequest, opts ...grpc.CallOption) (*SetCampaignUTMResponse, error)
+	// SetUTMRules replaces the rules requiring UTM parameters on links to
+	// some domains.
+	SetUTMRules(ctx context.Context, in *SetUTMRulesRequest, opts ...grpc.CallOption) (*SetUTMRulesResponse, error)
+	// ListUTMRules returns the UTM rules of the caller.
+	ListUTMRules(ctx context.Context, in *ListUTMRulesRequest, opts ...grpc.CallOption) (*ListUTMRulesResponse, error)
+	// GetUTMStats groups the links and clicks of the caller by UTM source and
+	// medium.
+	GetUTMStats(ctx context.Context, in *GetUTMStatsRequest, opts ...grpc.CallOption) (*GetUTMStatsResponse, error)
+	// SetLinkOpenGraph sets the title, description and image chat apps and
+	// social networks show when a link is shared. Empty fields clear them.
+	SetLinkOpenGraph(ctx context.Context, in *SetLinkOpenGraphRequest, opts ...grpc.CallOption) (*SetLinkOpenGraphResponse, error)
+	// ListBrokenLinks lists the links of the caller whose destination failed
+	// its last health checks, newest first.
+	ListBrokenLinks(ctx context.Context, in *ListBrokenLinksRequest, opts ...grpc.CallOption) (*ListBrokenLinksResponse, error)
+	// CreateWorkspace creates a shared workspace owned by the caller. The
+	// response carries the caller's API key for it.
+	CreateWorkspace(ctx context.Context, in *CreateWorkspaceRequest, opts ...grpc.CallOption) (*CreateWorkspaceResponse, error)
+	// ListWorkspaces lists the workspaces the caller is a member of, with
+	// their API key for each.
+	ListWorkspaces(ctx context.Context, in *ListWorkspacesRequest, opts ...grpc.CallOption) (*ListWorkspacesResponse, error)
+	// ListMembers lists the members of the workspace of the API key.
+	ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersResponse, error)
+	// InviteMember adds a user to the workspace of the API key. Admins and
+	// owners may invite.
+	InviteMember(ctx context.Context, in *InviteMemberRequest, opts ...grpc.CallOption) (*InviteMemberResponse, error)
+	// ChangeMemberRole changes the role of a member of the workspace of the
+	// API key.
+	ChangeMemberRole(ctx context.Context, in *ChangeMemberRoleRequest, opts ...grpc.CallOption) (*ChangeMemberRoleResponse, error)
+	// RemoveMember removes a member from the workspace of the API key,
+	// revoking their API key for it. Members may remove themselves.
+	RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*RemoveMemberResponse, error)
 }
 
 type uRLShortenerClient struct {
@@ -95,6 +245,448 @@ func (c *uRLShortenerClient) GetTopDomains(ctx context.Context, in *GetTopDomain
 	return out, nil
 }
 
+func (c *uRLShortenerClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsageResponse)
+	err := c.cc.Invoke(ctx, URLShortener_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) AddPolicyRule(ctx context.Context, in *AddPolicyRuleRequest, opts ...grpc.CallOption) (*AddPolicyRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddPolicyRuleResponse)
+	err := c.cc.Invoke(ctx, URLShortener_AddPolicyRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) RemovePolicyRule(ctx context.Context, in *RemovePolicyRuleRequest, opts ...grpc.CallOption) (*RemovePolicyRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemovePolicyRuleResponse)
+	err := c.cc.Invoke(ctx, URLShortener_RemovePolicyRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListPolicyRules(ctx context.Context, in *ListPolicyRulesRequest, opts ...grpc.CallOption) (*ListPolicyRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPolicyRulesResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListPolicyRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) BatchShortenURLs(ctx context.Context, in *BatchShortenURLsRequest, opts ...grpc.CallOption) (*BatchShortenURLsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchShortenURLsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_BatchShortenURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) GetQRCode(ctx context.Context, in *GetQRCodeRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(httpbody.HttpBody)
+	err := c.cc.Invoke(ctx, URLShortener_GetQRCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) StreamShorten(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamShortenRequest, StreamShortenResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &URLShortener_ServiceDesc.Streams[0], URLShortener_StreamShorten_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamShortenRequest, StreamShortenResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLShortener_StreamShortenClient = grpc.BidiStreamingClient[StreamShortenRequest, StreamShortenResponse]
+
+func (c *uRLShortenerClient) WatchClicks(ctx context.Context, in *WatchClicksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ClickEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &URLShortener_ServiceDesc.Streams[1], URLShortener_WatchClicks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchClicksRequest, ClickEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLShortener_WatchClicksClient = grpc.ServerStreamingClient[ClickEvent]
+
+func (c *uRLShortenerClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateWebhookResponse)
+	err := c.cc.Invoke(ctx, URLShortener_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, URLShortener_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListWebhookDeadLetters(ctx context.Context, in *ListWebhookDeadLettersRequest, opts ...grpc.CallOption) (*ListWebhookDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeadLettersResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListWebhookDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) CreateDomain(ctx context.Context, in *CreateDomainRequest, opts ...grpc.CallOption) (*CreateDomainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateDomainResponse)
+	err := c.cc.Invoke(ctx, URLShortener_CreateDomain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListDomains(ctx context.Context, in *ListDomainsRequest, opts ...grpc.CallOption) (*ListDomainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDomainsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListDomains_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) VerifyDomain(ctx context.Context, in *VerifyDomainRequest, opts ...grpc.CallOption) (*VerifyDomainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyDomainResponse)
+	err := c.cc.Invoke(ctx, URLShortener_VerifyDomain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) DeleteDomain(ctx context.Context, in *DeleteDomainRequest, opts ...grpc.CallOption) (*DeleteDomainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteDomainResponse)
+	err := c.cc.Invoke(ctx, URLShortener_DeleteDomain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) AddRoutingRule(ctx context.Context, in *AddRoutingRuleRequest, opts ...grpc.CallOption) (*AddRoutingRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddRoutingRuleResponse)
+	err := c.cc.Invoke(ctx, URLShortener_AddRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListRoutingRules(ctx context.Context, in *ListRoutingRulesRequest, opts ...grpc.CallOption) (*ListRoutingRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRoutingRulesResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListRoutingRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) DeleteRoutingRule(ctx context.Context, in *DeleteRoutingRuleRequest, opts ...grpc.CallOption) (*DeleteRoutingRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRoutingRuleResponse)
+	err := c.cc.Invoke(ctx, URLShortener_DeleteRoutingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) SetLinkVariants(ctx context.Context, in *SetLinkVariantsRequest, opts ...grpc.CallOption) (*SetLinkVariantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLinkVariantsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_SetLinkVariants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) RecordConversion(ctx context.Context, in *RecordConversionRequest, opts ...grpc.CallOption) (*RecordConversionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecordConversionResponse)
+	err := c.cc.Invoke(ctx, URLShortener_RecordConversion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) GetLinkStats(ctx context.Context, in *GetLinkStatsRequest, opts ...grpc.CallOption) (*GetLinkStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLinkStatsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_GetLinkStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) SetLinkSchedule(ctx context.Context, in *SetLinkScheduleRequest, opts ...grpc.CallOption) (*SetLinkScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLinkScheduleResponse)
+	err := c.cc.Invoke(ctx, URLShortener_SetLinkSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) GetLinkSchedule(ctx context.Context, in *GetLinkScheduleRequest, opts ...grpc.CallOption) (*GetLinkScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLinkScheduleResponse)
+	err := c.cc.Invoke(ctx, URLShortener_GetLinkSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) CreateLinkGroup(ctx context.Context, in *CreateLinkGroupRequest, opts ...grpc.CallOption) (*CreateLinkGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateLinkGroupResponse)
+	err := c.cc.Invoke(ctx, URLShortener_CreateLinkGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) RenameLinkGroup(ctx context.Context, in *RenameLinkGroupRequest, opts ...grpc.CallOption) (*RenameLinkGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenameLinkGroupResponse)
+	err := c.cc.Invoke(ctx, URLShortener_RenameLinkGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListLinkGroups(ctx context.Context, in *ListLinkGroupsRequest, opts ...grpc.CallOption) (*ListLinkGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLinkGroupsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListLinkGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) AssignLinks(ctx context.Context, in *AssignLinksRequest, opts ...grpc.CallOption) (*AssignLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignLinksResponse)
+	err := c.cc.Invoke(ctx, URLShortener_AssignLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListLinks(ctx context.Context, in *ListLinksRequest, opts ...grpc.CallOption) (*ListLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLinksResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) GetLinkGroupStats(ctx context.Context, in *GetLinkGroupStatsRequest, opts ...grpc.CallOption) (*GetLinkGroupStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLinkGroupStatsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_GetLinkGroupStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) SetCampaignUTM(ctx context.Context, in *SetCampaignUTMRequest, opts ...grpc.CallOption) (*SetCampaignUTMResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetCampaignUTMResponse)
+	err := c.cc.Invoke(ctx, URLShortener_SetCampaignUTM_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) SetUTMRules(ctx context.Context, in *SetUTMRulesRequest, opts ...grpc.CallOption) (*SetUTMRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetUTMRulesResponse)
+	err := c.cc.Invoke(ctx, URLShortener_SetUTMRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListUTMRules(ctx context.Context, in *ListUTMRulesRequest, opts ...grpc.CallOption) (*ListUTMRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUTMRulesResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListUTMRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) GetUTMStats(ctx context.Context, in *GetUTMStatsRequest, opts ...grpc.CallOption) (*GetUTMStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUTMStatsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_GetUTMStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) SetLinkOpenGraph(ctx context.Context, in *SetLinkOpenGraphRequest, opts ...grpc.CallOption) (*SetLinkOpenGraphResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLinkOpenGraphResponse)
+	err := c.cc.Invoke(ctx, URLShortener_SetLinkOpenGraph_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListBrokenLinks(ctx context.Context, in *ListBrokenLinksRequest, opts ...grpc.CallOption) (*ListBrokenLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBrokenLinksResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListBrokenLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) CreateWorkspace(ctx context.Context, in *CreateWorkspaceRequest, opts ...grpc.CallOption) (*CreateWorkspaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateWorkspaceResponse)
+	err := c.cc.Invoke(ctx, URLShortener_CreateWorkspace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListWorkspaces(ctx context.Context, in *ListWorkspacesRequest, opts ...grpc.CallOption) (*ListWorkspacesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWorkspacesResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListWorkspaces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListMembers(ctx context.Context, in *ListMembersRequest, opts ...grpc.CallOption) (*ListMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMembersResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) InviteMember(ctx context.Context, in *InviteMemberRequest, opts ...grpc.CallOption) (*InviteMemberResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InviteMemberResponse)
+	err := c.cc.Invoke(ctx, URLShortener_InviteMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ChangeMemberRole(ctx context.Context, in *ChangeMemberRoleRequest, opts ...grpc.CallOption) (*ChangeMemberRoleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeMemberRoleResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ChangeMemberRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*RemoveMemberResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveMemberResponse)
+	err := c.cc.Invoke(ctx, URLShortener_RemoveMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // URLShortenerServer is the server API for URLShortener service.
 // All implementations must embed UnimplementedURLShortenerServer
 // for forward compatibility.
@@ -104,138 +696,1129 @@ type URLShortenerServer interface {
 	CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error)
 	FetchApiKey(context.Context, *FetchApiKeyRequest) (*FetchApiKeyResponse, error)
 	GetTopDomains(context.Context, *GetTopDomainsRequest) (*GetTopDomainsResponse, error)
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error)
+	AddPolicyRule(context.Context, *AddPolicyRuleRequest) (*AddPolicyRuleResponse, error)
+	RemovePolicyRule(context.Context, *RemovePolicyRuleRequest) (*RemovePolicyRuleResponse, error)
+	ListPolicyRules(context.Context, *ListPolicyRulesRequest) (*ListPolicyRulesResponse, error)
+	// BatchShortenURLs shortens up to 1000 URLs at once. Each item gets its own
+	// result; a failing item does not fail the others.
+	BatchShortenURLs(context.Context, *BatchShortenURLsRequest) (*BatchShortenURLsResponse, error)
+	// GetQRCode renders a QR code of the public short URL. Over HTTP the image
+	// itself is returned.
+	GetQRCode(context.Context, *GetQRCodeRequest) (*httpbody.HttpBody, error)
+	// StreamShorten is a long-lived variant of ShortenURL for high-volume
+	// clients. Every request gets a response; a failing item does not end the
+	// stream.
+	StreamShorten(grpc.BidiStreamingServer[StreamShortenRequest, StreamShortenResponse]) error
+	// WatchClicks pushes click events of the caller's links as they happen.
+	WatchClicks(*WatchClicksRequest, grpc.ServerStreamingServer[ClickEvent]) error
+	// CreateWebhook subscribes a URL to events of the caller's links. The
+	// secret signing the deliveries is only returned here.
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	// DeleteWebhook removes a subscription and cancels its pending deliveries.
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	// ListWebhookDeliveries returns the delivery log, newest first.
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	// ListWebhookDeadLetters returns deliveries that failed all attempts,
+	// newest first.
+	ListWebhookDeadLetters(context.Context, *ListWebhookDeadLettersRequest) (*ListWebhookDeadLettersResponse, error)
+	// CreateDomain adds a branded domain for the caller's links. Links are
+	// only served on it once VerifyDomain found its DNS TXT record.
+	CreateDomain(context.Context, *CreateDomainRequest) (*CreateDomainResponse, error)
+	ListDomains(context.Context, *ListDomainsRequest) (*ListDomainsResponse, error)
+	// VerifyDomain looks up the verification TXT record of a domain.
+	VerifyDomain(context.Context, *VerifyDomainRequest) (*VerifyDomainResponse, error)
+	// DeleteDomain removes a domain. Its links are no longer served.
+	DeleteDomain(context.Context, *DeleteDomainRequest) (*DeleteDomainResponse, error)
+	// AddRoutingRule sends visitors of a link on a matching OS, device or
+	// browser to another destination, e.g. an app store or a deep link.
+	AddRoutingRule(context.Context, *AddRoutingRuleRequest) (*AddRoutingRuleResponse, error)
+	// ListRoutingRules returns the routing rules of a link in the order they
+	// are tried.
+	ListRoutingRules(context.Context, *ListRoutingRulesRequest) (*ListRoutingRulesResponse, error)
+	// DeleteRoutingRule removes a routing rule of a link.
+	DeleteRoutingRule(context.Context, *DeleteRoutingRuleRequest) (*DeleteRoutingRuleResponse, error)
+	// SetLinkVariants splits the traffic of a link across weighted
+	// destinations, replacing its variants. An empty list stops the split.
+	SetLinkVariants(context.Context, *SetLinkVariantsRequest) (*SetLinkVariantsResponse, error)
+	// RecordConversion counts a conversion of a variant, e.g. a signup on its
+	// landing page.
+	RecordConversion(context.Context, *RecordConversionRequest) (*RecordConversionResponse, error)
+	// GetLinkStats returns the clicks and conversions of a link per variant.
+	GetLinkStats(context.Context, *GetLinkStatsRequest) (*GetLinkStatsResponse, error)
+	// SetLinkSchedule replaces the destinations a link switches to at given
+	// times. An empty schedule sends visitors to the long URL again.
+	SetLinkSchedule(context.Context, *SetLinkScheduleRequest) (*SetLinkScheduleResponse, error)
+	// GetLinkSchedule returns the schedule of a link and its current
+	// destination.
+	GetLinkSchedule(context.Context, *GetLinkScheduleRequest) (*GetLinkScheduleResponse, error)
+	// CreateLinkGroup adds a tag, campaign or folder to organize links in.
+	CreateLinkGroup(context.Context, *CreateLinkGroupRequest) (*CreateLinkGroupResponse, error)
+	// RenameLinkGroup renames a tag, campaign or folder.
+	RenameLinkGroup(context.Context, *RenameLinkGroupRequest) (*RenameLinkGroupResponse, error)
+	// ListLinkGroups returns the tags, campaigns and folders of the caller.
+	ListLinkGroups(context.Context, *ListLinkGroupsRequest) (*ListLinkGroupsResponse, error)
+	// AssignLinks adds links to a tag, campaign or folder, or removes them
+	// from it.
+	AssignLinks(context.Context, *AssignLinksRequest) (*AssignLinksResponse, error)
+	// ListLinks returns the links of the caller, newest first, optionally
+	// only those with a tag, campaign or folder.
+	ListLinks(context.Context, *ListLinksRequest) (*ListLinksResponse, error)
+	// GetLinkGroupStats aggregates the clicks and conversions of the links
+	// of a tag, campaign or folder.
+	GetLinkGroupStats(context.Context, *GetLinkGroupStatsRequest) (*GetLinkGroupStatsResponse, error)
+	// SetCampaignUTM sets the UTM parameters links created in a campaign
+	// inherit.
+	SetCampaignUTM(context.Context, *SetCampaignUTMRequest) (*SetCampaignUTMResponse, error)
+	// SetUTMRules replaces the rules requiring UTM parameters on links to
+	// some domains.
+	SetUTMRules(context.Context, *SetUTMRulesRequest) (*SetUTMRulesResponse, error)
+	// ListUTMRules returns the UTM rules of the caller.
+	ListUTMRules(context.Context, *ListUTMRulesRequest) (*ListUTMRulesResponse, error)
+	// GetUTMStats groups the links and clicks of the caller by UTM source and
+	// medium.
+	GetUTMStats(context.Context, *GetUTMStatsRequest) (*GetUTMStatsResponse, error)
+	// SetLinkOpenGraph sets the title, description and image chat apps and
+	// social networks show when a link is shared. Empty fields clear them.
+	SetLinkOpenGraph(context.Context, *SetLinkOpenGraphRequest) (*SetLinkOpenGraphResponse, error)
+	// ListBrokenLinks lists the links of the caller whose destination failed
+	// its last health checks, newest first.
+	ListBrokenLinks(context.Context, *ListBrokenLinksRequest) (*ListBrokenLinksResponse, error)
+	// CreateWorkspace creates a shared workspace owned by the caller. The
+	// response carries the caller's API key for it.
+	CreateWorkspace(context.Context, *CreateWorkspaceRequest) (*CreateWorkspaceResponse, error)
+	// ListWorkspaces lists the workspaces the caller is a member of, with
+	// their API key for each.
+	ListWorkspaces(context.Context, *ListWorkspacesRequest) (*ListWorkspacesResponse, error)
+	// ListMembers lists the members of the workspace of the API key.
+	ListMembers(context.Context, *ListMembersRequest) (*ListMembersResponse, error)
+	// InviteMember adds a user to the workspace of the API key. Admins and
+	// owners may invite.
+	InviteMember(context.Context, *InviteMemberRequest) (*InviteMemberResponse, error)
+	// ChangeMemberRole changes the role of a member of the workspace of the
+	// API key.
+	ChangeMemberRole(context.Context, *ChangeMemberRoleRequest) (*ChangeMemberRoleResponse, error)
+	// RemoveMember removes a member from the workspace of the API key,
+	// revoking their API key for it. Members may remove themselves.
+	RemoveMember(context.Context, *RemoveMemberRequest) (*RemoveMemberResponse, error)
+	mustEmbedUnimplementedURLShortenerServer()
+}
+
+// UnimplementedURLShortenerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedURLShortenerServer struct{}
+
+func (UnimplementedURLShortenerServer) ShortenURL(context.Context, *ShortenURLRequest) (*ShortenURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShortenURL not implemented")
+}
+func (UnimplementedURLShortenerServer) GetURL(context.Context, *GetURLRequest) (*GetURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetURL not implemented")
+}
+func (UnimplementedURLShortenerServer) CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedURLShortenerServer) FetchApiKey(context.Context, *FetchApiKeyRequest) (*FetchApiKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchApiKey not implemented")
+}
+func (UnimplementedURLShortenerServer) GetTopDomains(context.Context, *GetTopDomainsRequest) (*GetTopDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopDomains not implemented")
+}
+func (UnimplementedURLShortenerServer) GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedURLShortenerServer) AddPolicyRule(context.Context, *AddPolicyRuleRequest) (*AddPolicyRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPolicyRule not implemented")
+}
+func (UnimplementedURLShortenerServer) RemovePolicyRule(context.Context, *RemovePolicyRuleRequest) (*RemovePolicyRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePolicyRule not implemented")
+}
+func (UnimplementedURLShortenerServer) ListPolicyRules(context.Context, *ListPolicyRulesRequest) (*ListPolicyRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPolicyRules not implemented")
+}
+func (UnimplementedURLShortenerServer) BatchShortenURLs(context.Context, *BatchShortenURLsRequest) (*BatchShortenURLsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchShortenURLs not implemented")
+}
+func (UnimplementedURLShortenerServer) GetQRCode(context.Context, *GetQRCodeRequest) (*httpbody.HttpBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQRCode not implemented")
+}
+func (UnimplementedURLShortenerServer) StreamShorten(grpc.BidiStreamingServer[StreamShortenRequest, StreamShortenResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamShorten not implemented")
+}
+func (UnimplementedURLShortenerServer) WatchClicks(*WatchClicksRequest, grpc.ServerStreamingServer[ClickEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchClicks not implemented")
+}
+func (UnimplementedURLShortenerServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedURLShortenerServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedURLShortenerServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedURLShortenerServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedURLShortenerServer) ListWebhookDeadLetters(context.Context, *ListWebhookDeadLettersRequest) (*ListWebhookDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeadLetters not implemented")
+}
+func (UnimplementedURLShortenerServer) CreateDomain(context.Context, *CreateDomainRequest) (*CreateDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDomain not implemented")
+}
+func (UnimplementedURLShortenerServer) ListDomains(context.Context, *ListDomainsRequest) (*ListDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDomains not implemented")
+}
+func (UnimplementedURLShortenerServer) VerifyDomain(context.Context, *VerifyDomainRequest) (*VerifyDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyDomain not implemented")
+}
+func (UnimplementedURLShortenerServer) DeleteDomain(context.Context, *DeleteDomainRequest) (*DeleteDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDomain not implemented")
+}
+func (UnimplementedURLShortenerServer) AddRoutingRule(context.Context, *AddRoutingRuleRequest) (*AddRoutingRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRoutingRule not implemented")
+}
+func (UnimplementedURLShortenerServer) ListRoutingRules(context.Context, *ListRoutingRulesRequest) (*ListRoutingRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRoutingRules not implemented")
+}
+func (UnimplementedURLShortenerServer) DeleteRoutingRule(context.Context, *DeleteRoutingRuleRequest) (*DeleteRoutingRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRoutingRule not implemented")
+}
+func (UnimplementedURLShortenerServer) SetLinkVariants(context.Context, *SetLinkVariantsRequest) (*SetLinkVariantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLinkVariants not implemented")
+}
+func (UnimplementedURLShortenerServer) RecordConversion(context.Context, *RecordConversionRequest) (*RecordConversionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordConversion not implemented")
+}
+func (UnimplementedURLShortenerServer) GetLinkStats(context.Context, *GetLinkStatsRequest) (*GetLinkStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLinkStats not implemented")
+}
+func (UnimplementedURLShortenerServer) SetLinkSchedule(context.Context, *SetLinkScheduleRequest) (*SetLinkScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLinkSchedule not implemented")
+}
+func (UnimplementedURLShortenerServer) GetLinkSchedule(context.Context, *GetLinkScheduleRequest) (*GetLinkScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLinkSchedule not implemented")
+}
+func (UnimplementedURLShortenerServer) CreateLinkGroup(context.Context, *CreateLinkGroupRequest) (*CreateLinkGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLinkGroup not implemented")
+}
+func (UnimplementedURLShortenerServer) RenameLinkGroup(context.Context, *RenameLinkGroupRequest) (*RenameLinkGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameLinkGroup not implemented")
+}
+func (UnimplementedURLShortenerServer) ListLinkGroups(context.Context, *ListLinkGroupsRequest) (*ListLinkGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLinkGroups not implemented")
+}
+func (UnimplementedURLShortenerServer) AssignLinks(context.Context, *AssignLinksRequest) (*AssignLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignLinks not implemented")
+}
+func (UnimplementedURLShortenerServer) ListLinks(context.Context, *ListLinksRequest) (*ListLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLinks not implemented")
+}
+func (UnimplementedURLShortenerServer) GetLinkGroupStats(context.Context, *GetLinkGroupStatsRequest) (*GetLinkGroupStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLinkGroupStats not implemented")
+}
+func (UnimplementedURLShortenerServer) SetCampaignUTM(context.Context, *SetCampaignUTMRequest) (*SetCampaignUTMResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCampaignUTM not implemented")
+}
+func (UnimplementedURLShortenerServer) SetUTMRules(context.Context, *SetUTMRulesRequest) (*SetUTMRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUTMRules not implemented")
+}
+func (UnimplementedURLShortenerServer) ListUTMRules(context.Context, *ListUTMRulesRequest) (*ListUTMRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUTMRules not implemented")
+}
+func (UnimplementedURLShortenerServer) GetUTMStats(context.Context, *GetUTMStatsRequest) (*GetUTMStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUTMStats not implemented")
+}
+func (UnimplementedURLShortenerServer) SetLinkOpenGraph(context.Context, *SetLinkOpenGraphRequest) (*SetLinkOpenGraphResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLinkOpenGraph not implemented")
+}
+func (UnimplementedURLShortenerServer) ListBrokenLinks(context.Context, *ListBrokenLinksRequest) (*ListBrokenLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBrokenLinks not implemented")
+}
+func (UnimplementedURLShortenerServer) CreateWorkspace(context.Context, *CreateWorkspaceRequest) (*CreateWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWorkspace not implemented")
+}
+func (UnimplementedURLShortenerServer) ListWorkspaces(context.Context, *ListWorkspacesRequest) (*ListWorkspacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkspaces not implemented")
+}
+func (UnimplementedURLShortenerServer) ListMembers(context.Context, *ListMembersRequest) (*ListMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMembers not implemented")
+}
+func (UnimplementedURLShortenerServer) InviteMember(context.Context, *InviteMemberRequest) (*InviteMemberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteMember not implemented")
+}
+func (UnimplementedURLShortenerServer) ChangeMemberRole(context.Context, *ChangeMemberRoleRequest) (*ChangeMemberRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeMemberRole not implemented")
+}
+func (UnimplementedURLShortenerServer) RemoveMember(context.Context, *RemoveMemberRequest) (*RemoveMemberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMember not implemented")
+}
+func (UnimplementedURLShortenerServer) mustEmbedUnimplementedURLShortenerServer() {}
+func (UnimplementedURLShortenerServer) testEmbeddedByValue()                      {}
+
+// UnsafeURLShortenerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to URLShortenerServer will
+// result in compilation errors.
+type UnsafeURLShortenerServer interface {
 	mustEmbedUnimplementedURLShortenerServer()
 }
 
-// UnimplementedURLShortenerServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedURLShortenerServer struct{}
+func RegisterURLShortenerServer(s grpc.ServiceRegistrar, srv URLShortenerServer) {
+	// If the following call pancis, it indicates UnimplementedURLShortenerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&URLShortener_ServiceDesc, srv)
+}
+
+func _URLShortener_ShortenURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortenURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ShortenURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ShortenURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ShortenURL(ctx, req.(*ShortenURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_GetURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetURL(ctx, req.(*GetURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_FetchApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchApiKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).FetchApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_FetchApiKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).FetchApiKey(ctx, req.(*FetchApiKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_GetTopDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetTopDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetTopDomains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetTopDomains(ctx, req.(*GetTopDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_AddPolicyRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPolicyRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).AddPolicyRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_AddPolicyRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).AddPolicyRule(ctx, req.(*AddPolicyRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_RemovePolicyRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePolicyRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).RemovePolicyRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_RemovePolicyRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).RemovePolicyRule(ctx, req.(*RemovePolicyRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_ListPolicyRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPolicyRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ListPolicyRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ListPolicyRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ListPolicyRules(ctx, req.(*ListPolicyRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_BatchShortenURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchShortenURLsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).BatchShortenURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_BatchShortenURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).BatchShortenURLs(ctx, req.(*BatchShortenURLsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_GetQRCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQRCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetQRCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetQRCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetQRCode(ctx, req.(*GetQRCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_StreamShorten_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(URLShortenerServer).StreamShorten(&grpc.GenericServerStream[StreamShortenRequest, StreamShortenResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLShortener_StreamShortenServer = grpc.BidiStreamingServer[StreamShortenRequest, StreamShortenResponse]
+
+func _URLShortener_WatchClicks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchClicksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(URLShortenerServer).WatchClicks(m, &grpc.GenericServerStream[WatchClicksRequest, ClickEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLShortener_WatchClicksServer = grpc.ServerStreamingServer[ClickEvent]
+
+func _URLShortener_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_CreateWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_ListWebhookDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ListWebhookDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ListWebhookDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ListWebhookDeadLetters(ctx, req.(*ListWebhookDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_CreateDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).CreateDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_CreateDomain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).CreateDomain(ctx, req.(*CreateDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_ListDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ListDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ListDomains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ListDomains(ctx, req.(*ListDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_VerifyDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).VerifyDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_VerifyDomain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).VerifyDomain(ctx, req.(*VerifyDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_DeleteDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).DeleteDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_DeleteDomain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).DeleteDomain(ctx, req.(*DeleteDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_AddRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).AddRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_AddRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).AddRoutingRule(ctx, req.(*AddRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_ListRoutingRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoutingRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ListRoutingRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ListRoutingRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ListRoutingRules(ctx, req.(*ListRoutingRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_DeleteRoutingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRoutingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).DeleteRoutingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_DeleteRoutingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).DeleteRoutingRule(ctx, req.(*DeleteRoutingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_SetLinkVariants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLinkVariantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).SetLinkVariants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_SetLinkVariants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).SetLinkVariants(ctx, req.(*SetLinkVariantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_RecordConversion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordConversionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).RecordConversion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_RecordConversion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).RecordConversion(ctx, req.(*RecordConversionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_GetLinkStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetLinkStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetLinkStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetLinkStats(ctx, req.(*GetLinkStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_SetLinkSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLinkScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).SetLinkSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_SetLinkSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).SetLinkSchedule(ctx, req.(*SetLinkScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_GetLinkSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetLinkSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetLinkSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetLinkSchedule(ctx, req.(*GetLinkScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_CreateLinkGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLinkGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).CreateLinkGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_CreateLinkGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).CreateLinkGroup(ctx, req.(*CreateLinkGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_RenameLinkGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameLinkGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).RenameLinkGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_RenameLinkGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).RenameLinkGroup(ctx, req.(*RenameLinkGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_ListLinkGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLinkGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ListLinkGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ListLinkGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ListLinkGroups(ctx, req.(*ListLinkGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_AssignLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).AssignLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_AssignLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).AssignLinks(ctx, req.(*AssignLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_ListLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ListLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ListLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ListLinks(ctx, req.(*ListLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-func (UnimplementedURLShortenerServer) ShortenURL(context.Context, *ShortenURLRequest) (*ShortenURLResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ShortenURL not implemented")
+func _URLShortener_GetLinkGroupStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkGroupStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetLinkGroupStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetLinkGroupStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetLinkGroupStats(ctx, req.(*GetLinkGroupStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedURLShortenerServer) GetURL(context.Context, *GetURLRequest) (*GetURLResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetURL not implemented")
+
+func _URLShortener_SetCampaignUTM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCampaignUTMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).SetCampaignUTM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_SetCampaignUTM_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).SetCampaignUTM(ctx, req.(*SetCampaignUTMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedURLShortenerServer) CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+
+func _URLShortener_SetUTMRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUTMRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).SetUTMRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_SetUTMRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).SetUTMRules(ctx, req.(*SetUTMRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedURLShortenerServer) FetchApiKey(context.Context, *FetchApiKeyRequest) (*FetchApiKeyResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method FetchApiKey not implemented")
+
+func _URLShortener_ListUTMRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUTMRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ListUTMRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ListUTMRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ListUTMRules(ctx, req.(*ListUTMRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedURLShortenerServer) GetTopDomains(context.Context, *GetTopDomainsRequest) (*GetTopDomainsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetTopDomains not implemented")
+
+func _URLShortener_GetUTMStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUTMStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetUTMStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetUTMStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetUTMStats(ctx, req.(*GetUTMStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedURLShortenerServer) mustEmbedUnimplementedURLShortenerServer() {}
-func (UnimplementedURLShortenerServer) testEmbeddedByValue()                      {}
 
-// UnsafeURLShortenerServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to URLShortenerServer will
-// result in compilation errors.
-type UnsafeURLShortenerServer interface {
-	mustEmbedUnimplementedURLShortenerServer()
+func _URLShortener_SetLinkOpenGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLinkOpenGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).SetLinkOpenGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_SetLinkOpenGraph_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).SetLinkOpenGraph(ctx, req.(*SetLinkOpenGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterURLShortenerServer(s grpc.ServiceRegistrar, srv URLShortenerServer) {
-	// If the following call pancis, it indicates UnimplementedURLShortenerServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _URLShortener_ListBrokenLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBrokenLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&URLShortener_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ListBrokenLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ListBrokenLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ListBrokenLinks(ctx, req.(*ListBrokenLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _URLShortener_ShortenURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ShortenURLRequest)
+func _URLShortener_CreateWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWorkspaceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(URLShortenerServer).ShortenURL(ctx, in)
+		return srv.(URLShortenerServer).CreateWorkspace(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: URLShortener_ShortenURL_FullMethodName,
+		FullMethod: URLShortener_CreateWorkspace_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(URLShortenerServer).ShortenURL(ctx, req.(*ShortenURLRequest))
+		return srv.(URLShortenerServer).CreateWorkspace(ctx, req.(*CreateWorkspaceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _URLShortener_GetURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetURLRequest)
+func _URLShortener_ListWorkspaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkspacesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(URLShortenerServer).GetURL(ctx, in)
+		return srv.(URLShortenerServer).ListWorkspaces(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: URLShortener_GetURL_FullMethodName,
+		FullMethod: URLShortener_ListWorkspaces_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(URLShortenerServer).GetURL(ctx, req.(*GetURLRequest))
+		return srv.(URLShortenerServer).ListWorkspaces(ctx, req.(*ListWorkspacesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _URLShortener_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateUserRequest)
+func _URLShortener_ListMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMembersRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(URLShortenerServer).CreateUser(ctx, in)
+		return srv.(URLShortenerServer).ListMembers(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: URLShortener_CreateUser_FullMethodName,
+		FullMethod: URLShortener_ListMembers_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(URLShortenerServer).CreateUser(ctx, req.(*CreateUserRequest))
+		return srv.(URLShortenerServer).ListMembers(ctx, req.(*ListMembersRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _URLShortener_FetchApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FetchApiKeyRequest)
+func _URLShortener_InviteMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteMemberRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(URLShortenerServer).FetchApiKey(ctx, in)
+		return srv.(URLShortenerServer).InviteMember(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: URLShortener_FetchApiKey_FullMethodName,
+		FullMethod: URLShortener_InviteMember_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(URLShortenerServer).FetchApiKey(ctx, req.(*FetchApiKeyRequest))
+		return srv.(URLShortenerServer).InviteMember(ctx, req.(*InviteMemberRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _URLShortener_GetTopDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetTopDomainsRequest)
+func _URLShortener_ChangeMemberRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeMemberRoleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(URLShortenerServer).GetTopDomains(ctx, in)
+		return srv.(URLShortenerServer).ChangeMemberRole(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: URLShortener_GetTopDomains_FullMethodName,
+		FullMethod: URLShortener_ChangeMemberRole_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(URLShortenerServer).GetTopDomains(ctx, req.(*GetTopDomainsRequest))
+		return srv.(URLShortenerServer).ChangeMemberRole(ctx, req.(*ChangeMemberRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).RemoveMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_RemoveMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).RemoveMember(ctx, req.(*RemoveMemberRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -267,7 +1850,183 @@ var URLShortener_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetTopDomains",
 			Handler:    _URLShortener_GetTopDomains_Handler,
 		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _URLShortener_GetUsage_Handler,
+		},
+		{
+			MethodName: "AddPolicyRule",
+			Handler:    _URLShortener_AddPolicyRule_Handler,
+		},
+		{
+			MethodName: "RemovePolicyRule",
+			Handler:    _URLShortener_RemovePolicyRule_Handler,
+		},
+		{
+			MethodName: "ListPolicyRules",
+			Handler:    _URLShortener_ListPolicyRules_Handler,
+		},
+		{
+			MethodName: "BatchShortenURLs",
+			Handler:    _URLShortener_BatchShortenURLs_Handler,
+		},
+		{
+			MethodName: "GetQRCode",
+			Handler:    _URLShortener_GetQRCode_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _URLShortener_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _URLShortener_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _URLShortener_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _URLShortener_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeadLetters",
+			Handler:    _URLShortener_ListWebhookDeadLetters_Handler,
+		},
+		{
+			MethodName: "CreateDomain",
+			Handler:    _URLShortener_CreateDomain_Handler,
+		},
+		{
+			MethodName: "ListDomains",
+			Handler:    _URLShortener_ListDomains_Handler,
+		},
+		{
+			MethodName: "VerifyDomain",
+			Handler:    _URLShortener_VerifyDomain_Handler,
+		},
+		{
+			MethodName: "DeleteDomain",
+			Handler:    _URLShortener_DeleteDomain_Handler,
+		},
+		{
+			MethodName: "AddRoutingRule",
+			Handler:    _URLShortener_AddRoutingRule_Handler,
+		},
+		{
+			MethodName: "ListRoutingRules",
+			Handler:    _URLShortener_ListRoutingRules_Handler,
+		},
+		{
+			MethodName: "DeleteRoutingRule",
+			Handler:    _URLShortener_DeleteRoutingRule_Handler,
+		},
+		{
+			MethodName: "SetLinkVariants",
+			Handler:    _URLShortener_SetLinkVariants_Handler,
+		},
+		{
+			MethodName: "RecordConversion",
+			Handler:    _URLShortener_RecordConversion_Handler,
+		},
+		{
+			MethodName: "GetLinkStats",
+			Handler:    _URLShortener_GetLinkStats_Handler,
+		},
+		{
+			MethodName: "SetLinkSchedule",
+			Handler:    _URLShortener_SetLinkSchedule_Handler,
+		},
+		{
+			MethodName: "GetLinkSchedule",
+			Handler:    _URLShortener_GetLinkSchedule_Handler,
+		},
+		{
+			MethodName: "CreateLinkGroup",
+			Handler:    _URLShortener_CreateLinkGroup_Handler,
+		},
+		{
+			MethodName: "RenameLinkGroup",
+			Handler:    _URLShortener_RenameLinkGroup_Handler,
+		},
+		{
+			MethodName: "ListLinkGroups",
+			Handler:    _URLShortener_ListLinkGroups_Handler,
+		},
+		{
+			MethodName: "AssignLinks",
+			Handler:    _URLShortener_AssignLinks_Handler,
+		},
+		{
+			MethodName: "ListLinks",
+			Handler:    _URLShortener_ListLinks_Handler,
+		},
+		{
+			MethodName: "GetLinkGroupStats",
+			Handler:    _URLShortener_GetLinkGroupStats_Handler,
+		},
+		{
+			MethodName: "SetCampaignUTM",
+			Handler:    _URLShortener_SetCampaignUTM_Handler,
+		},
+		{
+			MethodName: "SetUTMRules",
+			Handler:    _URLShortener_SetUTMRules_Handler,
+		},
+		{
+			MethodName: "ListUTMRules",
+			Handler:    _URLShortener_ListUTMRules_Handler,
+		},
+		{
+			MethodName: "GetUTMStats",
+			Handler:    _URLShortener_GetUTMStats_Handler,
+		},
+		{
+			MethodName: "SetLinkOpenGraph",
+			Handler:    _URLShortener_SetLinkOpenGraph_Handler,
+		},
+		{
+			MethodName: "ListBrokenLinks",
+			Handler:    _URLShortener_ListBrokenLinks_Handler,
+		},
+		{
+			MethodName: "CreateWorkspace",
+			Handler:    _URLShortener_CreateWorkspace_Handler,
+		},
+		{
+			MethodName: "ListWorkspaces",
+			Handler:    _URLShortener_ListWorkspaces_Handler,
+		},
+		{
+			MethodName: "ListMembers",
+			Handler:    _URLShortener_ListMembers_Handler,
+		},
+		{
+			MethodName: "InviteMember",
+			Handler:    _URLShortener_InviteMember_Handler,
+		},
+		{
+			MethodName: "ChangeMemberRole",
+			Handler:    _URLShortener_ChangeMemberRole_Handler,
+		},
+		{
+			MethodName: "RemoveMember",
+			Handler:    _URLShortener_RemoveMember_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamShorten",
+			Handler:       _URLShortener_StreamShorten_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchClicks",
+			Handler:       _URLShortener_WatchClicks_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "url_shortener.proto",
 }